@@ -21,10 +21,13 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/minio/minio/cmd/config"
 	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/bucket/lifecycle"
 	"github.com/minio/minio/pkg/env"
 	iampolicy "github.com/minio/minio/pkg/iam/policy"
 	"github.com/minio/minio/pkg/madmin"
@@ -80,6 +83,7 @@ func (a adminAPIHandlers) PutBucketQuotaConfigHandler(w http.ResponseWriter, r *
 		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
 		return
 	}
+	globalBucketQuotaSys.reset(bucket)
 
 	// Write success response.
 	writeSuccessResponseHeadersOnly(w)
@@ -120,6 +124,160 @@ func (a adminAPIHandlers) GetBucketQuotaConfigHandler(w http.ResponseWriter, r *
 	writeSuccessResponseJSON(w, configData)
 }
 
+// PutUserQuotaHandler - PUT /minio/admin/v3/set-user-quota?userOrGroup=<name>&isGroup=<bool>
+// ----------
+// Places a quota on the objects written by a user, or by the members
+// of a group, across all buckets.
+func (a adminAPIHandlers) PutUserQuotaHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "PutUserQuota")
+
+	defer logger.AuditLog(w, r, "PutUserQuota", mustGetClaimsFromToken(r))
+
+	objectAPI, _ := validateAdminUsersReq(ctx, w, r, iampolicy.SetUserQuotaAdminAction)
+	if objectAPI == nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	name := vars["userOrGroup"]
+	isGroup := vars["isGroup"] == "true"
+
+	// Turn off quota commands if data usage info is unavailable.
+	if env.Get(envDataUsageCrawlConf, config.EnableOn) == config.EnableOff {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminBucketQuotaDisabled), r.URL)
+		return
+	}
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrInvalidRequest), r.URL)
+		return
+	}
+
+	var quota madmin.UserQuota
+	if err = json.Unmarshal(data, &quota); err != nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigBadJSON), r.URL)
+		return
+	}
+
+	if err = globalIAMSys.SetQuota(ctx, name, isGroup, quota); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+	globalBucketQuotaSys.reset("")
+
+	// Write success response.
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetUserQuotaHandler - GET /minio/admin/v3/get-user-quota?userOrGroup=<name>&isGroup=<bool>
+func (a adminAPIHandlers) GetUserQuotaHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "GetUserQuota")
+
+	defer logger.AuditLog(w, r, "GetUserQuota", mustGetClaimsFromToken(r))
+
+	objectAPI, _ := validateAdminUsersReq(ctx, w, r, iampolicy.GetUserQuotaAdminAction)
+	if objectAPI == nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	name := vars["userOrGroup"]
+	isGroup := vars["isGroup"] == "true"
+
+	quotas, err := globalIAMSys.GetQuotas(ctx)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+	quota := quotas.Users[name]
+	if isGroup {
+		quota = quotas.Groups[name]
+	}
+
+	data, err := json.Marshal(quota)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	// Write success response.
+	writeSuccessResponseJSON(w, data)
+}
+
+// PutRateLimitHandler - PUT /minio/admin/v3/set-rate-limit?type=<user|group|bucket>&name=<name>
+func (a adminAPIHandlers) PutRateLimitHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "PutRateLimit")
+
+	defer logger.AuditLog(w, r, "PutRateLimit", mustGetClaimsFromToken(r))
+
+	objectAPI, _ := validateAdminUsersReq(ctx, w, r, iampolicy.SetRateLimitAdminAction)
+	if objectAPI == nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	limitType := madmin.RateLimitType(vars["type"])
+	name := vars["name"]
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrInvalidRequest), r.URL)
+		return
+	}
+
+	var limit madmin.RateLimit
+	if err = json.Unmarshal(data, &limit); err != nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigBadJSON), r.URL)
+		return
+	}
+
+	// Rate limits of removed buckets can be removed.
+	if limitType == madmin.BucketRateLimit && !limit.IsEmpty() {
+		if _, err = objectAPI.GetBucketInfo(ctx, name); err != nil {
+			writeErrorResponseJSON(ctx, w, toAPIError(ctx, err), r.URL)
+			return
+		}
+	}
+
+	if err = globalIAMSys.SetRateLimit(ctx, limitType, name, limit); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+	// The other servers reload the rate limits periodically.
+	logger.LogIf(ctx, globalRateLimitSys.load(ctx, globalIAMSys))
+
+	// Write success response.
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetRateLimitsHandler - GET /minio/admin/v3/get-rate-limits
+func (a adminAPIHandlers) GetRateLimitsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "GetRateLimits")
+
+	defer logger.AuditLog(w, r, "GetRateLimits", mustGetClaimsFromToken(r))
+
+	objectAPI, _ := validateAdminUsersReq(ctx, w, r, iampolicy.GetRateLimitAdminAction)
+	if objectAPI == nil {
+		return
+	}
+
+	limits, err := globalIAMSys.GetRateLimits(ctx)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	data, err := json.Marshal(limits.RateLimits)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	// Write success response.
+	writeSuccessResponseJSON(w, data)
+}
+
 // SetRemoteTargetHandler - sets a remote target for bucket
 func (a adminAPIHandlers) SetRemoteTargetHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := newContext(r, w, "SetBucketTarget")
@@ -296,3 +454,330 @@ func (a adminAPIHandlers) RemoveRemoteTargetHandler(w http.ResponseWriter, r *ht
 	// Write success response.
 	writeSuccessNoContent(w)
 }
+
+// ReplicationQueueInfoHandler - GET /minio/admin/v3/replication-queue?bucket=mybucket
+// ----------
+// Returns the state of the replication queue of all nodes, pending
+// and dead-lettered tasks are reported per bucket.
+func (a adminAPIHandlers) ReplicationQueueInfoHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "ReplicationQueueInfo")
+
+	defer logger.AuditLog(w, r, "ReplicationQueueInfo", mustGetClaimsFromToken(r))
+
+	// Bucket replication and thus its queue are only supported in
+	// erasure mode.
+	if !globalIsErasure {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrNotImplemented), r.URL)
+		return
+	}
+
+	objectAPI, _ := validateAdminUsersReq(ctx, w, r, iampolicy.ReplicationInfoAdminAction)
+	if objectAPI == nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL)
+		return
+	}
+
+	bucket := mux.Vars(r)["bucket"]
+	if bucket != "" {
+		if _, err := objectAPI.GetBucketInfo(ctx, bucket); err != nil {
+			writeErrorResponseJSON(ctx, w, toAPIError(ctx, err), r.URL)
+			return
+		}
+	}
+
+	info := globalNotificationSys.GetReplicationQueueInfo(ctx, bucket)
+	now := UTCNow()
+	for b, bi := range info.Buckets {
+		if !bi.OldestPending.IsZero() {
+			bi.OldestPendingAge = now.Sub(bi.OldestPending)
+		}
+		info.Buckets[b] = bi
+	}
+
+	data, err := json.Marshal(info)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	// Write success response.
+	writeSuccessResponseJSON(w, data)
+}
+
+// ReplicationMetricsHandler - GET /minio/admin/v3/replication-metrics?bucket=mybucket
+// ----------
+// Returns the replication metrics of all nodes per bucket and remote
+// target: pending and failed operations, lag, bandwidth and last error.
+func (a adminAPIHandlers) ReplicationMetricsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "ReplicationMetrics")
+
+	defer logger.AuditLog(w, r, "ReplicationMetrics", mustGetClaimsFromToken(r))
+
+	objectAPI, _ := validateAdminUsersReq(ctx, w, r, iampolicy.ReplicationInfoAdminAction)
+	if objectAPI == nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL)
+		return
+	}
+
+	bucket := mux.Vars(r)["bucket"]
+	if bucket != "" {
+		if _, err := objectAPI.GetBucketInfo(ctx, bucket); err != nil {
+			writeErrorResponseJSON(ctx, w, toAPIError(ctx, err), r.URL)
+			return
+		}
+	}
+
+	metrics := globalNotificationSys.GetReplicationMetrics(ctx, bucket)
+	now := UTCNow()
+	for _, bm := range metrics.Buckets {
+		for arn, tm := range bm.Targets {
+			if !tm.OldestPending.IsZero() {
+				tm.OldestPendingAge = now.Sub(tm.OldestPending)
+			}
+			bm.Targets[arn] = tm
+		}
+	}
+
+	data, err := json.Marshal(metrics)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	// Write success response.
+	writeSuccessResponseJSON(w, data)
+}
+
+// StartReplicationResyncHandler - PUT /minio/admin/v3/replication-resync?bucket=mybucket&arn=arn&reset=false&resume=false
+// ----------
+// Starts replicating the existing objects of the bucket which have not
+// been replicated to its targets, or to the target named by arn, yet.
+// With reset the objects are replicated to the target even if they were
+// replicated before, with resume the previous canceled or failed resync
+// continues where it stopped.
+func (a adminAPIHandlers) StartReplicationResyncHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "StartReplicationResync")
+
+	defer logger.AuditLog(w, r, "StartReplicationResync", mustGetClaimsFromToken(r))
+
+	objectAPI, _ := validateAdminUsersReq(ctx, w, r, iampolicy.ReplicationResyncAdminAction)
+	if objectAPI == nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL)
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	if _, err := objectAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponseJSON(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	opts := madmin.ReplicationResyncOptions{
+		Arn:    vars["arn"],
+		Reset:  vars["reset"] == "true",
+		Resume: vars["resume"] == "true",
+	}
+	info, err := startReplicationResync(GlobalContext, objectAPI, bucket, opts)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	data, err := json.Marshal(info)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	// Write success response.
+	writeSuccessResponseJSON(w, data)
+}
+
+// GetReplicationResyncHandler - GET /minio/admin/v3/replication-resync?bucket=mybucket
+// ----------
+// Returns the progress of the last replication resync of the bucket.
+func (a adminAPIHandlers) GetReplicationResyncHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "GetReplicationResync")
+
+	defer logger.AuditLog(w, r, "GetReplicationResync", mustGetClaimsFromToken(r))
+
+	objectAPI, _ := validateAdminUsersReq(ctx, w, r, iampolicy.ReplicationInfoAdminAction)
+	if objectAPI == nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL)
+		return
+	}
+
+	bucket := mux.Vars(r)["bucket"]
+	if _, err := objectAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponseJSON(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	info, err := loadReplicationResync(ctx, objectAPI, bucket)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	data, err := json.Marshal(info)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	// Write success response.
+	writeSuccessResponseJSON(w, data)
+}
+
+// CancelReplicationResyncHandler - DELETE /minio/admin/v3/replication-resync?bucket=mybucket
+// ----------
+// Cancels the running replication resync of the bucket, it can be
+// resumed later on.
+func (a adminAPIHandlers) CancelReplicationResyncHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "CancelReplicationResync")
+
+	defer logger.AuditLog(w, r, "CancelReplicationResync", mustGetClaimsFromToken(r))
+
+	objectAPI, _ := validateAdminUsersReq(ctx, w, r, iampolicy.ReplicationResyncAdminAction)
+	if objectAPI == nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL)
+		return
+	}
+
+	bucket := mux.Vars(r)["bucket"]
+	if _, err := objectAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponseJSON(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	info, err := cancelReplicationResync(ctx, objectAPI, bucket)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	data, err := json.Marshal(info)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	// Write success response.
+	writeSuccessResponseJSON(w, data)
+}
+
+// PreviewLifecycleHandler - POST /minio/admin/v3/lifecycle-preview?bucket=mybucket&prefix=prefix&max-samples=10&max-objects=0
+// ----------
+// Evaluates the lifecycle configuration in the request body against the
+// objects of the bucket under prefix without applying it. Returns per rule
+// and action the number and size of the objects affected at the moment,
+// along with a few object names.
+func (a adminAPIHandlers) PreviewLifecycleHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "PreviewLifecycle")
+
+	defer logger.AuditLog(w, r, "PreviewLifecycle", mustGetClaimsFromToken(r))
+
+	objectAPI, _ := validateAdminUsersReq(ctx, w, r, iampolicy.LifecycleInfoAdminAction)
+	if objectAPI == nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL)
+		return
+	}
+
+	bucket := mux.Vars(r)["bucket"]
+	if _, err := objectAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponseJSON(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	opts := madmin.PreviewLifecycleOptions{
+		Prefix: r.URL.Query().Get("prefix"),
+	}
+	for param, value := range map[string]*int{
+		"max-samples": &opts.MaxSamples,
+		"max-objects": &opts.MaxObjects,
+	} {
+		if s := r.URL.Query().Get(param); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 0 {
+				writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminInvalidArgument), r.URL)
+				return
+			}
+			*value = n
+		}
+	}
+
+	lc, err := lifecycle.ParseLifecycleConfig(io.LimitReader(r.Body, r.ContentLength))
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+	if err = lc.Validate(); err != nil {
+		writeErrorResponseJSON(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	preview, err := previewLifecycle(ctx, objectAPI, bucket, lc, opts)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	data, err := json.Marshal(preview)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	// Write success response.
+	writeSuccessResponseJSON(w, data)
+}
+
+// LifecycleActionLogHandler - GET /minio/admin/v3/lifecycle-log?bucket=mybucket&days=7
+// ----------
+// Returns the lifecycle actions the crawlers of all nodes applied to the
+// objects of the bucket over the last days, per day and the latest ones
+// individually. The actions are kept in memory for up to 30 days.
+func (a adminAPIHandlers) LifecycleActionLogHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "LifecycleActionLog")
+
+	defer logger.AuditLog(w, r, "LifecycleActionLog", mustGetClaimsFromToken(r))
+
+	objectAPI, _ := validateAdminUsersReq(ctx, w, r, iampolicy.LifecycleInfoAdminAction)
+	if objectAPI == nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL)
+		return
+	}
+
+	bucket := mux.Vars(r)["bucket"]
+	if _, err := objectAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponseJSON(ctx, w, toAPIError(ctx, err), r.URL)
+		return
+	}
+
+	days := 7 // by default list the actions of the last week
+	if s := r.URL.Query().Get("days"); s != "" {
+		var err error
+		days, err = strconv.Atoi(s)
+		if err != nil || days < 0 {
+			writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminInvalidArgument), r.URL)
+			return
+		}
+	}
+	if days == 0 || days > lifecycleActionLogDays {
+		days = lifecycleActionLogDays
+	}
+
+	// Include today.
+	since := UTCNow().Truncate(24*time.Hour).AddDate(0, 0, 1-days)
+	log := globalNotificationSys.GetLifecycleActionLog(ctx, bucket, since)
+
+	data, err := json.Marshal(log)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	// Write success response.
+	writeSuccessResponseJSON(w, data)
+}
@@ -24,6 +24,7 @@ import (
 	"net/http"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/minio/minio/cmd/logger"
@@ -171,6 +172,7 @@ func (a adminAPIHandlers) GetUserInfo(w http.ResponseWriter, r *http.Request) {
 		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
 		return
 	}
+	userInfo.LastUsed = globalIAMSys.AccessKeyLastUsed(ctx, name)
 
 	data, err := json.Marshal(userInfo)
 	if err != nil {
@@ -281,6 +283,96 @@ func (a adminAPIHandlers) ListGroups(w http.ResponseWriter, r *http.Request) {
 	writeSuccessResponseJSON(w, body)
 }
 
+// ListSTSSessions - GET /minio/admin/v3/list-sts-sessions?parent=<parent>
+func (a adminAPIHandlers) ListSTSSessions(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "ListSTSSessions")
+
+	defer logger.AuditLog(w, r, "ListSTSSessions", mustGetClaimsFromToken(r))
+
+	objectAPI, _ := validateAdminUsersReq(ctx, w, r, iampolicy.ListSTSSessionsAdminAction)
+	if objectAPI == nil {
+		return
+	}
+
+	sessions, err := globalIAMSys.ListSTSSessions(r.URL.Query().Get("parent"))
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	body, err := json.Marshal(sessions)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, body)
+}
+
+// RevokeSTSSessions - POST /minio/admin/v3/revoke-sts-sessions?accessKey=<accessKey>
+// or POST /minio/admin/v3/revoke-sts-sessions?parent=<parent>
+func (a adminAPIHandlers) RevokeSTSSessions(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "RevokeSTSSessions")
+
+	defer logger.AuditLog(w, r, "RevokeSTSSessions", mustGetClaimsFromToken(r))
+
+	objectAPI, _ := validateAdminUsersReq(ctx, w, r, iampolicy.RevokeSTSSessionsAdminAction)
+	if objectAPI == nil {
+		return
+	}
+
+	accessKey := r.URL.Query().Get("accessKey")
+	parent := r.URL.Query().Get("parent")
+	if (accessKey == "") == (parent == "") {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrInvalidRequest), r.URL)
+		return
+	}
+
+	revoked, err := globalIAMSys.RevokeSTSSessions(accessKey, parent)
+	// Notify all other MinIO peers to reload temp users, revoked
+	// users are removed by the reload.
+	for _, accessKey := range revoked {
+		for _, nerr := range globalNotificationSys.LoadUser(accessKey, true) {
+			if nerr.Err != nil {
+				logger.GetReqInfo(ctx).SetTags("peerAddress", nerr.Host.String())
+				logger.LogIf(ctx, nerr.Err)
+			}
+		}
+	}
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	body, err := json.Marshal(revoked)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, body)
+}
+
+// LDAPGroupSyncStatus - GET /minio/admin/v3/ldap-group-sync-status
+func (a adminAPIHandlers) LDAPGroupSyncStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "LDAPGroupSyncStatus")
+
+	defer logger.AuditLog(w, r, "LDAPGroupSyncStatus", mustGetClaimsFromToken(r))
+
+	objectAPI, _ := validateAdminUsersReq(ctx, w, r, iampolicy.ListGroupsAdminAction)
+	if objectAPI == nil {
+		return
+	}
+
+	body, err := json.Marshal(globalLDAPGroupSync.status(ctx, globalIAMSys.store))
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, body)
+}
+
 // SetGroupStatus - PUT /minio/admin/v3/set-group-status?group=mygroup1&status=enabled
 func (a adminAPIHandlers) SetGroupStatus(w http.ResponseWriter, r *http.Request) {
 	ctx := newContext(r, w, "SetGroupStatus")
@@ -484,7 +576,12 @@ func (a adminAPIHandlers) AddServiceAccount(w http.ResponseWriter, r *http.Reque
 		parentUser = cred.ParentUser
 	}
 
-	newCred, err := globalIAMSys.NewServiceAccount(ctx, parentUser, createReq.Policy)
+	var expiration time.Time
+	if createReq.Expiration != nil {
+		expiration = createReq.Expiration.UTC()
+	}
+
+	newCred, err := globalIAMSys.NewServiceAccount(ctx, parentUser, createReq.Policy, expiration)
 	if err != nil {
 		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
 		return
@@ -556,8 +653,15 @@ func (a adminAPIHandlers) ListServiceAccounts(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	for i := range serviceAccounts {
+		serviceAccounts[i].LastUsed = globalIAMSys.AccessKeyLastUsed(ctx, serviceAccounts[i].AccessKey)
+	}
+
 	var listResp = madmin.ListServiceAccountsResp{
-		Accounts: serviceAccounts,
+		AccountsInfo: serviceAccounts,
+	}
+	for _, sa := range serviceAccounts {
+		listResp.Accounts = append(listResp.Accounts, sa.AccessKey)
 	}
 
 	data, err := json.Marshal(listResp)
@@ -634,6 +738,214 @@ func (a adminAPIHandlers) DeleteServiceAccount(w http.ResponseWriter, r *http.Re
 	writeSuccessNoContent(w)
 }
 
+// validateAccessKeyReq - authenticates and authorizes admin requests
+// managing the access key of a user or service account. Users manage
+// their own access key and the access keys of their service accounts,
+// any other access key requires the admin:CreateUser action.
+func validateAccessKeyReq(ctx context.Context, w http.ResponseWriter, r *http.Request, accessKey string) (auth.Credentials, bool) {
+	// Get current object layer instance.
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil || globalNotificationSys == nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL)
+		return auth.Credentials{}, false
+	}
+
+	cred, claims, owner, s3Err := validateAdminSignature(ctx, r, "")
+	if s3Err != ErrNone {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(s3Err), r.URL)
+		return cred, false
+	}
+
+	// The root credential is not managed by the IAM sub-system.
+	if accessKey == "" || accessKey == globalActiveCred.AccessKey {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminInvalidArgument), r.URL)
+		return cred, false
+	}
+
+	if (cred.IsTemp() || cred.IsServiceAccount()) && cred.ParentUser == accessKey {
+		// Temporary credentials and service accounts are not
+		// allowed to manage the access key of their parent user.
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAccessDenied), r.URL)
+		return cred, false
+	}
+
+	parentUser := cred.AccessKey
+	if cred.ParentUser != "" {
+		parentUser = cred.ParentUser
+	}
+
+	implicitPerm := accessKey == parentUser
+	if !implicitPerm {
+		isSvc, svcParent, err := globalIAMSys.IsServiceAccount(accessKey)
+		implicitPerm = err == nil && isSvc && svcParent == parentUser
+	}
+	if !implicitPerm {
+		if !globalIAMSys.IsAllowed(iampolicy.Args{
+			AccountName:     parentUser,
+			Action:          iampolicy.CreateUserAdminAction,
+			ConditionValues: getConditionValues(r, "", parentUser, claims),
+			IsOwner:         owner,
+			Claims:          claims,
+		}) {
+			writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAccessDenied), r.URL)
+			return cred, false
+		}
+	}
+	return cred, true
+}
+
+// notifyAccessKeyUpdate - notifies all other MinIO peers to reload the
+// user or service account of the given access key.
+func notifyAccessKeyUpdate(ctx context.Context, accessKey string) {
+	var nerrs []NotificationPeerErr
+	if isSvc, _, _ := globalIAMSys.IsServiceAccount(accessKey); isSvc {
+		nerrs = globalNotificationSys.LoadServiceAccount(accessKey)
+	} else {
+		nerrs = globalNotificationSys.LoadUser(accessKey, false)
+	}
+	for _, nerr := range nerrs {
+		if nerr.Err != nil {
+			logger.GetReqInfo(ctx).SetTags("peerAddress", nerr.Host.String())
+			logger.LogIf(ctx, nerr.Err)
+		}
+	}
+}
+
+// RotateSecretKey - PUT /minio/admin/v3/rotate-secret-key?accessKey=<access_key>
+func (a adminAPIHandlers) RotateSecretKey(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "RotateSecretKey")
+
+	defer logger.AuditLog(w, r, "RotateSecretKey", mustGetClaimsFromToken(r))
+
+	accessKey := mux.Vars(r)["accessKey"]
+	cred, ok := validateAccessKeyReq(ctx, w, r, accessKey)
+	if !ok {
+		return
+	}
+
+	if r.ContentLength > maxEConfigJSONSize || r.ContentLength == -1 {
+		// More than maxConfigSize bytes were available
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigTooLarge), r.URL)
+		return
+	}
+
+	password := cred.SecretKey
+	reqBytes, err := madmin.DecryptData(password, io.LimitReader(r.Body, r.ContentLength))
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErrWithErr(ErrAdminConfigBadJSON, err), r.URL)
+		return
+	}
+
+	var rotateReq madmin.RotateSecretKeyReq
+	if err = json.Unmarshal(reqBytes, &rotateReq); err != nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErrWithErr(ErrAdminConfigBadJSON, err), r.URL)
+		return
+	}
+
+	if rotateReq.SecretKey == "" {
+		newCred, err := auth.GetNewCredentials()
+		if err != nil {
+			writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+			return
+		}
+		rotateReq.SecretKey = newCred.SecretKey
+	}
+
+	rotatedCred, err := globalIAMSys.RotateSecretKey(accessKey, rotateReq.SecretKey, rotateReq.Overlap)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	notifyAccessKeyUpdate(ctx, accessKey)
+
+	_, previousSecretExpiration := keyExpirations(rotatedCred)
+	data, err := json.Marshal(madmin.RotateSecretKeyResp{
+		SecretKey:                rotatedCred.SecretKey,
+		PreviousSecretExpiration: previousSecretExpiration,
+	})
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	encryptedData, err := madmin.EncryptData(password, data)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, encryptedData)
+}
+
+// SetKeyExpiration - PUT /minio/admin/v3/set-key-expiration?accessKey=<access_key>&expiration=<RFC3339 time>
+func (a adminAPIHandlers) SetKeyExpiration(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "SetKeyExpiration")
+
+	defer logger.AuditLog(w, r, "SetKeyExpiration", mustGetClaimsFromToken(r))
+
+	accessKey := mux.Vars(r)["accessKey"]
+	if _, ok := validateAccessKeyReq(ctx, w, r, accessKey); !ok {
+		return
+	}
+
+	// An empty expiration removes the expiration of the access key.
+	var expiration time.Time
+	if value := r.URL.Query().Get("expiration"); value != "" {
+		var err error
+		if expiration, err = time.Parse(time.RFC3339, value); err != nil {
+			writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErrWithErr(ErrAdminInvalidArgument, err), r.URL)
+			return
+		}
+	}
+
+	if err := globalIAMSys.SetKeyExpiration(accessKey, expiration); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	notifyAccessKeyUpdate(ctx, accessKey)
+}
+
+// SetUserTags - PUT /minio/admin/v3/set-user-tags?accessKey=<access_key>
+func (a adminAPIHandlers) SetUserTags(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "SetUserTags")
+
+	defer logger.AuditLog(w, r, "SetUserTags", mustGetClaimsFromToken(r))
+
+	objectAPI, _ := validateAdminUsersReq(ctx, w, r, iampolicy.CreateUserAdminAction)
+	if objectAPI == nil {
+		return
+	}
+
+	accessKey := mux.Vars(r)["accessKey"]
+
+	if r.ContentLength > maxEConfigJSONSize || r.ContentLength == -1 {
+		// More than maxConfigSize bytes were available
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigTooLarge), r.URL)
+		return
+	}
+
+	var tagMap map[string]string
+	if err := json.NewDecoder(io.LimitReader(r.Body, r.ContentLength)).Decode(&tagMap); err != nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigBadJSON), r.URL)
+		return
+	}
+
+	if err := globalIAMSys.SetUserTags(accessKey, tagMap); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	// Notify all other MinIO peers to reload user.
+	for _, nerr := range globalNotificationSys.LoadUser(accessKey, false) {
+		if nerr.Err != nil {
+			logger.GetReqInfo(ctx).SetTags("peerAddress", nerr.Host.String())
+			logger.LogIf(ctx, nerr.Err)
+		}
+	}
+}
+
 // AccountInfoHandler returns usage
 func (a adminAPIHandlers) AccountInfoHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := newContext(r, w, "AccountInfo")
@@ -701,6 +1013,7 @@ func (a adminAPIHandlers) AccountInfoHandler(w http.ResponseWriter, r *http.Requ
 		// log the error, continue with the accounting response
 		logger.LogIf(ctx, err)
 	}
+	dataUsageInfo = globalRealtimeUsage.dataUsageInfo(dataUsageInfo)
 
 	accountName := cred.AccessKey
 	if cred.ParentUser != "" {
@@ -1010,3 +1323,212 @@ func (a adminAPIHandlers) SetPolicyForUserOrGroup(w http.ResponseWriter, r *http
 		}
 	}
 }
+
+// SetPermissionBoundary - PUT /minio/admin/v3/set-permission-boundary?policyName=<policy>&userOrGroup=<name>&isGroup=<true/false>
+func (a adminAPIHandlers) SetPermissionBoundary(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "SetPermissionBoundary")
+
+	defer logger.AuditLog(w, r, "SetPermissionBoundary", mustGetClaimsFromToken(r))
+
+	objectAPI, _ := validateAdminUsersReq(ctx, w, r, iampolicy.AttachPolicyAdminAction)
+	if objectAPI == nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	policyName := vars["policyName"]
+	entityName := vars["userOrGroup"]
+	isGroup := vars["isGroup"] == "true"
+
+	if err := globalIAMSys.SetPermissionBoundary(entityName, isGroup, policyName); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	// Notify all other MinIO peers to reload user or group
+	var nerrs []NotificationPeerErr
+	if isGroup {
+		nerrs = globalNotificationSys.LoadGroup(entityName)
+	} else {
+		nerrs = globalNotificationSys.LoadUser(entityName, false)
+	}
+	for _, nerr := range nerrs {
+		if nerr.Err != nil {
+			logger.GetReqInfo(ctx).SetTags("peerAddress", nerr.Host.String())
+			logger.LogIf(ctx, nerr.Err)
+		}
+	}
+}
+
+// SimulatePolicy - POST /minio/admin/v3/simulate-policy
+func (a adminAPIHandlers) SimulatePolicy(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "SimulatePolicy")
+
+	defer logger.AuditLog(w, r, "SimulatePolicy", mustGetClaimsFromToken(r))
+
+	objectAPI, _ := validateAdminUsersReq(ctx, w, r, iampolicy.SimulatePolicyAdminAction)
+	if objectAPI == nil {
+		return
+	}
+
+	if r.ContentLength > maxEConfigJSONSize || r.ContentLength == -1 {
+		// More than maxConfigSize bytes were available
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigTooLarge), r.URL)
+		return
+	}
+
+	var sargs madmin.PolicySimulationArgs
+	if err := json.NewDecoder(io.LimitReader(r.Body, r.ContentLength)).Decode(&sargs); err != nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigBadJSON), r.URL)
+		return
+	}
+
+	if !iampolicy.Action(sargs.Action).IsValid() && !iampolicy.AdminAction(sargs.Action).IsValid() {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminInvalidArgument), r.URL)
+		return
+	}
+
+	result, err := simulatePolicy(r, sargs)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, data)
+}
+
+// ExportIAM - GET /minio/admin/v3/export-iam
+func (a adminAPIHandlers) ExportIAM(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "ExportIAM")
+
+	defer logger.AuditLog(w, r, "ExportIAM", mustGetClaimsFromToken(r))
+
+	objectAPI, cred := validateAdminUsersReq(ctx, w, r, iampolicy.ExportIAMAdminAction)
+	if objectAPI == nil {
+		return
+	}
+
+	archive, err := globalIAMSys.ExportIAM()
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	data, err := json.Marshal(archive)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	econfigData, err := madmin.EncryptData(cred.SecretKey, data)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, econfigData)
+}
+
+// ImportIAM - PUT /minio/admin/v3/import-iam?mode=merge|replace&dryRun=true|false
+func (a adminAPIHandlers) ImportIAM(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "ImportIAM")
+
+	defer logger.AuditLog(w, r, "ImportIAM", mustGetClaimsFromToken(r))
+
+	objectAPI, cred := validateAdminUsersReq(ctx, w, r, iampolicy.ImportIAMAdminAction)
+	if objectAPI == nil {
+		return
+	}
+
+	vars := mux.Vars(r)
+	replace := vars["mode"] == "replace"
+	dryRun := vars["dryRun"] == "true"
+
+	if r.ContentLength > maxIAMArchiveSize || r.ContentLength == -1 {
+		// More than maxIAMArchiveSize bytes were available
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigTooLarge), r.URL)
+		return
+	}
+
+	data, err := madmin.DecryptData(cred.SecretKey, io.LimitReader(r.Body, r.ContentLength))
+	if err != nil {
+		logger.LogIf(ctx, err)
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigBadJSON), r.URL)
+		return
+	}
+
+	var archive iamArchive
+	if err = json.Unmarshal(data, &archive); err != nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigBadJSON), r.URL)
+		return
+	}
+
+	result, err := globalIAMSys.ImportIAM(ctx, archive, replace, dryRun)
+	if !dryRun {
+		// Notify all other MinIO peers to reload the changed entities,
+		// also those changed before an error.
+		notifyIAMImport(ctx, result)
+	}
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseJSON(w, body)
+}
+
+// notifyIAMImport - notifies all other MinIO peers of the entities
+// changed by an IAM import.
+func notifyIAMImport(ctx context.Context, result madmin.IAMImportResult) {
+	var errs []NotificationPeerErr
+	for _, entity := range append(result.Added, result.Updated...) {
+		switch entity.Type {
+		case madmin.IAMPolicyEntity:
+			errs = append(errs, globalNotificationSys.LoadPolicy(entity.Name)...)
+		case madmin.IAMUserEntity:
+			errs = append(errs, globalNotificationSys.LoadUser(entity.Name, false)...)
+		case madmin.IAMServiceAccountEntity:
+			errs = append(errs, globalNotificationSys.LoadServiceAccount(entity.Name)...)
+		case madmin.IAMGroupEntity:
+			errs = append(errs, globalNotificationSys.LoadGroup(entity.Name)...)
+		case madmin.IAMUserPolicyEntity:
+			errs = append(errs, globalNotificationSys.LoadPolicyMapping(entity.Name, false)...)
+		case madmin.IAMGroupPolicyEntity:
+			errs = append(errs, globalNotificationSys.LoadPolicyMapping(entity.Name, true)...)
+		}
+	}
+	for _, entity := range result.Removed {
+		switch entity.Type {
+		case madmin.IAMPolicyEntity:
+			errs = append(errs, globalNotificationSys.DeletePolicy(entity.Name)...)
+		case madmin.IAMUserEntity:
+			errs = append(errs, globalNotificationSys.DeleteUser(entity.Name)...)
+		case madmin.IAMServiceAccountEntity:
+			errs = append(errs, globalNotificationSys.DeleteServiceAccount(entity.Name)...)
+		case madmin.IAMGroupEntity:
+			errs = append(errs, globalNotificationSys.LoadGroup(entity.Name)...)
+		case madmin.IAMUserPolicyEntity:
+			errs = append(errs, globalNotificationSys.LoadPolicyMapping(entity.Name, false)...)
+		case madmin.IAMGroupPolicyEntity:
+			errs = append(errs, globalNotificationSys.LoadPolicyMapping(entity.Name, true)...)
+		}
+	}
+	for _, nerr := range errs {
+		if nerr.Err != nil {
+			logger.GetReqInfo(ctx).SetTags("peerAddress", nerr.Host.String())
+			logger.LogIf(ctx, nerr.Err)
+		}
+	}
+}
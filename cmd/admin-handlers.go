@@ -51,6 +51,7 @@ import (
 
 const (
 	maxEConfigJSONSize = 262272
+	maxIAMArchiveSize  = 64 << 20
 )
 
 // Only valid query params for mgmt admin APIs.
@@ -339,6 +340,7 @@ func (a adminAPIHandlers) DataUsageInfoHandler(w http.ResponseWriter, r *http.Re
 		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
 		return
 	}
+	dataUsageInfo = globalRealtimeUsage.dataUsageInfo(dataUsageInfo)
 
 	dataUsageInfoJSON, err := json.Marshal(dataUsageInfo)
 	if err != nil {
@@ -991,6 +993,18 @@ func toAdminAPIErr(ctx context.Context, err error) APIError {
 				Description:    err.Error(),
 				HTTPStatusCode: http.StatusForbidden,
 			}
+		case errors.Is(err, errSessionPolicyExceedsBoundary):
+			apiErr = APIError{
+				Code:           "XMinioIAMSessionPolicyExceedsBoundary",
+				Description:    err.Error(),
+				HTTPStatusCode: http.StatusForbidden,
+			}
+		case errors.Is(err, errInvalidIAMArchive):
+			apiErr = APIError{
+				Code:           "XMinioInvalidIAMArchive",
+				Description:    err.Error(),
+				HTTPStatusCode: http.StatusBadRequest,
+			}
 		case errors.Is(err, errIAMNotInitialized):
 			apiErr = APIError{
 				Code:           "XMinioIAMNotInitialized",
@@ -1542,6 +1556,7 @@ func (a adminAPIHandlers) ServerInfoHandler(w http.ResponseWriter, r *http.Reque
 		// Load data usage
 		dataUsageInfo, err := loadDataUsageFromBackend(ctx, objectAPI)
 		if err == nil {
+			dataUsageInfo = globalRealtimeUsage.dataUsageInfo(dataUsageInfo)
 			buckets = madmin.Buckets{Count: dataUsageInfo.BucketsCount}
 			objects = madmin.Objects{Count: dataUsageInfo.ObjectsTotalCount}
 			usage = madmin.Usage{Size: dataUsageInfo.ObjectsTotalSize}
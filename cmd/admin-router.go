@@ -121,12 +121,17 @@ func registerAdminRouter(router *mux.Router, enableConfigOps, enableIAMOps bool)
 			adminRouter.Methods(http.MethodPut).Path(adminVersion+"/add-user").HandlerFunc(httpTraceHdrs(adminAPI.AddUser)).Queries("accessKey", "{accessKey:.*}")
 
 			adminRouter.Methods(http.MethodPut).Path(adminVersion+"/set-user-status").HandlerFunc(httpTraceHdrs(adminAPI.SetUserStatus)).Queries("accessKey", "{accessKey:.*}").Queries("status", "{status:.*}")
+			adminRouter.Methods(http.MethodPut).Path(adminVersion+"/set-user-tags").HandlerFunc(httpTraceHdrs(adminAPI.SetUserTags)).Queries("accessKey", "{accessKey:.*}")
 
 			// Service accounts ops
 			adminRouter.Methods(http.MethodPut).Path(adminVersion + "/add-service-account").HandlerFunc(httpTraceHdrs(adminAPI.AddServiceAccount))
 			adminRouter.Methods(http.MethodGet).Path(adminVersion + "/list-service-accounts").HandlerFunc(httpTraceHdrs(adminAPI.ListServiceAccounts))
 			adminRouter.Methods(http.MethodDelete).Path(adminVersion+"/delete-service-account").HandlerFunc(httpTraceHdrs(adminAPI.DeleteServiceAccount)).Queries("accessKey", "{accessKey:.*}")
 
+			// Access key rotation and expiration of users and service accounts
+			adminRouter.Methods(http.MethodPut).Path(adminVersion+"/rotate-secret-key").HandlerFunc(httpTraceHdrs(adminAPI.RotateSecretKey)).Queries("accessKey", "{accessKey:.*}")
+			adminRouter.Methods(http.MethodPut).Path(adminVersion+"/set-key-expiration").HandlerFunc(httpTraceHdrs(adminAPI.SetKeyExpiration)).Queries("accessKey", "{accessKey:.*}")
+
 			if adminVersion == adminAPIVersionV2Prefix {
 				// Info policy IAM v2
 				adminRouter.Methods(http.MethodGet).Path(adminVersion+"/info-canned-policy").HandlerFunc(httpTraceHdrs(adminAPI.InfoCannedPolicyV2)).Queries("name", "{name:.*}")
@@ -149,6 +154,14 @@ func registerAdminRouter(router *mux.Router, enableConfigOps, enableIAMOps bool)
 				HandlerFunc(httpTraceHdrs(adminAPI.SetPolicyForUserOrGroup)).
 				Queries("policyName", "{policyName:.*}", "userOrGroup", "{userOrGroup:.*}", "isGroup", "{isGroup:true|false}")
 
+			// Set user or group permission boundary
+			adminRouter.Methods(http.MethodPut).Path(adminVersion+"/set-permission-boundary").
+				HandlerFunc(httpTraceHdrs(adminAPI.SetPermissionBoundary)).
+				Queries("policyName", "{policyName:.*}", "userOrGroup", "{userOrGroup:.*}", "isGroup", "{isGroup:true|false}")
+
+			// Simulate the policies applying to a request
+			adminRouter.Methods(http.MethodPost).Path(adminVersion + "/simulate-policy").HandlerFunc(httpTraceHdrs(adminAPI.SimulatePolicy))
+
 			// Remove user IAM
 			adminRouter.Methods(http.MethodDelete).Path(adminVersion+"/remove-user").HandlerFunc(httpTraceHdrs(adminAPI.RemoveUser)).Queries("accessKey", "{accessKey:.*}")
 
@@ -169,6 +182,23 @@ func registerAdminRouter(router *mux.Router, enableConfigOps, enableIAMOps bool)
 
 			// Set Group Status
 			adminRouter.Methods(http.MethodPut).Path(adminVersion+"/set-group-status").HandlerFunc(httpTraceHdrs(adminAPI.SetGroupStatus)).Queries("group", "{group:.*}").Queries("status", "{status:.*}")
+
+			// List and revoke STS sessions
+			adminRouter.Methods(http.MethodGet).Path(adminVersion + "/list-sts-sessions").HandlerFunc(httpTraceHdrs(adminAPI.ListSTSSessions))
+			adminRouter.Methods(http.MethodPost).Path(adminVersion + "/revoke-sts-sessions").HandlerFunc(httpTraceHdrs(adminAPI.RevokeSTSSessions))
+
+			// Export and import the IAM state
+			adminRouter.Methods(http.MethodGet).Path(adminVersion + "/export-iam").HandlerFunc(httpTraceHdrs(adminAPI.ExportIAM))
+			adminRouter.Methods(http.MethodPut).Path(adminVersion+"/import-iam").HandlerFunc(
+				httpTraceHdrs(adminAPI.ImportIAM)).Queries("mode", "{mode:merge|replace}", "dryRun", "{dryRun:true|false}")
+
+			// LDAP group sync status
+			adminRouter.Methods(http.MethodGet).Path(adminVersion + "/ldap-group-sync-status").HandlerFunc(httpTraceHdrs(adminAPI.LDAPGroupSyncStatus))
+
+			// Rate limits of users, groups and buckets
+			adminRouter.Methods(http.MethodGet).Path(adminVersion + "/get-rate-limits").HandlerFunc(httpTraceHdrs(adminAPI.GetRateLimitsHandler))
+			adminRouter.Methods(http.MethodPut).Path(adminVersion+"/set-rate-limit").HandlerFunc(
+				httpTraceHdrs(adminAPI.PutRateLimitHandler)).Queries("type", "{type:user|group|bucket}", "name", "{name:.*}")
 		}
 
 		if globalIsDistErasure || globalIsErasure {
@@ -180,6 +210,12 @@ func registerAdminRouter(router *mux.Router, enableConfigOps, enableIAMOps bool)
 				// PutBucketQuotaConfig
 				adminRouter.Methods(http.MethodPut).Path(adminVersion+"/set-bucket-quota").HandlerFunc(
 					httpTraceHdrs(adminAPI.PutBucketQuotaConfigHandler)).Queries("bucket", "{bucket:.*}")
+				// GetUserQuota
+				adminRouter.Methods(http.MethodGet).Path(adminVersion+"/get-user-quota").HandlerFunc(
+					httpTraceHdrs(adminAPI.GetUserQuotaHandler)).Queries("userOrGroup", "{userOrGroup:.*}", "isGroup", "{isGroup:true|false}")
+				// PutUserQuota
+				adminRouter.Methods(http.MethodPut).Path(adminVersion+"/set-user-quota").HandlerFunc(
+					httpTraceHdrs(adminAPI.PutUserQuotaHandler)).Queries("userOrGroup", "{userOrGroup:.*}", "isGroup", "{isGroup:true|false}")
 
 				// Bucket replication operations
 				// GetBucketTargetHandler
@@ -191,8 +227,34 @@ func registerAdminRouter(router *mux.Router, enableConfigOps, enableIAMOps bool)
 				// RemoveRemoteTargetHandler
 				adminRouter.Methods(http.MethodDelete).Path(adminVersion+"/remove-remote-target").HandlerFunc(
 					httpTraceHdrs(adminAPI.RemoveRemoteTargetHandler)).Queries("bucket", "{bucket:.*}", "arn", "{arn:.*}")
+				// ReplicationQueueInfoHandler
+				adminRouter.Methods(http.MethodGet).Path(adminVersion+"/replication-queue").HandlerFunc(
+					httpTraceHdrs(adminAPI.ReplicationQueueInfoHandler)).Queries("bucket", "{bucket:.*}")
+				// ReplicationMetricsHandler
+				adminRouter.Methods(http.MethodGet).Path(adminVersion+"/replication-metrics").HandlerFunc(
+					httpTraceHdrs(adminAPI.ReplicationMetricsHandler)).Queries("bucket", "{bucket:.*}")
+				// StartReplicationResyncHandler
+				adminRouter.Methods(http.MethodPut).Path(adminVersion+"/replication-resync").HandlerFunc(
+					httpTraceHdrs(adminAPI.StartReplicationResyncHandler)).Queries("bucket", "{bucket:.*}",
+					"arn", "{arn:.*}", "reset", "{reset:true|false}", "resume", "{resume:true|false}")
+				// GetReplicationResyncHandler
+				adminRouter.Methods(http.MethodGet).Path(adminVersion+"/replication-resync").HandlerFunc(
+					httpTraceHdrs(adminAPI.GetReplicationResyncHandler)).Queries("bucket", "{bucket:.*}")
+				// CancelReplicationResyncHandler
+				adminRouter.Methods(http.MethodDelete).Path(adminVersion+"/replication-resync").HandlerFunc(
+					httpTraceHdrs(adminAPI.CancelReplicationResyncHandler)).Queries("bucket", "{bucket:.*}")
 			}
 		}
+
+		if !globalIsGateway {
+			// Bucket lifecycle operations
+			// PreviewLifecycleHandler
+			adminRouter.Methods(http.MethodPost).Path(adminVersion+"/lifecycle-preview").HandlerFunc(
+				httpTraceHdrs(adminAPI.PreviewLifecycleHandler)).Queries("bucket", "{bucket:.*}")
+			// LifecycleActionLogHandler
+			adminRouter.Methods(http.MethodGet).Path(adminVersion+"/lifecycle-log").HandlerFunc(
+				httpTraceHdrs(adminAPI.LifecycleActionLogHandler)).Queries("bucket", "{bucket:.*}")
+		}
 		// -- Top APIs --
 		// Top locks
 		if globalIsDistErasure {
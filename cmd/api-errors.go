@@ -33,6 +33,7 @@ import (
 	"github.com/minio/minio/cmd/crypto"
 	"github.com/minio/minio/cmd/logger"
 	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/bucket/inventory"
 	"github.com/minio/minio/pkg/bucket/lifecycle"
 	"github.com/minio/minio/pkg/bucket/replication"
 
@@ -115,6 +116,7 @@ const (
 	ErrBucketRemoteLabelInUse
 	ErrBucketRemoteArnTypeInvalid
 	ErrBucketRemoteArnInvalid
+	ErrBucketRemoteLocalPathInvalid
 	ErrBucketRemoteRemoveDisallowed
 	ErrRemoteTargetNotVersionedError
 	ErrReplicationSourceNotVersionedError
@@ -122,6 +124,8 @@ const (
 	ErrReplicationBucketNeedsVersioningError
 	ErrBucketReplicationDisabledError
 	ErrObjectRestoreAlreadyInProgress
+	ErrNoSuchInventoryConfiguration
+	ErrInvalidInventoryDestination
 	ErrNoSuchKey
 	ErrNoSuchUpload
 	ErrInvalidVersionID
@@ -264,6 +268,7 @@ const (
 	ErrObjectTampered
 	// Bucket Quota error codes
 	ErrAdminBucketQuotaExceeded
+	ErrAdminUserQuotaExceeded
 	ErrAdminNoSuchQuotaConfiguration
 	ErrAdminBucketQuotaDisabled
 
@@ -876,6 +881,11 @@ var errorCodes = errorCodeMap{
 		Description:    "The bucket remote ARN does not have correct format",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrBucketRemoteLocalPathInvalid: {
+		Code:           "XMinioAdminRemoteLocalPathInvalid",
+		Description:    "The local tier path cannot be, contain or be inside a drive of the server",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrRemoteTargetNotVersionedError: {
 		Code:           "RemoteTargetNotVersionedError",
 		Description:    "The remote target does not have versioning enabled",
@@ -896,6 +906,16 @@ var errorCodes = errorCodeMap{
 		Description:    "Versioning must be 'Enabled' on the bucket to add a replication target",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrNoSuchInventoryConfiguration: {
+		Code:           "NoSuchConfiguration",
+		Description:    "The specified inventory configuration does not exist",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	ErrInvalidInventoryDestination: {
+		Code:           "InvalidS3DestinationBucket",
+		Description:    "The inventory destination bucket does not exist",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrBucketReplicationDisabledError: {
 		Code:           "XMinioAdminBucketReplicationDisabled",
 		Description:    "Replication specified but disk usage crawl is disabled on MinIO server",
@@ -1217,6 +1237,11 @@ var errorCodes = errorCodeMap{
 		Description:    "Bucket quota exceeded",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrAdminUserQuotaExceeded: {
+		Code:           "XMinioAdminUserQuotaExceeded",
+		Description:    "User quota exceeded",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrAdminNoSuchQuotaConfiguration: {
 		Code:           "XMinioAdminNoSuchQuotaConfiguration",
 		Description:    "The quota configuration does not exist",
@@ -1957,6 +1982,8 @@ func toAPIErrorCode(ctx context.Context, err error) (apiErr APIErrorCode) {
 		apiErr = ErrAdminNoSuchQuotaConfiguration
 	case BucketReplicationConfigNotFound:
 		apiErr = ErrReplicationConfigurationNotFoundError
+	case BucketInventoryConfigNotFound:
+		apiErr = ErrNoSuchInventoryConfiguration
 	case BucketRemoteDestinationNotFound:
 		apiErr = ErrRemoteDestinationNotFoundError
 	case BucketReplicationDestinationMissingLock:
@@ -1973,6 +2000,8 @@ func toAPIErrorCode(ctx context.Context, err error) (apiErr APIErrorCode) {
 		apiErr = ErrBucketRemoteArnTypeInvalid
 	case BucketRemoteArnInvalid:
 		apiErr = ErrBucketRemoteArnInvalid
+	case BucketRemoteLocalPathInvalid:
+		apiErr = ErrBucketRemoteLocalPathInvalid
 	case BucketRemoteRemoveDisallowed:
 		apiErr = ErrBucketRemoteRemoveDisallowed
 	case BucketRemoteTargetNotVersioned:
@@ -1981,6 +2010,8 @@ func toAPIErrorCode(ctx context.Context, err error) (apiErr APIErrorCode) {
 		apiErr = ErrReplicationSourceNotVersionedError
 	case BucketQuotaExceeded:
 		apiErr = ErrAdminBucketQuotaExceeded
+	case UserQuotaExceeded:
+		apiErr = ErrAdminUserQuotaExceeded
 	case *event.ErrInvalidEventName:
 		apiErr = ErrEventNotification
 	case *event.ErrInvalidARN:
@@ -2097,6 +2128,12 @@ func toAPIError(ctx context.Context, err error) APIError {
 				Description:    e.Error(),
 				HTTPStatusCode: http.StatusBadRequest,
 			}
+		case inventory.Error:
+			apiErr = APIError{
+				Code:           "MalformedXML",
+				Description:    e.Error(),
+				HTTPStatusCode: http.StatusBadRequest,
+			}
 		case tags.Error:
 			apiErr = APIError{
 				Code:           e.Code(),
@@ -186,6 +186,7 @@ func setObjectHeaders(w http.ResponseWriter, objInfo ObjectInfo, rs *HTTPRangeSp
 			UserTags:     objInfo.UserTags,
 			VersionID:    objInfo.VersionID,
 			ModTime:      objInfo.ModTime,
+			Size:         objInfo.Size,
 			IsLatest:     objInfo.IsLatest,
 			DeleteMarker: objInfo.DeleteMarker,
 		})
@@ -192,6 +192,12 @@ func registerAPIRouter(router *mux.Router) {
 		// GetBucketReplicationConfig
 		bucket.Methods(http.MethodGet).HandlerFunc(
 			maxClients(collectAPIStats("getbucketreplicationconfiguration", httpTraceAll(api.GetBucketReplicationConfigHandler)))).Queries("replication", "")
+		// GetBucketInventoryConfig
+		bucket.Methods(http.MethodGet).HandlerFunc(
+			maxClients(collectAPIStats("getbucketinventoryconfiguration", httpTraceAll(api.GetBucketInventoryConfigHandler)))).Queries("inventory", "", "id", "{id:.*}")
+		// ListBucketInventoryConfigs
+		bucket.Methods(http.MethodGet).HandlerFunc(
+			maxClients(collectAPIStats("listbucketinventoryconfigurations", httpTraceAll(api.ListBucketInventoryConfigsHandler)))).Queries("inventory", "")
 
 		// GetBucketVersioning
 		bucket.Methods(http.MethodGet).HandlerFunc(
@@ -258,6 +264,9 @@ func registerAPIRouter(router *mux.Router) {
 		// PutBucketReplicationConfig
 		bucket.Methods(http.MethodPut).HandlerFunc(
 			maxClients(collectAPIStats("putbucketreplicationconfiguration", httpTraceAll(api.PutBucketReplicationConfigHandler)))).Queries("replication", "")
+		// PutBucketInventoryConfig
+		bucket.Methods(http.MethodPut).HandlerFunc(
+			maxClients(collectAPIStats("putbucketinventoryconfiguration", httpTraceAll(api.PutBucketInventoryConfigHandler)))).Queries("inventory", "", "id", "{id:.*}")
 		// GetObjectRetention
 
 		// PutBucketEncryption
@@ -298,6 +307,9 @@ func registerAPIRouter(router *mux.Router) {
 		// DeleteBucketReplication
 		bucket.Methods(http.MethodDelete).HandlerFunc(
 			maxClients(collectAPIStats("deletebucketreplicationconfiguration", httpTraceAll(api.DeleteBucketReplicationConfigHandler)))).Queries("replication", "")
+		// DeleteBucketInventoryConfig
+		bucket.Methods(http.MethodDelete).HandlerFunc(
+			maxClients(collectAPIStats("deletebucketinventoryconfiguration", httpTraceAll(api.DeleteBucketInventoryConfigHandler)))).Queries("inventory", "", "id", "{id:.*}")
 		// DeleteBucketLifecycle
 		bucket.Methods(http.MethodDelete).HandlerFunc(
 			maxClients(collectAPIStats("deletebucketlifecycle", httpTraceAll(api.DeleteBucketLifecycleHandler)))).Queries("lifecycle", "")
@@ -30,12 +30,14 @@ import (
 	"strings"
 	"time"
 
+	"github.com/minio/minio-go/v7/pkg/tags"
 	xhttp "github.com/minio/minio/cmd/http"
 	xjwt "github.com/minio/minio/cmd/jwt"
 	"github.com/minio/minio/cmd/logger"
 	"github.com/minio/minio/pkg/auth"
 	objectlock "github.com/minio/minio/pkg/bucket/object/lock"
 	"github.com/minio/minio/pkg/bucket/policy"
+	"github.com/minio/minio/pkg/bucket/policy/condition"
 	"github.com/minio/minio/pkg/hash"
 	iampolicy "github.com/minio/minio/pkg/iam/policy"
 )
@@ -148,6 +150,7 @@ func validateAdminSignature(ctx context.Context, r *http.Request, region string)
 		return cred, nil, owner, s3Err
 	}
 
+	globalAccessKeyUsage.record(ctx, r, cred)
 	return cred, claims, owner, ErrNone
 }
 
@@ -159,17 +162,19 @@ func checkAdminRequestAuth(ctx context.Context, r *http.Request, action iampolic
 	if s3Err != ErrNone {
 		return cred, s3Err
 	}
-	if globalIAMSys.IsAllowed(iampolicy.Args{
+	args := iampolicy.Args{
 		AccountName:     cred.AccessKey,
 		Action:          iampolicy.Action(action),
 		ConditionValues: getConditionValues(r, "", cred.AccessKey, claims),
 		IsOwner:         owner,
 		Claims:          claims,
-	}) {
+	}
+	if globalIAMSys.IsAllowed(args) {
 		// Request is allowed return the appropriate access key.
 		return cred, ErrNone
 	}
 
+	traceDeniedRequest(ctx, args)
 	return cred, ErrAccessDenied
 }
 
@@ -275,6 +280,25 @@ func checkRequestAuthType(ctx context.Context, r *http.Request, action policy.Ac
 	return s3Err
 }
 
+// requestObjectTags returns the tags of the XML body of a PUT object
+// tagging request, nil if they are invalid. The body is read whatever
+// its content length, chunked requests have none, and populated again to
+// handle it in the HTTP handler.
+func requestObjectTags(r *http.Request) (map[string]string, error) {
+	payload, err := ioutil.ReadAll(io.LimitReader(r.Body, maxObjectTaggingSize))
+	if err != nil {
+		return nil, err
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(payload))
+
+	// Invalid tags are rejected by the HTTP handler.
+	t, err := tags.ParseObjectXML(bytes.NewReader(payload))
+	if err != nil {
+		return nil, nil
+	}
+	return t.ToMap(), nil
+}
+
 // Check request auth type verifies the incoming http request
 // - validates the request signature
 // - validates the policy action if anonymous tests bucket policies if any,
@@ -283,6 +307,13 @@ func checkRequestAuthType(ctx context.Context, r *http.Request, action policy.Ac
 // Additionally returns the accessKey used in the request, and if this request is by an admin.
 func checkRequestAuthTypeToAccessKey(ctx context.Context, r *http.Request, action policy.Action, bucketName, objectName string) (accessKey string, owner bool, s3Err APIErrorCode) {
 	var cred auth.Credentials
+	defer func() {
+		// Only allowed requests count as a use of the access key.
+		if s3Err == ErrNone {
+			globalAccessKeyUsage.record(ctx, r, cred)
+		}
+	}()
+
 	switch getRequestAuthType(r) {
 	case authTypeUnknown, authTypeStreamingSigned:
 		return accessKey, owner, ErrSignatureVersionNotSupported
@@ -334,20 +365,53 @@ func checkRequestAuthTypeToAccessKey(ctx context.Context, r *http.Request, actio
 		// Populate payload again to handle it in HTTP handler.
 		r.Body = ioutil.NopCloser(bytes.NewReader(payload))
 	}
+
+	// Tag condition values, only evaluated when policies apply.
+	tagValues := make(map[string][]string)
+	if !owner {
+		if action == policy.PutObjectTaggingAction {
+			t, err := requestObjectTags(r)
+			if err != nil {
+				logger.LogIf(ctx, err, logger.Application)
+				return accessKey, owner, ErrMalformedXML
+			}
+			addRequestTagConditionValues(tagValues, t)
+		}
+
+		// Looking up the tags of the object costs a metadata read,
+		// only do it when a policy has a condition on them.
+		if objectName != "" {
+			var hasKey bool
+			if cred.AccessKey == "" {
+				hasKey = globalPolicySys.HasConditionKey(bucketName, action, condition.S3ExistingObjectTag)
+			} else {
+				hasKey = globalIAMSys.HasConditionKey(claims, iampolicy.Action(action), condition.S3ExistingObjectTag)
+			}
+			if hasKey {
+				addTagConditionValues(tagValues, condition.S3ExistingObjectTag, existingObjectTags(ctx, r, bucketName, objectName))
+			}
+		}
+	}
+
+	if s3Err = globalRateLimitSys.enforce(ctx, cred, bucketName); s3Err != ErrNone {
+		return accessKey, owner, s3Err
+	}
+
 	if cred.AccessKey != "" {
 		logger.GetReqInfo(ctx).AccessKey = cred.AccessKey
 	}
 
 	if action != policy.ListAllMyBucketsAction && cred.AccessKey == "" {
 		// Anonymous checks are not meant for ListBuckets action
-		if globalPolicySys.IsAllowed(policy.Args{
+		args := policy.Args{
 			AccountName:     cred.AccessKey,
 			Action:          action,
 			BucketName:      bucketName,
-			ConditionValues: getConditionValues(r, locationConstraint, "", nil),
+			ConditionValues: withConditionValues(getConditionValues(r, locationConstraint, "", nil), tagValues),
 			IsOwner:         false,
 			ObjectName:      objectName,
-		}) {
+		}
+		if globalPolicySys.IsAllowed(args) {
 			// Request is allowed return the appropriate access key.
 			return cred.AccessKey, owner, ErrNone
 		}
@@ -368,18 +432,20 @@ func checkRequestAuthTypeToAccessKey(ctx context.Context, r *http.Request, actio
 			}
 		}
 
+		traceDeniedAnonymousRequest(ctx, args)
 		return cred.AccessKey, owner, ErrAccessDenied
 	}
 
-	if globalIAMSys.IsAllowed(iampolicy.Args{
+	args := iampolicy.Args{
 		AccountName:     cred.AccessKey,
 		Action:          iampolicy.Action(action),
 		BucketName:      bucketName,
-		ConditionValues: getConditionValues(r, "", cred.AccessKey, claims),
+		ConditionValues: withConditionValues(getConditionValues(r, "", cred.AccessKey, claims), tagValues),
 		ObjectName:      objectName,
 		IsOwner:         owner,
 		Claims:          claims,
-	}) {
+	}
+	if globalIAMSys.IsAllowed(args) {
 		// Request is allowed return the appropriate access key.
 		return cred.AccessKey, owner, ErrNone
 	}
@@ -401,9 +467,44 @@ func checkRequestAuthTypeToAccessKey(ctx context.Context, r *http.Request, actio
 		}
 	}
 
+	traceDeniedRequest(ctx, args)
 	return cred.AccessKey, owner, ErrAccessDenied
 }
 
+// existingObjectTags - returns the tags of the object, or of the object
+// version, of a request. No tags are returned if the object is missing.
+func existingObjectTags(ctx context.Context, r *http.Request, bucket, object string) map[string]string {
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		return nil
+	}
+
+	opts, err := getOpts(ctx, r, bucket, object)
+	if err != nil {
+		return nil
+	}
+
+	objInfo, err := objectAPI.GetObjectInfo(ctx, bucket, object, opts)
+	if err != nil {
+		return nil
+	}
+
+	t, err := tags.ParseObjectTags(objInfo.UserTags)
+	if err != nil {
+		return nil
+	}
+	return t.ToMap()
+}
+
+// withConditionValues - returns the condition values along with the
+// extra condition values.
+func withConditionValues(values, extra map[string][]string) map[string][]string {
+	for k, v := range extra {
+		values[k] = v
+	}
+	return values
+}
+
 // Verify if request has valid AWS Signature Version '2'.
 func isReqAuthenticatedV2(r *http.Request) (s3Error APIErrorCode) {
 	if isRequestSignatureV2(r) {
@@ -647,6 +748,10 @@ func isPutActionAllowed(ctx context.Context, atype authType, bucketName, objectN
 		return s3Err
 	}
 
+	if s3Err = globalRateLimitSys.enforce(ctx, cred, bucketName); s3Err != ErrNone {
+		return s3Err
+	}
+
 	if cred.AccessKey != "" {
 		logger.GetReqInfo(ctx).AccessKey = cred.AccessKey
 	}
@@ -661,20 +766,22 @@ func isPutActionAllowed(ctx context.Context, atype authType, bucketName, objectN
 	}
 
 	if cred.AccessKey == "" {
-		if globalPolicySys.IsAllowed(policy.Args{
+		args := policy.Args{
 			AccountName:     cred.AccessKey,
 			Action:          policy.Action(action),
 			BucketName:      bucketName,
 			ConditionValues: getConditionValues(r, "", "", nil),
 			IsOwner:         false,
 			ObjectName:      objectName,
-		}) {
+		}
+		if globalPolicySys.IsAllowed(args) {
 			return ErrNone
 		}
+		traceDeniedAnonymousRequest(ctx, args)
 		return ErrAccessDenied
 	}
 
-	if globalIAMSys.IsAllowed(iampolicy.Args{
+	args := iampolicy.Args{
 		AccountName:     cred.AccessKey,
 		Action:          action,
 		BucketName:      bucketName,
@@ -682,8 +789,36 @@ func isPutActionAllowed(ctx context.Context, atype authType, bucketName, objectN
 		ObjectName:      objectName,
 		IsOwner:         owner,
 		Claims:          claims,
-	}) {
+	}
+	if globalIAMSys.IsAllowed(args) {
 		return ErrNone
 	}
+	traceDeniedRequest(ctx, args)
 	return ErrAccessDenied
 }
+
+// verifyPutObjectSignature - verifies the signature of an upload allowed
+// by isPutActionAllowed, the signature of streaming uploads is verified
+// while their payload is read from the returned reader. The use of the
+// access key is recorded once the signature is verified.
+func verifyPutObjectSignature(ctx context.Context, atype authType, r *http.Request) (reader io.Reader, sha256hex string, s3Err APIErrorCode) {
+	reader = r.Body
+	switch atype {
+	case authTypeStreamingSigned:
+		// Initialize stream signature verifier.
+		reader, s3Err = newSignV4ChunkedReader(r)
+	case authTypeSignedV2, authTypePresignedV2:
+		s3Err = isReqAuthenticatedV2(r)
+	case authTypePresigned, authTypeSigned:
+		s3Err = reqSignatureV4Verify(r, globalServerRegion, serviceS3)
+		if s3Err == ErrNone && !skipContentSha256Cksum(r) {
+			sha256hex = getContentSha256Cksum(r, serviceS3)
+		}
+	}
+	if s3Err != ErrNone {
+		return nil, "", s3Err
+	}
+
+	globalAccessKeyUsage.recordRequest(ctx, r)
+	return reader, sha256hex, ErrNone
+}
@@ -22,8 +22,11 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -471,3 +474,29 @@ func TestValidateAdminSignature(t *testing.T) {
 		}
 	}
 }
+
+func TestRequestObjectTags(t *testing.T) {
+	body := `<Tagging><TagSet><Tag><Key>project</Key><Value>alpha</Value></Tag></TagSet></Tagging>`
+	for _, contentLength := range []int64{int64(len(body)), -1} {
+		r := httptest.NewRequest(http.MethodPut, "http://localhost:9000/bucket/object?tagging", strings.NewReader(body))
+		// Chunked requests have no content length.
+		r.ContentLength = contentLength
+
+		objTags, err := requestObjectTags(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(objTags, map[string]string{"project": "alpha"}) {
+			t.Errorf("content length %d: unexpected tags %v", contentLength, objTags)
+		}
+		// The body is populated again for the HTTP handler.
+		if payload, err := ioutil.ReadAll(r.Body); err != nil || string(payload) != body {
+			t.Errorf("content length %d: unexpected body %q (%v)", contentLength, payload, err)
+		}
+	}
+
+	r := httptest.NewRequest(http.MethodPut, "http://localhost:9000/bucket/object?tagging", strings.NewReader("<Tagging>"))
+	if objTags, err := requestObjectTags(r); err != nil || objTags != nil {
+		t.Errorf("expected no tags for invalid XML, got %v (%v)", objTags, err)
+	}
+}
@@ -418,6 +418,10 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 	if _, err := globalBucketMetadataSys.GetLifecycleConfig(bucket); err == nil {
 		hasLifecycleConfig = true
 	}
+	// Objects no longer counted in the usage of the bucket, by index in
+	// the request.
+	trackUsage := globalBucketQuotaSys.tracksUsage(bucket)
+	quotaObjects := make(map[int]ObjectInfo)
 	dErrs := make([]DeleteError, len(deleteObjects.Objects))
 	for index, object := range deleteObjects.Objects {
 		if apiErrCode := checkRequestAuthType(ctx, r, policy.DeleteObjectAction, bucket, object.ObjectName); apiErrCode != ErrNone {
@@ -448,10 +452,13 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 			}
 		}
 
-		if replicateDeletes || hasLockEnabled || hasLifecycleConfig {
+		if replicateDeletes || hasLockEnabled || hasLifecycleConfig || trackUsage {
 			goi, gerr = getObjectInfoFn(ctx, bucket, object.ObjectName, ObjectOptions{
 				VersionID: object.VersionID,
 			})
+			if trackUsage && gerr == nil {
+				quotaObjects[index] = goi
+			}
 		}
 		if hasLifecycleConfig && gerr == nil {
 			object.PurgeTransitioned = goi.TransitionStatus
@@ -504,8 +511,9 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 	}
 
 	deleteList := toNames(objectsToDelete)
+	versioned := globalBucketVersioningSys.Enabled(bucket)
 	dObjects, errs := deleteObjectsFn(ctx, bucket, deleteList, ObjectOptions{
-		Versioned:        globalBucketVersioningSys.Enabled(bucket),
+		Versioned:        versioned,
 		VersionSuspended: globalBucketVersioningSys.Suspended(bucket),
 	})
 	deletedObjects := make([]DeletedObject, len(deleteObjects.Objects))
@@ -519,6 +527,9 @@ func (api objectAPIHandlers) DeleteMultipleObjectsHandler(w http.ResponseWriter,
 			PurgeTransitioned:             dObjects[i].PurgeTransitioned,
 		}]
 		if errs[i] == nil || isErrObjectNotFound(errs[i]) || isErrVersionNotFound(errs[i]) {
+			if oi, ok := quotaObjects[dindex]; ok && errs[i] == nil && (deleteList[i].VersionID != "" || !versioned) {
+				globalBucketQuotaSys.objectDeleted(ctx, bucket, oi)
+			}
 			if replicateDeletes {
 				dObjects[i].DeleteMarkerReplicationStatus = deleteList[i].DeleteMarkerReplicationStatus
 				dObjects[i].VersionPurgeStatus = deleteList[i].VersionPurgeStatus
@@ -891,6 +902,11 @@ func (api objectAPIHandlers) PostPolicyBucketHandler(w http.ResponseWriter, r *h
 		return
 	}
 
+	if err = enforceBucketQuota(ctx, bucket, object, fileSize); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
 	hashReader, err := hash.NewReader(fileBody, fileSize, "", "", fileSize, globalCLIContext.StrictS3Compat)
 	if err != nil {
 		logger.LogIf(ctx, err)
@@ -947,11 +963,14 @@ func (api objectAPIHandlers) PostPolicyBucketHandler(w http.ResponseWriter, r *h
 		}
 	}
 
+	qw := globalBucketQuotaSys.beginWrite(ctx, bucket, object, metadata, &opts)
+
 	objInfo, err := objectAPI.PutObject(ctx, bucket, object, pReader, opts)
 	if err != nil {
 		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
 		return
 	}
+	qw.done(ctx, objInfo)
 
 	// We must not use the http.Header().Set method here because some (broken)
 	// clients expect the ETag header key to be literally "ETag" - not "Etag" (case-sensitive).
@@ -0,0 +1,317 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/gorilla/mux"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/bucket/inventory"
+	"github.com/minio/minio/pkg/bucket/policy"
+)
+
+const (
+	// Inventory configuration file.
+	bucketInventoryConfig = "inventory.xml"
+
+	// Maximum number of inventory configurations returned in a single list response.
+	maxInventoryConfigList = 100
+)
+
+// ListInventoryConfigurationsResponse - format for list bucket inventory configurations response.
+type ListInventoryConfigurationsResponse struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListInventoryConfigurationsResult" json:"-"`
+
+	InventoryConfigurations []inventory.Config `xml:"InventoryConfiguration"`
+	IsTruncated             bool               `xml:"IsTruncated"`
+	ContinuationToken       string             `xml:"ContinuationToken,omitempty"`
+	NextContinuationToken   string             `xml:"NextContinuationToken,omitempty"`
+}
+
+// PutBucketInventoryConfigHandler - PUT Bucket inventory configuration.
+// ----------
+// Adds or replaces the inventory configuration identified by the
+// id query parameter.
+func (api objectAPIHandlers) PutBucketInventoryConfigHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "PutBucketInventoryConfig")
+
+	defer logger.AuditLog(w, r, "PutBucketInventoryConfig", mustGetClaimsFromToken(r))
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	id := vars["id"]
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.PutInventoryConfigurationAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Check if bucket exists.
+	if _, err := objAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	config, err := inventory.ParseConfig(io.LimitReader(r.Body, r.ContentLength))
+	if err != nil {
+		apiErr := errorCodes.ToAPIErr(ErrMalformedXML)
+		apiErr.Description = err.Error()
+		writeErrorResponse(ctx, w, apiErr, r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	if config.ID != id {
+		apiErr := errorCodes.ToAPIErr(ErrMalformedXML)
+		apiErr.Description = "The inventory configuration Id must match the id query parameter"
+		writeErrorResponse(ctx, w, apiErr, r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Validate the received inventory configuration
+	if err = config.Validate(); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Reports are written to the destination bucket on behalf of
+	// the requester, make sure the destination exists and that the
+	// requester is allowed to write into it.
+	dstBucket := config.DestinationBucket()
+	if _, err = objAPI.GetBucketInfo(ctx, dstBucket); err != nil {
+		if isErrBucketNotFound(err) {
+			writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrInvalidInventoryDestination), r.URL, guessIsBrowserReq(r))
+			return
+		}
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+	if s3Error := checkRequestAuthType(ctx, r, policy.PutObjectAction, dstBucket, ""); s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	configs, err := globalBucketMetadataSys.GetInventoryConfig(bucket)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	configs, err = configs.Put(*config)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	configData, err := xml.Marshal(configs)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	if err = globalBucketMetadataSys.Update(bucket, bucketInventoryConfig, configData); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Write success response.
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetBucketInventoryConfigHandler - GET Bucket inventory configuration.
+// ----------
+// Returns the inventory configuration identified by the id query parameter.
+func (api objectAPIHandlers) GetBucketInventoryConfigHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "GetBucketInventoryConfig")
+
+	defer logger.AuditLog(w, r, "GetBucketInventoryConfig", mustGetClaimsFromToken(r))
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	id := vars["id"]
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.GetInventoryConfigurationAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Check if bucket exists.
+	if _, err := objAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	configs, err := globalBucketMetadataSys.GetInventoryConfig(bucket)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	config, ok := configs.Get(id)
+	if !ok {
+		writeErrorResponse(ctx, w, toAPIError(ctx, BucketInventoryConfigNotFound{Bucket: bucket, ID: id}), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	configData, err := xml.Marshal(config)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Write success response.
+	writeSuccessResponseXML(w, configData)
+}
+
+// ListBucketInventoryConfigsHandler - GET Bucket inventory configurations.
+// ----------
+// Returns up to 100 inventory configurations of a bucket, sorted by id.
+func (api objectAPIHandlers) ListBucketInventoryConfigsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "ListBucketInventoryConfigs")
+
+	defer logger.AuditLog(w, r, "ListBucketInventoryConfigs", mustGetClaimsFromToken(r))
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.GetInventoryConfigurationAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Check if bucket exists.
+	if _, err := objAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	token := r.URL.Query().Get("continuation-token")
+	marker, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrIncorrectContinuationToken), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	configs, err := globalBucketMetadataSys.GetInventoryConfig(bucket)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	sorted := make([]inventory.Config, 0, len(configs.Configs))
+	for _, config := range configs.Configs {
+		if token == "" || config.ID > string(marker) {
+			sorted = append(sorted, config)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	response := ListInventoryConfigurationsResponse{
+		ContinuationToken: token,
+	}
+	if len(sorted) > maxInventoryConfigList {
+		sorted = sorted[:maxInventoryConfigList]
+		response.IsTruncated = true
+		response.NextContinuationToken = base64.StdEncoding.EncodeToString([]byte(sorted[len(sorted)-1].ID))
+	}
+	response.InventoryConfigurations = sorted
+
+	// Write success response.
+	writeSuccessResponseXML(w, encodeResponse(response))
+}
+
+// DeleteBucketInventoryConfigHandler - DELETE Bucket inventory configuration.
+// ----------
+// Removes the inventory configuration identified by the id query parameter.
+func (api objectAPIHandlers) DeleteBucketInventoryConfigHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "DeleteBucketInventoryConfig")
+
+	defer logger.AuditLog(w, r, "DeleteBucketInventoryConfig", mustGetClaimsFromToken(r))
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	id := vars["id"]
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.PutInventoryConfigurationAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Check if bucket exists.
+	if _, err := objAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	configs, err := globalBucketMetadataSys.GetInventoryConfig(bucket)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	configs, found := configs.Remove(id)
+	if !found {
+		writeErrorResponse(ctx, w, toAPIError(ctx, BucketInventoryConfigNotFound{Bucket: bucket, ID: id}), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	var configData []byte
+	if len(configs.Configs) > 0 {
+		configData, err = xml.Marshal(configs)
+		if err != nil {
+			writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+			return
+		}
+	}
+
+	if err = globalBucketMetadataSys.Update(bucket, bucketInventoryConfig, configData); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Write success response.
+	writeSuccessNoContent(w)
+}
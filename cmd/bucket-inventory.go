@@ -0,0 +1,438 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/md5"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode"
+
+	"github.com/minio/minio/cmd/crypto"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/bucket/inventory"
+	objectlock "github.com/minio/minio/pkg/bucket/object/lock"
+	"github.com/minio/minio/pkg/hash"
+	"github.com/minio/minio/pkg/s3select/parquet"
+)
+
+const (
+	// Inventory state file, records when each inventory report
+	// of a bucket was last generated.
+	bucketInventoryStateFile = "inventory-state.json"
+
+	// Version of the inventory manifest format.
+	inventoryManifestVersion = "2016-11-30"
+
+	// Number of records per parquet row group.
+	inventoryParquetRowGroupCount = 10000
+)
+
+// Set while inventory reports are being generated.
+var inventoryRunning int32
+
+// inventoryState - last generation time of each inventory configuration of a bucket.
+type inventoryState struct {
+	LastRun map[string]time.Time `json:"lastRun"`
+}
+
+// inventoryManifestFile - a data file of an inventory report.
+type inventoryManifestFile struct {
+	Key         string `json:"key"`
+	Size        int64  `json:"size"`
+	MD5Checksum string `json:"MD5checksum"`
+}
+
+// inventoryManifest - describes the data files of an inventory report as per
+// https://docs.aws.amazon.com/AmazonS3/latest/dev/storage-inventory-location.html
+type inventoryManifest struct {
+	SourceBucket      string                  `json:"sourceBucket"`
+	DestinationBucket string                  `json:"destinationBucket"`
+	Version           string                  `json:"version"`
+	CreationTimestamp string                  `json:"creationTimestamp"`
+	FileFormat        inventory.Format        `json:"fileFormat"`
+	FileSchema        string                  `json:"fileSchema"`
+	Files             []inventoryManifestFile `json:"files"`
+}
+
+func loadInventoryState(ctx context.Context, objAPI ObjectLayer, bucket string) (inventoryState, error) {
+	state := inventoryState{LastRun: make(map[string]time.Time)}
+	data, err := readConfig(ctx, objAPI, path.Join(bucketConfigPrefix, bucket, bucketInventoryStateFile))
+	if err != nil {
+		if errors.Is(err, errConfigNotFound) {
+			return state, nil
+		}
+		return state, err
+	}
+	if err = json.Unmarshal(data, &state); err != nil {
+		return state, err
+	}
+	if state.LastRun == nil {
+		state.LastRun = make(map[string]time.Time)
+	}
+	return state, nil
+}
+
+func saveInventoryState(ctx context.Context, objAPI ObjectLayer, bucket string, state inventoryState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return saveConfig(ctx, objAPI, path.Join(bucketConfigPrefix, bucket, bucketInventoryStateFile), data)
+}
+
+// startBucketInventories generates all inventory reports which are due in
+// the background, unless a previous generation is still in progress.
+// It is invoked by the data crawler, so only one node of the cluster
+// generates inventory reports.
+func startBucketInventories(ctx context.Context, objAPI ObjectLayer) {
+	if !atomic.CompareAndSwapInt32(&inventoryRunning, 0, 1) {
+		return
+	}
+	go func() {
+		defer atomic.StoreInt32(&inventoryRunning, 0)
+		runBucketInventories(ctx, objAPI)
+	}()
+}
+
+// runBucketInventories generates the inventory reports of all buckets
+// which have not been generated within their configured schedule.
+func runBucketInventories(ctx context.Context, objAPI ObjectLayer) {
+	buckets, err := objAPI.ListBuckets(ctx)
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return
+	}
+
+	for _, bucket := range buckets {
+		configs, err := globalBucketMetadataSys.GetInventoryConfig(bucket.Name)
+		if err != nil || len(configs.Configs) == 0 {
+			continue
+		}
+
+		state, err := loadInventoryState(ctx, objAPI, bucket.Name)
+		if err != nil {
+			logger.LogIf(ctx, err)
+			continue
+		}
+
+		lastRun := make(map[string]time.Time, len(configs.Configs))
+		for _, config := range configs.Configs {
+			last, ok := state.LastRun[config.ID]
+			if ok {
+				lastRun[config.ID] = last
+			}
+			if !config.IsEnabled || (ok && UTCNow().Sub(last) < config.Interval()) {
+				continue
+			}
+			now := UTCNow()
+			if err = generateInventoryReport(ctx, objAPI, bucket.Name, config, now); err != nil {
+				logger.LogIf(ctx, fmt.Errorf("inventory %s of bucket %s: %w", config.ID, bucket.Name, err))
+				continue
+			}
+			lastRun[config.ID] = now
+		}
+
+		// Entries of removed configurations are dropped.
+		state.LastRun = lastRun
+		logger.LogIf(ctx, saveInventoryState(ctx, objAPI, bucket.Name, state))
+	}
+}
+
+// generateInventoryReport writes a data file listing all objects of the
+// bucket matching the configuration and a manifest describing it into
+// the destination bucket.
+func generateInventoryReport(ctx context.Context, objAPI ObjectLayer, bucket string, config inventory.Config, now time.Time) error {
+	dst := config.Destination.S3BucketDestination
+	dstBucket := config.DestinationBucket()
+	basePath := path.Join(dst.Prefix, bucket, config.ID)
+
+	ext := ".csv.gz"
+	if dst.Format == inventory.ParquetFormat {
+		ext = ".parquet"
+	}
+	dataKey := path.Join(basePath, "data", mustGetUUID()+ext)
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeInventoryData(ctx, objAPI, bucket, config, pw))
+	}()
+
+	hashReader, err := hash.NewReader(pr, -1, "", "", -1, globalCLIContext.StrictS3Compat)
+	if err != nil {
+		pr.CloseWithError(err)
+		return err
+	}
+	dataInfo, err := objAPI.PutObject(ctx, dstBucket, dataKey, NewPutObjReader(hashReader, nil, nil), ObjectOptions{})
+	// Unblock the writer in case the upload failed.
+	pr.CloseWithError(err)
+	if err != nil {
+		return err
+	}
+
+	manifest := inventoryManifest{
+		SourceBucket:      bucket,
+		DestinationBucket: dst.Bucket,
+		Version:           inventoryManifestVersion,
+		CreationTimestamp: strconv.FormatInt(now.UnixNano()/int64(time.Millisecond), 10),
+		FileFormat:        dst.Format,
+		FileSchema:        inventoryFileSchema(config),
+		Files: []inventoryManifestFile{{
+			Key:         dataKey,
+			Size:        dataInfo.Size,
+			MD5Checksum: dataInfo.ETag,
+		}},
+	}
+	manifestData, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+
+	manifestPath := path.Join(basePath, now.Format("2006-01-02T15-04Z"))
+	sum := md5.Sum(manifestData)
+	if err = putInventoryObject(ctx, objAPI, dstBucket, path.Join(manifestPath, "manifest.json"), manifestData, "application/json"); err != nil {
+		return err
+	}
+	checksum := []byte(hex.EncodeToString(sum[:]))
+	return putInventoryObject(ctx, objAPI, dstBucket, path.Join(manifestPath, "manifest.checksum"), checksum, "text/plain")
+}
+
+func putInventoryObject(ctx context.Context, objAPI ObjectLayer, bucket, object string, data []byte, contentType string) error {
+	hashReader, err := hash.NewReader(bytes.NewReader(data), int64(len(data)), "", getSHA256Hash(data), int64(len(data)), globalCLIContext.StrictS3Compat)
+	if err != nil {
+		return err
+	}
+	_, err = objAPI.PutObject(ctx, bucket, object, NewPutObjReader(hashReader, nil, nil), ObjectOptions{
+		UserDefined: map[string]string{"content-type": contentType},
+	})
+	return err
+}
+
+// writeInventoryData walks the bucket and writes one record per object
+// (or object version) in the configured format.
+func writeInventoryData(ctx context.Context, objAPI ObjectLayer, bucket string, config inventory.Config, w io.WriteCloser) error {
+	enc, err := newInventoryEncoder(config, w)
+	if err != nil {
+		return err
+	}
+
+	results := make(chan ObjectInfo, 100)
+	opts := ObjectOptions{WalkVersions: config.IncludedObjectVersions == inventory.AllVersions}
+	if err = objAPI.Walk(ctx, bucket, config.Prefix(), results, opts); err != nil {
+		return err
+	}
+
+	for oi := range results {
+		// Keep draining the results to let the walker finish.
+		if err != nil {
+			continue
+		}
+		if !opts.WalkVersions && oi.DeleteMarker {
+			continue
+		}
+		err = enc.write(inventoryRecord(config, oi))
+	}
+	if err != nil {
+		return err
+	}
+	return enc.close()
+}
+
+// inventoryRecord returns the values of all columns of the report for an object.
+func inventoryRecord(config inventory.Config, oi ObjectInfo) []interface{} {
+	values := []interface{}{oi.Bucket, oi.Name}
+	if config.IncludedObjectVersions == inventory.AllVersions {
+		values = append(values, oi.VersionID, oi.IsLatest, oi.DeleteMarker)
+	}
+
+	for _, field := range config.Fields() {
+		switch field {
+		case inventory.SizeField:
+			values = append(values, oi.Size)
+		case inventory.LastModifiedDateField:
+			values = append(values, oi.ModTime.UTC().Format(iso8601TimeFormat))
+		case inventory.ETagField:
+			values = append(values, oi.ETag)
+		case inventory.StorageClassField:
+			storageClass := oi.StorageClass
+			if storageClass == "" {
+				storageClass = globalMinioDefaultStorageClass
+			}
+			values = append(values, storageClass)
+		case inventory.IsMultipartUploadedField:
+			values = append(values, strings.Contains(oi.ETag, "-"))
+		case inventory.ReplicationStatusField:
+			values = append(values, oi.ReplicationStatus.String())
+		case inventory.EncryptionStatusField:
+			values = append(values, inventoryEncryptionStatus(oi.UserDefined))
+		case inventory.ObjectLockRetainUntilDateField:
+			var retainUntil string
+			if ret := objectlock.GetObjectRetentionMeta(oi.UserDefined); !ret.RetainUntilDate.IsZero() {
+				retainUntil = ret.RetainUntilDate.UTC().Format(iso8601TimeFormat)
+			}
+			values = append(values, retainUntil)
+		case inventory.ObjectLockModeField:
+			values = append(values, string(objectlock.GetObjectRetentionMeta(oi.UserDefined).Mode))
+		case inventory.ObjectLockLegalHoldStatusField:
+			values = append(values, string(objectlock.GetObjectLegalHoldMeta(oi.UserDefined).Status))
+		}
+	}
+	return values
+}
+
+func inventoryEncryptionStatus(metadata map[string]string) string {
+	switch {
+	case crypto.SSEC.IsEncrypted(metadata):
+		return "SSE-C"
+	case crypto.S3KMS.IsEncrypted(metadata):
+		return "SSE-KMS"
+	case crypto.IsEncrypted(metadata):
+		return "SSE-S3"
+	}
+	return "NOT-SSE"
+}
+
+// inventoryParquetColumns returns the parquet columns of the report,
+// named in snake case, e.g. LastModifiedDate is last_modified_date.
+func inventoryParquetColumns(config inventory.Config) []parquet.Column {
+	var columns []parquet.Column
+	for _, name := range config.Columns() {
+		column := parquet.Column{Name: inventorySnakeCase(name), Type: parquet.StringColumn}
+		switch name {
+		case string(inventory.SizeField):
+			column.Type = parquet.Int64Column
+		case inventory.IsLatestColumn, inventory.IsDeleteMarkerColumn, string(inventory.IsMultipartUploadedField):
+			column.Type = parquet.BoolColumn
+		}
+		columns = append(columns, column)
+	}
+	return columns
+}
+
+func inventorySnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) &&
+			(unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// inventoryFileSchema returns the schema of the data files for the manifest.
+func inventoryFileSchema(config inventory.Config) string {
+	if config.Destination.S3BucketDestination.Format != inventory.ParquetFormat {
+		return strings.Join(config.Columns(), ", ")
+	}
+
+	var b strings.Builder
+	b.WriteString("message s3.inventory { ")
+	for _, column := range inventoryParquetColumns(config) {
+		switch column.Type {
+		case parquet.Int64Column:
+			fmt.Fprintf(&b, "required int64 %s; ", column.Name)
+		case parquet.BoolColumn:
+			fmt.Fprintf(&b, "required boolean %s; ", column.Name)
+		default:
+			fmt.Fprintf(&b, "required binary %s (UTF8); ", column.Name)
+		}
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// inventoryEncoder - encodes inventory records into a data file.
+type inventoryEncoder interface {
+	write(values []interface{}) error
+	close() error
+}
+
+func newInventoryEncoder(config inventory.Config, w io.WriteCloser) (inventoryEncoder, error) {
+	if config.Destination.S3BucketDestination.Format == inventory.ParquetFormat {
+		pw, err := parquet.NewWriter(w, inventoryParquetColumns(config), inventoryParquetRowGroupCount)
+		if err != nil {
+			return nil, err
+		}
+		return &inventoryParquetEncoder{writer: pw}, nil
+	}
+
+	gw := gzip.NewWriter(w)
+	return &inventoryCSVEncoder{w: w, gw: gw, cw: csv.NewWriter(gw)}, nil
+}
+
+// inventoryCSVEncoder - writes gzip compressed CSV data files without a header,
+// object names are URL encoded.
+type inventoryCSVEncoder struct {
+	w  io.WriteCloser
+	gw *gzip.Writer
+	cw *csv.Writer
+}
+
+func (e *inventoryCSVEncoder) write(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		switch v := v.(type) {
+		case string:
+			record[i] = v
+		case int64:
+			record[i] = strconv.FormatInt(v, 10)
+		case bool:
+			record[i] = strconv.FormatBool(v)
+		}
+	}
+	// Object name is always the second column.
+	record[1] = s3URLEncode(record[1])
+	return e.cw.Write(record)
+}
+
+func (e *inventoryCSVEncoder) close() error {
+	e.cw.Flush()
+	if err := e.cw.Error(); err != nil {
+		return err
+	}
+	if err := e.gw.Close(); err != nil {
+		return err
+	}
+	return e.w.Close()
+}
+
+// inventoryParquetEncoder - writes parquet data files.
+type inventoryParquetEncoder struct {
+	writer *parquet.Writer
+}
+
+func (e *inventoryParquetEncoder) write(values []interface{}) error {
+	return e.writer.Write(values...)
+}
+
+func (e *inventoryParquetEncoder) close() error {
+	return e.writer.Close()
+}
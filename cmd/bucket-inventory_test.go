@@ -0,0 +1,102 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/bucket/inventory"
+)
+
+type nopWriteCloser struct {
+	*bytes.Buffer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+func TestInventorySnakeCase(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected string
+	}{
+		{"Bucket", "bucket"},
+		{"VersionId", "version_id"},
+		{"ETag", "e_tag"},
+		{"LastModifiedDate", "last_modified_date"},
+		{"IsMultipartUploaded", "is_multipart_uploaded"},
+	}
+	for i, testCase := range testCases {
+		if got := inventorySnakeCase(testCase.name); got != testCase.expected {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expected, got)
+		}
+	}
+}
+
+func TestInventoryCSVRecord(t *testing.T) {
+	config := inventory.Config{
+		ID:                     "report",
+		IsEnabled:              true,
+		IncludedObjectVersions: inventory.AllVersions,
+		Destination: inventory.Destination{
+			S3BucketDestination: inventory.S3BucketDestination{
+				Bucket: "arn:aws:s3:::dest",
+				Format: inventory.CSVFormat,
+			},
+		},
+		OptionalFields: &inventory.OptionalFields{
+			Fields: []inventory.Field{inventory.SizeField, inventory.IsMultipartUploadedField, inventory.EncryptionStatusField},
+		},
+		Schedule: inventory.Schedule{Frequency: inventory.Daily},
+	}
+	oi := ObjectInfo{
+		Bucket:    "bucket",
+		Name:      "dir/my object",
+		VersionID: "v1",
+		IsLatest:  true,
+		Size:      42,
+		ETag:      "abc-2",
+		ModTime:   time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	var buf bytes.Buffer
+	enc, err := newInventoryEncoder(config, nopWriteCloser{&buf})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = enc.write(inventoryRecord(config, oi)); err != nil {
+		t.Fatal(err)
+	}
+	if err = enc.close(); err != nil {
+		t.Fatal(err)
+	}
+
+	gr, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(gr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "bucket,dir/my+object,v1,true,false,42,true,NOT-SSE\n"
+	if string(data) != expected {
+		t.Fatalf("expected %q, got %q", expected, string(data))
+	}
+}
@@ -178,7 +178,7 @@ func testBucketLifecycleHandlers(obj ObjectLayer, instanceType, bucketName strin
 			errorResponse: APIErrorResponse{
 				Resource: SlashSeparator + bucketName + SlashSeparator,
 				Code:     "InvalidRequest",
-				Message:  "Filter must have exactly one of Prefix, Tag, or And specified",
+				Message:  "Filter must have exactly one of Prefix, Tag, ObjectSizeGreaterThan, ObjectSizeLessThan or And specified",
 			},
 
 			shouldPass: false,
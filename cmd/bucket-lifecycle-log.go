@@ -0,0 +1,273 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+const (
+	// Number of days the lifecycle actions are kept per bucket.
+	lifecycleActionLogDays = 30
+	// Number of latest lifecycle actions kept individually per bucket.
+	lifecycleActionLogEntries = 1000
+
+	// The lifecycle actions applied by each server are saved under
+	// this prefix.
+	lifecycleActionLogPrefix = minioConfigPrefix + SlashSeparator + "lifecycle-log"
+	// Interval at which the lifecycle actions are saved.
+	lifecycleActionLogSaveInterval = time.Minute
+
+	lifecycleActionLogVersion1 = 1
+)
+
+// bucketLifecycleActionLog - lifecycle actions applied to the objects of
+// a bucket, the daily totals and the latest actions individually.
+type bucketLifecycleActionLog struct {
+	Days    map[time.Time]map[string]madmin.LifecycleActionSummary `json:"days"`
+	Entries []madmin.LifecycleActionLogEntry                       `json:"entries"`
+}
+
+// lifecycleActionLogState - lifecycle actions applied by a server, as
+// saved in the backend.
+type lifecycleActionLogState struct {
+	Version int                                  `json:"version"`
+	Buckets map[string]*bucketLifecycleActionLog `json:"buckets"`
+}
+
+// lifecycleActionLog - lifecycle actions applied by the crawler of this
+// node, restored from the backend when it starts.
+type lifecycleActionLog struct {
+	mu      sync.Mutex
+	buckets map[string]*bucketLifecycleActionLog
+	dirty   bool
+}
+
+var globalLifecycleActionLog = &lifecycleActionLog{
+	buckets: make(map[string]*bucketLifecycleActionLog),
+}
+
+// add - records the lifecycle action applied to the object version.
+func (l *lifecycleActionLog) add(bucket string, e madmin.LifecycleActionLogEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bl, ok := l.buckets[bucket]
+	if !ok {
+		bl = &bucketLifecycleActionLog{
+			Days: make(map[time.Time]map[string]madmin.LifecycleActionSummary),
+		}
+		l.buckets[bucket] = bl
+	}
+	l.dirty = true
+
+	day := e.Time.UTC().Truncate(24 * time.Hour)
+	actions, ok := bl.Days[day]
+	if !ok {
+		actions = make(map[string]madmin.LifecycleActionSummary)
+		bl.Days[day] = actions
+		// Forget the days which are not kept anymore.
+		for d := range bl.Days {
+			if day.Sub(d) >= lifecycleActionLogDays*24*time.Hour {
+				delete(bl.Days, d)
+			}
+		}
+	}
+	summary := actions[e.Action]
+	summary.Count++
+	summary.Size += e.Size
+	actions[e.Action] = summary
+
+	if len(bl.Entries) >= lifecycleActionLogEntries {
+		bl.Entries = append(bl.Entries[:0], bl.Entries[1:]...)
+	}
+	bl.Entries = append(bl.Entries, e)
+}
+
+// get - returns the lifecycle actions applied to the objects of the
+// bucket since the given time.
+func (l *lifecycleActionLog) get(bucket string, since time.Time) madmin.LifecycleActionLog {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return getBucketLifecycleActionLog(l.buckets, bucket, since)
+}
+
+func getBucketLifecycleActionLog(buckets map[string]*bucketLifecycleActionLog, bucket string, since time.Time) madmin.LifecycleActionLog {
+	log := madmin.LifecycleActionLog{Bucket: bucket}
+	bl, ok := buckets[bucket]
+	if !ok {
+		return log
+	}
+	since = since.UTC().Truncate(24 * time.Hour)
+	for day, actions := range bl.Days {
+		if day.Before(since) {
+			continue
+		}
+		d := madmin.LifecycleActionLogDay{
+			Day:     day,
+			Actions: make(map[string]madmin.LifecycleActionSummary, len(actions)),
+		}
+		for action, summary := range actions {
+			d.Actions[action] = summary
+		}
+		log.Days = append(log.Days, d)
+	}
+	sort.Slice(log.Days, func(i, j int) bool {
+		return log.Days[i].Day.Before(log.Days[j].Day)
+	})
+	for _, e := range bl.Entries {
+		if !e.Time.Before(since) {
+			log.Entries = append(log.Entries, e)
+		}
+	}
+	return log
+}
+
+// delete - forgets the lifecycle actions of a removed bucket.
+func (l *lifecycleActionLog) delete(bucket string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.buckets[bucket]; ok {
+		delete(l.buckets, bucket)
+		l.dirty = true
+	}
+}
+
+func lifecycleActionLogPath(node string) string {
+	return pathJoin(lifecycleActionLogPrefix, getSHA256Hash([]byte(node))+".json")
+}
+
+// save saves the lifecycle actions applied by this server if they
+// changed.
+func (l *lifecycleActionLog) save(ctx context.Context, objAPI ObjectLayer, node string) error {
+	l.mu.Lock()
+	if !l.dirty {
+		l.mu.Unlock()
+		return nil
+	}
+	data, err := json.Marshal(lifecycleActionLogState{
+		Version: lifecycleActionLogVersion1,
+		Buckets: l.buckets,
+	})
+	l.dirty = false
+	l.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return saveConfig(ctx, objAPI, lifecycleActionLogPath(node), data)
+}
+
+// loadLifecycleActionLog loads the lifecycle actions last saved by a
+// server.
+func loadLifecycleActionLog(ctx context.Context, objAPI ObjectLayer, node string) (lifecycleActionLogState, error) {
+	var state lifecycleActionLogState
+	data, err := readConfig(ctx, objAPI, lifecycleActionLogPath(node))
+	if err != nil {
+		return state, err
+	}
+	if err = json.Unmarshal(data, &state); err != nil {
+		return state, err
+	}
+	if state.Buckets == nil {
+		state.Buckets = make(map[string]*bucketLifecycleActionLog)
+	}
+	for _, bl := range state.Buckets {
+		if bl.Days == nil {
+			bl.Days = make(map[time.Time]map[string]madmin.LifecycleActionSummary)
+		}
+	}
+	return state, nil
+}
+
+// initLifecycleActionLog restores the lifecycle actions this server
+// applied before it restarted, then saves them periodically.
+func initLifecycleActionLog(ctx context.Context, objAPI ObjectLayer) {
+	_, local := globalEndpoints.peers()
+	if local == "" {
+		local = GetLocalPeer(globalEndpoints)
+	}
+
+	go func() {
+		if state, err := loadLifecycleActionLog(ctx, objAPI, local); err == nil {
+			globalLifecycleActionLog.mu.Lock()
+			// Keep the actions applied since the server started.
+			for bucket, bl := range globalLifecycleActionLog.buckets {
+				state.Buckets[bucket] = bl
+			}
+			globalLifecycleActionLog.buckets = state.Buckets
+			globalLifecycleActionLog.mu.Unlock()
+		} else if err != errConfigNotFound {
+			logger.LogIf(ctx, err)
+		}
+
+		t := time.NewTicker(lifecycleActionLogSaveInterval)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				logger.LogIf(ctx, globalLifecycleActionLog.save(ctx, objAPI, local))
+			}
+		}
+	}()
+}
+
+// mergeLifecycleActionLogs - merges the lifecycle action logs of a bucket
+// of several nodes, the daily totals are summed up and only the latest
+// actions are kept.
+func mergeLifecycleActionLogs(bucket string, logs ...madmin.LifecycleActionLog) madmin.LifecycleActionLog {
+	merged := madmin.LifecycleActionLog{Bucket: bucket}
+	days := make(map[time.Time]int)
+	for _, log := range logs {
+		for _, d := range log.Days {
+			idx, ok := days[d.Day]
+			if !ok {
+				idx = len(merged.Days)
+				days[d.Day] = idx
+				merged.Days = append(merged.Days, madmin.LifecycleActionLogDay{
+					Day:     d.Day,
+					Actions: make(map[string]madmin.LifecycleActionSummary),
+				})
+			}
+			for action, summary := range d.Actions {
+				m := merged.Days[idx].Actions[action]
+				m.Count += summary.Count
+				m.Size += summary.Size
+				merged.Days[idx].Actions[action] = m
+			}
+		}
+		merged.Entries = append(merged.Entries, log.Entries...)
+	}
+	sort.Slice(merged.Days, func(i, j int) bool {
+		return merged.Days[i].Day.Before(merged.Days[j].Day)
+	})
+	sort.SliceStable(merged.Entries, func(i, j int) bool {
+		return merged.Entries[i].Time.Before(merged.Entries[j].Time)
+	})
+	if len(merged.Entries) > lifecycleActionLogEntries {
+		merged.Entries = merged.Entries[len(merged.Entries)-lifecycleActionLogEntries:]
+	}
+	return merged
+}
@@ -0,0 +1,103 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func TestLifecycleActionLog(t *testing.T) {
+	today := UTCNow().Truncate(24 * time.Hour)
+	l := &lifecycleActionLog{buckets: make(map[string]*bucketLifecycleActionLog)}
+	l.add("bucket", madmin.LifecycleActionLogEntry{Time: today.Add(-47 * time.Hour), Object: "a", Size: 10, Rule: "rule", Action: madmin.LifecycleActionExpire})
+	l.add("bucket", madmin.LifecycleActionLogEntry{Time: today.Add(time.Hour), Object: "b", Size: 20, Rule: "rule", Action: madmin.LifecycleActionExpire})
+	l.add("bucket", madmin.LifecycleActionLogEntry{Time: today.Add(2 * time.Hour), Object: "c", Size: 30, Rule: "rule", Action: madmin.LifecycleActionTransition})
+	l.add("other", madmin.LifecycleActionLogEntry{Time: today.Add(time.Hour), Object: "d", Size: 40, Rule: "rule", Action: madmin.LifecycleActionExpire})
+
+	log := l.get("bucket", today.Add(-48*time.Hour))
+	if len(log.Days) != 2 || len(log.Entries) != 3 {
+		t.Fatalf("unexpected log %#v", log)
+	}
+	if !log.Days[0].Day.Equal(today.Add(-48*time.Hour)) || log.Days[0].Actions[madmin.LifecycleActionExpire].Count != 1 {
+		t.Fatalf("unexpected first day %#v", log.Days[0])
+	}
+	if s := log.Days[1].Actions[madmin.LifecycleActionExpire]; s.Count != 1 || s.Size != 20 {
+		t.Fatalf("unexpected second day %#v", log.Days[1])
+	}
+
+	// Only the actions since the start of the day are returned.
+	log = l.get("bucket", today.Add(time.Hour))
+	if len(log.Days) != 1 || len(log.Entries) != 2 {
+		t.Fatalf("unexpected log %#v", log)
+	}
+
+	// The logs of the nodes are summed up per day.
+	merged := mergeLifecycleActionLogs("bucket", log, l.get("bucket", today))
+	if len(merged.Days) != 1 || len(merged.Entries) != 4 {
+		t.Fatalf("unexpected merged log %#v", merged)
+	}
+	if s := merged.Days[0].Actions[madmin.LifecycleActionTransition]; s.Count != 2 || s.Size != 60 {
+		t.Fatalf("unexpected merged day %#v", merged.Days[0])
+	}
+
+	l.delete("bucket")
+	if log = l.get("bucket", today); len(log.Days) != 0 {
+		t.Fatalf("expected no actions after bucket removal, got %#v", log)
+	}
+}
+
+func TestLifecycleActionLogSave(t *testing.T) {
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fsDir)
+
+	ctx := context.Background()
+	today := UTCNow().Truncate(24 * time.Hour)
+	l := &lifecycleActionLog{buckets: make(map[string]*bucketLifecycleActionLog)}
+	if err = l.save(ctx, obj, "node"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = loadLifecycleActionLog(ctx, obj, "node"); err != errConfigNotFound {
+		t.Fatalf("expected nothing to be saved without actions, got %v", err)
+	}
+
+	l.add("bucket", madmin.LifecycleActionLogEntry{Time: today.Add(time.Hour), Object: "a", Size: 10, Rule: "rule", Action: madmin.LifecycleActionExpire})
+	if err = l.save(ctx, obj, "node"); err != nil {
+		t.Fatal(err)
+	}
+	state, err := loadLifecycleActionLog(ctx, obj, "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+	log := getBucketLifecycleActionLog(state.Buckets, "bucket", today)
+	if len(log.Days) != 1 || len(log.Entries) != 1 || log.Entries[0].Object != "a" {
+		t.Fatalf("unexpected restored log %#v", log)
+	}
+	if s := log.Days[0].Actions[madmin.LifecycleActionExpire]; s.Count != 1 || s.Size != 10 {
+		t.Fatalf("unexpected restored day %#v", log.Days[0])
+	}
+	if _, err = loadLifecycleActionLog(ctx, obj, "other"); err != errConfigNotFound {
+		t.Fatalf("expected the actions to be saved per node, got %v", err)
+	}
+}
@@ -0,0 +1,227 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/minio/minio/pkg/bucket/lifecycle"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+const (
+	// Number of object names reported per rule and action by default.
+	lifecyclePreviewDefaultSamples = 10
+	// Number of object versions evaluated by default.
+	lifecyclePreviewDefaultMaxObjects = 100000
+)
+
+// lifecycleActionName returns the name of the lifecycle action applied
+// to the object version as reported to admin clients.
+func lifecycleActionName(action lifecycle.Action, oi ObjectInfo) string {
+	switch action {
+	case lifecycle.DeleteAction:
+		return madmin.LifecycleActionExpire
+	case lifecycle.DeleteVersionAction:
+		if oi.DeleteMarker && oi.IsLatest {
+			return madmin.LifecycleActionExpireDeleteMarker
+		}
+		return madmin.LifecycleActionNoncurrentExpire
+	case lifecycle.TransitionAction:
+		return madmin.LifecycleActionTransition
+	case lifecycle.TransitionVersionAction:
+		return madmin.LifecycleActionNoncurrentTransition
+	case lifecycle.DeleteRestoredAction, lifecycle.DeleteRestoredVersionAction:
+		return madmin.LifecycleActionExpireRestored
+	}
+	return ""
+}
+
+// lifecyclePreviewer - accumulates the actions a lifecycle configuration
+// would apply to the object versions it evaluates.
+type lifecyclePreviewer struct {
+	lc          *lifecycle.Lifecycle
+	lockEnabled bool
+	maxSamples  int
+	maxObjects  int
+	preview     madmin.LifecyclePreview
+}
+
+func newLifecyclePreviewer(bucket string, lc *lifecycle.Lifecycle, lockEnabled bool, opts madmin.PreviewLifecycleOptions) *lifecyclePreviewer {
+	p := &lifecyclePreviewer{
+		lc:          lc,
+		lockEnabled: lockEnabled,
+		maxSamples:  opts.MaxSamples,
+		maxObjects:  opts.MaxObjects,
+		preview: madmin.LifecyclePreview{
+			Bucket: bucket,
+			Prefix: opts.Prefix,
+			Rules:  make([]madmin.LifecycleRulePreview, 0, len(lc.Rules)),
+		},
+	}
+	if p.maxSamples <= 0 {
+		p.maxSamples = lifecyclePreviewDefaultSamples
+	}
+	if p.maxObjects <= 0 {
+		p.maxObjects = lifecyclePreviewDefaultMaxObjects
+	}
+	// The rules are reported in the order of the configuration, their
+	// IDs are optional.
+	for _, rule := range lc.Rules {
+		p.preview.Rules = append(p.preview.Rules, madmin.LifecycleRulePreview{
+			ID:      rule.ID,
+			Actions: make(map[string]madmin.LifecycleActionSummary),
+		})
+	}
+	return p
+}
+
+// evaluate evaluates all versions of an object, newest first, the same
+// way the crawler does.
+func (p *lifecyclePreviewer) evaluate(ctx context.Context, versions []ObjectInfo) {
+	var noncurrent noncurrentVersionCounter
+	for i, oi := range versions {
+		p.preview.Scanned++
+		p.preview.ScannedSize += oi.Size
+
+		var successorModTime time.Time
+		if i > 0 {
+			successorModTime = versions[i-1].ModTime
+		}
+		newerNoncurrentVersions := noncurrent.next(oi.DeleteMarker)
+		action, idx := p.lc.ComputeActionRule(lifecycle.ObjectOpts{
+			Name:             oi.Name,
+			UserTags:         oi.UserTags,
+			ModTime:          oi.ModTime,
+			Size:             oi.Size,
+			VersionID:        oi.VersionID,
+			DeleteMarker:     oi.DeleteMarker,
+			IsLatest:         oi.IsLatest,
+			NumVersions:      len(versions),
+			SuccessorModTime: successorModTime,
+			RestoreOngoing:   oi.RestoreOngoing,
+			RestoreExpires:   oi.RestoreExpires,
+			TransitionStatus: oi.TransitionStatus,
+
+			NewerNoncurrentVersions: newerNoncurrentVersions,
+		})
+		switch action {
+		case lifecycle.NoneAction:
+			continue
+		case lifecycle.DeleteVersionAction, lifecycle.DeleteRestoredVersionAction:
+			// The crawler does not remove versions under retention.
+			if p.lockEnabled && enforceRetentionForDeletion(ctx, oi) {
+				continue
+			}
+		}
+		name := lifecycleActionName(action, oi)
+		summary := p.preview.Rules[idx].Actions[name]
+		summary.Count++
+		summary.Size += oi.Size
+		if len(summary.Samples) < p.maxSamples {
+			sample := oi.Name
+			if oi.VersionID != "" && oi.VersionID != nullVersionID {
+				sample += "?versionId=" + oi.VersionID
+			}
+			summary.Samples = append(summary.Samples, sample)
+		}
+		p.preview.Rules[idx].Actions[name] = summary
+	}
+}
+
+// full returns whether the maximum number of object versions has been
+// evaluated.
+func (p *lifecyclePreviewer) full() bool {
+	if p.preview.Scanned >= uint64(p.maxObjects) {
+		p.preview.Truncated = true
+		return true
+	}
+	return false
+}
+
+// previewLifecycle evaluates the lifecycle configuration against the
+// object versions of the bucket under the prefix, without applying any
+// of the resulting actions.
+func previewLifecycle(ctx context.Context, objAPI ObjectLayer, bucket string, lc *lifecycle.Lifecycle, opts madmin.PreviewLifecycleOptions) (madmin.LifecyclePreview, error) {
+	var lockEnabled bool
+	if rcfg, err := globalBucketObjectLockSys.Get(bucket); err == nil {
+		lockEnabled = rcfg.LockEnabled
+	}
+	p := newLifecyclePreviewer(bucket, lc, lockEnabled, opts)
+
+	err := p.evaluateVersions(ctx, objAPI, bucket)
+	if _, ok := err.(NotImplemented); ok {
+		// FS and gateways do not list versions, only the latest
+		// versions are evaluated.
+		err = p.evaluateObjects(ctx, objAPI, bucket)
+	}
+	return p.preview, err
+}
+
+// evaluateVersions evaluates all versions of the objects.
+func (p *lifecyclePreviewer) evaluateVersions(ctx context.Context, objAPI ObjectLayer, bucket string) error {
+	// Versions of an object are listed newest first and may span pages.
+	var versions []ObjectInfo
+	var marker, versionIDMarker string
+	for {
+		loi, err := objAPI.ListObjectVersions(ctx, bucket, p.preview.Prefix, marker, versionIDMarker, "", maxObjectList)
+		if err != nil {
+			return err
+		}
+		for _, oi := range loi.Objects {
+			if len(versions) > 0 && versions[0].Name != oi.Name {
+				p.evaluate(ctx, versions)
+				versions = versions[:0]
+				if p.full() {
+					return nil
+				}
+			}
+			versions = append(versions, oi)
+		}
+		if !loi.IsTruncated {
+			break
+		}
+		marker, versionIDMarker = loi.NextMarker, loi.NextVersionIDMarker
+	}
+	if len(versions) > 0 {
+		p.evaluate(ctx, versions)
+	}
+	return nil
+}
+
+// evaluateObjects evaluates the latest versions of the objects.
+func (p *lifecyclePreviewer) evaluateObjects(ctx context.Context, objAPI ObjectLayer, bucket string) error {
+	var marker string
+	for {
+		loi, err := objAPI.ListObjects(ctx, bucket, p.preview.Prefix, marker, "", maxObjectList)
+		if err != nil {
+			return err
+		}
+		for _, oi := range loi.Objects {
+			if p.full() {
+				return nil
+			}
+			oi.IsLatest = true
+			p.evaluate(ctx, []ObjectInfo{oi})
+		}
+		if !loi.IsTruncated {
+			return nil
+		}
+		marker = loi.NextMarker
+	}
+}
@@ -0,0 +1,144 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/bucket/lifecycle"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// Wrapper for calling lifecycle preview tests for both Erasure multiple disks and single node setup.
+func TestPreviewLifecycle(t *testing.T) {
+	setTestFilesystemPath(t)
+	ExecObjectLayerTest(t, testPreviewLifecycle)
+}
+
+func testPreviewLifecycle(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	ctx := context.Background()
+	bucket := "lifecycle-preview"
+	if err := obj.MakeBucketWithLocation(ctx, bucket, BucketOptions{}); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	objects := map[string]int{
+		"logs/a":    10,
+		"logs/b":    2000,
+		"logs/c":    3000,
+		"outputs/a": 5000,
+		"config/a":  10,
+		"tmp/a":     100,
+	}
+	for name, size := range objects {
+		data := bytes.Repeat([]byte("a"), size)
+		if _, err := obj.PutObject(ctx, bucket, name, mustGetPutObjReader(t, bytes.NewReader(data), int64(size), "", ""), ObjectOptions{}); err != nil {
+			t.Fatalf("%s: %s", instanceType, err)
+		}
+	}
+
+	lc, err := lifecycle.ParseLifecycleConfig(bytes.NewReader([]byte(`<LifecycleConfiguration>` +
+		`<Rule><ID>logs</ID><Filter><Prefix>logs/</Prefix></Filter><Status>Enabled</Status><Expiration><Date>2020-01-01T00:00:00Z</Date></Expiration></Rule>` +
+		`<Rule><ID>bulky</ID><Filter><And><Prefix>outputs/</Prefix><ObjectSizeGreaterThan>1024</ObjectSizeGreaterThan></And></Filter><Status>Enabled</Status><Expiration><Date>2020-01-01T00:00:00Z</Date></Expiration></Rule>` +
+		`<Rule><ID>config</ID><Filter><Prefix>config/</Prefix></Filter><Status>Enabled</Status><Expiration><Days>30</Days></Expiration></Rule>` +
+		`<Rule><Filter><Prefix>tmp/</Prefix></Filter><Status>Enabled</Status><Expiration><Date>2020-01-01T00:00:00Z</Date></Expiration></Rule>` +
+		`</LifecycleConfiguration>`)))
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+
+	preview, err := previewLifecycle(ctx, obj, bucket, lc, madmin.PreviewLifecycleOptions{MaxSamples: 2})
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if preview.Scanned != 6 || preview.Truncated {
+		t.Fatalf("%s: unexpected preview %#v", instanceType, preview)
+	}
+	if len(preview.Rules) != 4 {
+		t.Fatalf("%s: expected 4 rules, got %d", instanceType, len(preview.Rules))
+	}
+	logs := preview.Rules[0].Actions[madmin.LifecycleActionExpire]
+	if logs.Count != 3 || logs.Size != 5010 || len(logs.Samples) != 2 {
+		t.Fatalf("%s: unexpected preview of rule logs %#v", instanceType, logs)
+	}
+	bulky := preview.Rules[1].Actions[madmin.LifecycleActionExpire]
+	if bulky.Count != 1 || bulky.Size != 5000 || bulky.Samples[0] != "outputs/a" {
+		t.Fatalf("%s: unexpected preview of rule bulky %#v", instanceType, bulky)
+	}
+	if len(preview.Rules[2].Actions) != 0 {
+		t.Fatalf("%s: unexpected preview of rule config %#v", instanceType, preview.Rules[2])
+	}
+	// Rules without ID are reported in the order of the configuration.
+	if tmp := preview.Rules[3].Actions[madmin.LifecycleActionExpire]; tmp.Count != 1 || tmp.Size != 100 {
+		t.Fatalf("%s: unexpected preview of the rule without ID %#v", instanceType, preview.Rules[3])
+	}
+
+	// Only the objects under the prefix are evaluated.
+	preview, err = previewLifecycle(ctx, obj, bucket, lc, madmin.PreviewLifecycleOptions{Prefix: "outputs/"})
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if preview.Scanned != 1 || preview.Rules[1].Actions[madmin.LifecycleActionExpire].Count != 1 {
+		t.Fatalf("%s: unexpected preview %#v", instanceType, preview)
+	}
+
+	// The evaluation stops after the requested number of objects.
+	preview, err = previewLifecycle(ctx, obj, bucket, lc, madmin.PreviewLifecycleOptions{MaxObjects: 2})
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if preview.Scanned != 2 || !preview.Truncated {
+		t.Fatalf("%s: unexpected preview %#v", instanceType, preview)
+	}
+}
+
+func TestLifecyclePreviewNewerNoncurrentVersions(t *testing.T) {
+	lc, err := lifecycle.ParseLifecycleConfig(bytes.NewReader([]byte(`<LifecycleConfiguration>` +
+		`<Rule><ID>keep-2</ID><Filter></Filter><Status>Enabled</Status><NoncurrentVersionExpiration><NewerNoncurrentVersions>2</NewerNoncurrentVersions></NoncurrentVersionExpiration></Rule>` +
+		`</LifecycleConfiguration>`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Versions newest first, delete markers are not counted as newer
+	// noncurrent versions so only v0 exceeds the two retained ones.
+	now := UTCNow()
+	var versions []ObjectInfo
+	for i, deleteMarker := range []bool{false, true, false, true, false, false} {
+		versions = append(versions, ObjectInfo{
+			Bucket:       "bucket",
+			Name:         "object",
+			VersionID:    fmt.Sprintf("v%d", 5-i),
+			IsLatest:     i == 0,
+			DeleteMarker: deleteMarker,
+			ModTime:      now.Add(-time.Duration(i) * time.Hour),
+		})
+	}
+
+	p := newLifecyclePreviewer("bucket", lc, false, madmin.PreviewLifecycleOptions{})
+	p.evaluate(context.Background(), versions)
+	if len(p.preview.Rules[0].Actions) != 1 {
+		t.Fatalf("unexpected actions %#v", p.preview.Rules[0].Actions)
+	}
+	expired := p.preview.Rules[0].Actions[madmin.LifecycleActionNoncurrentExpire]
+	if expired.Count != 1 || len(expired.Samples) != 1 || expired.Samples[0] != "object?versionId=v0" {
+		t.Fatalf("unexpected noncurrent expirations %#v", expired)
+	}
+}
@@ -0,0 +1,175 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+
+	miniogo "github.com/minio/minio-go/v7"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// transitionTier - storage the objects of a bucket are transitioned to by
+// the lifecycle rules referring to its target label.
+type transitionTier interface {
+	// Put stores the content of an object version, the version is
+	// opts.Internal.SourceVersionID.
+	Put(ctx context.Context, object string, r io.Reader, size int64, opts miniogo.PutObjectOptions) error
+	// Get returns length bytes of the object version from offset, the
+	// whole content if offset or length is negative.
+	Get(ctx context.Context, object, versionID string, offset, length int64) (io.ReadCloser, error)
+	// Remove removes the object version.
+	Remove(ctx context.Context, object, versionID string) error
+	// Exists returns true if the tier is reachable and its bucket exists.
+	Exists(ctx context.Context) bool
+}
+
+// remoteTransitionTier - bucket of a remote MinIO or S3 service.
+type remoteTransitionTier struct {
+	clnt   *miniogo.Core
+	bucket string
+}
+
+func (t *remoteTransitionTier) Put(ctx context.Context, object string, r io.Reader, size int64, opts miniogo.PutObjectOptions) error {
+	_, err := t.clnt.PutObject(ctx, t.bucket, object, r, size, "", "", opts)
+	return err
+}
+
+func (t *remoteTransitionTier) Get(ctx context.Context, object, versionID string, offset, length int64) (io.ReadCloser, error) {
+	gopts := miniogo.GetObjectOptions{VersionID: versionID}
+	if offset >= 0 && length >= 0 {
+		if err := gopts.SetRange(offset, offset+length-1); err != nil {
+			return nil, err
+		}
+	}
+	reader, _, _, err := t.clnt.GetObject(ctx, t.bucket, object, gopts)
+	return reader, err
+}
+
+func (t *remoteTransitionTier) Remove(ctx context.Context, object, versionID string) error {
+	return t.clnt.RemoveObject(ctx, t.bucket, object, miniogo.RemoveObjectOptions{VersionID: versionID})
+}
+
+func (t *remoteTransitionTier) Exists(ctx context.Context) bool {
+	found, _ := t.clnt.BucketExists(ctx, t.bucket)
+	return found
+}
+
+// localTransitionTier - directory on a local mount, such as a cheaper
+// filesystem or an object store mounted on the same node. Each version
+// is a file named after the version ID in a directory named after the
+// hash of the object name, so that object names never collide with the
+// versions of other objects.
+type localTransitionTier struct {
+	dir string
+}
+
+func newLocalTransitionTier(tgt madmin.BucketTarget) *localTransitionTier {
+	return &localTransitionTier{dir: filepath.Join(tgt.LocalPath, tgt.TargetBucket)}
+}
+
+func (t *localTransitionTier) versionPath(object, versionID string) string {
+	if versionID == "" {
+		versionID = nullVersionID
+	}
+	return pathJoin(t.dir, getSHA256Hash([]byte(object)), versionID)
+}
+
+func (t *localTransitionTier) Put(ctx context.Context, object string, r io.Reader, size int64, opts miniogo.PutObjectOptions) error {
+	// Write to a temporary file first so that readers never see
+	// a partially transitioned version.
+	tmpPath := pathJoin(t.dir, minioMetaTmpBucket, mustGetUUID())
+	if _, err := fsCreateFile(ctx, tmpPath, r, nil, size); err != nil {
+		fsRemoveFile(ctx, tmpPath)
+		return err
+	}
+	if err := fsRenameFile(ctx, tmpPath, t.versionPath(object, opts.Internal.SourceVersionID)); err != nil {
+		fsRemoveFile(ctx, tmpPath)
+		return err
+	}
+	return nil
+}
+
+func (t *localTransitionTier) Get(ctx context.Context, object, versionID string, offset, length int64) (io.ReadCloser, error) {
+	if offset < 0 {
+		offset = 0
+	}
+	reader, _, err := fsOpenFile(ctx, t.versionPath(object, versionID), offset)
+	if err != nil {
+		return nil, err
+	}
+	if length < 0 {
+		return reader, nil
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(reader, length), reader}, nil
+}
+
+func (t *localTransitionTier) Remove(ctx context.Context, object, versionID string) error {
+	err := fsDeleteFile(ctx, t.dir, t.versionPath(object, versionID))
+	if err == errFileNotFound {
+		return nil
+	}
+	return err
+}
+
+func (t *localTransitionTier) Exists(ctx context.Context) bool {
+	if !filepath.IsAbs(t.dir) {
+		return false
+	}
+	_, err := fsStatDir(ctx, t.dir)
+	return err == nil
+}
+
+// localTierOverlapsDrives returns true if the path of a local tier is a
+// drive of the server, is inside one or contains one. Transitioned
+// versions would otherwise be written into the backend.
+func localTierOverlapsDrives(localPath string, endpoints EndpointServerPools) bool {
+	tierPath := resolveLocalPath(localPath)
+	for _, ep := range endpoints {
+		for _, endpoint := range ep.Endpoints {
+			drivePath := resolveLocalPath(endpoint.Path)
+			if isPathWithin(tierPath, drivePath) || isPathWithin(drivePath, tierPath) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// resolveLocalPath returns the cleaned absolute path with symbolic links
+// resolved, as far as the path exists.
+func resolveLocalPath(p string) string {
+	p = filepath.Clean(p)
+	if resolved, err := filepath.EvalSymlinks(p); err == nil {
+		return resolved
+	}
+	return p
+}
+
+// isPathWithin returns true if p is dir or a path inside dir.
+func isPathWithin(p, dir string) bool {
+	if p == dir {
+		return true
+	}
+	return strings.HasPrefix(p, strings.TrimSuffix(dir, string(filepath.Separator))+string(filepath.Separator))
+}
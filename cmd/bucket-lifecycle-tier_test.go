@@ -0,0 +1,284 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	miniogo "github.com/minio/minio-go/v7"
+	"github.com/minio/minio/pkg/bucket/lifecycle"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func TestLocalTransitionTier(t *testing.T) {
+	dir, err := ioutil.TempDir("", "minio-tier-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	tier := newLocalTransitionTier(madmin.BucketTarget{
+		LocalPath:    dir,
+		TargetBucket: "tier",
+		Type:         madmin.ILMLocalService,
+	})
+	if tier.Exists(ctx) {
+		t.Fatal("Expected tier bucket to be missing")
+	}
+	if err = os.Mkdir(tier.dir, 0777); err != nil {
+		t.Fatal(err)
+	}
+	if !tier.Exists(ctx) {
+		t.Fatal("Expected tier bucket to exist")
+	}
+
+	versions := map[string][]byte{
+		"":         []byte("null version"),
+		"version1": []byte("first version"),
+	}
+	for versionID, data := range versions {
+		opts := miniogo.PutObjectOptions{Internal: miniogo.AdvancedPutOptions{SourceVersionID: versionID}}
+		if err = tier.Put(ctx, "dir/object", bytes.NewReader(data), int64(len(data)), opts); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Object names sharing the prefix of another object must not collide.
+	data := []byte("other object")
+	if err = tier.Put(ctx, "dir/object/null", bytes.NewReader(data), int64(len(data)), miniogo.PutObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		object    string
+		versionID string
+		offset    int64
+		length    int64
+		expected  string
+	}{
+		{"dir/object", "", -1, -1, "null version"},
+		{"dir/object", nullVersionID, 0, 4, "null"},
+		{"dir/object", "version1", 6, 7, "version"},
+		{"dir/object", "version1", 6, -1, "version"},
+		{"dir/object/null", "", -1, -1, "other object"},
+	}
+	for i, tc := range testCases {
+		r, err := tier.Get(ctx, tc.object, tc.versionID, tc.offset, tc.length)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		got, err := ioutil.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if string(got) != tc.expected {
+			t.Errorf("Test %d: expected %q, got %q", i+1, tc.expected, string(got))
+		}
+	}
+
+	if err = tier.Remove(ctx, "dir/object", "version1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = tier.Get(ctx, "dir/object", "version1", -1, -1); err != errFileNotFound {
+		t.Fatalf("Expected %v, got %v", errFileNotFound, err)
+	}
+	// Removing a missing version is not an error.
+	if err = tier.Remove(ctx, "dir/object", "version1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = tier.Get(ctx, "dir/object", "", -1, -1); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLocalTierOverlapsDrives(t *testing.T) {
+	dir, err := ioutil.TempDir("", "minio-tier-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	drives := []string{filepath.Join(dir, "drive1"), filepath.Join(dir, "drive2")}
+	endpoints := mustGetZoneEndpoints(drives...)
+	testCases := []struct {
+		localPath string
+		overlaps  bool
+	}{
+		{drives[0], true},
+		{drives[1] + "/", true},
+		{filepath.Join(drives[0], "tier"), true},
+		{dir, true},
+		{filepath.Join(dir, "drive"), false},
+		{filepath.Join(dir, "drive10"), false},
+		{filepath.Join(dir, "tier"), false},
+	}
+	for i, tc := range testCases {
+		if overlaps := localTierOverlapsDrives(tc.localPath, endpoints); overlaps != tc.overlaps {
+			t.Errorf("Test %d: %s expected overlap %v, got %v", i+1, tc.localPath, tc.overlaps, overlaps)
+		}
+	}
+
+	// Symbolic links to the drives are resolved.
+	link := filepath.Join(dir, "link")
+	if err = os.Mkdir(drives[0], 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Symlink(drives[0], link); err != nil {
+		t.Fatal(err)
+	}
+	if !localTierOverlapsDrives(link, endpoints) {
+		t.Errorf("Expected a link to a drive to overlap")
+	}
+}
+
+// Transitions an object to a local tier, reads it from the tier and
+// restores it.
+func TestLocalTierTransition(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	defer setObjectLayer(newObjectLayerFn())
+	newAllSubsystems()
+	obj, fsDirs, err := prepareErasure16(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	defer obj.Shutdown(context.Background())
+	setObjectLayer(obj)
+	if err = newTestConfig(globalMinioDefaultRegion, obj); err != nil {
+		t.Fatal(err)
+	}
+	initAllSubsystems(ctx, obj)
+
+	prevEndpoints := globalEndpoints
+	globalEndpoints = mustGetZoneEndpoints(fsDirs...)
+	defer func() { globalEndpoints = prevEndpoints }()
+
+	bucket := "transition-bucket"
+	object := "dir/object"
+	if err = obj.MakeBucketWithLocation(ctx, bucket, BucketOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	lc := `<LifecycleConfiguration><Rule><ID>transition</ID><Filter><Prefix>dir/</Prefix></Filter><Status>Enabled</Status>` +
+		`<Transition><Days>1</Days><StorageClass>LOCALTIER</StorageClass></Transition></Rule></LifecycleConfiguration>`
+	if err = globalBucketMetadataSys.Update(bucket, bucketLifecycleConfig, []byte(lc)); err != nil {
+		t.Fatal(err)
+	}
+
+	tierDir, err := ioutil.TempDir("", "minio-tier-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tierDir)
+	if err = os.Mkdir(filepath.Join(tierDir, "tier"), 0777); err != nil {
+		t.Fatal(err)
+	}
+
+	// Tiers on the drives of the server are rejected.
+	tgt := madmin.BucketTarget{
+		SourceBucket: bucket,
+		TargetBucket: "tier",
+		LocalPath:    fsDirs[0],
+		Type:         madmin.ILMLocalService,
+		Label:        "LOCALTIER",
+	}
+	tgt.Arn = globalBucketTargetSys.getRemoteARN(bucket, &tgt)
+	if err = globalBucketTargetSys.SetTarget(ctx, bucket, &tgt, false); err == nil {
+		t.Fatalf("Expected a tier on a drive to be rejected")
+	} else if _, ok := err.(BucketRemoteLocalPathInvalid); !ok {
+		t.Fatalf("Expected BucketRemoteLocalPathInvalid, got %v", err)
+	}
+
+	tgt.LocalPath = tierDir
+	tgt.Arn = globalBucketTargetSys.getRemoteARN(bucket, &tgt)
+	if err = globalBucketTargetSys.SetTarget(ctx, bucket, &tgt, false); err != nil {
+		t.Fatal(err)
+	}
+
+	data := bytes.Repeat([]byte("transition"), 100)
+	oi, err := obj.PutObject(ctx, bucket, object, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = transitionObject(ctx, obj, oi); err != nil {
+		t.Fatal(err)
+	}
+	if oi, err = obj.GetObjectInfo(ctx, bucket, object, ObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if oi.TransitionStatus != lifecycle.TransitionComplete {
+		t.Fatalf("Expected the object to be transitioned, got status %q", oi.TransitionStatus)
+	}
+
+	readObject := func(rs *HTTPRangeSpec) []byte {
+		gr, err := obj.GetObjectNInfo(ctx, bucket, object, rs, http.Header{}, readLock, ObjectOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer gr.Close()
+		got, err := ioutil.ReadAll(gr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	gr, err := getTransitionedObjectReader(ctx, bucket, object, &HTTPRangeSpec{Start: 10, End: 19}, http.Header{}, oi, ObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ioutil.ReadAll(gr)
+	gr.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "transition" {
+		t.Fatalf("Expected %q from the tier, got %q", "transition", got)
+	}
+	// Reads of the transitioned object are served by the tier.
+	if got = readObject(nil); !bytes.Equal(got, data) {
+		t.Fatalf("Expected the content of the transitioned object, got %d bytes", len(got))
+	}
+
+	restoreExpiry := time.Now().Add(24 * time.Hour)
+	if err = restoreTransitionedObject(ctx, bucket, object, obj, oi, &RestoreObjectRequest{Days: 1}, restoreExpiry); err != nil {
+		t.Fatal(err)
+	}
+	if oi, err = obj.GetObjectInfo(ctx, bucket, object, ObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if oi.RestoreOngoing || oi.RestoreExpires.IsZero() {
+		t.Fatalf("Expected the object to be restored, got %#v", oi)
+	}
+
+	// The restored copy is served even once the tier is gone.
+	if err = os.RemoveAll(tierDir); err != nil {
+		t.Fatal(err)
+	}
+	if got = readObject(nil); !bytes.Equal(got, data) {
+		t.Fatalf("Expected the content of the restored object, got %d bytes", len(got))
+	}
+}
@@ -41,6 +41,15 @@ import (
 const (
 	// Disabled means the lifecycle rule is inactive
 	Disabled = "Disabled"
+
+	// Reserved metadata of an incomplete multipart upload holding its
+	// bucket and object, uploads are stored under a hash of both which
+	// cannot be mapped back to the lifecycle rules of the bucket.
+	multipartUploadObjectKey = ReservedMetadataPrefix + "Multipart-Object"
+	// Reserved metadata of an incomplete multipart upload holding its
+	// initiation time, the modification time of the upload changes with
+	// every part uploaded.
+	multipartUploadInitiatedKey = ReservedMetadataPrefix + "Multipart-Initiated"
 )
 
 // LifecycleSys - Bucket lifecycle subsystem.
@@ -60,6 +69,38 @@ func (sys *LifecycleSys) Get(bucketName string) (lc *lifecycle.Lifecycle, err er
 	return globalBucketMetadataSys.GetLifecycleConfig(bucketName)
 }
 
+// multipartUploadInitiated returns the initiation time of an incomplete
+// multipart upload, modTime for uploads initiated before it was saved.
+func multipartUploadInitiated(meta map[string]string, modTime time.Time) time.Time {
+	if initiated, err := time.Parse(time.RFC3339Nano, meta[multipartUploadInitiatedKey]); err == nil {
+		return initiated
+	}
+	return modTime
+}
+
+// isMultipartUploadExpired returns true if the incomplete multipart upload
+// is to be removed. AbortIncompleteMultipartUpload rules of the bucket
+// matching the upload take precedence and count from the initiation of
+// the upload, otherwise the upload is removed once it was not updated
+// within expiry.
+func isMultipartUploadExpired(meta map[string]string, modTime time.Time, expiry time.Duration, now time.Time) bool {
+	if objPath, ok := meta[multipartUploadObjectKey]; ok && globalLifecycleSys != nil {
+		initiated := multipartUploadInitiated(meta, modTime)
+		bucket, object := path2BucketObject(objPath)
+		if lc, err := globalLifecycleSys.Get(bucket); err == nil {
+			abortTime := lc.AbortIncompleteMultipartUploadTime(lifecycle.ObjectOpts{
+				Name:     object,
+				UserTags: meta[xhttp.AmzObjectTagging],
+				ModTime:  initiated,
+			})
+			if !abortTime.IsZero() {
+				return now.After(abortTime)
+			}
+		}
+	}
+	return now.Sub(modTime) > expiry
+}
+
 // NewLifecycleSys - creates new lifecycle system.
 func NewLifecycleSys() *LifecycleSys {
 	return &LifecycleSys{}
@@ -155,17 +196,22 @@ func validateTransitionDestination(ctx context.Context, bucket string, targetLab
 	if err != nil {
 		return false, "", BucketRemoteTargetNotFound{Bucket: bucket}
 	}
-	if arn.Type != madmin.ILMService {
+	if !arn.Type.IsTransition() {
 		return false, "", BucketRemoteArnTypeInvalid{}
 	}
-	clnt := globalBucketTargetSys.GetRemoteTargetClient(ctx, tgt.Arn)
-	if clnt == nil {
+	tier := globalBucketTargetSys.GetTransitionTier(ctx, bucket, tgt.Arn)
+	if tier == nil {
 		return false, "", BucketRemoteTargetNotFound{Bucket: bucket}
 	}
-	if found, _ := clnt.BucketExists(ctx, arn.Bucket); !found {
+	if !tier.Exists(ctx) {
 		return false, "", BucketRemoteDestinationNotFound{Bucket: arn.Bucket}
 	}
-	sameTarget, _ := isLocalHost(clnt.EndpointURL().Hostname(), clnt.EndpointURL().Port(), globalMinioPort)
+	rtier, ok := tier.(*remoteTransitionTier)
+	if !ok {
+		// local tiers are directories outside of the buckets of this server
+		return false, arn.Bucket, nil
+	}
+	sameTarget, _ := isLocalHost(rtier.clnt.EndpointURL().Hostname(), rtier.clnt.EndpointURL().Port(), globalMinioPort)
 	return sameTarget, arn.Bucket, nil
 }
 
@@ -258,8 +304,8 @@ func deleteTransitionedObject(ctx context.Context, objectAPI ObjectLayer, bucket
 	if arn == nil {
 		return fmt.Errorf("remote target not configured")
 	}
-	tgt := globalBucketTargetSys.GetRemoteTargetClient(ctx, arn.String())
-	if tgt == nil {
+	tier := globalBucketTargetSys.GetTransitionTier(ctx, bucket, arn.String())
+	if tier == nil {
 		return fmt.Errorf("remote target not configured")
 	}
 
@@ -277,7 +323,7 @@ func deleteTransitionedObject(ctx context.Context, objectAPI ObjectLayer, bucket
 	case lifecycle.DeleteAction, lifecycle.DeleteVersionAction:
 		// When an object is past expiry, delete the data from transitioned tier and
 		// metadata from source
-		if err := tgt.RemoveObject(context.Background(), arn.Bucket, object, miniogo.RemoveObjectOptions{VersionID: lcOpts.VersionID}); err != nil {
+		if err := tier.Remove(context.Background(), object, lcOpts.VersionID); err != nil {
 			logger.LogIf(ctx, err)
 		}
 
@@ -322,13 +368,14 @@ func transitionObject(ctx context.Context, objectAPI ObjectLayer, objInfo Object
 	lcOpts := lifecycle.ObjectOpts{
 		Name:     objInfo.Name,
 		UserTags: objInfo.UserTags,
+		Size:     objInfo.Size,
 	}
 	arn := getLifecycleTransitionTargetArn(ctx, lc, objInfo.Bucket, lcOpts)
 	if arn == nil {
 		return fmt.Errorf("remote target not configured")
 	}
-	tgt := globalBucketTargetSys.GetRemoteTargetClient(ctx, arn.String())
-	if tgt == nil {
+	tier := globalBucketTargetSys.GetTransitionTier(ctx, objInfo.Bucket, arn.String())
+	if tier == nil {
 		return fmt.Errorf("remote target not configured")
 	}
 
@@ -346,7 +393,8 @@ func transitionObject(ctx context.Context, objectAPI ObjectLayer, objInfo Object
 	}
 
 	putOpts := putTransitionOpts(oi)
-	if _, err = tgt.PutObject(ctx, arn.Bucket, oi.Name, gr, oi.Size, "", "", putOpts); err != nil {
+	if err = tier.Put(ctx, oi.Name, gr, oi.Size, putOpts); err != nil {
+		gr.Close()
 		return err
 	}
 	gr.Close()
@@ -396,6 +444,7 @@ func getTransitionedObjectReader(ctx context.Context, bucket, object string, rs
 		Name:         object,
 		UserTags:     oi.UserTags,
 		ModTime:      oi.ModTime,
+		Size:         oi.Size,
 		VersionID:    oi.VersionID,
 		DeleteMarker: oi.DeleteMarker,
 		IsLatest:     oi.IsLatest,
@@ -403,24 +452,17 @@ func getTransitionedObjectReader(ctx context.Context, bucket, object string, rs
 	if arn == nil {
 		return nil, fmt.Errorf("remote target not configured")
 	}
-	tgt := globalBucketTargetSys.GetRemoteTargetClient(ctx, arn.String())
-	if tgt == nil {
+	tier := globalBucketTargetSys.GetTransitionTier(ctx, bucket, arn.String())
+	if tier == nil {
 		return nil, fmt.Errorf("remote target not configured")
 	}
 	fn, off, length, err := NewGetObjectReader(rs, oi, opts)
 	if err != nil {
 		return nil, ErrorRespToObjectError(err, bucket, object)
 	}
-	gopts := miniogo.GetObjectOptions{VersionID: opts.VersionID}
 
 	// get correct offsets for encrypted object
-	if off >= 0 && length >= 0 {
-		if err := gopts.SetRange(off, off+length-1); err != nil {
-			return nil, ErrorRespToObjectError(err, bucket, object)
-		}
-	}
-
-	reader, _, _, err := tgt.GetObject(ctx, arn.Bucket, object, gopts)
+	reader, err := tier.Get(ctx, object, opts.VersionID, off, length)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,102 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+// Wrapper for calling abort incomplete multipart upload tests for both
+// Erasure multiple disks and single node setup.
+func TestAbortIncompleteMultipartUploads(t *testing.T) {
+	setTestFilesystemPath(t)
+	ExecObjectLayerTest(t, testAbortIncompleteMultipartUploads)
+}
+
+// removeStaleUploads - runs the stale uploads sweeper of the object layer
+// once at now.
+func removeStaleUploads(ctx context.Context, obj ObjectLayer, expiry time.Duration, now time.Time) {
+	switch z := obj.(type) {
+	case *FSObjects:
+		z.removeStaleUploads(ctx, expiry, now)
+	case *erasureServerPools:
+		for _, pool := range z.serverPools {
+			for _, set := range pool.sets {
+				set.cleanupStaleUploads(ctx, expiry, now)
+			}
+		}
+	}
+}
+
+func testAbortIncompleteMultipartUploads(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	ctx := context.Background()
+	bucket := "abort-multipart"
+	if err := obj.MakeBucketWithLocation(ctx, bucket, BucketOptions{}); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	lc := `<LifecycleConfiguration><Rule><ID>uploads</ID><Filter><Prefix>uploads/</Prefix></Filter><Status>Enabled</Status>` +
+		`<AbortIncompleteMultipartUpload><DaysAfterInitiation>2</DaysAfterInitiation></AbortIncompleteMultipartUpload></Rule></LifecycleConfiguration>`
+	if err := globalBucketMetadataSys.Update(bucket, bucketLifecycleConfig, []byte(lc)); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+
+	matching, err := obj.NewMultipartUpload(ctx, bucket, "uploads/object", ObjectOptions{})
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	other, err := obj.NewMultipartUpload(ctx, bucket, "other/object", ObjectOptions{})
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	data := bytes.Repeat([]byte("a"), 1024)
+	if _, err = obj.PutObjectPart(ctx, bucket, "uploads/object", matching, 1, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{}); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+
+	exists := func(object, uploadID string) bool {
+		_, err := obj.ListObjectParts(ctx, bucket, object, uploadID, 0, 1, ObjectOptions{})
+		return err == nil
+	}
+
+	expiry := 7 * 24 * time.Hour
+	removeStaleUploads(ctx, obj, expiry, time.Now())
+	if !exists("uploads/object", matching) || !exists("other/object", other) {
+		t.Fatalf("%s: expected uploads not to be removed before the rule applies", instanceType)
+	}
+
+	// The rule counts from the initiation of the upload, parts uploaded
+	// later do not postpone it.
+	removeStaleUploads(ctx, obj, expiry, time.Now().Add(3*24*time.Hour))
+	if exists("uploads/object", matching) {
+		t.Fatalf("%s: expected the upload matching the rule to be removed", instanceType)
+	}
+	if !exists("other/object", other) {
+		t.Fatalf("%s: expected the upload not matching the rule to be kept", instanceType)
+	}
+
+	initiated := time.Now().Add(-3 * 24 * time.Hour)
+	meta := map[string]string{
+		multipartUploadObjectKey:    pathJoin(bucket, "uploads/object"),
+		multipartUploadInitiatedKey: initiated.Format(time.RFC3339Nano),
+	}
+	if !isMultipartUploadExpired(meta, time.Now(), expiry, time.Now()) {
+		t.Fatalf("%s: expected an upload initiated 3 days ago and updated now to be expired", instanceType)
+	}
+}
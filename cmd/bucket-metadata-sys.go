@@ -27,6 +27,7 @@ import (
 	"github.com/minio/minio/cmd/crypto"
 	"github.com/minio/minio/cmd/logger"
 	bucketsse "github.com/minio/minio/pkg/bucket/encryption"
+	"github.com/minio/minio/pkg/bucket/inventory"
 	"github.com/minio/minio/pkg/bucket/lifecycle"
 	objectlock "github.com/minio/minio/pkg/bucket/object/lock"
 	"github.com/minio/minio/pkg/bucket/policy"
@@ -51,6 +52,8 @@ func (sys *BucketMetadataSys) Remove(bucket string) {
 	sys.Lock()
 	delete(sys.metadataMap, bucket)
 	globalBucketMonitor.DeleteBucket(bucket)
+	globalLifecycleActionLog.delete(bucket)
+	globalBucketQuotaSys.deleteBucket(bucket)
 	sys.Unlock()
 }
 
@@ -168,6 +171,8 @@ func (sys *BucketMetadataSys) Update(bucket string, configFile string, configDat
 			return NotImplemented{}
 		}
 		meta.ReplicationConfigXML = configData
+	case bucketInventoryConfig:
+		meta.InventoryConfigXML = configData
 	case bucketTargetsFile:
 		meta.BucketTargetsConfigJSON, meta.BucketTargetsConfigMetaJSON, err = encryptBucketMetadata(meta.Name, configData, crypto.Context{bucket: meta.Name, bucketTargetsFile: bucketTargetsFile})
 		if err != nil {
@@ -388,6 +393,22 @@ func (sys *BucketMetadataSys) GetBucketTarget(bucket string, arn string) (madmin
 	return madmin.BucketTarget{}, errConfigNotFound
 }
 
+// GetInventoryConfig returns all configured inventory configurations
+// The returned object may not be modified.
+func (sys *BucketMetadataSys) GetInventoryConfig(bucket string) (*inventory.Configs, error) {
+	meta, err := sys.GetConfig(bucket)
+	if err != nil {
+		if errors.Is(err, errConfigNotFound) {
+			return &inventory.Configs{}, nil
+		}
+		return nil, err
+	}
+	if meta.inventoryConfig == nil {
+		return &inventory.Configs{}, nil
+	}
+	return meta.inventoryConfig, nil
+}
+
 // GetConfig returns a specific configuration from the bucket metadata.
 // The returned object may not be modified.
 func (sys *BucketMetadataSys) GetConfig(bucket string) (BucketMetadata, error) {
@@ -32,6 +32,7 @@ import (
 	"github.com/minio/minio/cmd/crypto"
 	"github.com/minio/minio/cmd/logger"
 	bucketsse "github.com/minio/minio/pkg/bucket/encryption"
+	"github.com/minio/minio/pkg/bucket/inventory"
 	"github.com/minio/minio/pkg/bucket/lifecycle"
 	objectlock "github.com/minio/minio/pkg/bucket/object/lock"
 	"github.com/minio/minio/pkg/bucket/policy"
@@ -78,6 +79,7 @@ type BucketMetadata struct {
 	ReplicationConfigXML        []byte
 	BucketTargetsConfigJSON     []byte
 	BucketTargetsConfigMetaJSON []byte
+	InventoryConfigXML          []byte
 
 	// Unexported fields. Must be updated atomically.
 	policyConfig           *policy.Policy
@@ -91,6 +93,7 @@ type BucketMetadata struct {
 	replicationConfig      *replication.Config
 	bucketTargetConfig     *madmin.BucketTargets
 	bucketTargetConfigMeta map[string]string
+	inventoryConfig        *inventory.Configs
 }
 
 // newBucketMetadata creates BucketMetadata with the supplied name and Created to Now.
@@ -247,6 +250,15 @@ func (b *BucketMetadata) parseAllConfigs(ctx context.Context, objectAPI ObjectLa
 	} else {
 		b.bucketTargetConfig = &madmin.BucketTargets{}
 	}
+
+	if len(b.InventoryConfigXML) != 0 {
+		b.inventoryConfig, err = inventory.ParseConfigs(bytes.NewReader(b.InventoryConfigXML))
+		if err != nil {
+			return err
+		}
+	} else {
+		b.inventoryConfig = nil
+	}
 	return nil
 }
 
@@ -372,6 +384,7 @@ func deleteBucketMetadata(ctx context.Context, obj objectDeleter, bucket string)
 	metadataFiles := []string{
 		dataUsageCacheName,
 		bucketMetadataFile,
+		bucketInventoryStateFile,
 	}
 	for _, metaFile := range metadataFiles {
 		configFile := path.Join(bucketConfigPrefix, bucket, metaFile)
@@ -26,9 +26,11 @@ import (
 
 	jsoniter "github.com/json-iterator/go"
 	miniogopolicy "github.com/minio/minio-go/v7/pkg/policy"
+	"github.com/minio/minio-go/v7/pkg/tags"
 	xhttp "github.com/minio/minio/cmd/http"
 	"github.com/minio/minio/cmd/logger"
 	"github.com/minio/minio/pkg/bucket/policy"
+	"github.com/minio/minio/pkg/bucket/policy/condition"
 	"github.com/minio/minio/pkg/handlers"
 )
 
@@ -42,9 +44,13 @@ func (sys *PolicySys) Get(bucket string) (*policy.Policy, error) {
 
 // IsAllowed - checks given policy args is allowed to continue the Rest API.
 func (sys *PolicySys) IsAllowed(args policy.Args) bool {
+	return sys.isAllowed(args, &policyDecision{})
+}
+
+func (sys *PolicySys) isAllowed(args policy.Args, d *policyDecision) bool {
 	p, err := sys.Get(args.BucketName)
 	if err == nil {
-		return p.IsAllowed(args)
+		return d.evaluateBucketPolicy(args, p)
 	}
 
 	// Log unhandled errors.
@@ -54,7 +60,20 @@ func (sys *PolicySys) IsAllowed(args policy.Args) bool {
 
 	// As policy is not available for given bucket name, returns IsOwner i.e.
 	// operation is allowed only for owner.
-	return args.IsOwner
+	if args.IsOwner {
+		return d.allow("the owner is allowed without a bucket policy")
+	}
+	return d.deny("no policy of bucket %s is available: %v", args.BucketName, err)
+}
+
+// HasConditionKey - returns whether the policy of the bucket has a
+// condition on the key for the action.
+func (sys *PolicySys) HasConditionKey(bucket string, action policy.Action, key condition.Key) bool {
+	p, err := sys.Get(bucket)
+	if err != nil {
+		return false
+	}
+	return p.HasConditionKey(action, key)
 }
 
 // NewPolicySys - creates new policy system.
@@ -145,6 +164,15 @@ func getConditionValues(r *http.Request, lc string, username string, claims map[
 		}
 	}
 
+	// Object tags sent along with the object.
+	if tagging := r.Header.Get(xhttp.AmzObjectTagging); tagging != "" {
+		if t, err := tags.ParseObjectTags(tagging); err == nil {
+			addRequestTagConditionValues(args, t.ToMap())
+		}
+	}
+
+	addTagConditionValues(args, condition.AWSPrincipalTag, globalIAMSys.PrincipalTags(username, claims))
+
 	// JWT specific values
 	for k, v := range claims {
 		vStr, ok := v.(string)
@@ -161,6 +189,56 @@ func getConditionValues(r *http.Request, lc string, username string, claims map[
 	return args
 }
 
+// addTagConditionValues - adds the tags as the condition values of the
+// tag condition key, such as "ExistingObjectTag/<tag-key>".
+func addTagConditionValues(args map[string][]string, key condition.Key, tagMap map[string]string) {
+	for k, v := range tagMap {
+		args[key.TagKey(k).Name()] = []string{v}
+	}
+}
+
+// addRequestTagConditionValues - adds the object tags sent in a request
+// as the condition values of the request tag condition keys.
+func addRequestTagConditionValues(args map[string][]string, tagMap map[string]string) {
+	addTagConditionValues(args, condition.S3RequestObjectTag, tagMap)
+	keys := make([]string, 0, len(tagMap))
+	for k := range tagMap {
+		keys = append(keys, k)
+	}
+	args[condition.S3RequestObjectTagKeys.Name()] = keys
+}
+
+// sessionTagsClaim is the claim of the session tags in the tokens of
+// OpenID providers, as defined for AssumeRoleWithWebIdentity by AWS.
+const sessionTagsClaim = "https://aws.amazon.com/tags"
+
+// sessionTagsFromClaims - returns the session tags in the claims, such as
+// {"https://aws.amazon.com/tags": {"principal_tags": {"project": ["blue"]}}}.
+func sessionTagsFromClaims(claims map[string]interface{}) map[string]string {
+	tagMap := make(map[string]string)
+	sessionTags, ok := claims[sessionTagsClaim].(map[string]interface{})
+	if !ok {
+		return tagMap
+	}
+	principalTags, ok := sessionTags["principal_tags"].(map[string]interface{})
+	if !ok {
+		return tagMap
+	}
+	for k, v := range principalTags {
+		switch values := v.(type) {
+		case string:
+			tagMap[k] = values
+		case []interface{}:
+			if len(values) > 0 {
+				if value, ok := values[0].(string); ok {
+					tagMap[k] = value
+				}
+			}
+		}
+	}
+	return tagMap
+}
+
 // PolicyToBucketAccessPolicy converts a MinIO policy into a minio-go policy data structure.
 func PolicyToBucketAccessPolicy(bucketPolicy *policy.Policy) (*miniogopolicy.BucketAccessPolicy, error) {
 	// Return empty BucketAccessPolicy for empty bucket policy.
@@ -0,0 +1,78 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+
+	xhttp "github.com/minio/minio/cmd/http"
+)
+
+func TestGetConditionValuesRequestTags(t *testing.T) {
+	r, err := http.NewRequest(http.MethodPut, "http://localhost:9000/bucket/object", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set(xhttp.AmzObjectTagging, "project=blue&team=storage")
+
+	values := getConditionValues(r, "", "", nil)
+	if v := values["RequestObjectTag/project"]; !reflect.DeepEqual(v, []string{"blue"}) {
+		t.Fatalf("expected: %v, got: %v", []string{"blue"}, v)
+	}
+	keys := values["RequestObjectTagKeys"]
+	sort.Strings(keys)
+	if !reflect.DeepEqual(keys, []string{"project", "team"}) {
+		t.Fatalf("expected: %v, got: %v", []string{"project", "team"}, keys)
+	}
+
+	r.Header.Del(xhttp.AmzObjectTagging)
+	values = getConditionValues(r, "", "", nil)
+	if _, ok := values["RequestObjectTagKeys"]; ok {
+		t.Fatal("expected no request tag keys without tags")
+	}
+}
+
+func TestSessionTagsFromClaims(t *testing.T) {
+	testCases := []struct {
+		claims   map[string]interface{}
+		expected map[string]string
+	}{
+		{nil, map[string]string{}},
+		{map[string]interface{}{sessionTagsClaim: "invalid"}, map[string]string{}},
+		{
+			map[string]interface{}{
+				sessionTagsClaim: map[string]interface{}{
+					"principal_tags": map[string]interface{}{
+						"project": []interface{}{"blue"},
+						"team":    "storage",
+						"invalid": []interface{}{},
+					},
+				},
+			},
+			map[string]string{"project": "blue", "team": "storage"},
+		},
+	}
+
+	for i, testCase := range testCases {
+		if tagMap := sessionTagsFromClaims(testCase.claims); !reflect.DeepEqual(tagMap, testCase.expected) {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expected, tagMap)
+		}
+	}
+}
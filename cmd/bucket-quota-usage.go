@@ -0,0 +1,325 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+)
+
+const (
+	// Reserved metadata of the objects written by a user with a quota.
+	quotaUserKey = ReservedMetadataPrefix + "Quota-User"
+	// Reserved metadata of the objects written by a member of groups
+	// with a quota, JSON encoded since group names may contain commas.
+	quotaGroupsKey = ReservedMetadataPrefix + "Quota-Groups"
+)
+
+// quotaUsage - size and number of objects counted against a quota.
+type quotaUsage struct {
+	Size    int64 `json:"size"`
+	Objects int64 `json:"objects"`
+}
+
+func (u quotaUsage) add(d quotaUsage) quotaUsage {
+	return quotaUsage{Size: u.Size + d.Size, Objects: u.Objects + d.Objects}
+}
+
+// prefixQuotaUsage - usage of a prefix with a quota, counted by listing
+// the prefix once and then updated on every write and delete.
+type prefixQuotaUsage struct {
+	quotaUsage
+	listed   bool
+	listing  bool
+	listedAt time.Time
+	// Changes made while the prefix is being listed.
+	pending quotaUsage
+}
+
+// quotaUsageTracker - usage counted against the quotas of prefixes since
+// their listing, the usage of buckets, users and groups is tracked by
+// globalRealtimeUsage.
+type quotaUsageTracker struct {
+	mu       sync.Mutex
+	prefixes map[string]*prefixQuotaUsage
+	// Highest alert level reached by each quota.
+	alerts map[string]int
+}
+
+func newQuotaUsageTracker() *quotaUsageTracker {
+	return &quotaUsageTracker{
+		prefixes: make(map[string]*prefixQuotaUsage),
+		alerts:   make(map[string]int),
+	}
+}
+
+func prefixQuotaKey(bucket, prefix string) string {
+	return bucket + SlashSeparator + prefix
+}
+
+// update adds the change of usage of a write or delete to the prefixes.
+func (t *quotaUsageTracker) update(bucket string, prefixes []string, d quotaUsage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, prefix := range prefixes {
+		p, ok := t.prefixes[prefixQuotaKey(bucket, prefix)]
+		if !ok {
+			continue
+		}
+		p.quotaUsage = p.quotaUsage.add(d)
+		if p.listing {
+			p.pending = p.pending.add(d)
+		}
+	}
+}
+
+// prefixUsage returns the usage of the prefix and false if it was never
+// listed yet, the prefix is listed in the background on first use and
+// again once a crawl completed after the listing.
+func (t *quotaUsageTracker) prefixUsage(objAPI ObjectLayer, bucket, prefix string, crawled time.Time) (quotaUsage, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := prefixQuotaKey(bucket, prefix)
+	p, ok := t.prefixes[key]
+	if !ok {
+		p = &prefixQuotaUsage{}
+		t.prefixes[key] = p
+	}
+	if !p.listing && (!p.listed || p.listedAt.Before(crawled)) {
+		p.listing = true
+		p.pending = quotaUsage{}
+		go t.listPrefix(objAPI, bucket, prefix, p)
+	}
+	return p.quotaUsage, p.listed
+}
+
+func (t *quotaUsageTracker) listPrefix(objAPI ObjectLayer, bucket, prefix string, p *prefixQuotaUsage) {
+	started := UTCNow()
+
+	var u quotaUsage
+	objInfoCh := make(chan ObjectInfo)
+	err := objAPI.Walk(GlobalContext, bucket, prefix, objInfoCh, ObjectOptions{})
+	if err == nil {
+		for oi := range objInfoCh {
+			u.Size += quotaObjectSize(oi)
+			u.Objects++
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p.listing = false
+	if err != nil {
+		logger.LogIf(GlobalContext, err)
+		return
+	}
+	p.quotaUsage = u.add(p.pending)
+	p.listed = true
+	p.listedAt = started
+}
+
+// alert records the alert level reached by a quota and returns true if it
+// is higher than the level previously recorded.
+func (t *quotaUsageTracker) alert(key string, level int) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	raised := level > t.alerts[key]
+	if level == 0 {
+		delete(t.alerts, key)
+	} else {
+		t.alerts[key] = level
+	}
+	return raised
+}
+
+// deleteBucket forgets the usage of a removed bucket.
+func (t *quotaUsageTracker) deleteBucket(bucket string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for key := range t.prefixes {
+		if HasPrefix(key, bucket+SlashSeparator) {
+			delete(t.prefixes, key)
+		}
+	}
+}
+
+// quotaOwners returns the user and groups with a quota stamped on the
+// object when it was written.
+func quotaOwners(metadata map[string]string) (user string, groups []string) {
+	user = metadata[quotaUserKey]
+	if v, ok := metadata[quotaGroupsKey]; ok {
+		if err := json.Unmarshal([]byte(v), &groups); err != nil {
+			groups = nil
+		}
+	}
+	return user, groups
+}
+
+// setQuotaOwners stamps the user and groups with a quota on the metadata
+// of an object being written, stale stamps copied from a source object
+// are removed.
+func setQuotaOwners(metadata map[string]string, user string, groups []string) {
+	delete(metadata, quotaUserKey)
+	delete(metadata, quotaGroupsKey)
+	if user != "" {
+		metadata[quotaUserKey] = user
+	}
+	if len(groups) > 0 {
+		if v, err := json.Marshal(groups); err == nil {
+			metadata[quotaGroupsKey] = string(v)
+		}
+	}
+}
+
+const (
+	// Usage of the users and groups with a quota as counted after the
+	// last crawl.
+	quotaOwnersUsagePath = realtimeUsagePrefix + SlashSeparator + "owners.json"
+
+	quotaOwnersUsageVersion1 = 1
+)
+
+// quotaOwnersUsage - usage of the objects written by the users and groups
+// with a quota, counted from the owners stamped on the objects.
+type quotaOwnersUsage struct {
+	Version int                   `json:"version"`
+	Updated time.Time             `json:"updated"`
+	Users   map[string]quotaUsage `json:"users"`
+	Groups  map[string]quotaUsage `json:"groups"`
+}
+
+func newQuotaOwnersUsage() quotaOwnersUsage {
+	return quotaOwnersUsage{
+		Version: quotaOwnersUsageVersion1,
+		Users:   make(map[string]quotaUsage),
+		Groups:  make(map[string]quotaUsage),
+	}
+}
+
+// usage returns the usage of a user, or of a group.
+func (o quotaOwnersUsage) usage(name string, isGroup bool) quotaUsage {
+	if isGroup {
+		return o.Groups[name]
+	}
+	return o.Users[name]
+}
+
+// add adds the change of usage to the owners of the object.
+func (o quotaOwnersUsage) add(oi ObjectInfo, d quotaUsage) {
+	user, groups := quotaOwners(oi.UserDefined)
+	if user != "" {
+		o.Users[user] = o.Users[user].add(d)
+	}
+	for _, group := range groups {
+		o.Groups[group] = o.Groups[group].add(d)
+	}
+}
+
+// countQuotaOwnersUsage counts the usage of the owners stamped on the
+// objects of all buckets. Versions of an object count as a single object
+// of the owners of its oldest version, like they are counted on write.
+func countQuotaOwnersUsage(ctx context.Context, objAPI ObjectLayer) (quotaOwnersUsage, error) {
+	usage := newQuotaOwnersUsage()
+	usage.Updated = UTCNow()
+
+	buckets, err := objAPI.ListBuckets(ctx)
+	if err != nil {
+		return usage, err
+	}
+	for _, bucket := range buckets {
+		// Buckets with versioning suspended keep their versions too.
+		objInfoCh := make(chan ObjectInfo)
+		if err = objAPI.Walk(ctx, bucket.Name, "", objInfoCh, ObjectOptions{WalkVersions: true}); err != nil {
+			return usage, err
+		}
+		var oldest ObjectInfo
+		for oi := range objInfoCh {
+			if oi.DeleteMarker {
+				continue
+			}
+			if oldest.Name != "" && oi.Name != oldest.Name {
+				usage.add(oldest, quotaUsage{Objects: 1})
+			}
+			usage.add(oi, quotaUsage{Size: quotaObjectSize(oi)})
+			oldest = oi
+		}
+		if oldest.Name != "" {
+			usage.add(oldest, quotaUsage{Objects: 1})
+		}
+	}
+	return usage, nil
+}
+
+func saveQuotaOwnersUsage(ctx context.Context, objAPI ObjectLayer, usage quotaOwnersUsage) error {
+	data, err := json.Marshal(usage)
+	if err != nil {
+		return err
+	}
+	return saveConfig(ctx, objAPI, quotaOwnersUsagePath, data)
+}
+
+func loadQuotaOwnersUsage(ctx context.Context, objAPI ObjectLayer) (quotaOwnersUsage, error) {
+	usage := newQuotaOwnersUsage()
+	data, err := readConfig(ctx, objAPI, quotaOwnersUsagePath)
+	if err != nil {
+		return usage, err
+	}
+	if err = json.Unmarshal(data, &usage); err != nil {
+		return usage, err
+	}
+	if usage.Users == nil {
+		usage.Users = make(map[string]quotaUsage)
+	}
+	if usage.Groups == nil {
+		usage.Groups = make(map[string]quotaUsage)
+	}
+	return usage, nil
+}
+
+var quotaOwnersCountRunning int32
+
+// startQuotaOwnersCount counts the usage of the users and groups with a
+// quota in the background, the servers count their changes of usage from
+// this count on.
+func startQuotaOwnersCount(ctx context.Context, objAPI ObjectLayer) {
+	iq := globalBucketQuotaSys.getIAMQuotas()
+	if len(iq.Users) == 0 && len(iq.Groups) == 0 {
+		return
+	}
+	if !atomic.CompareAndSwapInt32(&quotaOwnersCountRunning, 0, 1) {
+		return
+	}
+	go func() {
+		defer atomic.StoreInt32(&quotaOwnersCountRunning, 0)
+		usage, err := countQuotaOwnersUsage(ctx, objAPI)
+		if err != nil {
+			logger.LogIf(ctx, err)
+			return
+		}
+		logger.LogIf(ctx, saveQuotaOwnersUsage(ctx, objAPI, usage))
+	}()
+}
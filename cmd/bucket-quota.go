@@ -20,6 +20,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/minio/minio/cmd/logger"
@@ -27,9 +29,24 @@ import (
 	"github.com/minio/minio/pkg/madmin"
 )
 
+// Duration for which the quota configurations are cached.
+const quotaConfigCacheTTL = 10 * time.Second
+
 // BucketQuotaSys - map of bucket and quota configuration.
 type BucketQuotaSys struct {
-	bucketStorageCache timedValue
+	usage *quotaUsageTracker
+
+	mu              sync.Mutex
+	configs         map[string]cachedBucketQuota
+	iamQuotas       IAMQuotas
+	iamQuotasExpiry time.Time
+}
+
+// cachedBucketQuota - quota configuration of a bucket, reloaded from the
+// bucket metadata once expired.
+type cachedBucketQuota struct {
+	quota  *madmin.BucketQuota
+	expiry time.Time
 }
 
 // Get - Get quota configuration.
@@ -38,12 +55,80 @@ func (sys *BucketQuotaSys) Get(bucketName string) (*madmin.BucketQuota, error) {
 	if objAPI == nil {
 		return nil, errServerNotInitialized
 	}
-	return &madmin.BucketQuota{}, nil
+	if globalIsGateway {
+		return &madmin.BucketQuota{}, nil
+	}
+
+	sys.mu.Lock()
+	c, ok := sys.configs[bucketName]
+	sys.mu.Unlock()
+	if ok && UTCNow().Before(c.expiry) {
+		return c.quota, nil
+	}
+
+	q, err := globalBucketMetadataSys.GetQuotaConfig(bucketName)
+	if err != nil {
+		return nil, err
+	}
+	if q == nil {
+		q = &madmin.BucketQuota{}
+	}
+
+	sys.mu.Lock()
+	sys.configs[bucketName] = cachedBucketQuota{quota: q, expiry: UTCNow().Add(quotaConfigCacheTTL)}
+	sys.mu.Unlock()
+	return q, nil
+}
+
+// getIAMQuotas returns the quotas of the users and groups.
+func (sys *BucketQuotaSys) getIAMQuotas() IAMQuotas {
+	sys.mu.Lock()
+	q, expiry := sys.iamQuotas, sys.iamQuotasExpiry
+	sys.mu.Unlock()
+	if UTCNow().Before(expiry) || globalIAMSys == nil {
+		return q
+	}
+
+	ctx, done := context.WithTimeout(GlobalContext, 5*time.Second)
+	defer done()
+	q, err := globalIAMSys.GetQuotas(ctx)
+	if err != nil {
+		return q
+	}
+
+	sys.mu.Lock()
+	sys.iamQuotas, sys.iamQuotasExpiry = q, UTCNow().Add(quotaConfigCacheTTL)
+	sys.mu.Unlock()
+	return q
+}
+
+// reset forgets the cached quota configurations, of the bucket or of
+// the users and groups if bucket is empty.
+func (sys *BucketQuotaSys) reset(bucket string) {
+	sys.mu.Lock()
+	defer sys.mu.Unlock()
+
+	if bucket == "" {
+		sys.iamQuotasExpiry = time.Time{}
+		return
+	}
+	delete(sys.configs, bucket)
+}
+
+// deleteBucket forgets the quota configuration and usage of a removed
+// bucket.
+func (sys *BucketQuotaSys) deleteBucket(bucket string) {
+	sys.reset(bucket)
+	sys.usage.deleteBucket(bucket)
+	globalRealtimeUsage.deleteBucket(bucket)
 }
 
 // NewBucketQuotaSys returns initialized BucketQuotaSys
 func NewBucketQuotaSys() *BucketQuotaSys {
-	return &BucketQuotaSys{}
+	return &BucketQuotaSys{
+		usage:   newQuotaUsageTracker(),
+		configs: make(map[string]cachedBucketQuota),
+	}
 }
 
 // parseBucketQuota parses BucketQuota from json
@@ -58,56 +143,397 @@ func parseBucketQuota(bucket string, data []byte) (quotaCfg *madmin.BucketQuota,
 	return
 }
 
-func (sys *BucketQuotaSys) check(ctx context.Context, bucket string, size int64) error {
+// Kinds of quotas an object counts against.
+const (
+	bucketQuotaKind = "bucket"
+	prefixQuotaKind = "prefix"
+	userQuotaKind   = "user"
+	groupQuotaKind  = "group"
+)
+
+// quotaLimit - a quota an object counts against.
+type quotaLimit struct {
+	kind    string
+	bucket  string
+	name    string // Prefix, user or group
+	typ     madmin.QuotaType
+	quota   uint64
+	objects uint64
+	alerts  []int
+}
+
+// String returns the name of the quota as reported in alerts.
+func (l quotaLimit) String() string {
+	if l.kind == bucketQuotaKind {
+		return l.kind
+	}
+	return l.kind + ":" + l.name
+}
+
+// key identifies the quota among the quotas of all buckets.
+func (l quotaLimit) key() string {
+	if l.kind == bucketQuotaKind || l.kind == prefixQuotaKind {
+		return l.bucket + SlashSeparator + l.String()
+	}
+	return l.String()
+}
+
+// exceeded returns true if writing an object of the given size exceeds
+// the quota, only hard quotas are ever exceeded.
+func (l quotaLimit) exceeded(u quotaUsage, size int64) bool {
+	if l.typ != madmin.HardQuota {
+		return false
+	}
+	if l.quota > 0 && float64(u.Size)+float64(size) > float64(l.quota) {
+		return true
+	}
+	return l.objects > 0 && float64(u.Objects+1) > float64(l.objects)
+}
+
+// level returns the highest alert threshold reached by the usage, zero
+// if none is.
+func (l quotaLimit) level(u quotaUsage) int {
+	alerts := l.alerts
+	if len(alerts) == 0 && l.typ == madmin.SoftQuota {
+		alerts = madmin.DefaultQuotaAlerts
+	}
+	var level int
+	for _, alert := range alerts {
+		if alert <= level {
+			continue
+		}
+		reached := l.quota > 0 && float64(u.Size)*100 >= float64(l.quota)*float64(alert)
+		reached = reached || l.objects > 0 && float64(u.Objects)*100 >= float64(l.objects)*float64(alert)
+		if reached {
+			level = alert
+		}
+	}
+	return level
+}
+
+// err returns the error of a write exceeding the quota.
+func (l quotaLimit) err() error {
+	switch l.kind {
+	case prefixQuotaKind:
+		return BucketQuotaExceeded{Bucket: l.bucket, Object: l.name}
+	case userQuotaKind, groupQuotaKind:
+		return UserQuotaExceeded{Name: l.name, Group: l.kind == groupQuotaKind}
+	}
+	return BucketQuotaExceeded{Bucket: l.bucket}
+}
+
+// owners returns the user making the request if it has a quota, and the
+// groups with a quota it is a member of. Objects written by temporary
+// users and service accounts count against the quotas of their parent.
+func (sys *BucketQuotaSys) owners(ctx context.Context) (user string, groups []string) {
+	iq := sys.getIAMQuotas()
+	if len(iq.Users) == 0 && len(iq.Groups) == 0 {
+		return "", nil
+	}
+	reqInfo := logger.GetReqInfo(ctx)
+	if reqInfo == nil || reqInfo.AccessKey == "" {
+		return "", nil
+	}
+
+	name := reqInfo.AccessKey
+	var memberOf []string
+	if cred, ok := globalIAMSys.GetUser(name); ok {
+		if cred.ParentUser != "" {
+			name = cred.ParentUser
+		}
+		memberOf = append(memberOf, cred.Groups...)
+	}
+	memberOf = append(memberOf, globalIAMSys.GetUserGroups(name)...)
+
+	if _, ok := iq.Users[name]; ok {
+		user = name
+	}
+	for _, group := range memberOf {
+		if _, ok := iq.Groups[group]; ok && !contains(groups, group) {
+			groups = append(groups, group)
+		}
+	}
+	return user, groups
+}
+
+// limits returns the quotas an object of the bucket written by the user
+// and groups counts against.
+func (sys *BucketQuotaSys) limits(bucket, object, user string, groups []string) []quotaLimit {
+	var limits []quotaLimit
+	if q, err := sys.Get(bucket); err == nil {
+		if q.Quota > 0 || q.Objects > 0 {
+			limits = append(limits, quotaLimit{
+				kind:    bucketQuotaKind,
+				bucket:  bucket,
+				typ:     q.Type,
+				quota:   q.Quota,
+				objects: q.Objects,
+				alerts:  q.Alerts,
+			})
+		}
+		for _, p := range q.Prefixes {
+			if !HasPrefix(object, p.Prefix) {
+				continue
+			}
+			limits = append(limits, quotaLimit{
+				kind:    prefixQuotaKind,
+				bucket:  bucket,
+				name:    p.Prefix,
+				typ:     p.Type,
+				quota:   p.Quota,
+				objects: p.Objects,
+				alerts:  p.Alerts,
+			})
+		}
+	}
+	if user == "" && len(groups) == 0 {
+		return limits
+	}
+
+	iq := sys.getIAMQuotas()
+	if q, ok := iq.Users[user]; ok {
+		limits = append(limits, quotaLimit{
+			kind:    userQuotaKind,
+			bucket:  bucket,
+			name:    user,
+			typ:     q.Type,
+			quota:   q.Quota,
+			objects: q.Objects,
+			alerts:  q.Alerts,
+		})
+	}
+	for _, group := range groups {
+		if q, ok := iq.Groups[group]; ok {
+			limits = append(limits, quotaLimit{
+				kind:    groupQuotaKind,
+				bucket:  bucket,
+				name:    group,
+				typ:     q.Type,
+				quota:   q.Quota,
+				objects: q.Objects,
+				alerts:  q.Alerts,
+			})
+		}
+	}
+	return limits
+}
+
+// usageOf returns the usage counted against the quota, false if it is
+// not known yet.
+func (sys *BucketQuotaSys) usageOf(objAPI ObjectLayer, l quotaLimit) (quotaUsage, bool) {
+	switch l.kind {
+	case bucketQuotaKind:
+		// bucket usage unknown until the first crawl, cannot enforce
+		// quota.
+		return globalRealtimeUsage.bucketUsage(GlobalContext, objAPI, l.bucket)
+	case prefixQuotaKind:
+		crawled := globalRealtimeUsage.getCrawled(GlobalContext, objAPI)
+		return sys.usage.prefixUsage(objAPI, l.bucket, l.name, crawled.LastUpdate)
+	}
+	return globalRealtimeUsage.ownerUsage(GlobalContext, objAPI, l.name, l.kind == groupQuotaKind), true
+}
+
+func (sys *BucketQuotaSys) check(ctx context.Context, bucket, object string, size int64) error {
 	objAPI := newObjectLayerFn()
 	if objAPI == nil {
 		return errServerNotInitialized
 	}
 
-	sys.bucketStorageCache.Once.Do(func() {
-		sys.bucketStorageCache.TTL = 1 * time.Second
-		sys.bucketStorageCache.Update = func() (interface{}, error) {
-			ctx, done := context.WithTimeout(context.Background(), 5*time.Second)
-			defer done()
-			return loadDataUsageFromBackend(ctx, objAPI)
+	user, groups := sys.owners(ctx)
+	for _, l := range sys.limits(bucket, object, user, groups) {
+		if l.typ != madmin.HardQuota {
+			continue
 		}
-	})
+		u, ok := sys.usageOf(objAPI, l)
+		if !ok {
+			continue
+		}
+		if l.exceeded(u, size) {
+			sendEvent(eventArgs{
+				EventName:  event.QuotaExceeded,
+				BucketName: bucket,
+				Object:     ObjectInfo{Bucket: bucket, Name: object},
+				ReqParams:  map[string]string{"quota": l.String()},
+				Host:       "Internal: [QUOTA]",
+			})
+			return l.err()
+		}
+	}
+	return nil
+}
 
-	q, err := sys.Get(bucket)
-	if err != nil {
-		return err
+// alert raises an alert for every quota of the object whose usage reached
+// a higher threshold than before.
+func (sys *BucketQuotaSys) alert(ctx context.Context, bucket, object, user string, groups []string) {
+	objAPI := newObjectLayerFn()
+	if objAPI == nil {
+		return
 	}
 
-	if q != nil && q.Type == madmin.HardQuota && q.Quota > 0 {
-		v, err := sys.bucketStorageCache.Get()
-		if err != nil {
-			return err
+	for _, l := range sys.limits(bucket, object, user, groups) {
+		u, ok := sys.usageOf(objAPI, l)
+		if !ok {
+			continue
 		}
+		level := l.level(u)
+		if !sys.usage.alert(l.key(), level) {
+			continue
+		}
+		logger.LogIf(ctx, fmt.Errorf("%s quota of bucket %s reached %d%% (%d bytes, %d objects)",
+			l, bucket, level, u.Size, u.Objects))
+		sendEvent(eventArgs{
+			EventName:  event.QuotaThresholdReached,
+			BucketName: bucket,
+			Object:     ObjectInfo{Bucket: bucket, Name: object},
+			ReqParams: map[string]string{
+				"quota":     l.String(),
+				"threshold": strconv.Itoa(level),
+			},
+			Host: "Internal: [QUOTA]",
+		})
+	}
+}
+
+// record adds the change of usage of an object version written or
+// removed to the usage of the bucket and to the quotas of its prefixes
+// and owners.
+func (sys *BucketQuotaSys) record(ctx context.Context, bucket string, oi ObjectInfo, d quotaUsage) {
+	var prefixes []string
+	if q, err := sys.Get(bucket); err == nil {
+		for _, p := range q.Prefixes {
+			if HasPrefix(oi.Name, p.Prefix) {
+				prefixes = append(prefixes, p.Prefix)
+			}
+		}
+	}
+	user, groups := quotaOwners(oi.UserDefined)
+	sys.usage.update(bucket, prefixes, d)
+	if objAPI := newObjectLayerFn(); objAPI != nil {
+		globalRealtimeUsage.update(ctx, objAPI, bucket, oi.Name, user, groups, d)
+	}
+	sys.alert(ctx, bucket, oi.Name, user, groups)
+}
 
-		dui := v.(DataUsageInfo)
+// quotaObjectSize returns the size of the object counted against quotas,
+// the size before compression and encryption.
+func quotaObjectSize(oi ObjectInfo) int64 {
+	size, err := oi.GetActualSize()
+	if err != nil {
+		return oi.Size
+	}
+	return size
+}
 
-		bui, ok := dui.BucketsUsage[bucket]
-		if !ok {
-			// bucket not found, cannot enforce quota
-			// call will fail anyways later.
-			return nil
+// quotaWrite - an object being written, counted against its quotas once
+// the write completes.
+type quotaWrite struct {
+	bucket   string
+	replaced *ObjectInfo
+}
+
+// beginWrite stamps the owners with a quota on the metadata of the object
+// being written, metadata may be nil when the object is already stamped.
+// If the usage of the bucket is tracked, the object layer reports the
+// object replaced by the write once it holds the object lock, so that
+// concurrent writes of the object never replace the same version.
+func (sys *BucketQuotaSys) beginWrite(ctx context.Context, bucket, object string, metadata map[string]string, opts *ObjectOptions) *quotaWrite {
+	user, groups := sys.owners(ctx)
+	if metadata != nil {
+		setQuotaOwners(metadata, user, groups)
+	}
+	if !sys.tracksUsage(bucket) {
+		return nil
+	}
+
+	w := &quotaWrite{bucket: bucket}
+	opts.ReplacedObjectFn = func(oi ObjectInfo) {
+		if !oi.DeleteMarker {
+			w.replaced = &oi
 		}
+	}
+	return w
+}
 
-		if (bui.Size + uint64(size)) >= q.Quota {
-			return BucketQuotaExceeded{Bucket: bucket}
+// done counts the object written, the version it replaced in an
+// unversioned bucket is not counted anymore. Versions of an object
+// count as a single object.
+func (w *quotaWrite) done(ctx context.Context, oi ObjectInfo) {
+	if w == nil {
+		return
+	}
+
+	d := quotaUsage{Size: quotaObjectSize(oi), Objects: 1}
+	if w.replaced != nil {
+		if !globalBucketVersioningSys.Enabled(w.bucket) {
+			globalBucketQuotaSys.record(ctx, w.bucket, *w.replaced, quotaUsage{Size: -quotaObjectSize(*w.replaced), Objects: -1})
+		} else {
+			d.Objects = 0
 		}
 	}
+	globalBucketQuotaSys.record(ctx, w.bucket, oi, d)
+}
 
-	return nil
+// tracksUsage returns true if the usage of the bucket is updated on
+// every write and delete: when realtime usage is enabled, the bucket has
+// a quota or users and groups have quotas across buckets. Otherwise the
+// objects replaced and removed are not looked up.
+func (sys *BucketQuotaSys) tracksUsage(bucket string) bool {
+	if globalRealtimeUsage.isEnabled() {
+		return true
+	}
+	if q, err := sys.Get(bucket); err == nil && (q.Quota > 0 || q.Objects > 0 || len(q.Prefixes) > 0) {
+		return true
+	}
+	iq := sys.getIAMQuotas()
+	return len(iq.Users) > 0 || len(iq.Groups) > 0
 }
 
-func enforceBucketQuota(ctx context.Context, bucket string, size int64) error {
-	//if size < 0 {
-	//	return nil
-	//}
-	//return globalBucketQuotaSys.check(ctx, bucket, size)
+// objectDeleted stops counting a removed object version against its
+// quotas, the object is counted until its last version is removed.
+func (sys *BucketQuotaSys) objectDeleted(ctx context.Context, bucket string, oi ObjectInfo) {
+	if oi.DeleteMarker || oi.Name == "" || !sys.tracksUsage(bucket) {
+		return
+	}
+	d := quotaUsage{Size: -quotaObjectSize(oi), Objects: -1}
+	if globalBucketVersioningSys.Enabled(bucket) || globalBucketVersioningSys.Suspended(bucket) {
+		if objAPI := newObjectLayerFn(); objAPI == nil || objectVersionsRemain(ctx, objAPI, bucket, oi.Name) {
+			d.Objects = 0
+		}
+	}
+	sys.record(ctx, bucket, oi, d)
+}
 
-	return nil
+// objectVersionsRemain returns true if versions of the object other than
+// delete markers remain, or if it cannot tell.
+func objectVersionsRemain(ctx context.Context, objAPI ObjectLayer, bucket, object string) bool {
+	var marker, versionMarker string
+	for {
+		loi, err := objAPI.ListObjectVersions(ctx, bucket, object, marker, versionMarker, "", maxObjectList)
+		if err != nil {
+			logger.LogIf(ctx, err)
+			return true
+		}
+		for _, oi := range loi.Objects {
+			if oi.Name != object {
+				// Versions are listed by object name.
+				return false
+			}
+			if !oi.DeleteMarker {
+				return true
+			}
+		}
+		if !loi.IsTruncated {
+			return false
+		}
+		marker, versionMarker = loi.NextMarker, loi.NextVersionIDMarker
+	}
+}
+
+func enforceBucketQuota(ctx context.Context, bucket, object string, size int64) error {
+	if size < 0 {
+		return nil
+	}
+	return globalBucketQuotaSys.check(ctx, bucket, object, size)
 }
 
 // enforceFIFOQuota deletes objects in FIFO order until sufficient objects
@@ -0,0 +1,255 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func TestQuotaLimit(t *testing.T) {
+	testCases := []struct {
+		limit    quotaLimit
+		usage    quotaUsage
+		size     int64
+		exceeded bool
+		level    int
+	}{
+		// Hard quota on size.
+		{quotaLimit{typ: madmin.HardQuota, quota: 100}, quotaUsage{Size: 50}, 50, false, 0},
+		{quotaLimit{typ: madmin.HardQuota, quota: 100}, quotaUsage{Size: 50}, 51, true, 0},
+		{quotaLimit{typ: madmin.HardQuota, quota: 100, alerts: []int{50, 90}}, quotaUsage{Size: 95}, 1, false, 90},
+		// Hard quota on the number of objects.
+		{quotaLimit{typ: madmin.HardQuota, objects: 10}, quotaUsage{Objects: 9}, 1 << 40, false, 0},
+		{quotaLimit{typ: madmin.HardQuota, objects: 10}, quotaUsage{Objects: 10}, 0, true, 0},
+		// Soft quotas are never exceeded, alerts default to 80, 90 and 100%.
+		{quotaLimit{typ: madmin.SoftQuota, quota: 100}, quotaUsage{Size: 200}, 1, false, 100},
+		{quotaLimit{typ: madmin.SoftQuota, quota: 100}, quotaUsage{Size: 85}, 1, false, 80},
+		{quotaLimit{typ: madmin.SoftQuota, quota: 100}, quotaUsage{Size: 79}, 1, false, 0},
+		{quotaLimit{typ: madmin.SoftQuota, objects: 10, alerts: []int{50}}, quotaUsage{Objects: 5}, 0, false, 50},
+	}
+
+	for i, testCase := range testCases {
+		if exceeded := testCase.limit.exceeded(testCase.usage, testCase.size); exceeded != testCase.exceeded {
+			t.Errorf("Test %d: expected exceeded %v, got %v", i+1, testCase.exceeded, exceeded)
+		}
+		if level := testCase.limit.level(testCase.usage); level != testCase.level {
+			t.Errorf("Test %d: expected level %d, got %d", i+1, testCase.level, level)
+		}
+	}
+}
+
+func TestQuotaUsageTracker(t *testing.T) {
+	tr := newQuotaUsageTracker()
+	tr.prefixes[prefixQuotaKey("bucket", "a/")] = &prefixQuotaUsage{listed: true, listing: true}
+
+	tr.update("bucket", []string{"a/"}, quotaUsage{Size: 10, Objects: 1})
+	tr.update("bucket", []string{"b/"}, quotaUsage{Size: 5, Objects: 1})
+
+	p := tr.prefixes[prefixQuotaKey("bucket", "a/")]
+	if p.quotaUsage != (quotaUsage{Size: 10, Objects: 1}) || p.pending != p.quotaUsage {
+		t.Errorf("unexpected prefix usage %v pending %v", p.quotaUsage, p.pending)
+	}
+	if _, ok := tr.prefixes[prefixQuotaKey("bucket", "b/")]; ok {
+		t.Error("unexpected usage of prefix without quota")
+	}
+
+	// Alerts are only raised when a higher level is reached.
+	if !tr.alert("bucket/bucket", 80) {
+		t.Error("expected alert at 80%")
+	}
+	if tr.alert("bucket/bucket", 80) {
+		t.Error("unexpected alert at 80% again")
+	}
+	if !tr.alert("bucket/bucket", 90) {
+		t.Error("expected alert at 90%")
+	}
+	tr.alert("bucket/bucket", 0)
+	if !tr.alert("bucket/bucket", 80) {
+		t.Error("expected alert at 80% after usage dropped")
+	}
+}
+
+func TestQuotaOwners(t *testing.T) {
+	metadata := map[string]string{
+		quotaUserKey:   "stale",
+		"content-type": "text/plain",
+	}
+	setQuotaOwners(metadata, "", []string{"cn=dev,ou=groups,dc=example,dc=com"})
+	user, groups := quotaOwners(metadata)
+	if user != "" {
+		t.Errorf("expected stale user to be removed, got %s", user)
+	}
+	if !reflect.DeepEqual(groups, []string{"cn=dev,ou=groups,dc=example,dc=com"}) {
+		t.Errorf("unexpected groups %v", groups)
+	}
+
+	setQuotaOwners(metadata, "alice", nil)
+	user, groups = quotaOwners(metadata)
+	if user != "alice" || groups != nil {
+		t.Errorf("unexpected owners %s %v", user, groups)
+	}
+}
+
+// Wrapper for calling quota owners usage tests for both Erasure multiple
+// disks and single node setup.
+func TestQuotaOwnersUsage(t *testing.T) {
+	setTestFilesystemPath(t)
+	ExecObjectLayerTest(t, testQuotaOwnersUsage)
+}
+
+func testQuotaOwnersUsage(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	ctx := context.Background()
+	bucket := "quota-owners"
+	if err := obj.MakeBucketWithLocation(ctx, bucket, BucketOptions{}); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	_, versioned := obj.(*erasureServerPools)
+
+	put := func(object, user string, groups []string, size int) {
+		metadata := make(map[string]string)
+		setQuotaOwners(metadata, user, groups)
+		data := bytes.Repeat([]byte("a"), size)
+		_, err := obj.PutObject(ctx, bucket, object, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""),
+			ObjectOptions{UserDefined: metadata, Versioned: versioned})
+		if err != nil {
+			t.Fatalf("%s: %s", instanceType, err)
+		}
+	}
+	put("alice/object", "alice", []string{"dev"}, 10)
+	put("bob/object", "", []string{"dev"}, 5)
+	put("other/object", "", nil, 100)
+
+	usage, err := countQuotaOwnersUsage(ctx, obj)
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if u := usage.usage("alice", false); u != (quotaUsage{Size: 10, Objects: 1}) {
+		t.Errorf("%s: unexpected user usage %v", instanceType, u)
+	}
+	if u := usage.usage("dev", true); u != (quotaUsage{Size: 15, Objects: 2}) {
+		t.Errorf("%s: unexpected group usage %v", instanceType, u)
+	}
+
+	if err = saveQuotaOwnersUsage(ctx, obj, usage); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	loaded, err := loadQuotaOwnersUsage(ctx, obj)
+	if err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if !reflect.DeepEqual(loaded.Users, usage.Users) || !reflect.DeepEqual(loaded.Groups, usage.Groups) {
+		t.Errorf("%s: expected %v, got %v", instanceType, usage, loaded)
+	}
+
+	if !versioned {
+		return
+	}
+
+	// Versions of an object count as a single object.
+	put("alice/object", "alice", []string{"dev"}, 20)
+	if usage, err = countQuotaOwnersUsage(ctx, obj); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if u := usage.usage("alice", false); u != (quotaUsage{Size: 30, Objects: 1}) {
+		t.Errorf("%s: unexpected usage of versions %v", instanceType, u)
+	}
+
+	loi, err := obj.ListObjectVersions(ctx, bucket, "alice/object", "", "", "", maxObjectList)
+	if err != nil || len(loi.Objects) != 2 {
+		t.Fatalf("%s: expected 2 versions, got %v (%v)", instanceType, loi.Objects, err)
+	}
+	if _, err = obj.DeleteObject(ctx, bucket, "alice/object", ObjectOptions{VersionID: loi.Objects[0].VersionID, Versioned: true}); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if !objectVersionsRemain(ctx, obj, bucket, "alice/object") {
+		t.Errorf("%s: expected the older version to remain", instanceType)
+	}
+	if _, err = obj.DeleteObject(ctx, bucket, "alice/object", ObjectOptions{Versioned: true}); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if !objectVersionsRemain(ctx, obj, bucket, "alice/object") {
+		t.Errorf("%s: expected the version behind the delete marker to remain", instanceType)
+	}
+	if _, err = obj.DeleteObject(ctx, bucket, "alice/object", ObjectOptions{VersionID: loi.Objects[1].VersionID, Versioned: true}); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+	if objectVersionsRemain(ctx, obj, bucket, "alice/object") {
+		t.Errorf("%s: expected no version to remain", instanceType)
+	}
+}
+
+func TestQuotaTracksUsage(t *testing.T) {
+	sys := NewBucketQuotaSys()
+	if sys.tracksUsage("bucket") {
+		t.Fatal("expected the usage not to be tracked without quotas")
+	}
+	var opts ObjectOptions
+	if w := sys.beginWrite(context.Background(), "bucket", "object", nil, &opts); w != nil || opts.ReplacedObjectFn != nil {
+		t.Fatal("expected the replaced object not to be looked up without quotas")
+	}
+
+	globalRealtimeUsage.setEnabled(true)
+	if !sys.tracksUsage("bucket") {
+		t.Fatal("expected the usage to be tracked with realtime usage enabled")
+	}
+	globalRealtimeUsage.setEnabled(false)
+
+	sys.iamQuotas = IAMQuotas{Users: map[string]madmin.UserQuota{"alice": {Quota: 1}}}
+	sys.iamQuotasExpiry = UTCNow().Add(time.Hour)
+	if !sys.tracksUsage("bucket") {
+		t.Fatal("expected the usage to be tracked with user quotas")
+	}
+}
+
+// Wrapper for calling replaced object tests for both Erasure multiple
+// disks and single node setup.
+func TestQuotaWriteReplacedObject(t *testing.T) {
+	setTestFilesystemPath(t)
+	ExecObjectLayerTest(t, testQuotaWriteReplacedObject)
+}
+
+func testQuotaWriteReplacedObject(obj ObjectLayer, instanceType string, t TestErrHandler) {
+	ctx := context.Background()
+	bucket := "quota-replaced"
+	if err := obj.MakeBucketWithLocation(ctx, bucket, BucketOptions{}); err != nil {
+		t.Fatalf("%s: %s", instanceType, err)
+	}
+
+	globalRealtimeUsage.setEnabled(true)
+	defer globalRealtimeUsage.setEnabled(false)
+
+	// The object layer reports the object replaced by the write.
+	for i, size := range []int{5, 7} {
+		var opts ObjectOptions
+		w := globalBucketQuotaSys.beginWrite(ctx, bucket, "object", nil, &opts)
+		if _, err := obj.PutObject(ctx, bucket, "object", mustGetPutObjReader(t, bytes.NewReader(make([]byte, size)), int64(size), "", ""), opts); err != nil {
+			t.Fatalf("%s: %s", instanceType, err)
+		}
+		switch {
+		case i == 0 && w.replaced != nil:
+			t.Fatalf("%s: expected no replaced object, got %v", instanceType, w.replaced)
+		case i == 1 && (w.replaced == nil || w.replaced.Size != 5):
+			t.Fatalf("%s: expected the first object to be replaced, got %v", instanceType, w.replaced)
+		}
+	}
+}
@@ -0,0 +1,713 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	minio "github.com/minio/minio-go/v7"
+	"github.com/minio/minio/cmd/crypto"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/bucket/replication"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+const (
+	// Prefix under the meta bucket holding the journals of the nodes.
+	replicationJournalPrefix = minioConfigPrefix + SlashSeparator + "replication-journal"
+	replicationPendingDir    = "pending"
+	replicationFailedDir     = "failed"
+	replicationTaskExt       = ".task"
+
+	// Maximum number of tasks held in the journal, anything beyond
+	// this is dropped and left for the crawler to pick up again.
+	replicationQueueLimit = 100000
+
+	// Number of attempts before a task is moved to the dead-letter set.
+	replicationMaxAttempts = 10
+
+	// Backoff applied between attempts of a task or a target.
+	replicationBaseBackoff = time.Second
+	replicationMaxBackoff  = 30 * time.Minute
+
+	// Dead-lettered tasks are not re-queued by the crawler until this
+	// interval has elapsed since they were dead-lettered.
+	replicationFailedRetryInterval = 24 * time.Hour
+
+	// Interval at which due tasks are handed to the workers.
+	replicationDispatchInterval = 5 * time.Second
+
+	// Maximum number of dead-lettered objects reported per bucket.
+	replicationMaxFailedObjects = 100
+)
+
+type replicationOp string
+
+const (
+	replicationOpPut    replicationOp = "put"
+	replicationOpDelete replicationOp = "delete"
+)
+
+// replicationTask is a journal entry of a replication operation which
+// has not completed yet.
+type replicationTask struct {
+	Op        replicationOp             `json:"op"`
+	Bucket    string                    `json:"bucket"`
+	Object    string                    `json:"object"`
+	VersionID string                    `json:"versionId,omitempty"`
+	Size      int64                     `json:"size,omitempty"`
+	Delete    *DeletedObjectVersionInfo `json:"delete,omitempty"`
+
+	Queued      time.Time `json:"queued"`
+	Attempts    int       `json:"attempts"`
+	NextAttempt time.Time `json:"nextAttempt"`
+	Target      string    `json:"target,omitempty"`
+	LastError   string    `json:"lastError,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+	Failed      time.Time `json:"failed,omitempty"`
+
+	// ID of the resync which queued the task and the target which is
+	// replicated to even if the object was replicated to it before.
+	Resync       string `json:"resync,omitempty"`
+	ResyncTarget string `json:"resyncTarget,omitempty"`
+
+	// ARNs of the targets the operation is pending for, all targets
+	// of the bucket if empty.
+	Targets []string `json:"targets,omitempty"`
+}
+
+// key uniquely identifies a task, the same object version queued
+// several times maps to a single journal entry.
+func (t replicationTask) key() string {
+	return getSHA256Hash([]byte(strings.Join([]string{string(t.Op), t.Bucket, t.Object, t.VersionID}, SlashSeparator)))
+}
+
+// replicationTargetError is returned when the remote target of a
+// replication could not be reached, such errors back off the whole
+// target instead of counting towards the attempts of a task.
+type replicationTargetError struct {
+	Arn string
+	Err error
+}
+
+func (e replicationTargetError) Error() string {
+	return fmt.Sprintf("replication target %s unreachable: %v", e.Arn, e.Err)
+}
+
+func (e replicationTargetError) Unwrap() error {
+	return e.Err
+}
+
+// replicationTargetUnreachable is the failure reason of the tasks whose
+// target could not be reached.
+const replicationTargetUnreachable = "XMinioReplicationTargetUnreachable"
+
+// replicationFailureReason returns the error code reported as the failure
+// reason of a task, unlike the error message it does not depend on the
+// object or the request so that failures are counted per cause.
+func replicationFailureReason(err error) string {
+	var terr replicationTargetError
+	if errors.As(err, &terr) {
+		return replicationTargetUnreachable
+	}
+	var rerr minio.ErrorResponse
+	if errors.As(err, &rerr) && rerr.Code != "" {
+		return rerr.Code
+	}
+	for errors.Unwrap(err) != nil {
+		err = errors.Unwrap(err)
+	}
+	return toAPIError(GlobalContext, err).Code
+}
+
+// replicationPartialError is returned when an operation failed for some
+// of the targets only, which are retried while the others are left alone.
+type replicationPartialError struct {
+	Arns []string
+	Err  error
+}
+
+func (e replicationPartialError) Error() string {
+	return e.Err.Error()
+}
+
+func (e replicationPartialError) Unwrap() error {
+	return e.Err
+}
+
+// replicationBackoff returns the delay before the next attempt after
+// n consecutive failures.
+func replicationBackoff(n int) time.Duration {
+	d := replicationBaseBackoff
+	for i := 1; i < n; i++ {
+		d *= 2
+		if d >= replicationMaxBackoff {
+			return replicationMaxBackoff
+		}
+	}
+	return d
+}
+
+// targetBackoff tracks consecutive connectivity failures of a target.
+type targetBackoff struct {
+	failures int
+	until    time.Time
+}
+
+// replicationJournal persists the replication tasks of a node through
+// the object layer so that pending work survives restarts. Updates are
+// recorded under the lock of the queue and written in batches by a
+// single writer, tasks queued right before a crash are recovered by the
+// crawler.
+type replicationJournal struct {
+	objAPI ObjectLayer
+	prefix string
+
+	mu      sync.Mutex
+	updates map[replicationJournalEntry]*replicationTask
+	notify  chan struct{}
+}
+
+// replicationJournalEntry identifies an object of the journal.
+type replicationJournalEntry struct {
+	dir string
+	key string
+}
+
+// newReplicationJournal returns the journal of the tasks of node.
+func newReplicationJournal(objAPI ObjectLayer, node string) *replicationJournal {
+	return &replicationJournal{
+		objAPI:  objAPI,
+		prefix:  pathJoin(replicationJournalPrefix, getSHA256Hash([]byte(node))),
+		updates: make(map[replicationJournalEntry]*replicationTask),
+		notify:  make(chan struct{}, 1),
+	}
+}
+
+// path - returns the path of the task under the meta bucket.
+func (j *replicationJournal) path(dir string, key string) string {
+	return pathJoin(j.prefix, dir, key+replicationTaskExt)
+}
+
+// update - records that the task must be written to the journal
+// sub-directory, or removed from it if t is nil. Only the last update
+// of a task is written.
+func (j *replicationJournal) update(dir string, key string, t *replicationTask) {
+	if t != nil {
+		// The queue keeps modifying its tasks, write a copy.
+		tc := *t
+		t = &tc
+	}
+
+	j.mu.Lock()
+	j.updates[replicationJournalEntry{dir: dir, key: key}] = t
+	j.mu.Unlock()
+
+	select {
+	case j.notify <- struct{}{}:
+	default:
+	}
+}
+
+// flush - writes the recorded updates.
+func (j *replicationJournal) flush(ctx context.Context) error {
+	j.mu.Lock()
+	updates := j.updates
+	j.updates = make(map[replicationJournalEntry]*replicationTask)
+	j.mu.Unlock()
+
+	var firstErr error
+	for e, t := range updates {
+		var err error
+		if t == nil {
+			err = j.del(ctx, e.dir, e.key)
+		} else {
+			err = j.put(ctx, e.dir, t)
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// run - writes the recorded updates until ctx is done.
+func (j *replicationJournal) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			logger.LogIf(GlobalContext, j.flush(GlobalContext))
+			return
+		case <-j.notify:
+			if err := j.flush(ctx); err != nil {
+				logger.LogOnceIf(ctx, err, "replication-journal")
+			}
+		}
+	}
+}
+
+// put - writes the task to the journal sub-directory, the object layer
+// never leaves a partially written task.
+func (j *replicationJournal) put(ctx context.Context, dir string, t *replicationTask) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	return saveConfig(ctx, j.objAPI, j.path(dir, t.key()), data)
+}
+
+// del - removes the task from the journal sub-directory.
+func (j *replicationJournal) del(ctx context.Context, dir string, key string) error {
+	err := deleteConfig(ctx, j.objAPI, j.path(dir, key))
+	if err == errConfigNotFound {
+		return nil
+	}
+	return err
+}
+
+// load - reads all tasks of the journal sub-directory, entries which
+// cannot be decoded are removed.
+func (j *replicationJournal) load(ctx context.Context, dir string) ([]*replicationTask, error) {
+	var tasks []*replicationTask
+	prefix := pathJoin(j.prefix, dir) + SlashSeparator
+	marker := ""
+	for {
+		res, err := j.objAPI.ListObjects(ctx, minioMetaBucket, prefix, marker, "", maxObjectList)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range res.Objects {
+			data, err := readConfig(ctx, j.objAPI, obj.Name)
+			if err != nil {
+				if err == errConfigNotFound {
+					continue
+				}
+				return nil, err
+			}
+			t := &replicationTask{}
+			if err = json.Unmarshal(data, t); err != nil || t.Bucket == "" {
+				deleteConfig(ctx, j.objAPI, obj.Name)
+				continue
+			}
+			tasks = append(tasks, t)
+		}
+		if !res.IsTruncated {
+			return tasks, nil
+		}
+		marker = res.NextMarker
+	}
+}
+
+// replicationState is the replication queue of this node, every task
+// is journaled before it is handed to the workers and only removed
+// once it completed or got dead-lettered.
+type replicationState struct {
+	mu        sync.Mutex
+	journal   *replicationJournal
+	pending   map[string]*replicationTask
+	failed    map[string]*replicationTask
+	scheduled map[string]struct{}
+	inflight  map[string]struct{}
+	targets   map[string]*targetBackoff
+	dropped   map[string]uint64
+	stats     map[string]map[string]*replicationTargetStats
+
+	workCh chan string
+}
+
+func (r *replicationState) queueReplicaTask(oi ObjectInfo) {
+	if r == nil {
+		return
+	}
+	r.queue(replicationTask{
+		Op:        replicationOpPut,
+		Bucket:    oi.Bucket,
+		Object:    oi.Name,
+		VersionID: oi.VersionID,
+		Size:      oi.Size,
+		Targets:   getPendingReplicationTargets(oi),
+	}, UTCNow())
+}
+
+// getPendingReplicationTargets returns the ARNs of the targets the object
+// version still has to be replicated to.
+func getPendingReplicationTargets(oi ObjectInfo) []string {
+	cfg, err := getReplicationConfig(GlobalContext, oi.Bucket)
+	if err != nil || cfg == nil {
+		return nil
+	}
+	statuses := getTargetReplicationStatus(oi.UserDefined)
+	var arns []string
+	for _, arn := range cfg.FilterTargetArns(replication.ObjectOpts{
+		Name:     oi.Name,
+		UserTags: oi.UserTags,
+		SSEC:     crypto.SSEC.IsEncrypted(oi.UserDefined),
+	}) {
+		if statuses[arn] != replication.Complete {
+			arns = append(arns, arn)
+		}
+	}
+	return arns
+}
+
+func (r *replicationState) queueReplicaDeleteTask(doi DeletedObjectVersionInfo) {
+	if r == nil {
+		return
+	}
+	versionID := doi.DeleteMarkerVersionID
+	if versionID == "" {
+		versionID = doi.VersionID
+	}
+	var arns []string
+	if cfg, err := getReplicationConfig(GlobalContext, doi.Bucket); err == nil && cfg != nil {
+		arns = cfg.FilterTargetArns(replication.ObjectOpts{
+			Name:         doi.ObjectName,
+			DeleteMarker: doi.DeleteMarkerVersionID != "",
+			VersionID:    doi.VersionID,
+		})
+	}
+	r.queue(replicationTask{
+		Op:        replicationOpDelete,
+		Bucket:    doi.Bucket,
+		Object:    doi.ObjectName,
+		VersionID: versionID,
+		Delete:    &doi,
+		Targets:   arns,
+	}, UTCNow())
+}
+
+// queue - journals the task and hands it to the workers if possible,
+// tasks which are already queued are only claimed by a resync, which
+// then tracks their outcome. Returns
+// false if the task was not queued.
+func (r *replicationState) queue(t replicationTask, now time.Time) bool {
+	key := t.key()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if pt, ok := r.pending[key]; ok {
+		if t.Resync != "" && pt.Resync != t.Resync {
+			pt.Resync = t.Resync
+			pt.ResyncTarget = t.ResyncTarget
+			if r.journal != nil {
+				r.journal.update(replicationPendingDir, key, pt)
+			}
+		}
+		return true
+	}
+	if ft, ok := r.failed[key]; ok {
+		// Resyncs are explicitly requested, retry right away.
+		if t.Resync == "" && now.Sub(ft.Failed) < replicationFailedRetryInterval {
+			return false
+		}
+		r.removeFailed(key)
+	}
+	if len(r.pending) >= replicationQueueLimit {
+		if t.Resync == "" {
+			r.dropped[t.Bucket]++
+		}
+		return false
+	}
+
+	t.Queued = now
+	t.NextAttempt = now
+	if r.journal != nil {
+		r.journal.update(replicationPendingDir, key, &t)
+	}
+	r.pending[key] = &t
+	r.dispatch(key)
+	return true
+}
+
+// dispatch - hands the task to the workers without blocking, tasks
+// which do not fit are picked up by the next schedule.
+// Must be called with r.mu held.
+func (r *replicationState) dispatch(key string) bool {
+	if _, ok := r.scheduled[key]; ok {
+		return true
+	}
+	select {
+	case r.workCh <- key:
+		r.scheduled[key] = struct{}{}
+		return true
+	default:
+		return false
+	}
+}
+
+// schedule - hands all due tasks to the workers.
+func (r *replicationState) schedule(now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, t := range r.pending {
+		if _, ok := r.inflight[key]; ok {
+			continue
+		}
+		if !r.due(t, now) {
+			continue
+		}
+		if !r.dispatch(key) {
+			return
+		}
+	}
+}
+
+// due - returns true if neither the task nor its target is backing off.
+// Must be called with r.mu held.
+func (r *replicationState) due(t *replicationTask, now time.Time) bool {
+	if t.NextAttempt.After(now) {
+		return false
+	}
+	if b, ok := r.targets[t.Target]; ok && b.until.After(now) {
+		return false
+	}
+	return true
+}
+
+// claim - marks the task as in progress, returns false if the task is
+// gone, already being processed or not due yet.
+func (r *replicationState) claim(key string, now time.Time) (replicationTask, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.scheduled, key)
+	t, ok := r.pending[key]
+	if !ok {
+		return replicationTask{}, false
+	}
+	if _, ok = r.inflight[key]; ok {
+		return replicationTask{}, false
+	}
+	if !r.due(t, now) {
+		return replicationTask{}, false
+	}
+	r.inflight[key] = struct{}{}
+	return *t, true
+}
+
+// finish - records the outcome of an attempt, successful tasks are
+// removed from the journal, failed ones are retried with backoff and
+// eventually dead-lettered.
+func (r *replicationState) finish(t replicationTask, err error, now time.Time) {
+	key := t.key()
+	var reason string
+	if err != nil {
+		reason = replicationFailureReason(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.inflight, key)
+	pt, ok := r.pending[key]
+	if !ok {
+		return
+	}
+
+	var perr replicationPartialError
+	if errors.As(err, &perr) {
+		pt.Targets = perr.Arns
+	}
+
+	var terr replicationTargetError
+	switch {
+	case err == nil:
+		if b, ok := r.targets[pt.Target]; ok {
+			b.failures = 0
+			b.until = time.Time{}
+		}
+		delete(r.pending, key)
+		r.removeFailed(key)
+		if r.journal != nil {
+			r.journal.update(replicationPendingDir, key, nil)
+		}
+		globalReplicationResyncs.done(pt, nil)
+		return
+	case errors.As(err, &terr):
+		// Connectivity issues back off the whole target and do
+		// not count towards the attempts of the task.
+		b, ok := r.targets[terr.Arn]
+		if !ok {
+			b = &targetBackoff{}
+			r.targets[terr.Arn] = b
+		}
+		b.failures++
+		b.until = now.Add(replicationBackoff(b.failures))
+		pt.Target = terr.Arn
+		pt.LastError = err.Error()
+		pt.Reason = reason
+	default:
+		pt.Attempts++
+		pt.LastError = err.Error()
+		pt.Reason = reason
+		pt.NextAttempt = now.Add(replicationBackoff(pt.Attempts))
+		if pt.Attempts >= replicationMaxAttempts {
+			pt.Failed = now
+			delete(r.pending, key)
+			r.failed[key] = pt
+			if r.journal != nil {
+				r.journal.update(replicationFailedDir, key, pt)
+				r.journal.update(replicationPendingDir, key, nil)
+			}
+			globalReplicationResyncs.done(pt, err)
+			return
+		}
+	}
+	if r.journal != nil {
+		r.journal.update(replicationPendingDir, key, pt)
+	}
+}
+
+// removeFailed - removes a dead-lettered task.
+// Must be called with r.mu held.
+func (r *replicationState) removeFailed(key string) {
+	if _, ok := r.failed[key]; !ok {
+		return
+	}
+	delete(r.failed, key)
+	if r.journal != nil {
+		r.journal.update(replicationFailedDir, key, nil)
+	}
+}
+
+// getQueueInfo - returns the queue state of the bucket, or of all
+// buckets if bucket is empty.
+func (r *replicationState) getQueueInfo(bucket string) madmin.ReplicationQueueInfo {
+	info := madmin.ReplicationQueueInfo{
+		Buckets: make(map[string]madmin.BucketReplicationQueueInfo),
+	}
+	if r == nil {
+		return info
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := func(b string) madmin.BucketReplicationQueueInfo {
+		bi, ok := info.Buckets[b]
+		if !ok {
+			bi.FailureReasons = make(map[string]uint64)
+		}
+		return bi
+	}
+	for _, t := range r.pending {
+		if bucket != "" && t.Bucket != bucket {
+			continue
+		}
+		bi := stats(t.Bucket)
+		bi.Pending++
+		bi.PendingSize += t.Size
+		if bi.OldestPending.IsZero() || t.Queued.Before(bi.OldestPending) {
+			bi.OldestPending = t.Queued
+		}
+		if t.LastError != "" {
+			bi.Retrying++
+			bi.FailureReasons[t.Reason]++
+		}
+		info.Buckets[t.Bucket] = bi
+	}
+	for _, t := range r.failed {
+		if bucket != "" && t.Bucket != bucket {
+			continue
+		}
+		bi := stats(t.Bucket)
+		bi.Failed++
+		bi.FailedSize += t.Size
+		bi.FailureReasons[t.Reason]++
+		if len(bi.FailedObjects) < replicationMaxFailedObjects {
+			bi.FailedObjects = append(bi.FailedObjects, madmin.ReplicationFailedObject{
+				Object:    t.Object,
+				VersionID: t.VersionID,
+				Operation: string(t.Op),
+				Attempts:  t.Attempts,
+				LastError: t.LastError,
+				Failed:    t.Failed,
+			})
+		}
+		info.Buckets[t.Bucket] = bi
+	}
+	for b, n := range r.dropped {
+		if bucket != "" && b != bucket {
+			continue
+		}
+		bi := stats(b)
+		bi.Dropped = n
+		info.Buckets[b] = bi
+	}
+	for b, bi := range info.Buckets {
+		sort.Slice(bi.FailedObjects, func(i, j int) bool {
+			return bi.FailedObjects[i].Failed.Before(bi.FailedObjects[j].Failed)
+		})
+		info.Buckets[b] = bi
+	}
+	return info
+}
+
+// load - restores the journaled tasks into the queue.
+func (r *replicationState) load(ctx context.Context) error {
+	if r.journal == nil {
+		return nil
+	}
+	pending, err := r.journal.load(ctx, replicationPendingDir)
+	if err != nil {
+		return err
+	}
+	failed, err := r.journal.load(ctx, replicationFailedDir)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, t := range r.pending {
+		// Journal the tasks queued before the journal was available.
+		r.journal.update(replicationPendingDir, key, t)
+	}
+	for _, t := range pending {
+		if _, ok := r.pending[t.key()]; ok {
+			continue
+		}
+		r.pending[t.key()] = t
+	}
+	for _, t := range failed {
+		r.failed[t.key()] = t
+	}
+	return nil
+}
+
+// replicateTask performs a single attempt of the task.
+func replicateTask(ctx context.Context, t replicationTask, objectAPI ObjectLayer) error {
+	switch t.Op {
+	case replicationOpDelete:
+		if t.Delete == nil {
+			return nil
+		}
+		return replicateDelete(ctx, *t.Delete, objectAPI)
+	default:
+		return replicateObject(ctx, t, objectAPI)
+	}
+}
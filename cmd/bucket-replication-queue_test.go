@@ -0,0 +1,187 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	minio "github.com/minio/minio-go/v7"
+)
+
+func newTestReplicationState(t *testing.T) (*replicationState, func()) {
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := newReplicationState()
+	r.journal = newReplicationJournal(obj, "node")
+	if err = r.load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return r, func() { os.RemoveAll(fsDir) }
+}
+
+func TestReplicationBackoff(t *testing.T) {
+	testCases := []struct {
+		n        int
+		expected time.Duration
+	}{
+		{0, time.Second},
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{5, 16 * time.Second},
+		{30, replicationMaxBackoff},
+	}
+	for i, tc := range testCases {
+		if d := replicationBackoff(tc.n); d != tc.expected {
+			t.Errorf("Test %d: expected %v, got %v", i+1, tc.expected, d)
+		}
+	}
+}
+
+func TestReplicationQueueJournal(t *testing.T) {
+	r, cleanup := newTestReplicationState(t)
+	defer cleanup()
+
+	now := UTCNow()
+	r.queue(replicationTask{Op: replicationOpPut, Bucket: "bucket", Object: "a", VersionID: "v1", Size: 10}, now)
+	r.queue(replicationTask{Op: replicationOpPut, Bucket: "bucket", Object: "a", VersionID: "v1", Size: 10}, now.Add(time.Minute))
+	r.queue(replicationTask{Op: replicationOpDelete, Bucket: "bucket", Object: "b", VersionID: "v2",
+		Delete: &DeletedObjectVersionInfo{DeletedObject: DeletedObject{ObjectName: "b", VersionID: "v2"}, Bucket: "bucket"}}, now.Add(time.Second))
+
+	if len(r.pending) != 2 {
+		t.Fatalf("expected 2 pending tasks, got %d", len(r.pending))
+	}
+	if err := r.journal.flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a restart, tasks are restored from the journal.
+	r2 := newReplicationState()
+	r2.journal = r.journal
+	if err := r2.load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	info := r2.getQueueInfo("bucket").Buckets["bucket"]
+	if info.Pending != 2 || info.PendingSize != 10 {
+		t.Fatalf("unexpected queue info after restart %#v", info)
+	}
+	if !info.OldestPending.Equal(now) {
+		t.Fatalf("expected oldest pending %v, got %v", now, info.OldestPending)
+	}
+	for _, task := range r2.pending {
+		if task.Op == replicationOpDelete && (task.Delete == nil || task.Delete.VersionID != "v2") {
+			t.Fatalf("delete task not restored %#v", task)
+		}
+	}
+
+	// Completed tasks are removed from the journal.
+	for key := range r2.pending {
+		task, ok := r2.claim(key, now.Add(time.Minute))
+		if !ok {
+			t.Fatalf("expected task %s to be claimed", key)
+		}
+		r2.finish(task, nil, now.Add(time.Minute))
+	}
+	if err := r2.journal.flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	tasks, err := r2.journal.load(context.Background(), replicationPendingDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 0 || len(r2.pending) != 0 {
+		t.Fatalf("expected empty journal, got %d entries", len(tasks))
+	}
+}
+
+func TestReplicationQueueRetry(t *testing.T) {
+	r, cleanup := newTestReplicationState(t)
+	defer cleanup()
+
+	now := UTCNow()
+	task := replicationTask{Op: replicationOpPut, Bucket: "bucket", Object: "a", VersionID: "v1", Size: 10}
+	key := task.key()
+	r.queue(task, now)
+
+	// Unreachable targets back off without counting attempts.
+	claimed, ok := r.claim(key, now)
+	if !ok {
+		t.Fatal("expected task to be claimed")
+	}
+	r.finish(claimed, replicationTargetError{Arn: "arn", Err: errors.New("connection refused")}, now)
+	if r.pending[key].Attempts != 0 {
+		t.Fatalf("expected no attempts, got %d", r.pending[key].Attempts)
+	}
+	if reasons := r.getQueueInfo("").Buckets["bucket"].FailureReasons; reasons[replicationTargetUnreachable] != 1 {
+		t.Fatalf("unexpected failure reasons %#v", reasons)
+	}
+	if _, ok = r.claim(key, now); ok {
+		t.Fatal("expected target to be backing off")
+	}
+
+	// Other errors are retried with backoff until dead-lettered.
+	for i := 1; i <= replicationMaxAttempts; i++ {
+		now = now.Add(replicationMaxBackoff)
+		claimed, ok = r.claim(key, now)
+		if !ok {
+			t.Fatalf("attempt %d: expected task to be claimed", i)
+		}
+		if _, ok = r.claim(key, now); ok {
+			t.Fatalf("attempt %d: expected in-flight task not to be claimed twice", i)
+		}
+		r.finish(claimed, fmt.Errorf("replicating %s: %w", claimed.Object, minio.ErrorResponse{Code: "AccessDenied", Message: "Access Denied."}), now)
+		if i < replicationMaxAttempts {
+			if _, ok = r.claim(key, now); ok {
+				t.Fatalf("attempt %d: expected task to be backing off", i)
+			}
+		}
+	}
+
+	info := r.getQueueInfo("").Buckets["bucket"]
+	if info.Pending != 0 || info.Failed != 1 || info.FailedSize != 10 {
+		t.Fatalf("unexpected queue info %#v", info)
+	}
+	if len(info.FailureReasons) != 1 || info.FailureReasons["AccessDenied"] != 1 || len(info.FailedObjects) != 1 {
+		t.Fatalf("unexpected failure reasons %#v", info)
+	}
+
+	// Dead-lettered tasks are only re-queued after the retry interval.
+	r.queue(task, now)
+	if len(r.pending) != 0 {
+		t.Fatal("expected dead-lettered task not to be re-queued")
+	}
+	r.queue(task, now.Add(replicationFailedRetryInterval))
+	if len(r.pending) != 1 || len(r.failed) != 0 {
+		t.Fatal("expected dead-lettered task to be re-queued")
+	}
+	if err := r.journal.flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	tasks, err := r.journal.load(context.Background(), replicationFailedDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 0 {
+		t.Fatalf("expected empty dead-letter journal, got %d entries", len(tasks))
+	}
+}
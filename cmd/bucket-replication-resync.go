@@ -0,0 +1,428 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/cmd/crypto"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/bucket/replication"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+const (
+	// Resync state file, records the progress of the last resync of a bucket.
+	bucketReplicationResyncFile = "replication-resync.json"
+
+	// Progress of a running resync is saved every so many objects
+	// and at least once per interval.
+	replicationResyncCheckpointObjects  = 1000
+	replicationResyncCheckpointInterval = 10 * time.Second
+
+	// A running resync whose progress was not saved within this
+	// interval is considered abandoned and may be started again.
+	replicationResyncStaleInterval = 2 * time.Minute
+)
+
+var errReplicationResyncInProgress = AdminError{
+	Code:       "XMinioAdminReplicationResyncInProgress",
+	Message:    "A replication resync of this bucket is already in progress",
+	StatusCode: http.StatusConflict,
+}
+
+var errReplicationResyncResetTarget = AdminError{
+	Code:       "XMinioAdminReplicationResyncInvalidArgument",
+	Message:    "A replication resync resetting the replication status requires a target ARN",
+	StatusCode: http.StatusBadRequest,
+}
+
+func replicationResyncPath(bucket string) string {
+	return path.Join(bucketConfigPrefix, bucket, bucketReplicationResyncFile)
+}
+
+func loadReplicationResync(ctx context.Context, objAPI ObjectLayer, bucket string) (madmin.ReplicationResyncInfo, error) {
+	var info madmin.ReplicationResyncInfo
+	data, err := readConfig(ctx, objAPI, replicationResyncPath(bucket))
+	if err != nil {
+		return info, err
+	}
+	err = json.Unmarshal(data, &info)
+	return info, err
+}
+
+func saveReplicationResync(ctx context.Context, objAPI ObjectLayer, info madmin.ReplicationResyncInfo) error {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	return saveConfig(ctx, objAPI, replicationResyncPath(info.Bucket), data)
+}
+
+// updateReplicationResync loads the resync state of the bucket, applies
+// fn to it and saves it back, under a cluster wide lock.
+func updateReplicationResync(ctx context.Context, objAPI ObjectLayer, bucket string, fn func(info *madmin.ReplicationResyncInfo, found bool) error) (madmin.ReplicationResyncInfo, error) {
+	lk := objAPI.NewNSLock(minioMetaBucket, replicationResyncPath(bucket)+".lock")
+	if err := lk.GetLock(ctx, globalOperationTimeout); err != nil {
+		return madmin.ReplicationResyncInfo{}, err
+	}
+	defer lk.Unlock()
+
+	info, err := loadReplicationResync(ctx, objAPI, bucket)
+	found := err == nil
+	if err != nil && !errors.Is(err, errConfigNotFound) {
+		return info, err
+	}
+	if err = fn(&info, found); err != nil {
+		return info, err
+	}
+	info.LastUpdate = UTCNow()
+	return info, saveReplicationResync(ctx, objAPI, info)
+}
+
+// resyncProgress - outcome of the tasks queued by a running resync.
+type resyncProgress struct {
+	queued         uint64
+	replicated     uint64
+	replicatedSize uint64
+	failed         uint64
+	failedSize     uint64
+	lastError      string
+}
+
+// replicationResyncs - resyncs running on this node, tasks report their
+// outcome here once they completed or got dead-lettered.
+type replicationResyncs struct {
+	mu       sync.Mutex
+	progress map[string]*resyncProgress
+}
+
+var globalReplicationResyncs = &replicationResyncs{
+	progress: make(map[string]*resyncProgress),
+}
+
+func (r *replicationResyncs) add(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.progress[id] = &resyncProgress{}
+}
+
+func (r *replicationResyncs) remove(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.progress, id)
+}
+
+// queued - records a task queued by the resync.
+func (r *replicationResyncs) queued(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if p, ok := r.progress[id]; ok {
+		p.queued++
+	}
+}
+
+// done - records the final outcome of a task.
+func (r *replicationResyncs) done(t *replicationTask, err error) {
+	if t.Resync == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.progress[t.Resync]
+	if !ok {
+		return
+	}
+	if err != nil {
+		p.failed++
+		p.failedSize += uint64(t.Size)
+		p.lastError = err.Error()
+		return
+	}
+	p.replicated++
+	p.replicatedSize += uint64(t.Size)
+}
+
+// get - returns the progress of the resync.
+func (r *replicationResyncs) get(id string) resyncProgress {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if p, ok := r.progress[id]; ok {
+		return *p
+	}
+	return resyncProgress{}
+}
+
+// startReplicationResync starts a resync of the bucket in the background,
+// resuming the previous one if requested and possible.
+func startReplicationResync(ctx context.Context, objAPI ObjectLayer, bucket string, opts madmin.ReplicationResyncOptions) (madmin.ReplicationResyncInfo, error) {
+	if globalReplicationState == nil {
+		// No replication queue in gateway mode.
+		return madmin.ReplicationResyncInfo{}, NotImplemented{}
+	}
+	cfg, err := getReplicationConfig(ctx, bucket)
+	if err != nil {
+		return madmin.ReplicationResyncInfo{}, err
+	}
+	if opts.Arn != "" && !cfg.HasTarget(opts.Arn) {
+		return madmin.ReplicationResyncInfo{}, BucketRemoteTargetNotFound{Bucket: bucket}
+	}
+	if opts.Reset && opts.Arn == "" {
+		return madmin.ReplicationResyncInfo{}, errReplicationResyncResetTarget
+	}
+
+	now := UTCNow()
+	info, err := updateReplicationResync(ctx, objAPI, bucket, func(info *madmin.ReplicationResyncInfo, found bool) error {
+		if found && info.Status == madmin.ReplicationResyncRunning && now.Sub(info.LastUpdate) < replicationResyncStaleInterval {
+			return errReplicationResyncInProgress
+		}
+		resume := opts.Resume && found && info.Status != madmin.ReplicationResyncCompleted &&
+			info.Arn == opts.Arn && info.Reset == opts.Reset
+		if !resume {
+			*info = madmin.ReplicationResyncInfo{
+				Bucket:    bucket,
+				Arn:       opts.Arn,
+				Reset:     opts.Reset,
+				StartTime: now,
+			}
+		}
+		info.ID = mustGetUUID()
+		info.Status = madmin.ReplicationResyncRunning
+		info.EndTime = time.Time{}
+		info.Error = ""
+		return nil
+	})
+	if err != nil {
+		return info, err
+	}
+
+	globalReplicationResyncs.add(info.ID)
+	go func() {
+		defer globalReplicationResyncs.remove(info.ID)
+		runReplicationResync(ctx, objAPI, cfg, info)
+	}()
+	return info, nil
+}
+
+// cancelReplicationResync marks the running resync of the bucket as
+// canceled, the node running it stops at its next checkpoint. Tasks
+// which were queued already are still replicated.
+func cancelReplicationResync(ctx context.Context, objAPI ObjectLayer, bucket string) (madmin.ReplicationResyncInfo, error) {
+	return updateReplicationResync(ctx, objAPI, bucket, func(info *madmin.ReplicationResyncInfo, found bool) error {
+		if !found {
+			return errConfigNotFound
+		}
+		if info.Status == madmin.ReplicationResyncRunning {
+			info.Status = madmin.ReplicationResyncCanceled
+			info.EndTime = UTCNow()
+		}
+		return nil
+	})
+}
+
+// replicationResyncer walks a bucket and queues all objects which have
+// not been replicated to the targets of the resync.
+type replicationResyncer struct {
+	objAPI ObjectLayer
+	cfg    *replication.Config
+	info   madmin.ReplicationResyncInfo
+
+	// Progress since the last checkpoint.
+	scanned, scannedSize uint64
+	queued, queuedSize   uint64
+	lastObject           string
+	lastCheckpoint       time.Time
+}
+
+// checkpoint - saves the progress, returns false if the resync was
+// canceled or superseded in the meantime.
+func (s *replicationResyncer) checkpoint(ctx context.Context, status madmin.ReplicationResyncStatus, resErr error) bool {
+	p := globalReplicationResyncs.get(s.info.ID)
+	running := true
+	_, err := updateReplicationResync(ctx, s.objAPI, s.info.Bucket, func(info *madmin.ReplicationResyncInfo, found bool) error {
+		if !found || info.ID != s.info.ID || info.Status != madmin.ReplicationResyncRunning {
+			running = false
+			if !found || info.ID != s.info.ID {
+				return errReplicationResyncInProgress
+			}
+		}
+		info.ObjectsScanned += s.scanned
+		info.BytesScanned += s.scannedSize
+		info.ObjectsQueued += s.queued
+		info.BytesQueued += s.queuedSize
+		info.ObjectsReplicated += p.replicated - s.info.ObjectsReplicated
+		info.BytesReplicated += p.replicatedSize - s.info.BytesReplicated
+		info.ObjectsFailed += p.failed - s.info.ObjectsFailed
+		info.BytesFailed += p.failedSize - s.info.BytesFailed
+		if p.lastError != "" {
+			info.LastError = p.lastError
+		}
+		if s.lastObject != "" {
+			info.LastObject = s.lastObject
+		}
+		if running && status != madmin.ReplicationResyncRunning {
+			info.Status = status
+			info.EndTime = UTCNow()
+			if resErr != nil {
+				info.Error = resErr.Error()
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if err != errReplicationResyncInProgress {
+			logger.LogIf(ctx, err)
+		}
+		return false
+	}
+	// Counters of the tasks are cumulative, remember what was saved.
+	s.info.ObjectsReplicated = p.replicated
+	s.info.BytesReplicated = p.replicatedSize
+	s.info.ObjectsFailed = p.failed
+	s.info.BytesFailed = p.failedSize
+	s.scanned, s.scannedSize, s.queued, s.queuedSize = 0, 0, 0, 0
+	s.lastCheckpoint = UTCNow()
+	return running
+}
+
+// needsResync returns true if the object version has to be replicated
+// to any target of the resync.
+func (s *replicationResyncer) needsResync(oi ObjectInfo) bool {
+	if oi.DeleteMarker || oi.ReplicationStatus == replication.Replica {
+		return false
+	}
+	opts := replication.ObjectOpts{
+		Name:     oi.Name,
+		UserTags: oi.UserTags,
+		SSEC:     crypto.SSEC.IsEncrypted(oi.UserDefined),
+	}
+	statuses := getTargetReplicationStatus(oi.UserDefined)
+	for _, arn := range s.cfg.FilterTargetArns(opts) {
+		if s.info.Arn != "" && arn != s.info.Arn {
+			continue
+		}
+		if s.info.Reset {
+			return true
+		}
+		status, ok := statuses[arn]
+		if !ok && len(statuses) == 0 {
+			// Replicated before the status was tracked per target.
+			status = oi.ReplicationStatus
+		}
+		if status != replication.Complete {
+			return true
+		}
+	}
+	return false
+}
+
+// runReplicationResync walks the bucket, skipping the objects processed by
+// a previous run, and queues the objects to replicate. It keeps saving its
+// progress until all queued objects have been replicated.
+func runReplicationResync(ctx context.Context, objAPI ObjectLayer, cfg *replication.Config, info madmin.ReplicationResyncInfo) {
+	s := &replicationResyncer{
+		objAPI:         objAPI,
+		cfg:            cfg,
+		info:           info,
+		lastCheckpoint: UTCNow(),
+	}
+	// Counters restored from a previous run are not tracked by this one.
+	s.info.ObjectsReplicated, s.info.BytesReplicated = 0, 0
+	s.info.ObjectsFailed, s.info.BytesFailed = 0, 0
+
+	rs := globalReplicationState
+	if rs == nil {
+		s.checkpoint(ctx, madmin.ReplicationResyncFailed, NotImplemented{})
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan ObjectInfo, 100)
+	if err := objAPI.Walk(ctx, info.Bucket, "", results, ObjectOptions{WalkVersions: true}); err != nil {
+		s.checkpoint(ctx, madmin.ReplicationResyncFailed, err)
+		return
+	}
+
+	for oi := range results {
+		if oi.Name < info.LastObject {
+			continue
+		}
+		s.scanned++
+		s.scannedSize += uint64(oi.Size)
+		if s.needsResync(oi) {
+			t := replicationTask{
+				Op:        replicationOpPut,
+				Bucket:    oi.Bucket,
+				Object:    oi.Name,
+				VersionID: oi.VersionID,
+				Size:      oi.Size,
+				Resync:    info.ID,
+			}
+			if info.Arn != "" {
+				t.Targets = []string{info.Arn}
+			}
+			if info.Reset {
+				t.ResyncTarget = info.Arn
+			}
+			// Wait for the workers to make room in the queue.
+			for !rs.queue(t, UTCNow()) {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(time.Second):
+				}
+			}
+			globalReplicationResyncs.queued(info.ID)
+			s.queued++
+			s.queuedSize += uint64(oi.Size)
+		}
+		s.lastObject = oi.Name
+		if s.scanned >= replicationResyncCheckpointObjects || UTCNow().Sub(s.lastCheckpoint) >= replicationResyncCheckpointInterval {
+			if !s.checkpoint(ctx, madmin.ReplicationResyncRunning, nil) {
+				// Canceled, stop the walker.
+				return
+			}
+		}
+	}
+
+	// Wait for the queued objects to be replicated.
+	for {
+		p := globalReplicationResyncs.get(info.ID)
+		if p.replicated+p.failed >= p.queued {
+			s.checkpoint(ctx, madmin.ReplicationResyncCompleted, nil)
+			return
+		}
+		if UTCNow().Sub(s.lastCheckpoint) >= replicationResyncCheckpointInterval {
+			if !s.checkpoint(ctx, madmin.ReplicationResyncRunning, nil) {
+				return
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
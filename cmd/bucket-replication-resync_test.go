@@ -0,0 +1,201 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	xhttp "github.com/minio/minio/cmd/http"
+	"github.com/minio/minio/pkg/bucket/replication"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+const (
+	testReplicationArn1 = "arn:minio:replication::target-1:dest"
+	testReplicationArn2 = "arn:minio:replication::target-2:dest"
+)
+
+func testReplicationConfig(arns ...string) *replication.Config {
+	cfg := &replication.Config{}
+	for i, arn := range arns {
+		cfg.Rules = append(cfg.Rules, replication.Rule{
+			Status:                  replication.Enabled,
+			Priority:                i + 1,
+			DeleteMarkerReplication: replication.DeleteMarkerReplication{Status: replication.Disabled},
+			DeleteReplication:       replication.DeleteReplication{Status: replication.Disabled},
+			Destination:             replication.Destination{Bucket: "dest", ARN: arn},
+		})
+	}
+	return cfg
+}
+
+func TestReplicationResyncNeedsResync(t *testing.T) {
+	s := &replicationResyncer{cfg: testReplicationConfig(testReplicationArn1, testReplicationArn2)}
+	testCases := []struct {
+		oi       ObjectInfo
+		arn      string
+		reset    bool
+		expected bool
+	}{
+		// Never replicated.
+		{ObjectInfo{Name: "a"}, "", false, true},
+		// Replicas are not replicated back.
+		{ObjectInfo{Name: "a", ReplicationStatus: replication.Replica}, "", false, false},
+		// Replicated before the status was tracked per target.
+		{ObjectInfo{Name: "a", ReplicationStatus: replication.Complete}, "", false, false},
+		// Replicated to one of the targets only.
+		{ObjectInfo{Name: "a", ReplicationStatus: replication.Failed, UserDefined: map[string]string{
+			replicationStatusKey: testReplicationArn1 + "=COMPLETE;" + testReplicationArn2 + "=FAILED;",
+		}}, "", false, true},
+		{ObjectInfo{Name: "a", ReplicationStatus: replication.Failed, UserDefined: map[string]string{
+			replicationStatusKey: testReplicationArn1 + "=COMPLETE;" + testReplicationArn2 + "=FAILED;",
+		}}, testReplicationArn1, false, false},
+		// Reset replicates to the target regardless of its status.
+		{ObjectInfo{Name: "a", ReplicationStatus: replication.Complete}, testReplicationArn1, true, true},
+		{ObjectInfo{Name: "a", DeleteMarker: true}, testReplicationArn1, true, false},
+	}
+	for i, tc := range testCases {
+		s.info.Arn = tc.arn
+		s.info.Reset = tc.reset
+		if needs := s.needsResync(tc.oi); needs != tc.expected {
+			t.Errorf("Test %d: expected %v, got %v", i+1, tc.expected, needs)
+		}
+	}
+}
+
+func TestReplicationResync(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if localMetacacheMgr != nil {
+		localMetacacheMgr.deleteAll()
+		defer localMetacacheMgr.deleteAll()
+	}
+	defer setObjectLayer(newObjectLayerFn())
+
+	newAllSubsystems()
+	objAPI, disks, err := prepareErasure16(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(disks)
+	defer objAPI.Shutdown(context.Background())
+	setObjectLayer(objAPI)
+	initAllSubsystems(ctx, objAPI)
+
+	bucket := "bucket"
+	if err = objAPI.MakeBucketWithLocation(ctx, bucket, BucketOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	objects := map[string]map[string]string{
+		"complete": {
+			xhttp.AmzBucketReplicationStatus: replication.Complete.String(),
+			replicationStatusKey:             testReplicationArn1 + "=COMPLETE;",
+		},
+		"failed": {
+			xhttp.AmzBucketReplicationStatus: replication.Failed.String(),
+			replicationStatusKey:             testReplicationArn1 + "=FAILED;",
+		},
+		"new": {},
+	}
+	for name, meta := range objects {
+		data := []byte("hello")
+		_, err = objAPI.PutObject(ctx, bucket, name, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{UserDefined: meta})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Process the queued tasks as a worker would.
+	saved := globalReplicationState
+	defer func() { globalReplicationState = saved }()
+	globalReplicationState = newReplicationState()
+	globalReplicationState.journal = newReplicationJournal(objAPI, "node")
+	if err = globalReplicationState.load(ctx); err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for key := range globalReplicationState.workCh {
+			task, ok := globalReplicationState.claim(key, UTCNow())
+			if !ok {
+				continue
+			}
+			globalReplicationState.finish(task, nil, UTCNow())
+		}
+	}()
+
+	info := madmin.ReplicationResyncInfo{
+		ID:        mustGetUUID(),
+		Bucket:    bucket,
+		Status:    madmin.ReplicationResyncRunning,
+		StartTime: UTCNow(),
+	}
+	if err = saveReplicationResync(ctx, objAPI, info); err != nil {
+		t.Fatal(err)
+	}
+	globalReplicationResyncs.add(info.ID)
+	defer globalReplicationResyncs.remove(info.ID)
+
+	done := make(chan struct{})
+	go func() {
+		runReplicationResync(ctx, objAPI, testReplicationConfig(testReplicationArn1), info)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		t.Fatal("resync did not complete")
+	}
+	close(globalReplicationState.workCh)
+
+	info, err = loadReplicationResync(ctx, objAPI, bucket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Status != madmin.ReplicationResyncCompleted {
+		t.Fatalf("expected resync to complete, got %s", info.Status)
+	}
+	if info.ObjectsScanned != 3 || info.BytesScanned != 15 || info.ObjectsQueued != 2 || info.ObjectsReplicated != 2 || info.LastObject != "new" {
+		t.Fatalf("unexpected progress %#v", info)
+	}
+
+	// Cancel marks a running resync as canceled.
+	info.Status = madmin.ReplicationResyncRunning
+	if err = saveReplicationResync(ctx, objAPI, info); err != nil {
+		t.Fatal(err)
+	}
+	if info, err = cancelReplicationResync(ctx, objAPI, bucket); err != nil {
+		t.Fatal(err)
+	}
+	if info.Status != madmin.ReplicationResyncCanceled || info.EndTime.IsZero() {
+		t.Fatalf("expected resync to be canceled, got %#v", info)
+	}
+}
+
+func TestReplicationResyncWithoutQueue(t *testing.T) {
+	saved := globalReplicationState
+	defer func() { globalReplicationState = saved }()
+	globalReplicationState = nil
+
+	_, err := startReplicationResync(context.Background(), nil, "bucket", madmin.ReplicationResyncOptions{})
+	if _, ok := err.(NotImplemented); !ok {
+		t.Fatalf("expected NotImplemented, got %v", err)
+	}
+}
@@ -0,0 +1,227 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"time"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// Weight of the previous average in the moving average of the lag.
+const replicationLagBeta = 0.9
+
+// replicationTargetStats - outcome of the replication operations of a
+// bucket to one remote target on this node since it started.
+type replicationTargetStats struct {
+	replicated     uint64
+	replicatedSize int64
+	lastReplicated time.Time
+	failedAttempts uint64
+	lastLag        time.Duration
+	avgLag         time.Duration
+	lastError      string
+	lastErrorTime  time.Time
+}
+
+// updateStats - records the outcome of an attempt to replicate to the
+// target, a zero lag is not accounted for.
+func (r *replicationState) updateStats(bucket, arn string, size int64, lag time.Duration, err error) {
+	if r == nil {
+		return
+	}
+	if err == nil && lag > 0 {
+		bucketReplicationLag.WithLabelValues(bucket, arn).Observe(lag.Seconds())
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	targets, ok := r.stats[bucket]
+	if !ok {
+		targets = make(map[string]*replicationTargetStats)
+		r.stats[bucket] = targets
+	}
+	s, ok := targets[arn]
+	if !ok {
+		s = &replicationTargetStats{}
+		targets[arn] = s
+	}
+
+	now := UTCNow()
+	if err != nil {
+		s.failedAttempts++
+		s.lastError = err.Error()
+		s.lastErrorTime = now
+		return
+	}
+	s.replicated++
+	s.replicatedSize += size
+	s.lastReplicated = now
+	if lag > 0 {
+		s.lastLag = lag
+		if s.avgLag == 0 {
+			s.avgLag = lag
+		} else {
+			s.avgLag = time.Duration(replicationLagBeta*float64(s.avgLag) + (1-replicationLagBeta)*float64(lag))
+		}
+	}
+}
+
+// getMetrics - returns the replication metrics per target of the bucket,
+// or of all buckets if bucket is empty. Operations which are pending for
+// unknown targets are accounted to all targets of the bucket.
+func (r *replicationState) getMetrics(bucket string) madmin.ReplicationMetrics {
+	metrics := madmin.ReplicationMetrics{
+		Buckets: make(map[string]madmin.BucketReplicationMetrics),
+	}
+	if r == nil {
+		return metrics
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	update := func(b, arn string, fn func(tm *madmin.TargetReplicationMetrics)) {
+		bm, ok := metrics.Buckets[b]
+		if !ok {
+			bm.Targets = make(map[string]madmin.TargetReplicationMetrics)
+			metrics.Buckets[b] = bm
+		}
+		tm := bm.Targets[arn]
+		fn(&tm)
+		bm.Targets[arn] = tm
+	}
+	bucketArns := make(map[string][]string)
+	targetArns := func(t *replicationTask) []string {
+		if len(t.Targets) > 0 {
+			return t.Targets
+		}
+		arns, ok := bucketArns[t.Bucket]
+		if !ok {
+			if cfg, err := getReplicationConfig(GlobalContext, t.Bucket); err == nil && cfg != nil {
+				arns = cfg.TargetArns()
+			}
+			bucketArns[t.Bucket] = arns
+		}
+		return arns
+	}
+
+	for _, t := range r.pending {
+		if bucket != "" && t.Bucket != bucket {
+			continue
+		}
+		for _, arn := range targetArns(t) {
+			update(t.Bucket, arn, func(tm *madmin.TargetReplicationMetrics) {
+				tm.Pending++
+				tm.PendingSize += t.Size
+				if tm.OldestPending.IsZero() || t.Queued.Before(tm.OldestPending) {
+					tm.OldestPending = t.Queued
+				}
+			})
+		}
+	}
+	for _, t := range r.failed {
+		if bucket != "" && t.Bucket != bucket {
+			continue
+		}
+		for _, arn := range targetArns(t) {
+			update(t.Bucket, arn, func(tm *madmin.TargetReplicationMetrics) {
+				tm.Failed++
+				tm.FailedSize += t.Size
+			})
+		}
+	}
+	for b, targets := range r.stats {
+		if bucket != "" && b != bucket {
+			continue
+		}
+		for arn, s := range targets {
+			update(b, arn, func(tm *madmin.TargetReplicationMetrics) {
+				tm.Replicated = s.replicated
+				tm.ReplicatedSize = s.replicatedSize
+				tm.LastReplicated = s.lastReplicated
+				tm.FailedAttempts = s.failedAttempts
+				tm.LastLag = s.lastLag
+				tm.AvgLag = s.avgLag
+				tm.LastError = s.lastError
+				tm.LastErrorTime = s.lastErrorTime
+			})
+		}
+	}
+	if globalBucketMonitor != nil {
+		for b, bm := range metrics.Buckets {
+			for arn, tm := range bm.Targets {
+				if details, ok := globalBucketMonitor.GetTargetReport(arn); ok {
+					tm.CurrentBandwidth = details.CurrentBandwidthInBytesPerSecond
+					tm.BandwidthLimit = details.LimitInBytesPerSecond
+				}
+				metrics.Buckets[b].Targets[arn] = tm
+			}
+		}
+	}
+	return metrics
+}
+
+// mergeReplicationMetrics - merges the replication metrics of several
+// nodes, counters are summed up and the latest lag and error are kept.
+func mergeReplicationMetrics(all ...madmin.ReplicationMetrics) madmin.ReplicationMetrics {
+	merged := madmin.ReplicationMetrics{
+		Buckets: make(map[string]madmin.BucketReplicationMetrics),
+	}
+	for _, metrics := range all {
+		for b, bm := range metrics.Buckets {
+			mbm, ok := merged.Buckets[b]
+			if !ok {
+				mbm.Targets = make(map[string]madmin.TargetReplicationMetrics)
+				merged.Buckets[b] = mbm
+			}
+			for arn, tm := range bm.Targets {
+				m := mbm.Targets[arn]
+				m.Pending += tm.Pending
+				m.PendingSize += tm.PendingSize
+				if !tm.OldestPending.IsZero() && (m.OldestPending.IsZero() || tm.OldestPending.Before(m.OldestPending)) {
+					m.OldestPending = tm.OldestPending
+				}
+				m.Failed += tm.Failed
+				m.FailedSize += tm.FailedSize
+				if tm.Replicated > 0 {
+					// Average of the nodes weighted by their operations.
+					m.AvgLag = time.Duration((float64(m.AvgLag)*float64(m.Replicated) + float64(tm.AvgLag)*float64(tm.Replicated)) /
+						float64(m.Replicated+tm.Replicated))
+				}
+				m.Replicated += tm.Replicated
+				m.ReplicatedSize += tm.ReplicatedSize
+				if tm.LastReplicated.After(m.LastReplicated) {
+					m.LastReplicated = tm.LastReplicated
+					m.LastLag = tm.LastLag
+				}
+				m.FailedAttempts += tm.FailedAttempts
+				m.CurrentBandwidth += tm.CurrentBandwidth
+				if tm.BandwidthLimit > m.BandwidthLimit {
+					m.BandwidthLimit = tm.BandwidthLimit
+				}
+				if tm.LastErrorTime.After(m.LastErrorTime) {
+					m.LastError = tm.LastError
+					m.LastErrorTime = tm.LastErrorTime
+				}
+				mbm.Targets[arn] = m
+			}
+		}
+	}
+	return merged
+}
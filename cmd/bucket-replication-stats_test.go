@@ -0,0 +1,66 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReplicationMetrics(t *testing.T) {
+	r, cleanup := newTestReplicationState(t)
+	defer cleanup()
+
+	now := UTCNow()
+	task := replicationTask{Op: replicationOpPut, Bucket: "bucket", Object: "a", VersionID: "v1", Size: 10,
+		Targets: []string{testReplicationArn1, testReplicationArn2}}
+	r.queue(task, now)
+
+	// A partial failure leaves the task pending for the failed target only.
+	claimed, ok := r.claim(task.key(), now)
+	if !ok {
+		t.Fatal("expected task to be claimed")
+	}
+	r.updateStats("bucket", testReplicationArn1, 10, 2*time.Second, nil)
+	r.updateStats("bucket", testReplicationArn2, 10, 0, errors.New("AccessDenied"))
+	r.finish(claimed, replicationPartialError{Arns: []string{testReplicationArn2}, Err: errors.New("AccessDenied")}, now)
+
+	metrics := r.getMetrics("bucket").Buckets["bucket"]
+	tm1, tm2 := metrics.Targets[testReplicationArn1], metrics.Targets[testReplicationArn2]
+	if tm1.Pending != 0 || tm1.Replicated != 1 || tm1.ReplicatedSize != 10 || tm1.LastLag != 2*time.Second || tm1.AvgLag != 2*time.Second {
+		t.Fatalf("unexpected metrics of first target %#v", tm1)
+	}
+	if tm2.Pending != 1 || tm2.PendingSize != 10 || !tm2.OldestPending.Equal(now) || tm2.FailedAttempts != 1 || tm2.LastError != "AccessDenied" {
+		t.Fatalf("unexpected metrics of second target %#v", tm2)
+	}
+
+	// Metrics of the nodes are summed up, the latest lag is kept.
+	r2, cleanup2 := newTestReplicationState(t)
+	defer cleanup2()
+	r2.updateStats("bucket", testReplicationArn1, 20, 4*time.Second, nil)
+	r2.updateStats("bucket", testReplicationArn1, 20, 4*time.Second, nil)
+	r2.updateStats("bucket", testReplicationArn1, 20, 4*time.Second, nil)
+	merged := mergeReplicationMetrics(r.getMetrics(""), r2.getMetrics("")).Buckets["bucket"]
+	tm1 = merged.Targets[testReplicationArn1]
+	if tm1.Replicated != 4 || tm1.ReplicatedSize != 70 || tm1.LastLag != 4*time.Second || tm1.AvgLag != 3500*time.Millisecond {
+		t.Fatalf("unexpected merged metrics %#v", tm1)
+	}
+	if merged.Targets[testReplicationArn2].Pending != 1 {
+		t.Fatalf("unexpected merged metrics %#v", merged.Targets[testReplicationArn2])
+	}
+}
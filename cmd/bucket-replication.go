@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"net/http"
 	"runtime"
+	"sort"
 	"strings"
 	"time"
 
@@ -36,6 +37,7 @@ import (
 	"github.com/minio/minio/pkg/event"
 	iampolicy "github.com/minio/minio/pkg/iam/policy"
 	"github.com/minio/minio/pkg/madmin"
+	xnet "github.com/minio/minio/pkg/net"
 )
 
 // gets replication config associated to a given bucket name.
@@ -49,32 +51,54 @@ func getReplicationConfig(ctx context.Context, bucketName string) (rc *replicati
 }
 
 // validateReplicationDestination returns error if replication destination bucket missing or not configured
-// It also returns true if replication destination is same as this server.
+// It also returns true if the destination of a rule is the source bucket on this server.
 func validateReplicationDestination(ctx context.Context, bucket string, rCfg *replication.Config) (bool, error) {
-	arn, err := madmin.ParseARN(rCfg.RoleArn)
+	var sameTarget bool
+	targets := make(map[string]bool)
+	for _, r := range rCfg.Rules {
+		arn := rCfg.TargetArn(r)
+		same, ok := targets[arn]
+		if !ok {
+			var err error
+			if same, err = validateReplicationTarget(ctx, bucket, arn, r.Destination); err != nil {
+				return false, err
+			}
+			targets[arn] = same
+		}
+		if same && r.Destination.Bucket == bucket {
+			sameTarget = true
+		}
+	}
+	return sameTarget, nil
+}
+
+// validateReplicationTarget returns error if the remote target or its destination bucket
+// is missing or not configured. It also returns true if the target is this server.
+func validateReplicationTarget(ctx context.Context, bucket, arnStr string, dest replication.Destination) (bool, error) {
+	arn, err := madmin.ParseARN(arnStr)
 	if err != nil {
 		return false, BucketRemoteArnInvalid{}
 	}
 	if arn.Type != madmin.ReplicationService {
 		return false, BucketRemoteArnTypeInvalid{}
 	}
-	clnt := globalBucketTargetSys.GetRemoteTargetClient(ctx, rCfg.RoleArn)
+	clnt := globalBucketTargetSys.GetRemoteTargetClient(ctx, arnStr)
 	if clnt == nil {
 		return false, BucketRemoteTargetNotFound{Bucket: bucket}
 	}
-	if found, _ := clnt.BucketExists(ctx, rCfg.GetDestination().Bucket); !found {
-		return false, BucketRemoteDestinationNotFound{Bucket: rCfg.GetDestination().Bucket}
+	if found, _ := clnt.BucketExists(ctx, dest.Bucket); !found {
+		return false, BucketRemoteDestinationNotFound{Bucket: dest.Bucket}
 	}
 	if ret, err := globalBucketObjectLockSys.Get(bucket); err == nil {
 		if ret.LockEnabled {
-			lock, _, _, _, err := clnt.GetObjectLockConfig(ctx, rCfg.GetDestination().Bucket)
+			lock, _, _, _, err := clnt.GetObjectLockConfig(ctx, dest.Bucket)
 			if err != nil || lock != "Enabled" {
-				return false, BucketReplicationDestinationMissingLock{Bucket: rCfg.GetDestination().Bucket}
+				return false, BucketReplicationDestinationMissingLock{Bucket: dest.Bucket}
 			}
 		}
 	}
 	// validate replication ARN against target endpoint
-	c, ok := globalBucketTargetSys.arnRemotesMap[rCfg.RoleArn]
+	c, ok := globalBucketTargetSys.arnRemotesMap[arnStr]
 	if ok {
 		if c.EndpointURL().String() == clnt.EndpointURL().String() {
 			sameTarget, _ := isLocalHost(clnt.EndpointURL().Hostname(), clnt.EndpointURL().Port(), globalMinioPort)
@@ -147,7 +171,7 @@ func checkReplicateDelete(ctx context.Context, bucket string, dobj ObjectToDelet
 	return oi.DeleteMarker, rcfg.Replicate(opts)
 }
 
-// replicate deletes to the designated replication target if replication configuration
+// replicate deletes to the designated replication targets if replication configuration
 // has delete marker replication or delete replication (MinIO extension to allow deletes where version id
 // is specified) enabled.
 // Similar to bucket replication for PUT operation, soft delete (a.k.a setting delete marker) and
@@ -156,29 +180,56 @@ func checkReplicateDelete(ctx context.Context, bucket string, dobj ObjectToDelet
 // then be retried by healing. In the case of permanent deletes, until the replication is completed on the
 // target cluster, the object version is marked deleted on the source and hidden from listing. It is permanently
 // deleted from the source when the VersionPurgeStatus changes to "Complete", i.e after replication succeeds
-// on target.
-func replicateDelete(ctx context.Context, dobj DeletedObjectVersionInfo, objectAPI ObjectLayer) {
+// on all targets. Deletes are idempotent, so a failure on any target retries the delete on all of them.
+// The returned error indicates whether the replication needs to be retried.
+func replicateDelete(ctx context.Context, dobj DeletedObjectVersionInfo, objectAPI ObjectLayer) error {
 	bucket := dobj.Bucket
 	rcfg, err := getReplicationConfig(ctx, bucket)
 	if err != nil || rcfg == nil {
-		return
-	}
-	tgt := globalBucketTargetSys.GetRemoteTargetClient(ctx, rcfg.RoleArn)
-	if tgt == nil {
-		return
+		return nil
 	}
 	versionID := dobj.DeleteMarkerVersionID
 	if versionID == "" {
 		versionID = dobj.VersionID
 	}
-	rmErr := tgt.RemoveObject(ctx, rcfg.GetDestination().Bucket, dobj.ObjectName, miniogo.RemoveObjectOptions{
-		VersionID: versionID,
-		Internal: miniogo.AdvancedRemoveOptions{
-			ReplicationDeleteMarker: dobj.DeleteMarkerVersionID != "",
-			ReplicationMTime:        dobj.DeleteMarkerMTime.Time,
-			ReplicationStatus:       miniogo.ReplicationStatusReplica,
-		},
-	})
+	opts := replication.ObjectOpts{
+		Name:         dobj.ObjectName,
+		DeleteMarker: dobj.DeleteMarkerVersionID != "",
+		VersionID:    dobj.VersionID,
+	}
+	var rmErr error
+	var failedArns []string
+	for _, arn := range rcfg.FilterTargetArns(opts) {
+		opts.TargetArn = arn
+		tgt := globalBucketTargetSys.GetRemoteTargetClient(ctx, arn)
+		if tgt == nil {
+			err = replicationTargetError{Arn: arn, Err: BucketRemoteTargetNotFound{Bucket: bucket}}
+			if rmErr == nil {
+				rmErr = err
+			}
+			failedArns = append(failedArns, arn)
+			globalReplicationState.updateStats(bucket, arn, 0, 0, err)
+			continue
+		}
+		err = tgt.RemoveObject(ctx, rcfg.GetTargetDestination(opts).Bucket, dobj.ObjectName, miniogo.RemoveObjectOptions{
+			VersionID: versionID,
+			Internal: miniogo.AdvancedRemoveOptions{
+				ReplicationDeleteMarker: dobj.DeleteMarkerVersionID != "",
+				ReplicationMTime:        dobj.DeleteMarkerMTime.Time,
+				ReplicationStatus:       miniogo.ReplicationStatusReplica,
+			},
+		})
+		if err != nil {
+			err = replicationError(arn, err)
+			failedArns = append(failedArns, arn)
+			// Errors of the object take precedence over unreachable
+			// targets, which are only backed off.
+			if _, ok := rmErr.(replicationTargetError); rmErr == nil || ok {
+				rmErr = err
+			}
+		}
+		globalReplicationState.updateStats(bucket, arn, 0, 0, err)
+	}
 
 	replicationStatus := dobj.DeleteMarkerReplicationStatus
 	versionPurgeStatus := dobj.VersionPurgeStatus
@@ -227,6 +278,22 @@ func replicateDelete(ctx context.Context, dobj DeletedObjectVersionInfo, objectA
 	}); err != nil {
 		logger.LogIf(ctx, fmt.Errorf("Unable to update replication metadata for %s/%s %s: %w", bucket, dobj.ObjectName, dobj.VersionID, err))
 	}
+	if rmErr != nil {
+		return replicationPartialError{Arns: failedArns, Err: rmErr}
+	}
+	return nil
+}
+
+// replicationError classifies an error returned by the remote target,
+// connectivity issues are reported as replicationTargetError.
+func replicationError(arn string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if xnet.IsNetworkOrHostDown(err, false) {
+		return replicationTargetError{Arn: arn, Err: err}
+	}
+	return err
 }
 
 func getCopyObjMetadata(oi ObjectInfo, dest replication.Destination) map[string]string {
@@ -350,14 +417,32 @@ func getReplicationAction(oi1 ObjectInfo, oi2 minio.ObjectInfo) replicationActio
 			return replicateMetadata
 		}
 	}
+	// Replication status differs between source and replica and, with
+	// several targets, changes on the source as each target completes.
+	meta1 := make(map[string]string, len(oi1.UserDefined))
+	for k, v := range oi1.UserDefined {
+		if isReplicationStatusMetadata(k) {
+			continue
+		}
+		meta1[k] = v
+	}
+	meta2 := make(map[string]string, len(oi2.Metadata))
+	for k, v := range oi2.Metadata {
+		meta2[k] = strings.Join(v, "")
+	}
 	for k, v := range oi2.UserMetadata {
-		oi2.Metadata[k] = []string{v}
+		meta2[k] = v
+	}
+	for k := range meta2 {
+		if isReplicationStatusMetadata(k) {
+			delete(meta2, k)
+		}
 	}
-	if len(oi2.Metadata) != len(oi1.UserDefined) {
+	if len(meta2) != len(meta1) {
 		return replicateMetadata
 	}
-	for k1, v1 := range oi1.UserDefined {
-		if v2, ok := oi2.Metadata[k1]; !ok || v1 != strings.Join(v2, "") {
+	for k1, v1 := range meta1 {
+		if v2, ok := meta2[k1]; !ok || v1 != v2 {
 			return replicateMetadata
 		}
 	}
@@ -368,40 +453,136 @@ func getReplicationAction(oi1 ObjectInfo, oi2 minio.ObjectInfo) replicationActio
 	return replicateNone
 }
 
-// replicateObject replicates the specified version of the object to destination bucket
-// The source object is then updated to reflect the replication status.
-func replicateObject(ctx context.Context, objInfo ObjectInfo, objectAPI ObjectLayer) {
-	bucket := objInfo.Bucket
-	object := objInfo.Name
+// replicateObject replicates the object version of the task to the destination
+// buckets of all targets it has not been replicated to yet. The source object is then
+// updated to reflect the replication status per target. The resync target of the task
+// is replicated to even if it completed before, which verifies the replica against
+// the source. The returned error indicates whether the replication needs to be retried.
+func replicateObject(ctx context.Context, t replicationTask, objectAPI ObjectLayer) error {
+	bucket := t.Bucket
+	object := t.Object
 
 	cfg, err := getReplicationConfig(ctx, bucket)
 	if err != nil {
+		if _, ok := err.(BucketReplicationConfigNotFound); ok {
+			// Replication was turned off in the meantime.
+			return nil
+		}
 		logger.LogIf(ctx, err)
-		return
+		return err
 	}
-	tgt := globalBucketTargetSys.GetRemoteTargetClient(ctx, cfg.RoleArn)
-	if tgt == nil {
-		logger.LogIf(ctx, fmt.Errorf("failed to get target for bucket:%s arn:%s", bucket, cfg.RoleArn))
-		return
-	}
-	gr, err := objectAPI.GetObjectNInfo(ctx, bucket, object, nil, http.Header{}, readLock, ObjectOptions{
-		VersionID: objInfo.VersionID,
+	objInfo, err := objectAPI.GetObjectInfo(ctx, bucket, object, ObjectOptions{
+		VersionID: t.VersionID,
 	})
 	if err != nil {
-		return
+		if isErrObjectNotFound(err) || isErrVersionNotFound(err) {
+			// Nothing left to replicate.
+			return nil
+		}
+		return err
 	}
-	objInfo = gr.ObjInfo
-	size, err := objInfo.GetActualSize()
-	if err != nil {
-		logger.LogIf(ctx, err)
-		gr.Close()
-		return
+
+	opts := replication.ObjectOpts{
+		Name:     object,
+		UserTags: objInfo.UserTags,
+		SSEC:     crypto.SSEC.IsEncrypted(objInfo.UserDefined),
+	}
+	arns := cfg.FilterTargetArns(opts)
+	if len(arns) == 0 {
+		return nil
 	}
+	statuses := getTargetReplicationStatus(objInfo.UserDefined)
+
+	var rerr error
+	var failedArns []string
+	var replicated bool
+	for _, arn := range arns {
+		if statuses[arn] == replication.Complete && arn != t.ResyncTarget {
+			continue
+		}
+		opts.TargetArn = arn
+		replicationStatus := replication.Complete
+		err = replicateObjectToTarget(ctx, objInfo, objectAPI, arn, cfg.GetTargetDestination(opts))
+		if err != nil {
+			replicationStatus = replication.Failed
+			err = replicationError(arn, err)
+			failedArns = append(failedArns, arn)
+			// Errors of the object take precedence over unreachable
+			// targets, which are only backed off.
+			if _, ok := rerr.(replicationTargetError); rerr == nil || ok {
+				rerr = err
+			}
+		}
+		// Backfilled objects were uploaded long before, their lag
+		// does not reflect the state of the replication.
+		var lag time.Duration
+		if t.Resync == "" {
+			lag = UTCNow().Sub(objInfo.ModTime)
+		}
+		globalReplicationState.updateStats(bucket, arn, objInfo.Size, lag, err)
+		statuses[arn] = replicationStatus
+		replicated = true
+
+		// FIXME: add support for missing replication events
+		// - event.ObjectReplicationNotTracked
+		// - event.ObjectReplicationMissedThreshold
+		// - event.ObjectReplicationReplicatedAfterThreshold
+		var eventName = event.ObjectReplicationComplete
+		if replicationStatus == replication.Failed {
+			eventName = event.ObjectReplicationFailed
+		}
+		sendEvent(eventArgs{
+			EventName:  eventName,
+			BucketName: bucket,
+			Object:     objInfo,
+			Host:       "Internal: [Replication]",
+		})
+	}
+
+	if rerr != nil {
+		rerr = replicationPartialError{Arns: failedArns, Err: rerr}
+	}
+
+	replicationStatus := compositeReplicationStatus(arns, statuses)
+	if !replicated && replicationStatus == objInfo.ReplicationStatus {
+		return rerr
+	}
+	objInfo.UserDefined[xhttp.AmzBucketReplicationStatus] = replicationStatus.String()
+	objInfo.UserDefined[replicationStatusKey] = encodeTargetReplicationStatus(statuses)
+	if objInfo.UserTags != "" {
+		objInfo.UserDefined[xhttp.AmzObjectTagging] = objInfo.UserTags
+	}
+
+	objInfo.metadataOnly = true // Perform only metadata updates.
+	if _, err = objectAPI.CopyObject(ctx, bucket, object, bucket, object, objInfo, ObjectOptions{
+		VersionID: objInfo.VersionID,
+	}, ObjectOptions{
+		VersionID: objInfo.VersionID,
+	}); err != nil {
+		logger.LogIf(ctx, fmt.Errorf("Unable to update replication metadata for %s: %s", objInfo.VersionID, err))
+	}
+	return rerr
+}
+
+// replicateObjectToTarget replicates the specified version of the object to the
+// destination bucket of the remote target.
+func replicateObjectToTarget(ctx context.Context, objInfo ObjectInfo, objectAPI ObjectLayer, arn string, dest replication.Destination) error {
+	bucket := objInfo.Bucket
+	object := objInfo.Name
 
-	dest := cfg.GetDestination()
 	if dest.Bucket == "" {
-		gr.Close()
-		return
+		return nil
+	}
+	tgt := globalBucketTargetSys.GetRemoteTargetClient(ctx, arn)
+	if tgt == nil {
+		err := fmt.Errorf("failed to get target for bucket:%s arn:%s", bucket, arn)
+		logger.LogIf(ctx, err)
+		return replicationTargetError{Arn: arn, Err: err}
+	}
+	target, err := globalBucketMetadataSys.GetBucketTarget(bucket, arn)
+	if err != nil {
+		logger.LogIf(ctx, fmt.Errorf("failed to get target for replication bucket:%s cfg:%s err:%s", bucket, arn, err))
+		return err
 	}
 
 	rtype := replicateAll
@@ -409,20 +590,31 @@ func replicateObject(ctx context.Context, objInfo ObjectInfo, objectAPI ObjectLa
 	if err == nil {
 		rtype = getReplicationAction(objInfo, oi)
 		if rtype == replicateNone {
-			gr.Close()
 			// object with same VersionID already exists, replication kicked off by
 			// PutObject might have completed.
-			return
+			return nil
 		}
 	}
 
-	target, err := globalBucketMetadataSys.GetBucketTarget(bucket, cfg.RoleArn)
+	gr, err := objectAPI.GetObjectNInfo(ctx, bucket, object, nil, http.Header{}, readLock, ObjectOptions{
+		VersionID: objInfo.VersionID,
+	})
 	if err != nil {
-		logger.LogIf(ctx, fmt.Errorf("failed to get target for replication bucket:%s cfg:%s err:%s", bucket, cfg.RoleArn, err))
-		return
+		if isErrObjectNotFound(err) || isErrVersionNotFound(err) {
+			// Nothing left to replicate.
+			return nil
+		}
+		return err
 	}
+	objInfo = gr.ObjInfo
+	size, err := objInfo.GetActualSize()
+	if err != nil {
+		logger.LogIf(ctx, err)
+		gr.Close()
+		return nil
+	}
+
 	putOpts := putReplicationOpts(ctx, dest, objInfo)
-	replicationStatus := replication.Complete
 
 	// Setup bandwidth throttling
 	peers, _ := globalEndpoints.peers()
@@ -435,7 +627,7 @@ func replicateObject(ctx context.Context, objInfo ObjectInfo, objectAPI ObjectLa
 	for k, v := range putOpts.Header() {
 		headerSize += len(k) + len(v)
 	}
-	r := bandwidth.NewMonitoredReader(ctx, globalBucketMonitor, objInfo.Bucket, objInfo.Name, gr, headerSize, b, target.BandwidthLimit)
+	r := bandwidth.NewMonitoredReader(ctx, globalBucketMonitor, objInfo.Bucket, arn, objInfo.Name, gr, headerSize, b, target.BandwidthLimit)
 	if rtype == replicateAll {
 		_, err = tgt.PutObject(ctx, dest.Bucket, object, r, size, "", "", putOpts)
 	} else {
@@ -443,38 +635,64 @@ func replicateObject(ctx context.Context, objInfo ObjectInfo, objectAPI ObjectLa
 		dstOpts := miniogo.PutObjectOptions{Internal: miniogo.AdvancedPutOptions{SourceVersionID: objInfo.VersionID}}
 		_, err = tgt.CopyObject(ctx, dest.Bucket, object, dest.Bucket, object, getCopyObjMetadata(objInfo, dest), dstOpts)
 	}
-
 	r.Close()
-	if err != nil {
-		replicationStatus = replication.Failed
-	}
-	objInfo.UserDefined[xhttp.AmzBucketReplicationStatus] = replicationStatus.String()
-	if objInfo.UserTags != "" {
-		objInfo.UserDefined[xhttp.AmzObjectTagging] = objInfo.UserTags
+	return err
+}
+
+// isReplicationStatusMetadata returns true for the metadata keys holding
+// the replication status of an object.
+func isReplicationStatusMetadata(k string) bool {
+	return strings.EqualFold(k, xhttp.AmzBucketReplicationStatus) || strings.EqualFold(k, replicationStatusKey)
+}
+
+// replicationStatusKey - reserved metadata holding the replication status
+// of the object per remote target, X-Amz-Replication-Status holds the
+// status across all targets.
+const replicationStatusKey = ReservedMetadataPrefixLower + "replication-status"
+
+// getTargetReplicationStatus returns the replication status per target
+// ARN, encoded as "arn1=COMPLETE;arn2=FAILED;".
+func getTargetReplicationStatus(meta map[string]string) map[string]replication.StatusType {
+	statuses := make(map[string]replication.StatusType)
+	for _, s := range strings.Split(meta[replicationStatusKey], ";") {
+		kv := strings.SplitN(s, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			continue
+		}
+		statuses[kv[0]] = replication.StatusType(kv[1])
 	}
+	return statuses
+}
 
-	// FIXME: add support for missing replication events
-	// - event.ObjectReplicationNotTracked
-	// - event.ObjectReplicationMissedThreshold
-	// - event.ObjectReplicationReplicatedAfterThreshold
-	var eventName = event.ObjectReplicationComplete
-	if replicationStatus == replication.Failed {
-		eventName = event.ObjectReplicationFailed
+// encodeTargetReplicationStatus encodes the replication status per target.
+func encodeTargetReplicationStatus(statuses map[string]replication.StatusType) string {
+	arns := make([]string, 0, len(statuses))
+	for arn := range statuses {
+		arns = append(arns, arn)
 	}
-	sendEvent(eventArgs{
-		EventName:  eventName,
-		BucketName: bucket,
-		Object:     objInfo,
-		Host:       "Internal: [Replication]",
-	})
-	objInfo.metadataOnly = true // Perform only metadata updates.
-	if _, err = objectAPI.CopyObject(ctx, bucket, object, bucket, object, objInfo, ObjectOptions{
-		VersionID: objInfo.VersionID,
-	}, ObjectOptions{
-		VersionID: objInfo.VersionID,
-	}); err != nil {
-		logger.LogIf(ctx, fmt.Errorf("Unable to update replication metadata for %s: %s", objInfo.VersionID, err))
+	sort.Strings(arns)
+	var sb strings.Builder
+	for _, arn := range arns {
+		sb.WriteString(arn + "=" + statuses[arn].String() + ";")
+	}
+	return sb.String()
+}
+
+// compositeReplicationStatus returns the replication status across the
+// targets, PENDING until all targets were attempted, FAILED if any of
+// them failed and COMPLETE otherwise.
+func compositeReplicationStatus(arns []string, statuses map[string]replication.StatusType) replication.StatusType {
+	status := replication.Complete
+	for _, arn := range arns {
+		switch statuses[arn] {
+		case replication.Complete:
+		case replication.Failed:
+			status = replication.Failed
+		default:
+			return replication.Pending
+		}
 	}
+	return status
 }
 
 // filterReplicationStatusMetadata filters replication status metadata for COPY
@@ -497,6 +715,7 @@ func filterReplicationStatusMetadata(metadata map[string]string) map[string]stri
 	}
 
 	delKey(xhttp.AmzBucketReplicationStatus)
+	delKey(replicationStatusKey)
 	return dst
 }
 
@@ -505,31 +724,6 @@ type DeletedObjectVersionInfo struct {
 	DeletedObject
 	Bucket string
 }
-type replicationState struct {
-	// add future metrics here
-	replicaCh       chan ObjectInfo
-	replicaDeleteCh chan DeletedObjectVersionInfo
-}
-
-func (r *replicationState) queueReplicaTask(oi ObjectInfo) {
-	if r == nil {
-		return
-	}
-	select {
-	case r.replicaCh <- oi:
-	default:
-	}
-}
-
-func (r *replicationState) queueReplicaDeleteTask(doi DeletedObjectVersionInfo) {
-	if r == nil {
-		return
-	}
-	select {
-	case r.replicaDeleteCh <- doi:
-	default:
-	}
-}
 
 var (
 	globalReplicationState *replicationState
@@ -547,14 +741,15 @@ func newReplicationState() *replicationState {
 		globalReplicationConcurrent = 1
 	}
 	rs := &replicationState{
-		replicaCh:       make(chan ObjectInfo, 10000),
-		replicaDeleteCh: make(chan DeletedObjectVersionInfo, 10000),
+		pending:   make(map[string]*replicationTask),
+		failed:    make(map[string]*replicationTask),
+		scheduled: make(map[string]struct{}),
+		inflight:  make(map[string]struct{}),
+		targets:   make(map[string]*targetBackoff),
+		dropped:   make(map[string]uint64),
+		stats:     make(map[string]map[string]*replicationTargetStats),
+		workCh:    make(chan string, 10000),
 	}
-	go func() {
-		<-GlobalContext.Done()
-		close(rs.replicaCh)
-		close(rs.replicaDeleteCh)
-	}()
 	return rs
 }
 
@@ -566,28 +761,54 @@ func (r *replicationState) addWorker(ctx context.Context, objectAPI ObjectLayer)
 			select {
 			case <-ctx.Done():
 				return
-			case oi, ok := <-r.replicaCh:
+			case key := <-r.workCh:
+				t, ok := r.claim(key, UTCNow())
 				if !ok {
-					return
+					continue
 				}
-				replicateObject(ctx, oi, objectAPI)
-			case doi, ok := <-r.replicaDeleteCh:
-				if !ok {
-					return
-				}
-				replicateDelete(ctx, doi, objectAPI)
+				r.finish(t, replicateTask(ctx, t, objectAPI), UTCNow())
 			}
 		}
 	}()
 }
 
+// scheduler periodically hands tasks which are due again to the workers.
+func (r *replicationState) scheduler(ctx context.Context) {
+	ticker := time.NewTicker(replicationDispatchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.schedule(UTCNow())
+		}
+	}
+}
+
 func initBackgroundReplication(ctx context.Context, objectAPI ObjectLayer) {
 	if globalReplicationState == nil {
 		return
 	}
 
+	// Restore the tasks journaled before a restart.
+	_, local := globalEndpoints.peers()
+	if local == "" {
+		local = GetLocalPeer(globalEndpoints)
+	}
+	journal := newReplicationJournal(objectAPI, local)
+	globalReplicationState.mu.Lock()
+	globalReplicationState.journal = journal
+	globalReplicationState.mu.Unlock()
+	if err := globalReplicationState.load(ctx); err != nil {
+		logger.LogIf(ctx, fmt.Errorf("Unable to load replication journal: %w", err))
+	}
+	go journal.run(ctx)
+
 	// Start with globalReplicationConcurrent.
 	for i := 0; i < globalReplicationConcurrent; i++ {
 		globalReplicationState.addWorker(ctx, objectAPI)
 	}
+	go globalReplicationState.scheduler(ctx)
+	globalReplicationState.schedule(UTCNow())
 }
@@ -0,0 +1,95 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	minio "github.com/minio/minio-go/v7"
+	xhttp "github.com/minio/minio/cmd/http"
+	"github.com/minio/minio/pkg/bucket/replication"
+)
+
+func TestTargetReplicationStatus(t *testing.T) {
+	statuses := map[string]replication.StatusType{
+		"arn2": replication.Failed,
+		"arn1": replication.Complete,
+	}
+	encoded := encodeTargetReplicationStatus(statuses)
+	if encoded != "arn1=COMPLETE;arn2=FAILED;" {
+		t.Fatalf("unexpected encoding %s", encoded)
+	}
+	decoded := getTargetReplicationStatus(map[string]string{replicationStatusKey: encoded})
+	if len(decoded) != 2 || decoded["arn1"] != replication.Complete || decoded["arn2"] != replication.Failed {
+		t.Fatalf("unexpected decoding %v", decoded)
+	}
+
+	testCases := []struct {
+		arns     []string
+		expected replication.StatusType
+	}{
+		{[]string{"arn1"}, replication.Complete},
+		{[]string{"arn1", "arn2"}, replication.Failed},
+		{[]string{"arn1", "arn3"}, replication.Pending},
+		{[]string{"arn2", "arn3"}, replication.Pending},
+	}
+	for i, tc := range testCases {
+		if status := compositeReplicationStatus(tc.arns, statuses); status != tc.expected {
+			t.Errorf("Test %d: expected %s, got %s", i+1, tc.expected, status)
+		}
+	}
+}
+
+func TestGetReplicationAction(t *testing.T) {
+	mtime := time.Now().UTC()
+	source := ObjectInfo{
+		ETag:      "etag",
+		VersionID: "v1",
+		Size:      10,
+		ModTime:   mtime,
+		UserDefined: map[string]string{
+			"X-Amz-Meta-A":                   "1",
+			xhttp.AmzBucketReplicationStatus: replication.Failed.String(),
+			replicationStatusKey:             "arn1=COMPLETE;arn2=FAILED;",
+		},
+	}
+	replica := func(meta map[string]string) minio.ObjectInfo {
+		return minio.ObjectInfo{
+			ETag:         "etag",
+			VersionID:    "v1",
+			Size:         10,
+			LastModified: mtime,
+			Metadata: map[string][]string{
+				xhttp.AmzBucketReplicationStatus: {replication.Replica.String()},
+			},
+			UserMetadata: meta,
+		}
+	}
+
+	if action := getReplicationAction(source, replica(map[string]string{"X-Amz-Meta-A": "1"})); action != replicateNone {
+		t.Errorf("expected %s, got %s", replicateNone, action)
+	}
+	if action := getReplicationAction(source, replica(map[string]string{"X-Amz-Meta-A": "2"})); action != replicateMetadata {
+		t.Errorf("expected %s, got %s", replicateMetadata, action)
+	}
+	other := replica(nil)
+	other.ETag = "other"
+	if action := getReplicationAction(source, other); action != replicateAll {
+		t.Errorf("expected %s, got %s", replicateAll, action)
+	}
+}
@@ -87,16 +87,28 @@ func (sys *BucketTargetSys) SetTarget(ctx context.Context, bucket string, tgt *m
 	if !tgt.Type.IsValid() && !update {
 		return BucketRemoteArnTypeInvalid{Bucket: bucket}
 	}
-	clnt, err := sys.getRemoteTargetClient(tgt)
-	if err != nil {
-		return BucketRemoteTargetNotFound{Bucket: tgt.TargetBucket}
-	}
-	// validate if target credentials are ok
-	if _, err = clnt.BucketExists(ctx, tgt.TargetBucket); err != nil {
-		if minio.ToErrorResponse(err).Code == "NoSuchBucket" {
+	var clnt *miniogo.Core
+	if tgt.Type == madmin.ILMLocalService {
+		if localTierOverlapsDrives(tgt.LocalPath, globalEndpoints) {
+			return BucketRemoteLocalPathInvalid{Bucket: bucket, Object: tgt.LocalPath}
+		}
+		// local targets have no client, validate if the directory exists
+		if !IsValidBucketName(tgt.TargetBucket) || !newLocalTransitionTier(*tgt).Exists(ctx) {
+			return BucketRemoteDestinationNotFound{Bucket: tgt.TargetBucket}
+		}
+	} else {
+		var err error
+		clnt, err = sys.getRemoteTargetClient(tgt)
+		if err != nil {
 			return BucketRemoteTargetNotFound{Bucket: tgt.TargetBucket}
 		}
-		return BucketRemoteConnectionErr{Bucket: tgt.TargetBucket}
+		// validate if target credentials are ok
+		if _, err = clnt.BucketExists(ctx, tgt.TargetBucket); err != nil {
+			if minio.ToErrorResponse(err).Code == "NoSuchBucket" {
+				return BucketRemoteTargetNotFound{Bucket: tgt.TargetBucket}
+			}
+			return BucketRemoteConnectionErr{Bucket: tgt.TargetBucket}
+		}
 	}
 	if tgt.Type == madmin.ReplicationService {
 		if !globalIsErasure {
@@ -157,7 +169,9 @@ func (sys *BucketTargetSys) SetTarget(ctx context.Context, bucket string, tgt *m
 	}
 
 	sys.targetsMap[bucket] = newtgts
-	sys.arnRemotesMap[tgt.Arn] = clnt
+	if clnt != nil {
+		sys.arnRemotesMap[tgt.Arn] = clnt
+	}
 	return nil
 }
 
@@ -179,17 +193,17 @@ func (sys *BucketTargetSys) RemoveTarget(ctx context.Context, bucket, arnStr str
 		}
 		// reject removal of remote target if replication configuration is present
 		rcfg, err := getReplicationConfig(ctx, bucket)
-		if err == nil && rcfg.RoleArn == arnStr {
+		if err == nil && rcfg.HasTarget(arnStr) {
 			if _, ok := sys.arnRemotesMap[arnStr]; ok {
 				return BucketRemoteRemoveDisallowed{Bucket: bucket}
 			}
 		}
 	}
-	if arn.Type == madmin.ILMService {
+	if arn.Type.IsTransition() {
 		// reject removal of remote target if lifecycle transition uses this arn
 		config, err := globalBucketMetadataSys.GetLifecycleConfig(bucket)
 		if err == nil && transitionSCInUse(ctx, config, bucket, arnStr) {
-			if _, ok := sys.arnRemotesMap[arnStr]; ok {
+			if _, ok := sys.arnRemotesMap[arnStr]; ok || arn.Type == madmin.ILMLocalService {
 				return BucketRemoteRemoveDisallowed{Bucket: bucket}
 			}
 		}
@@ -223,6 +237,30 @@ func (sys *BucketTargetSys) GetRemoteTargetClient(ctx context.Context, arn strin
 	return sys.arnRemotesMap[arn]
 }
 
+// GetTransitionTier returns the tier objects are transitioned to for
+// the ARN of a remote or local ilm target of this bucket.
+func (sys *BucketTargetSys) GetTransitionTier(ctx context.Context, bucket, arnStr string) transitionTier {
+	arn, err := madmin.ParseARN(arnStr)
+	if err != nil {
+		return nil
+	}
+	sys.RLock()
+	defer sys.RUnlock()
+	if arn.Type == madmin.ILMLocalService {
+		for _, t := range sys.targetsMap[bucket] {
+			if t.Arn == arnStr {
+				return newLocalTransitionTier(t)
+			}
+		}
+		return nil
+	}
+	clnt, ok := sys.arnRemotesMap[arnStr]
+	if !ok {
+		return nil
+	}
+	return &remoteTransitionTier{clnt: clnt, bucket: arn.Bucket}
+}
+
 // GetRemoteTargetWithLabel returns bucket target given a target label
 func (sys *BucketTargetSys) GetRemoteTargetWithLabel(ctx context.Context, bucket, targetLabel string) *madmin.BucketTarget {
 	sys.RLock()
@@ -300,6 +338,9 @@ func (sys *BucketTargetSys) UpdateAllTargets(bucket string, tgts *madmin.BucketT
 		sys.targetsMap[bucket] = tgts.Targets
 	}
 	for _, tgt := range tgts.Targets {
+		if tgt.Type == madmin.ILMLocalService {
+			continue
+		}
 		tgtClient, err := sys.getRemoteTargetClient(&tgt)
 		if err != nil {
 			continue
@@ -323,6 +364,9 @@ func (sys *BucketTargetSys) load(ctx context.Context, buckets []BucketInfo, objA
 			sys.targetsMap[bucket.Name] = cfg.Targets
 		}
 		for _, tgt := range cfg.Targets {
+			if tgt.Type == madmin.ILMLocalService {
+				continue
+			}
 			tgtClient, err := sys.getRemoteTargetClient(&tgt)
 			if err != nil {
 				continue
@@ -361,7 +405,8 @@ func (sys *BucketTargetSys) getRemoteARN(bucket string, target *madmin.BucketTar
 	}
 	tgts := sys.targetsMap[bucket]
 	for _, tgt := range tgts {
-		if tgt.Type == target.Type && tgt.TargetBucket == target.TargetBucket && target.URL().String() == tgt.URL().String() {
+		if tgt.Type == target.Type && tgt.TargetBucket == target.TargetBucket && target.URL().String() == tgt.URL().String() &&
+			tgt.LocalPath == target.LocalPath {
 			return tgt.Arn
 		}
 	}
@@ -377,6 +422,7 @@ func generateARN(t *madmin.BucketTarget) string {
 	hash.Write([]byte(t.Type))
 	hash.Write([]byte(t.Region))
 	hash.Write([]byte(t.TargetBucket))
+	hash.Write([]byte(t.LocalPath))
 	hashSum := hex.EncodeToString(hash.Sum(nil))
 	arn := madmin.ARN{
 		Type:   t.Type,
@@ -246,6 +246,11 @@ func handleCommonEnvVars() {
 		logger.Fatal(config.ErrInvalidFSODirectValue(err), "Invalid MINIO_ETCD_ONLY value in environment variable")
 	}
 
+	globalPolicyDebug, err = config.ParseBool(env.Get(config.EnvPolicyDebug, config.EnableOff))
+	if err != nil {
+		logger.Fatal(config.ErrInvalidPolicyDebugValue(err), "Invalid MINIO_POLICY_DEBUG value in environment variable")
+	}
+
 	domains := env.Get(config.EnvDomain, "")
 	if len(domains) != 0 {
 		for _, domainName := range strings.Split(domains, config.ValueSeparator) {
@@ -89,8 +89,9 @@ func initHelp() {
 			Description: "federate multiple clusters for IAM and Bucket DNS",
 		},
 		config.HelpKV{
-			Key:         config.IdentityOpenIDSubSys,
-			Description: "enable OpenID SSO support",
+			Key:             config.IdentityOpenIDSubSys,
+			Description:     "enable OpenID SSO support",
+			MultipleTargets: true,
 		},
 		config.HelpKV{
 			Key:         config.IdentityLDAPSubSys,
@@ -311,7 +312,7 @@ func validateConfig(s config.Config, setDriveCount int) error {
 		env.SetEnvOff()
 	}
 
-	if _, err := openid.LookupConfig(s[config.IdentityOpenIDSubSys][config.Default],
+	if _, err := openid.LookupConfigs(s[config.IdentityOpenIDSubSys],
 		NewGatewayHTTPTransport(), xhttp.DrainBody); err != nil {
 		return err
 	}
@@ -482,11 +483,12 @@ func lookupConfigs(s config.Config, setDriveCount int) {
 		logger.LogIf(ctx, fmt.Errorf("%s env is deprecated please migrate to using `mc encrypt` at bucket level", crypto.EnvKMSAutoEncryption))
 	}
 
-	globalOpenIDConfig, err = openid.LookupConfig(s[config.IdentityOpenIDSubSys][config.Default],
+	openIDConfigs, err := openid.LookupConfigs(s[config.IdentityOpenIDSubSys],
 		NewGatewayHTTPTransport(), xhttp.DrainBody)
 	if err != nil {
 		logger.LogIf(ctx, fmt.Errorf("Unable to initialize OpenID: %w", err))
 	}
+	globalOpenIDConfig = openIDConfigs[config.Default]
 
 	opaCfg, err := opa.LookupConfig(s[config.PolicyOPASubSys][config.Default],
 		NewGatewayHTTPTransport(), xhttp.DrainBody)
@@ -494,7 +496,7 @@ func lookupConfigs(s config.Config, setDriveCount int) {
 		logger.LogIf(ctx, fmt.Errorf("Unable to initialize OPA: %w", err))
 	}
 
-	globalOpenIDValidators = getOpenIDValidators(globalOpenIDConfig)
+	globalOpenIDValidators = getOpenIDValidators(openIDConfigs)
 	globalPolicyOPA = opa.New(opaCfg)
 
 	globalLDAPConfig, err = xldap.Lookup(s[config.IdentityLDAPSubSys][config.Default],
@@ -616,6 +618,8 @@ func applyDynamicConfig(ctx context.Context, s config.Config) error {
 	globalHealConfigMu.Unlock()
 
 	logger.LogIf(ctx, crawlerSleeper.Update(crawlerCfg.Delay, crawlerCfg.MaxWait))
+	globalRealtimeUsage.setPrefixDepth(crawlerCfg.PrefixDepth)
+	globalRealtimeUsage.setEnabled(crawlerCfg.RealtimeUsage)
 
 	// Update all dynamic config values in memory.
 	globalServerConfigMu.Lock()
@@ -750,11 +754,13 @@ func loadConfig(objAPI ObjectLayer) error {
 // enabled providers in server config.
 // A new authentication provider is added like below
 // * Add a new provider in pkg/iam/openid package.
-func getOpenIDValidators(cfg openid.Config) *openid.Validators {
+func getOpenIDValidators(cfgs map[string]openid.Config) *openid.Validators {
 	validators := openid.NewValidators()
 
-	if cfg.JWKS.URL != nil {
-		validators.Add(openid.NewJWT(cfg))
+	for _, cfg := range cfgs {
+		if cfg.JWKS.URL != nil {
+			validators.Add(openid.NewJWT(cfg))
+		}
 	}
 
 	return validators
@@ -149,7 +149,6 @@ var SubSystemsSingleTargets = set.CreateStringSet([]string{
 	KmsKesSubSys,
 	PolicyOPASubSys,
 	IdentityLDAPSubSys,
-	IdentityOpenIDSubSys,
 	HealSubSys,
 	CrawlerSubSys,
 }...)
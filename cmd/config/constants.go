@@ -57,5 +57,7 @@ const (
 
 	EnvETCDOnly = "MINIO_ETCD_ONLY"
 
+	EnvPolicyDebug = "MINIO_POLICY_DEBUG"
+
 	EnvMaxBucketsLimit = "MAX_BUCKETS_LIMIT"
 )
@@ -26,11 +26,15 @@ import (
 
 // Compression environment variables
 const (
-	Delay   = "delay"
-	MaxWait = "max_wait"
+	Delay         = "delay"
+	MaxWait       = "max_wait"
+	PrefixDepth   = "prefix_depth"
+	RealtimeUsage = "realtime_usage"
 
-	EnvDelay   = "MINIO_CRAWLER_DELAY"
-	EnvMaxWait = "MINIO_CRAWLER_MAX_WAIT"
+	EnvDelay         = "MINIO_CRAWLER_DELAY"
+	EnvMaxWait       = "MINIO_CRAWLER_MAX_WAIT"
+	EnvPrefixDepth   = "MINIO_CRAWLER_PREFIX_DEPTH"
+	EnvRealtimeUsage = "MINIO_CRAWLER_REALTIME_USAGE"
 )
 
 // Config represents the heal settings.
@@ -39,6 +43,11 @@ type Config struct {
 	Delay float64 `json:"delay"`
 	// MaxWait is maximum wait time between operations
 	MaxWait time.Duration
+	// PrefixDepth is the number of prefix levels the usage is reported for.
+	PrefixDepth int
+	// RealtimeUsage updates the usage of all buckets on every write and
+	// delete, not only of the buckets with quotas.
+	RealtimeUsage bool
 }
 
 var (
@@ -52,6 +61,14 @@ var (
 			Key:   MaxWait,
 			Value: "15s",
 		},
+		config.KV{
+			Key:   PrefixDepth,
+			Value: "1",
+		},
+		config.KV{
+			Key:   RealtimeUsage,
+			Value: config.EnableOff,
+		},
 	}
 
 	// Help provides help for config values
@@ -68,6 +85,18 @@ var (
 			Optional:    true,
 			Type:        "duration",
 		},
+		config.HelpKV{
+			Key:         PrefixDepth,
+			Description: `number of prefix levels the usage is reported for, defaults to '1'`,
+			Optional:    true,
+			Type:        "number",
+		},
+		config.HelpKV{
+			Key:         RealtimeUsage,
+			Description: `set to 'on' to update the usage of all buckets on every write and delete, defaults to 'off'`,
+			Optional:    true,
+			Type:        "on|off",
+		},
 	}
 )
 
@@ -84,5 +113,24 @@ func LookupConfig(kvs config.KVS) (cfg Config, err error) {
 	if err != nil {
 		return cfg, err
 	}
+	depth := env.Get(EnvPrefixDepth, kvs.Get(PrefixDepth))
+	if depth == "" {
+		depth = "1"
+	}
+	cfg.PrefixDepth, err = strconv.Atoi(depth)
+	if err != nil {
+		return cfg, err
+	}
+	if cfg.PrefixDepth < 0 {
+		return cfg, config.Errorf("invalid prefix depth %d", cfg.PrefixDepth)
+	}
+	realtime := env.Get(EnvRealtimeUsage, kvs.Get(RealtimeUsage))
+	if realtime == "" {
+		realtime = config.EnableOff
+	}
+	cfg.RealtimeUsage, err = config.ParseBool(realtime)
+	if err != nil {
+		return cfg, err
+	}
 	return cfg, nil
 }
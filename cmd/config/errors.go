@@ -36,6 +36,12 @@ var (
 		"Can only accept `on` and `off` values. To enable O_DIRECT for fs backend, set this value to `on`",
 	)
 
+	ErrInvalidPolicyDebugValue = newErrFn(
+		"Invalid policy debug value",
+		"Please check the passed value",
+		"Can only accept `on` and `off` values. To attach the policy decision of denied requests to the audit log, set this value to `on`",
+	)
+
 	ErrInvalidDomainValue = newErrFn(
 		"Invalid domain value",
 		"Please check the passed value",
@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"strconv"
 	"strings"
 	"time"
 
@@ -55,6 +56,18 @@ type Config struct {
 	GroupSearchFilter  string   `json:"groupSearchFilter"`
 	GroupNameAttribute string   `json:"groupNameAttribute"`
 
+	// Expand the groups of the groups of users, see GroupSearchNested.
+	GroupSearchNested bool `json:"groupSearchNested"`
+
+	// Credentials used by the group sync to look up users and
+	// their groups without their password.
+	LookupBindDN       string `json:"lookupBindDN"`
+	LookupBindPassword string `json:"-"`
+
+	// How often the groups of the users with temporary
+	// credentials are synced, zero disables the group sync.
+	GroupSyncInterval time.Duration `json:"groupSyncInterval"`
+
 	stsExpiryDuration time.Duration // contains converted value
 	tlsSkipVerify     bool          // allows skipping TLS verification
 	serverInsecure    bool          // allows plain text connection to LDAP Server
@@ -75,6 +88,10 @@ const (
 	TLSSkipVerify        = "tls_skip_verify"
 	ServerInsecure       = "server_insecure"
 	ServerStartTLS       = "server_starttls"
+	GroupSearchNested    = "group_search_nested"
+	LookupBindDN         = "lookup_bind_dn"
+	LookupBindPassword   = "lookup_bind_password"
+	GroupSyncInterval    = "group_sync_interval"
 
 	EnvServerAddr           = "MINIO_IDENTITY_LDAP_SERVER_ADDR"
 	EnvSTSExpiry            = "MINIO_IDENTITY_LDAP_STS_EXPIRY"
@@ -87,6 +104,10 @@ const (
 	EnvGroupSearchFilter    = "MINIO_IDENTITY_LDAP_GROUP_SEARCH_FILTER"
 	EnvGroupNameAttribute   = "MINIO_IDENTITY_LDAP_GROUP_NAME_ATTRIBUTE"
 	EnvGroupSearchBaseDN    = "MINIO_IDENTITY_LDAP_GROUP_SEARCH_BASE_DN"
+	EnvGroupSearchNested    = "MINIO_IDENTITY_LDAP_GROUP_SEARCH_NESTED"
+	EnvLookupBindDN         = "MINIO_IDENTITY_LDAP_LOOKUP_BIND_DN"
+	EnvLookupBindPassword   = "MINIO_IDENTITY_LDAP_LOOKUP_BIND_PASSWORD"
+	EnvGroupSyncInterval    = "MINIO_IDENTITY_LDAP_GROUP_SYNC_INTERVAL"
 )
 
 // DefaultKVS - default config for LDAP config
@@ -136,25 +157,67 @@ var (
 			Key:   ServerStartTLS,
 			Value: config.EnableOff,
 		},
+		config.KV{
+			Key:   GroupSearchNested,
+			Value: config.EnableOff,
+		},
+		config.KV{
+			Key:   LookupBindDN,
+			Value: "",
+		},
+		config.KV{
+			Key:   LookupBindPassword,
+			Value: "",
+		},
+		config.KV{
+			Key:   GroupSyncInterval,
+			Value: "",
+		},
 	}
 )
 
 const (
 	dnDelimiter = ";"
+
+	// Matching rule of AD expanding the nested groups of a user
+	// e.g. "(member:1.2.840.113556.1.4.1941:=%s)".
+	matchingRuleInChain = "1.2.840.113556.1.4.1941"
+
+	// Maximum depth of nested groups expanded by MinIO.
+	maxNestedGroupsDepth = 10
+
+	// AD userAccountControl flag of disabled accounts.
+	adAccountDisabled = 0x2
+)
+
+// Errors returned by LookupGroups.
+var (
+	ErrUserNotFound = errors.New("LDAP user not found")
+	ErrUserDisabled = errors.New("LDAP user is disabled")
 )
 
 func getGroups(conn *ldap.Conn, sreq *ldap.SearchRequest) ([]string, error) {
-	var groups []string
+	groups, _, err := searchGroups(conn, sreq)
+	return groups, err
+}
+
+// searchGroups - returns the group names and the DNs of the entries
+// found by the search request.
+func searchGroups(conn *ldap.Conn, sreq *ldap.SearchRequest) (groups []string, dns []string, err error) {
 	sres, err := conn.Search(sreq)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	for _, entry := range sres.Entries {
+		dns = append(dns, entry.DN)
+		if len(entry.Attributes) == 0 {
+			continue
+		}
 		// We only queried one attribute,
 		// so we only look up the first one.
 		groups = append(groups, entry.Attributes[0].Values...)
 	}
-	return groups, nil
+	return groups, dns, nil
 }
 
 func (l *Config) bind(conn *ldap.Conn, username, password string) ([]string, error) {
@@ -191,6 +254,75 @@ func (l *Config) Bind(username, password string) ([]string, error) {
 		return nil, err
 	}
 
+	return l.lookupGroups(conn, username, bindDNS)
+}
+
+// Authenticate - binds to ldap to validate the password of the user,
+// used when the groups of the user are already known.
+func (l *Config) Authenticate(username, password string) error {
+	conn, err := l.Connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = l.bind(conn, username, password)
+	return err
+}
+
+// LookupGroups - binds to ldap with the lookup bind credentials and
+// returns the list of groups of the user, ErrUserNotFound is returned
+// for users not in the directory and ErrUserDisabled for disabled AD
+// accounts.
+func (l *Config) LookupGroups(username string) ([]string, error) {
+	if l.LookupBindDN == "" {
+		return nil, fmt.Errorf("'%s' must be set to look up LDAP groups", LookupBindDN)
+	}
+
+	conn, err := l.Connect()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err = conn.Bind(l.LookupBindDN, l.LookupBindPassword); err != nil {
+		return nil, err
+	}
+
+	bindDNS := make([]string, len(l.UsernameFormats))
+	for i, usernameFormat := range l.UsernameFormats {
+		bindDNS[i] = fmt.Sprintf(usernameFormat, username)
+		searchRequest := ldap.NewSearchRequest(
+			bindDNS[i],
+			ldap.ScopeBaseObject, ldap.NeverDerefAliases, 0, 0, false,
+			"(objectClass=*)",
+			[]string{"userAccountControl"},
+			nil,
+		)
+		sres, err := conn.Search(searchRequest)
+		if err != nil {
+			if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+				return nil, ErrUserNotFound
+			}
+			return nil, err
+		}
+		if len(sres.Entries) == 0 {
+			return nil, ErrUserNotFound
+		}
+		if v := sres.Entries[0].GetAttributeValue("userAccountControl"); v != "" {
+			if flags, err := strconv.Atoi(v); err == nil && flags&adAccountDisabled != 0 {
+				return nil, ErrUserDisabled
+			}
+		}
+	}
+
+	return l.lookupGroups(conn, username, bindDNS)
+}
+
+// lookupGroups - searches LDAP for the groups of the user, nested groups
+// are expanded when GroupSearchNested is set.
+func (l *Config) lookupGroups(conn *ldap.Conn, username string, bindDNS []string) ([]string, error) {
+	var err error
 	var groups []string
 	if l.UsernameSearchFilter != "" {
 		for _, userSearchBase := range l.UsernameSearchBaseDNS {
@@ -235,20 +367,86 @@ func (l *Config) Bind(username, password string) ([]string, error) {
 					nil,
 				)
 
-				var newGroups []string
-				newGroups, err = getGroups(conn, searchRequest)
+				var newGroups, groupDNS []string
+				newGroups, groupDNS, err = searchGroups(conn, searchRequest)
 				if err != nil {
 					return nil, err
 				}
 
 				groups = append(groups, newGroups...)
+
+				if l.GroupSearchNested && !strings.Contains(l.GroupSearchFilter, matchingRuleInChain) {
+					newGroups, err = l.nestedGroups(conn, groupSearchBase, groupDNS)
+					if err != nil {
+						return nil, err
+					}
+					groups = append(groups, newGroups...)
+				}
 			}
 		}
 	}
 
+	return uniqueGroups(groups), nil
+}
+
+// nestedGroups - returns the groups of which the given groups are
+// members, recursively. Groups are looked up with the group search
+// filter substituted by the DN of their member group, directories
+// supporting the AD matching rule "1.2.840.113556.1.4.1941" in the group
+// search filter expand the nested groups themselves.
+func (l *Config) nestedGroups(conn *ldap.Conn, groupSearchBase string, groupDNS []string) ([]string, error) {
+	var groups []string
+	seen := make(map[string]struct{}, len(groupDNS))
+	for _, dn := range groupDNS {
+		seen[strings.ToLower(dn)] = struct{}{}
+	}
+
+	for depth := 0; len(groupDNS) > 0 && depth < maxNestedGroupsDepth; depth++ {
+		var parentDNS []string
+		for _, dn := range groupDNS {
+			searchRequest := ldap.NewSearchRequest(
+				groupSearchBase,
+				ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+				strings.Replace(l.GroupSearchFilter, "%s", ldap.EscapeFilter(dn), -1),
+				standardAttributes,
+				nil,
+			)
+			sres, err := conn.Search(searchRequest)
+			if err != nil {
+				return nil, err
+			}
+			for _, entry := range sres.Entries {
+				// Skip groups already seen, group memberships may
+				// have cycles.
+				if _, ok := seen[strings.ToLower(entry.DN)]; ok {
+					continue
+				}
+				seen[strings.ToLower(entry.DN)] = struct{}{}
+				parentDNS = append(parentDNS, entry.DN)
+				if len(entry.Attributes) > 0 {
+					groups = append(groups, entry.Attributes[0].Values...)
+				}
+			}
+		}
+		groupDNS = parentDNS
+	}
 	return groups, nil
 }
 
+// uniqueGroups - removes duplicate groups keeping their order.
+func uniqueGroups(groups []string) []string {
+	seen := make(map[string]struct{}, len(groups))
+	unique := groups[:0]
+	for _, group := range groups {
+		if _, ok := seen[group]; ok {
+			continue
+		}
+		seen[group] = struct{}{}
+		unique = append(unique, group)
+	}
+	return unique
+}
+
 // Connect connect to ldap server.
 func (l *Config) Connect() (ldapConn *ldap.Conn, err error) {
 	if l == nil {
@@ -373,6 +571,30 @@ func Lookup(kvs config.KVS, rootCAs *x509.CertPool) (l Config, err error) {
 		l.GroupSearchBaseDNS = strings.Split(grpSearchBaseDN, dnDelimiter)
 	}
 
+	if v := env.Get(EnvGroupSearchNested, kvs.Get(GroupSearchNested)); v != "" {
+		l.GroupSearchNested, err = config.ParseBool(v)
+		if err != nil {
+			return l, err
+		}
+	}
+
+	l.LookupBindDN = env.Get(EnvLookupBindDN, kvs.Get(LookupBindDN))
+	l.LookupBindPassword = env.Get(EnvLookupBindPassword, kvs.Get(LookupBindPassword))
+
+	if v := env.Get(EnvGroupSyncInterval, kvs.Get(GroupSyncInterval)); v != "" {
+		syncInterval, err := time.ParseDuration(v)
+		if err != nil {
+			return l, errors.New("LDAP group sync interval err:" + err.Error())
+		}
+		if syncInterval <= 0 {
+			return l, errors.New("LDAP group sync interval has to be positive")
+		}
+		if l.LookupBindDN == "" {
+			return l, fmt.Errorf("'%s' must be set to sync LDAP groups", LookupBindDN)
+		}
+		l.GroupSyncInterval = syncInterval
+	}
+
 	l.rootCAs = rootCAs
 	return l, nil
 }
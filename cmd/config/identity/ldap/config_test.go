@@ -0,0 +1,201 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ldap
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/cmd/config"
+)
+
+var testEntries = []testEntry{
+	{dn: "cn=admin,dc=min,dc=io", password: "admin-secret"},
+	{dn: "uid=alice,ou=people,dc=min,dc=io", password: "alice-secret"},
+	{
+		dn:       "uid=bob,ou=people,dc=min,dc=io",
+		password: "bob-secret",
+		attrs:    map[string][]string{"userAccountControl": {"514"}},
+	},
+	{
+		dn: "cn=dev,ou=groups,dc=min,dc=io",
+		attrs: map[string][]string{
+			"objectclass": {"groupOfNames"},
+			"cn":          {"dev"},
+			"member":      {"uid=alice,ou=people,dc=min,dc=io", "uid=bob,ou=people,dc=min,dc=io"},
+		},
+	},
+	{
+		dn: "cn=eng,ou=groups,dc=min,dc=io",
+		attrs: map[string][]string{
+			"objectclass": {"groupOfNames"},
+			"cn":          {"eng"},
+			"member":      {"cn=dev,ou=groups,dc=min,dc=io", "cn=all,ou=groups,dc=min,dc=io"},
+		},
+	},
+	{
+		dn: "cn=all,ou=groups,dc=min,dc=io",
+		attrs: map[string][]string{
+			"objectclass": {"groupOfNames"},
+			"cn":          {"all"},
+			"member":      {"cn=eng,ou=groups,dc=min,dc=io"},
+		},
+	},
+	{
+		dn: "cn=ops,ou=groups,dc=min,dc=io",
+		attrs: map[string][]string{
+			"objectclass": {"groupOfNames"},
+			"cn":          {"ops"},
+			"member":      {"uid=carol,ou=people,dc=min,dc=io"},
+		},
+	},
+}
+
+func newTestConfig(addr string) Config {
+	return Config{
+		Enabled:            true,
+		ServerAddr:         addr,
+		UsernameFormats:    []string{"uid=%s,ou=people,dc=min,dc=io"},
+		GroupSearchFilter:  "(&(objectclass=groupOfNames)(member=%s))",
+		GroupNameAttribute: "cn",
+		GroupSearchBaseDNS: []string{"ou=groups,dc=min,dc=io"},
+		LookupBindDN:       "cn=admin,dc=min,dc=io",
+		LookupBindPassword: "admin-secret",
+		serverInsecure:     true,
+	}
+}
+
+func TestBindGroups(t *testing.T) {
+	s := newTestServer(t, testEntries)
+	defer s.Close()
+
+	testCases := []struct {
+		filter   string
+		nested   bool
+		expected []string
+	}{
+		{
+			filter:   "(&(objectclass=groupOfNames)(member=%s))",
+			expected: []string{"dev"},
+		},
+		{
+			filter:   "(&(objectclass=groupOfNames)(member=%s))",
+			nested:   true,
+			expected: []string{"all", "dev", "eng"},
+		},
+		{
+			filter:   "(&(objectclass=groupOfNames)(member:1.2.840.113556.1.4.1941:=%s))",
+			nested:   true,
+			expected: []string{"all", "dev", "eng"},
+		},
+	}
+
+	for i, testCase := range testCases {
+		l := newTestConfig(s.Addr())
+		l.GroupSearchFilter = testCase.filter
+		l.GroupSearchNested = testCase.nested
+
+		groups, err := l.Bind("alice", "alice-secret")
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		sort.Strings(groups)
+		if !reflect.DeepEqual(groups, testCase.expected) {
+			t.Errorf("Test %d: expected groups %v, got %v", i+1, testCase.expected, groups)
+		}
+	}
+
+	l := newTestConfig(s.Addr())
+	if _, err := l.Bind("alice", "wrong-secret"); err == nil {
+		t.Fatal("Expected bind with a wrong password to fail")
+	}
+	if err := l.Authenticate("alice", "wrong-secret"); err == nil {
+		t.Fatal("Expected authenticate with a wrong password to fail")
+	}
+
+	// Authenticate only binds, it does not search groups.
+	searches := s.Searches()
+	if err := l.Authenticate("alice", "alice-secret"); err != nil {
+		t.Fatal(err)
+	}
+	if s.Searches() != searches {
+		t.Fatal("Expected authenticate not to search LDAP")
+	}
+}
+
+func TestLookupGroups(t *testing.T) {
+	s := newTestServer(t, testEntries)
+	defer s.Close()
+
+	l := newTestConfig(s.Addr())
+	l.GroupSearchNested = true
+
+	groups, err := l.LookupGroups("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(groups)
+	if expected := []string{"all", "dev", "eng"}; !reflect.DeepEqual(groups, expected) {
+		t.Fatalf("Expected groups %v, got %v", expected, groups)
+	}
+
+	if _, err = l.LookupGroups("bob"); err != ErrUserDisabled {
+		t.Fatalf("Expected %v, got %v", ErrUserDisabled, err)
+	}
+	if _, err = l.LookupGroups("carol"); err != ErrUserNotFound {
+		t.Fatalf("Expected %v, got %v", ErrUserNotFound, err)
+	}
+
+	l.LookupBindPassword = "wrong-secret"
+	if _, err = l.LookupGroups("alice"); err == nil {
+		t.Fatal("Expected lookup with wrong lookup bind credentials to fail")
+	}
+
+	l.LookupBindDN = ""
+	if _, err = l.LookupGroups("alice"); err == nil {
+		t.Fatal("Expected lookup without lookup bind DN to fail")
+	}
+}
+
+func TestLookupGroupSync(t *testing.T) {
+	kvs := config.KVS{}
+	for _, kv := range DefaultKVS {
+		kvs.Set(kv.Key, kv.Value)
+	}
+	kvs.Set(ServerAddr, "localhost:389")
+	kvs.Set(UsernameFormat, "uid=%s,ou=people,dc=min,dc=io")
+	kvs.Set(GroupSyncInterval, "15m")
+
+	if _, err := Lookup(kvs, nil); err == nil {
+		t.Fatal("Expected group sync without lookup bind DN to fail")
+	}
+
+	kvs.Set(LookupBindDN, "cn=admin,dc=min,dc=io")
+	kvs.Set(GroupSearchNested, config.EnableOn)
+	l, err := Lookup(kvs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.GroupSyncInterval != 15*time.Minute {
+		t.Fatalf("Expected group sync interval 15m, got %s", l.GroupSyncInterval)
+	}
+	if !l.GroupSearchNested {
+		t.Fatal("Expected nested group search to be enabled")
+	}
+}
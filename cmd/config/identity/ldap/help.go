@@ -58,6 +58,30 @@ var (
 			Optional:    true,
 			Type:        "string",
 		},
+		config.HelpKV{
+			Key:         GroupSearchNested,
+			Description: `expand nested groups of users by searching the groups of their groups, defaults to "off"`,
+			Optional:    true,
+			Type:        "on|off",
+		},
+		config.HelpKV{
+			Key:         LookupBindDN,
+			Description: `DN to bind to LDAP with to look up the groups of users e.g. "cn=admin,dc=myldapserver,dc=com"`,
+			Optional:    true,
+			Type:        "string",
+		},
+		config.HelpKV{
+			Key:         LookupBindPassword,
+			Description: `password of the lookup bind DN`,
+			Optional:    true,
+			Type:        "string",
+		},
+		config.HelpKV{
+			Key:         GroupSyncInterval,
+			Description: `interval to sync the groups of users with temporary credentials e.g. "15m", requires lookup_bind_dn`,
+			Optional:    true,
+			Type:        "duration",
+		},
 		config.HelpKV{
 			Key:         STSExpiry,
 			Description: `temporary credentials validity duration in s,m,h,d. Default is "1h"`,
@@ -0,0 +1,269 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ldap
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	ber "gopkg.in/asn1-ber.v1"
+	ldap "gopkg.in/ldap.v3"
+)
+
+// testEntry is an entry of the directory of testServer.
+type testEntry struct {
+	dn       string
+	password string
+	attrs    map[string][]string
+}
+
+// testServer is an in-process LDAP server answering simple binds and
+// searches, enough for testing group lookups.
+type testServer struct {
+	ln      net.Listener
+	entries []testEntry
+
+	mu       sync.Mutex
+	searches int
+}
+
+func newTestServer(t *testing.T, entries []testEntry) *testServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &testServer{ln: ln, entries: entries}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *testServer) Addr() string {
+	return s.ln.Addr().String()
+}
+
+func (s *testServer) Close() {
+	s.ln.Close()
+}
+
+// Searches returns the number of search requests served.
+func (s *testServer) Searches() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.searches
+}
+
+func (s *testServer) entry(dn string) (testEntry, bool) {
+	for _, e := range s.entries {
+		if strings.EqualFold(e.dn, dn) {
+			return e, true
+		}
+	}
+	return testEntry{}, false
+}
+
+func (s *testServer) serve(conn net.Conn) {
+	defer conn.Close()
+	for {
+		p, err := ber.ReadPacket(conn)
+		if err != nil || len(p.Children) < 2 {
+			return
+		}
+		id := p.Children[0].Value
+		op := p.Children[1]
+
+		var responses []*ber.Packet
+		switch op.Tag {
+		case ldap.ApplicationBindRequest:
+			code := ldap.LDAPResultInvalidCredentials
+			e, ok := s.entry(packetString(op.Children[1]))
+			if ok && e.password != "" && e.password == packetString(op.Children[2]) {
+				code = ldap.LDAPResultSuccess
+			}
+			responses = append(responses, testResult(ldap.ApplicationBindResponse, code))
+		case ldap.ApplicationSearchRequest:
+			responses = s.search(op)
+		default:
+			// Unbind and unsupported requests close the connection.
+			return
+		}
+
+		for _, response := range responses {
+			msg := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+			msg.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "MessageID"))
+			msg.AppendChild(response)
+			if _, err = conn.Write(msg.Bytes()); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *testServer) search(op *ber.Packet) []*ber.Packet {
+	s.mu.Lock()
+	s.searches++
+	s.mu.Unlock()
+
+	baseDN := packetString(op.Children[0])
+	scope, _ := op.Children[1].Value.(int64)
+	filter := op.Children[6]
+
+	var attributes []string
+	for _, attr := range op.Children[7].Children {
+		attributes = append(attributes, packetString(attr))
+	}
+
+	if scope == ldap.ScopeBaseObject {
+		if _, ok := s.entry(baseDN); !ok {
+			return []*ber.Packet{testResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultNoSuchObject)}
+		}
+	}
+
+	var responses []*ber.Packet
+	for _, e := range s.entries {
+		switch {
+		case scope == ldap.ScopeBaseObject && !strings.EqualFold(e.dn, baseDN):
+			continue
+		case !strings.HasSuffix(strings.ToLower(e.dn), strings.ToLower(baseDN)):
+			continue
+		case !s.match(e, filter):
+			continue
+		}
+
+		entry := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+		entry.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, e.dn, "DN"))
+		attrs := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+		for _, name := range attributes {
+			values, ok := e.attrs[name]
+			if !ok {
+				continue
+			}
+			attr := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+			attr.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Type"))
+			set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+			for _, value := range values {
+				set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+			}
+			attr.AppendChild(set)
+			attrs.AppendChild(attr)
+		}
+		entry.AppendChild(attrs)
+		responses = append(responses, entry)
+	}
+	return append(responses, testResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess))
+}
+
+// match evaluates the and, or, not, equality, present and extensible
+// match filters, the only supported extensible match is AD's in chain
+// matching rule.
+func (s *testServer) match(e testEntry, filter *ber.Packet) bool {
+	switch filter.Tag {
+	case ldap.FilterAnd:
+		for _, child := range filter.Children {
+			if !s.match(e, child) {
+				return false
+			}
+		}
+		return true
+	case ldap.FilterOr:
+		for _, child := range filter.Children {
+			if s.match(e, child) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterNot:
+		return !s.match(e, filter.Children[0])
+	case ldap.FilterEqualityMatch:
+		return hasValue(e, packetString(filter.Children[0]), packetString(filter.Children[1]))
+	case ldap.FilterPresent:
+		attr := packetString(filter)
+		if strings.EqualFold(attr, "objectClass") {
+			return true
+		}
+		_, ok := e.attrs[attr]
+		return ok
+	case ldap.FilterExtensibleMatch:
+		var rule, attr, value string
+		for _, child := range filter.Children {
+			switch child.Tag {
+			case ldap.MatchingRuleAssertionMatchingRule:
+				rule = packetString(child)
+			case ldap.MatchingRuleAssertionType:
+				attr = packetString(child)
+			case ldap.MatchingRuleAssertionMatchValue:
+				value = packetString(child)
+			}
+		}
+		if rule != matchingRuleInChain {
+			return false
+		}
+		return s.inChain(e, attr, value, map[string]bool{})
+	}
+	return false
+}
+
+// inChain returns if value is a value of attr of the entry or of the
+// entries named by the values of attr, recursively.
+func (s *testServer) inChain(e testEntry, attr, value string, seen map[string]bool) bool {
+	if seen[strings.ToLower(e.dn)] {
+		return false
+	}
+	seen[strings.ToLower(e.dn)] = true
+	if hasValue(e, attr, value) {
+		return true
+	}
+	for _, dn := range e.attrs[attr] {
+		if child, ok := s.entry(dn); ok && s.inChain(child, attr, value, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasValue(e testEntry, attr, value string) bool {
+	for _, v := range e.attrs[attr] {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func packetString(p *ber.Packet) string {
+	if p.Data == nil {
+		return ""
+	}
+	return p.Data.String()
+}
+
+func testResult(tag ber.Tag, code int) *ber.Packet {
+	result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	result.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, code, "Result Code"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic Message"))
+	return result
+}
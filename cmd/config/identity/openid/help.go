@@ -50,6 +50,24 @@ var (
 			Optional:    true,
 			Type:        "csv",
 		},
+		config.HelpKV{
+			Key:         GroupsClaim,
+			Description: `Comma separated list of JWT claims listing the groups or roles of the user e.g. "groups,roles"`,
+			Optional:    true,
+			Type:        "csv",
+		},
+		config.HelpKV{
+			Key:         GroupsRegex,
+			Description: `only map the groups matching this regex to IAM groups, the first capturing group is the IAM group name e.g. "^minio-(.+)$"`,
+			Optional:    true,
+			Type:        "string",
+		},
+		config.HelpKV{
+			Key:         GroupsPrefix,
+			Description: `prefix added to the IAM group names mapped from groups e.g. "ci-"`,
+			Optional:    true,
+			Type:        "string",
+		},
 		config.HelpKV{
 			Key:         config.Comment,
 			Description: config.DefaultComment,
@@ -23,6 +23,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -49,10 +51,20 @@ type Config struct {
 	ClaimName    string    `json:"claimName,omitempty"`
 	DiscoveryDoc DiscoveryDoc
 	ClientID     string
-	publicKeys   map[string]crypto.PublicKey
-	transport    *http.Transport
-	closeRespFn  func(io.ReadCloser)
-	mutex        *sync.Mutex
+
+	// Name of the provider, config.Default for the default provider.
+	Name string `json:"name,omitempty"`
+
+	// Claims listing the groups or roles of the user, they are
+	// mapped to IAM groups by GroupsRegex and GroupsPrefix.
+	GroupsClaims []string       `json:"groupsClaims,omitempty"`
+	GroupsRegex  *regexp.Regexp `json:"-"`
+	GroupsPrefix string         `json:"groupsPrefix,omitempty"`
+
+	publicKeys  map[string]crypto.PublicKey
+	transport   *http.Transport
+	closeRespFn func(io.ReadCloser)
+	mutex       *sync.Mutex
 }
 
 // PopulatePublicKey - populates a new publickey from the JWKS URL.
@@ -218,7 +230,66 @@ func (p *JWT) Validate(token, dsecs string) (map[string]interface{}, error) {
 
 // ID returns the provider name and authentication type.
 func (p *JWT) ID() ID {
-	return "jwt"
+	if p.Name == "" || p.Name == config.Default {
+		return "jwt"
+	}
+	return ID("jwt" + config.Default + p.Name)
+}
+
+// Issuer returns the issuer of the tokens of the provider, empty when
+// the provider has no discovery document.
+func (p *JWT) Issuer() string {
+	return p.DiscoveryDoc.Issuer
+}
+
+// PolicyClaimName returns the claim naming the canned policies.
+func (p *JWT) PolicyClaimName() string {
+	return p.ClaimPrefix + p.ClaimName
+}
+
+// Groups returns the IAM groups of the groups or roles claims, claim
+// values not matching GroupsRegex are ignored. When GroupsRegex has a
+// capturing group the first submatch is the group name, which is then
+// prefixed by GroupsPrefix.
+func (p *JWT) Groups(claims map[string]interface{}) []string {
+	var values []string
+	for _, claimName := range p.GroupsClaims {
+		switch v := claims[p.ClaimPrefix+claimName].(type) {
+		case string:
+			values = append(values, strings.Split(v, ",")...)
+		case []interface{}:
+			for _, value := range v {
+				if value, ok := value.(string); ok {
+					values = append(values, value)
+				}
+			}
+		}
+	}
+
+	groups := make(map[string]struct{})
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if p.GroupsRegex != nil {
+			m := p.GroupsRegex.FindStringSubmatch(value)
+			if m == nil {
+				continue
+			}
+			if len(m) > 1 {
+				value = m[1]
+			}
+		}
+		groups[p.GroupsPrefix+value] = struct{}{}
+	}
+
+	names := make([]string, 0, len(groups))
+	for group := range groups {
+		names = append(names, group)
+	}
+	sort.Strings(names)
+	return names
 }
 
 // OpenID keys and envs.
@@ -230,12 +301,20 @@ const (
 	ClientID    = "client_id"
 	Scopes      = "scopes"
 
+	GroupsClaim  = "groups_claim"
+	GroupsRegex  = "groups_regex"
+	GroupsPrefix = "groups_prefix"
+
 	EnvIdentityOpenIDClientID    = "MINIO_IDENTITY_OPENID_CLIENT_ID"
 	EnvIdentityOpenIDJWKSURL     = "MINIO_IDENTITY_OPENID_JWKS_URL"
 	EnvIdentityOpenIDURL         = "MINIO_IDENTITY_OPENID_CONFIG_URL"
 	EnvIdentityOpenIDClaimName   = "MINIO_IDENTITY_OPENID_CLAIM_NAME"
 	EnvIdentityOpenIDClaimPrefix = "MINIO_IDENTITY_OPENID_CLAIM_PREFIX"
 	EnvIdentityOpenIDScopes      = "MINIO_IDENTITY_OPENID_SCOPES"
+
+	EnvIdentityOpenIDGroupsClaim  = "MINIO_IDENTITY_OPENID_GROUPS_CLAIM"
+	EnvIdentityOpenIDGroupsRegex  = "MINIO_IDENTITY_OPENID_GROUPS_REGEX"
+	EnvIdentityOpenIDGroupsPrefix = "MINIO_IDENTITY_OPENID_GROUPS_PREFIX"
 )
 
 // DiscoveryDoc - parses the output from openid-configuration
@@ -308,6 +387,18 @@ var (
 			Key:   JwksURL,
 			Value: "",
 		},
+		config.KV{
+			Key:   GroupsClaim,
+			Value: "",
+		},
+		config.KV{
+			Key:   GroupsRegex,
+			Value: "",
+		},
+		config.KV{
+			Key:   GroupsPrefix,
+			Value: "",
+		},
 	}
 )
 
@@ -318,26 +409,92 @@ func Enabled(kvs config.KVS) bool {
 
 // LookupConfig lookup jwks from config, override with any ENVs.
 func LookupConfig(kvs config.KVS, transport *http.Transport, closeRespFn func(io.ReadCloser)) (c Config, err error) {
+	return lookupConfig(config.Default, kvs, transport, closeRespFn)
+}
+
+// LookupConfigs looks up the default and the named OpenID providers, such
+// as "identity_openid:ci", providers may also be named by the suffix of
+// their ENVs e.g. "MINIO_IDENTITY_OPENID_JWKS_URL_CI". The first error is
+// returned along with the configurations.
+func LookupConfigs(targets map[string]config.KVS, transport *http.Transport, closeRespFn func(io.ReadCloser)) (map[string]Config, error) {
+	kvsByName := map[string]config.KVS{config.Default: DefaultKVS}
+	for _, envName := range []string{EnvIdentityOpenIDJWKSURL, EnvIdentityOpenIDURL} {
+		for _, e := range env.List(envName + config.Default) {
+			kvsByName[strings.TrimPrefix(e, envName+config.Default)] = DefaultKVS
+		}
+	}
+	for name, kvs := range targets {
+		kvsByName[name] = kvs
+	}
+
+	// Like LookupConfig, the configuration of a provider is returned
+	// along with its error, a provider may be unreachable at startup.
+	var lookupErr error
+	configs := make(map[string]Config, len(kvsByName))
+	for name, kvs := range kvsByName {
+		c, err := lookupConfig(name, kvs, transport, closeRespFn)
+		if err != nil && lookupErr == nil {
+			lookupErr = err
+			if name != config.Default {
+				lookupErr = config.Errorf("openid provider '%s': %v", name, err)
+			}
+		}
+		configs[name] = c
+	}
+	return configs, lookupErr
+}
+
+func lookupConfig(name string, kvs config.KVS, transport *http.Transport, closeRespFn func(io.ReadCloser)) (c Config, err error) {
 	if err = config.CheckValidKeys(config.IdentityOpenIDSubSys, kvs, DefaultKVS); err != nil {
 		return c, err
 	}
 
-	jwksURL := env.Get(EnvIamJwksURL, "") // Legacy
+	// ENVs of named providers are suffixed by their name.
+	envName := func(key string) string {
+		if name == config.Default {
+			return key
+		}
+		return key + config.Default + name
+	}
+
+	var jwksURL string
+	if name == config.Default {
+		jwksURL = env.Get(EnvIamJwksURL, "") // Legacy
+	}
 	if jwksURL == "" {
-		jwksURL = env.Get(EnvIdentityOpenIDJWKSURL, kvs.Get(JwksURL))
+		jwksURL = env.Get(envName(EnvIdentityOpenIDJWKSURL), kvs.Get(JwksURL))
 	}
 
 	c = Config{
-		ClaimName:   env.Get(EnvIdentityOpenIDClaimName, kvs.Get(ClaimName)),
-		ClaimPrefix: env.Get(EnvIdentityOpenIDClaimPrefix, kvs.Get(ClaimPrefix)),
-		publicKeys:  make(map[string]crypto.PublicKey),
-		ClientID:    env.Get(EnvIdentityOpenIDClientID, kvs.Get(ClientID)),
-		transport:   transport,
-		closeRespFn: closeRespFn,
-		mutex:       &sync.Mutex{}, // allocate for copying
+		ClaimName:    env.Get(envName(EnvIdentityOpenIDClaimName), kvs.Get(ClaimName)),
+		ClaimPrefix:  env.Get(envName(EnvIdentityOpenIDClaimPrefix), kvs.Get(ClaimPrefix)),
+		publicKeys:   make(map[string]crypto.PublicKey),
+		ClientID:     env.Get(envName(EnvIdentityOpenIDClientID), kvs.Get(ClientID)),
+		Name:         name,
+		GroupsPrefix: env.Get(envName(EnvIdentityOpenIDGroupsPrefix), kvs.Get(GroupsPrefix)),
+		transport:    transport,
+		closeRespFn:  closeRespFn,
+		mutex:        &sync.Mutex{}, // allocate for copying
+	}
+
+	if claimList := env.Get(envName(EnvIdentityOpenIDGroupsClaim), kvs.Get(GroupsClaim)); claimList != "" {
+		for _, claim := range strings.Split(claimList, ",") {
+			claim = strings.TrimSpace(claim)
+			if claim == "" {
+				return c, config.Errorf("empty groups claim is not allowed '%s', please refer to our documentation", claimList)
+			}
+			c.GroupsClaims = append(c.GroupsClaims, claim)
+		}
+	}
+
+	if groupsRegex := env.Get(envName(EnvIdentityOpenIDGroupsRegex), kvs.Get(GroupsRegex)); groupsRegex != "" {
+		c.GroupsRegex, err = regexp.Compile(groupsRegex)
+		if err != nil {
+			return c, config.Errorf("invalid groups regex '%s': %v", groupsRegex, err)
+		}
 	}
 
-	configURL := env.Get(EnvIdentityOpenIDURL, kvs.Get(ConfigURL))
+	configURL := env.Get(envName(EnvIdentityOpenIDURL), kvs.Get(ConfigURL))
 	if configURL != "" {
 		c.URL, err = xnet.ParseHTTPURL(configURL)
 		if err != nil {
@@ -349,7 +506,7 @@ func LookupConfig(kvs config.KVS, transport *http.Transport, closeRespFn func(io
 		}
 	}
 
-	if scopeList := env.Get(EnvIdentityOpenIDScopes, kvs.Get(Scopes)); scopeList != "" {
+	if scopeList := env.Get(envName(EnvIdentityOpenIDScopes), kvs.Get(Scopes)); scopeList != "" {
 		var scopes []string
 		for _, scope := range strings.Split(scopeList, ",") {
 			scope = strings.TrimSpace(scope)
@@ -19,11 +19,19 @@ package openid
 import (
 	"crypto"
 	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"reflect"
+	"regexp"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/minio/minio/cmd/config"
+
 	xnet "github.com/minio/minio/pkg/net"
 )
 
@@ -156,6 +164,89 @@ func TestJWT(t *testing.T) {
 	}
 }
 
+func TestJWTGroups(t *testing.T) {
+	testCases := []struct {
+		cfg      Config
+		claims   map[string]interface{}
+		expected []string
+	}{
+		{
+			cfg:      Config{GroupsClaims: []string{"groups"}},
+			claims:   map[string]interface{}{"groups": []interface{}{"dev", "ops", "dev"}},
+			expected: []string{"dev", "ops"},
+		},
+		{
+			cfg:      Config{GroupsClaims: []string{"groups", "roles"}, GroupsPrefix: "oidc-"},
+			claims:   map[string]interface{}{"groups": "dev, ops", "roles": []interface{}{"admin"}},
+			expected: []string{"oidc-admin", "oidc-dev", "oidc-ops"},
+		},
+		{
+			cfg: Config{
+				GroupsClaims: []string{"groups"},
+				GroupsRegex:  regexp.MustCompile(`^/minio/(.+)$`),
+			},
+			claims:   map[string]interface{}{"groups": []interface{}{"/minio/dev", "/other/ops"}},
+			expected: []string{"dev"},
+		},
+		{
+			cfg:      Config{GroupsClaims: []string{"groups"}, ClaimPrefix: "https://minio/"},
+			claims:   map[string]interface{}{"https://minio/groups": []interface{}{"dev"}, "groups": "ops"},
+			expected: []string{"dev"},
+		},
+		{
+			cfg:      Config{},
+			claims:   map[string]interface{}{"groups": []interface{}{"dev"}},
+			expected: []string{},
+		},
+	}
+
+	for i, testCase := range testCases {
+		groups := NewJWT(testCase.cfg).Groups(testCase.claims)
+		if !reflect.DeepEqual(groups, testCase.expected) {
+			t.Errorf("Test %d: expected groups %v, got %v", i+1, testCase.expected, groups)
+		}
+	}
+}
+
+func TestLookupConfigs(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"keys":[]}`))
+	}))
+	defer ts.Close()
+
+	kvs := config.KVS{}
+	for _, kv := range DefaultKVS {
+		kvs.Set(kv.Key, kv.Value)
+	}
+	kvs.Set(JwksURL, ts.URL)
+	kvs.Set(GroupsClaim, "groups")
+
+	configs, err := LookupConfigs(map[string]config.KVS{
+		config.Default: DefaultKVS,
+		"ci":           kvs,
+	}, nil, func(rc io.ReadCloser) { io.Copy(ioutil.Discard, rc) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(configs) != 2 {
+		t.Fatalf("Expected 2 providers, got %d", len(configs))
+	}
+	if cfg := configs[config.Default]; cfg.JWKS.URL != nil {
+		t.Fatalf("Expected the default provider to be disabled, got %s", cfg.JWKS.URL)
+	}
+	cfg := configs["ci"]
+	if cfg.JWKS.URL == nil || cfg.JWKS.URL.String() != ts.URL {
+		t.Fatalf("Expected JWKS URL %s, got %v", ts.URL, cfg.JWKS.URL)
+	}
+	if !reflect.DeepEqual(cfg.GroupsClaims, []string{"groups"}) {
+		t.Fatalf("Expected groups claims [groups], got %v", cfg.GroupsClaims)
+	}
+	if id := NewJWT(cfg).ID(); id != "jwt_ci" {
+		t.Fatalf("Unexpected id %s for the validator", id)
+	}
+}
+
 func TestDefaultExpiryDuration(t *testing.T) {
 	testCases := []struct {
 		reqURL    string
@@ -19,7 +19,10 @@ package openid
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
 )
 
 // ID - holds identification name authentication validator target.
@@ -85,6 +88,53 @@ func (list *Validators) Get(id ID) (p Validator, err error) {
 	return p, nil
 }
 
+// issuerValidator is implemented by providers knowing the issuer of
+// their tokens.
+type issuerValidator interface {
+	Issuer() string
+}
+
+// Validate - validates the token with the provider which issued it and
+// returns the provider along with the claims of the token. Providers are
+// matched by the issuer of the token, the token is validated by every
+// provider in turn when none has its issuer.
+func (list *Validators) Validate(token, duration string) (Validator, map[string]interface{}, error) {
+	list.RLock()
+	providers := make([]Validator, 0, len(list.providers))
+	for _, p := range list.providers {
+		providers = append(providers, p)
+	}
+	list.RUnlock()
+
+	if len(providers) == 0 {
+		return nil, nil, errors.New("no OpenID provider is configured")
+	}
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].ID() < providers[j].ID()
+	})
+
+	var claims jwtgo.MapClaims
+	if _, _, err := new(jwtgo.Parser).ParseUnverified(token, &claims); err == nil {
+		if issuer, ok := claims["iss"].(string); ok && issuer != "" {
+			for _, p := range providers {
+				if ip, ok := p.(issuerValidator); ok && ip.Issuer() == issuer {
+					m, err := p.Validate(token, duration)
+					return p, m, err
+				}
+			}
+		}
+	}
+
+	var err error
+	for _, p := range providers {
+		var m map[string]interface{}
+		if m, err = p.Validate(token, duration); err == nil {
+			return p, m, nil
+		}
+	}
+	return nil, nil, err
+}
+
 // NewValidators - creates Validators.
 func NewValidators() *Validators {
 	return &Validators{providers: make(map[ID]Validator)}
@@ -115,12 +115,19 @@ func runDataCrawler(ctx context.Context, objAPI ObjectLayer) {
 
 			// Wait before starting next cycle and wait on startup.
 			results := make(chan DataUsageInfo, 1)
-			go storeDataUsageInBackend(ctx, objAPI, results)
+			go storeDataUsageInBackend(ctx, objAPI, UTCNow(), results)
 			bf, err := globalNotificationSys.updateBloomFilter(ctx, nextBloomCycle)
 			logger.LogIf(ctx, err)
 			err = objAPI.CrawlAndGetDataUsage(ctx, bf, results)
 			close(results)
 			logger.LogIf(ctx, err)
+
+			// Generate inventory reports which are due.
+			startBucketInventories(ctx, objAPI)
+
+			// Count the usage of the users and groups with a quota.
+			startQuotaOwnersCount(ctx, objAPI)
+
 			if err == nil {
 				// Store new cycle...
 				nextBloomCycle++
@@ -763,6 +770,27 @@ type actionMeta struct {
 	oi               ObjectInfo
 	successorModTime time.Time // The modtime of the successor version
 	numVersions      int       // The number of versions of this object
+	// The number of noncurrent versions of this object newer than this version
+	newerNoncurrentVersions int
+}
+
+// noncurrentVersionCounter counts the noncurrent versions of an object
+// visited newest first, delete markers are not counted.
+type noncurrentVersionCounter struct {
+	visited    int
+	noncurrent int
+}
+
+// next returns the number of noncurrent versions newer than the next
+// version of the object.
+func (c *noncurrentVersionCounter) next(deleteMarker bool) int {
+	newer := c.noncurrent
+	// All versions but the first are noncurrent.
+	if c.visited > 0 && !deleteMarker {
+		c.noncurrent++
+	}
+	c.visited++
+	return newer
 }
 
 // applyActions will apply lifecycle checks on to a scanned item.
@@ -805,6 +833,7 @@ func (i *crawlItem) applyActions(ctx context.Context, o ObjectLayer, meta action
 			Name:             i.objectPath(),
 			UserTags:         meta.oi.UserTags,
 			ModTime:          meta.oi.ModTime,
+			Size:             meta.oi.Size,
 			VersionID:        meta.oi.VersionID,
 			DeleteMarker:     meta.oi.DeleteMarker,
 			IsLatest:         meta.oi.IsLatest,
@@ -813,6 +842,8 @@ func (i *crawlItem) applyActions(ctx context.Context, o ObjectLayer, meta action
 			RestoreOngoing:   meta.oi.RestoreOngoing,
 			RestoreExpires:   meta.oi.RestoreExpires,
 			TransitionStatus: meta.oi.TransitionStatus,
+
+			NewerNoncurrentVersions: meta.newerNoncurrentVersions,
 		})
 	if i.debug {
 		if versionID != "" {
@@ -860,6 +891,7 @@ func (i *crawlItem) applyActions(ctx context.Context, o ObjectLayer, meta action
 		Name:             i.objectPath(),
 		UserTags:         obj.UserTags,
 		ModTime:          obj.ModTime,
+		Size:             obj.Size,
 		VersionID:        obj.VersionID,
 		DeleteMarker:     obj.DeleteMarker,
 		IsLatest:         obj.IsLatest,
@@ -868,8 +900,10 @@ func (i *crawlItem) applyActions(ctx context.Context, o ObjectLayer, meta action
 		RestoreOngoing:   obj.RestoreOngoing,
 		RestoreExpires:   obj.RestoreExpires,
 		TransitionStatus: obj.TransitionStatus,
+
+		NewerNoncurrentVersions: meta.newerNoncurrentVersions,
 	}
-	action = i.lifeCycle.ComputeAction(lcOpts)
+	action, ruleIdx := i.lifeCycle.ComputeActionRule(lcOpts)
 	if i.debug {
 		logger.Info(color.Green("applyActions:")+" lifecycle: Secondary scan: %v", action)
 	}
@@ -917,6 +951,7 @@ func (i *crawlItem) applyActions(ctx context.Context, o ObjectLayer, meta action
 			}
 		}
 		globalTransitionState.queueTransitionTask(obj)
+		i.logAction(action, i.lifeCycle.Rules[ruleIdx].ID, obj)
 		return 0
 	}
 
@@ -925,15 +960,21 @@ func (i *crawlItem) applyActions(ctx context.Context, o ObjectLayer, meta action
 			logger.LogIf(ctx, err)
 			return size
 		}
+		i.logAction(action, i.lifeCycle.Rules[ruleIdx].ID, obj)
 		// Notification already sent at *deleteTransitionedObject*, return '0' here.
 		return 0
 	}
+	expired := obj
 	obj, err = o.DeleteObject(ctx, i.bucket, i.objectPath(), opts)
 	if err != nil {
 		// Assume it is still there.
 		logger.LogIf(ctx, err)
 		return size
 	}
+	i.logAction(action, i.lifeCycle.Rules[ruleIdx].ID, expired)
+	if opts.VersionID != "" || !opts.Versioned {
+		globalBucketQuotaSys.objectDeleted(ctx, i.bucket, expired)
+	}
 
 	eventName := event.ObjectRemovedDelete
 	if obj.DeleteMarker {
@@ -950,6 +991,18 @@ func (i *crawlItem) applyActions(ctx context.Context, o ObjectLayer, meta action
 	return 0
 }
 
+// logAction records the lifecycle action applied to the object version.
+func (i *crawlItem) logAction(action lifecycle.Action, ruleID string, obj ObjectInfo) {
+	globalLifecycleActionLog.add(i.bucket, madmin.LifecycleActionLogEntry{
+		Time:      UTCNow(),
+		Object:    i.objectPath(),
+		VersionID: obj.VersionID,
+		Size:      obj.Size,
+		Rule:      ruleID,
+		Action:    lifecycleActionName(action, obj),
+	})
+}
+
 // objectPath returns the prefix and object name.
 func (i *crawlItem) objectPath() string {
 	return path.Join(i.prefix, i.objectName)
@@ -238,6 +238,49 @@ func (d *dataUsageCache) dui(path string, buckets []BucketInfo) DataUsageInfo {
 		ReplicaSize:            flat.ReplicaSize,
 		BucketsCount:           uint64(len(e.Children)),
 		BucketsUsage:           d.bucketsUsageInfo(buckets),
+		PrefixesUsage:          d.prefixesUsageInfo(buckets, globalRealtimeUsage.getPrefixDepth()),
+	}
+}
+
+// prefixesUsageInfo returns the usage of the prefixes of the buckets, down
+// to depth levels, prefixes are keyed with their trailing slash.
+func (d *dataUsageCache) prefixesUsageInfo(buckets []BucketInfo, depth int) map[string]map[string]PrefixUsageInfo {
+	if depth <= 0 {
+		return nil
+	}
+	var dst = make(map[string]map[string]PrefixUsageInfo, len(buckets))
+	for _, bucket := range buckets {
+		e := d.find(bucket.Name)
+		if e == nil {
+			continue
+		}
+		prefixes := make(map[string]PrefixUsageInfo)
+		d.addPrefixesUsage(prefixes, bucket.Name, *e, depth)
+		if len(prefixes) > 0 {
+			dst[bucket.Name] = prefixes
+		}
+	}
+	return dst
+}
+
+func (d *dataUsageCache) addPrefixesUsage(dst map[string]PrefixUsageInfo, bucket string, e dataUsageEntry, depth int) {
+	for id := range e.Children {
+		child, ok := d.Cache[id]
+		if !ok {
+			continue
+		}
+		flat := child
+		if len(child.Children) > 0 {
+			flat = d.flatten(child)
+		}
+		prefix := strings.TrimPrefix(id, bucket+SlashSeparator) + SlashSeparator
+		dst[prefix] = PrefixUsageInfo{
+			Size:         uint64(flat.Size),
+			ObjectsCount: flat.Objects,
+		}
+		if depth > 1 {
+			d.addPrefixesUsage(dst, bucket, child, depth-1)
+		}
 	}
 }
 
@@ -0,0 +1,577 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+)
+
+const (
+	// Changes of usage of each server are saved under this prefix.
+	realtimeUsagePrefix = minioConfigPrefix + SlashSeparator + "usage"
+
+	// Interval at which the changes of usage are saved, and the changes
+	// of the other servers loaded.
+	realtimeUsageSaveInterval = 30 * time.Second
+
+	// Duration for which the crawled usage is cached.
+	crawledUsageCacheTTL = time.Second
+
+	// Changes of usage made within the same period are summed up.
+	realtimeUsageChangePeriod = time.Minute
+
+	// Maximum number of periods of changes kept per entry, the oldest
+	// periods are summed up beyond.
+	realtimeUsageMaxChanges = 64
+
+	// Version 1 kept the changes since the usage last differed from the
+	// crawled usage, they are not loaded anymore.
+	realtimeUsageVersion2 = 2
+)
+
+// realtimeUsageChange - sum of the changes of usage made within a period,
+// timed by the latest change.
+type realtimeUsageChange struct {
+	quotaUsage
+	Time time.Time `json:"time"`
+}
+
+// realtimeUsageEntry - changes of usage of a bucket, prefix or quota owner.
+// The changes made before a crawl started are dropped, the crawl counted
+// them.
+type realtimeUsageEntry struct {
+	Changes []realtimeUsageChange `json:"changes,omitempty"`
+}
+
+// prune drops the changes made before the crawl started.
+func (e *realtimeUsageEntry) prune(crawlStart time.Time) {
+	i := 0
+	for i < len(e.Changes) && !e.Changes[i].Time.After(crawlStart) {
+		i++
+	}
+	if i > 0 {
+		e.Changes = append(e.Changes[:0], e.Changes[i:]...)
+	}
+}
+
+// update adds the change made at now to the entry, crawlStart is the time
+// the crawl of the crawled usage started.
+func (e *realtimeUsageEntry) update(crawlStart, now time.Time, d quotaUsage) {
+	e.prune(crawlStart)
+	if n := len(e.Changes); n > 0 && now.Truncate(realtimeUsageChangePeriod).Equal(e.Changes[n-1].Time.Truncate(realtimeUsageChangePeriod)) {
+		e.Changes[n-1].quotaUsage = e.Changes[n-1].add(d)
+		if now.After(e.Changes[n-1].Time) {
+			e.Changes[n-1].Time = now
+		}
+		return
+	}
+	if len(e.Changes) >= realtimeUsageMaxChanges {
+		// Kept as long as the newer of both.
+		e.Changes[1].quotaUsage = e.Changes[1].add(e.Changes[0].quotaUsage)
+		e.Changes = append(e.Changes[:0], e.Changes[1:]...)
+	}
+	e.Changes = append(e.Changes, realtimeUsageChange{quotaUsage: d, Time: now})
+}
+
+// changes returns the changes made since the crawl started.
+func (e *realtimeUsageEntry) changes(crawlStart time.Time) quotaUsage {
+	var d quotaUsage
+	for _, c := range e.Changes {
+		if c.Time.After(crawlStart) {
+			d = d.add(c.quotaUsage)
+		}
+	}
+	return d
+}
+
+// realtimeBucketUsage - changes of usage of a bucket and its prefixes.
+type realtimeBucketUsage struct {
+	realtimeUsageEntry
+	Prefixes map[string]*realtimeUsageEntry `json:"prefixes,omitempty"`
+}
+
+// realtimeUsageState - changes of usage counted by a server since the
+// buckets and the usage of the quota owners were crawled.
+type realtimeUsageState struct {
+	Version int                             `json:"version"`
+	Updated time.Time                       `json:"updated"`
+	Buckets map[string]*realtimeBucketUsage `json:"buckets"`
+	Users   map[string]*realtimeUsageEntry  `json:"users,omitempty"`
+	Groups  map[string]*realtimeUsageEntry  `json:"groups,omitempty"`
+}
+
+func newRealtimeUsageState() realtimeUsageState {
+	return realtimeUsageState{
+		Version: realtimeUsageVersion2,
+		Buckets: make(map[string]*realtimeBucketUsage),
+		Users:   make(map[string]*realtimeUsageEntry),
+		Groups:  make(map[string]*realtimeUsageEntry),
+	}
+}
+
+// ownerEntries returns the changes of usage of the users, or of the
+// groups.
+func (s realtimeUsageState) ownerEntries(isGroup bool) map[string]*realtimeUsageEntry {
+	if isGroup {
+		return s.Groups
+	}
+	return s.Users
+}
+
+// realtimeUsage - usage of the buckets and prefixes as crawled, updated on
+// every write and delete since. The changes counted by each server are
+// saved periodically and summed up with the changes of the other servers.
+type realtimeUsage struct {
+	mu          sync.Mutex
+	prefixDepth int
+	enabled     bool
+	local       realtimeUsageState
+	dirty       bool
+	peers       map[string]realtimeUsageState
+
+	crawledMu  sync.Mutex
+	crawled    DataUsageInfo
+	owners     quotaOwnersUsage
+	crawledAt  time.Time
+	refreshing bool
+}
+
+var globalRealtimeUsage = &realtimeUsage{
+	prefixDepth: 1,
+	local:       newRealtimeUsageState(),
+	peers:       make(map[string]realtimeUsageState),
+}
+
+func (u *realtimeUsage) getPrefixDepth() int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.prefixDepth
+}
+
+func (u *realtimeUsage) setPrefixDepth(depth int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.prefixDepth = depth
+}
+
+// isEnabled returns true if the usage of all buckets is updated, not
+// only the usage of the buckets with quotas.
+func (u *realtimeUsage) isEnabled() bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.enabled
+}
+
+func (u *realtimeUsage) setEnabled(enabled bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.enabled = enabled
+}
+
+// usagePrefixes returns the prefixes of the object down to depth levels.
+func usagePrefixes(object string, depth int) []string {
+	var prefixes []string
+	var offset int
+	for i := 0; i < depth; i++ {
+		idx := strings.Index(object[offset:], SlashSeparator)
+		if idx < 0 {
+			break
+		}
+		offset += idx + 1
+		prefixes = append(prefixes, object[:offset])
+	}
+	return prefixes
+}
+
+// getCrawled returns the latest usage saved by the crawler.
+func (u *realtimeUsage) getCrawled(ctx context.Context, objAPI ObjectLayer) DataUsageInfo {
+	dui, _ := u.getCrawledUsage(ctx, objAPI)
+	return dui
+}
+
+// getCrawledUsage returns the latest usage of the buckets and of the
+// quota owners saved by the crawler. Once expired, the usage is reloaded
+// in the background and the previous usage is returned meanwhile.
+func (u *realtimeUsage) getCrawledUsage(ctx context.Context, objAPI ObjectLayer) (DataUsageInfo, quotaOwnersUsage) {
+	u.crawledMu.Lock()
+	defer u.crawledMu.Unlock()
+
+	if time.Since(u.crawledAt) >= crawledUsageCacheTTL && !u.refreshing {
+		u.refreshing = true
+		go u.refreshCrawled(objAPI)
+	}
+	return u.crawled, u.owners
+}
+
+// refreshCrawled reloads the latest usage saved by the crawler.
+func (u *realtimeUsage) refreshCrawled(objAPI ObjectLayer) {
+	ctx, cancel := context.WithTimeout(GlobalContext, 5*time.Second)
+	defer cancel()
+
+	dui, err := loadDataUsageFromBackend(ctx, objAPI)
+	if err != nil {
+		// Keep counting against the previous crawl.
+		logger.LogIf(ctx, err)
+	}
+	owners, oerr := loadQuotaOwnersUsage(ctx, objAPI)
+	if oerr != nil && oerr != errConfigNotFound {
+		logger.LogIf(ctx, oerr)
+	}
+
+	u.crawledMu.Lock()
+	defer u.crawledMu.Unlock()
+
+	u.refreshing = false
+	if err == nil {
+		u.crawled = dui
+	}
+	if oerr == nil || oerr == errConfigNotFound {
+		u.owners = owners
+	}
+	u.crawledAt = UTCNow()
+}
+
+// crawlStart returns the time the crawl of the usage started, the changes
+// made before were counted by the crawl.
+func crawlStart(dui DataUsageInfo) time.Time {
+	if dui.CrawlStart.IsZero() {
+		// Saved by an older server.
+		return dui.LastUpdate
+	}
+	return dui.CrawlStart
+}
+
+func crawledBucketUsage(dui DataUsageInfo, bucket string) quotaUsage {
+	bui := dui.BucketsUsage[bucket]
+	return quotaUsage{Size: int64(bui.Size), Objects: int64(bui.ObjectsCount)}
+}
+
+// updateEntry adds the change to the entry of the map, created if needed.
+func updateEntry(entries map[string]*realtimeUsageEntry, key string, crawlStart, now time.Time, d quotaUsage) {
+	e, ok := entries[key]
+	if !ok {
+		e = &realtimeUsageEntry{}
+		entries[key] = e
+	}
+	e.update(crawlStart, now, d)
+}
+
+// update counts the change of usage of a write or delete of an object
+// owned by the user and groups.
+func (u *realtimeUsage) update(ctx context.Context, objAPI ObjectLayer, bucket, object, user string, groups []string, d quotaUsage) {
+	crawled, owners := u.getCrawledUsage(ctx, objAPI)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	b, ok := u.local.Buckets[bucket]
+	if !ok {
+		b = &realtimeBucketUsage{Prefixes: make(map[string]*realtimeUsageEntry)}
+		u.local.Buckets[bucket] = b
+	}
+	now := UTCNow()
+	b.update(crawlStart(crawled), now, d)
+	for _, prefix := range usagePrefixes(object, u.prefixDepth) {
+		updateEntry(b.Prefixes, prefix, crawlStart(crawled), now, d)
+	}
+	if user != "" {
+		updateEntry(u.local.Users, user, owners.Updated, now, d)
+	}
+	for _, group := range groups {
+		updateEntry(u.local.Groups, group, owners.Updated, now, d)
+	}
+	u.local.Updated = now
+	u.dirty = true
+}
+
+// states returns the changes of usage of this server and of the other
+// servers as last saved, must be called with the lock held.
+func (u *realtimeUsage) states() []realtimeUsageState {
+	states := make([]realtimeUsageState, 0, len(u.peers)+1)
+	states = append(states, u.local)
+	for _, state := range u.peers {
+		states = append(states, state)
+	}
+	return states
+}
+
+// bucketUsage returns the current usage of the bucket, false if the
+// buckets were never crawled.
+func (u *realtimeUsage) bucketUsage(ctx context.Context, objAPI ObjectLayer, bucket string) (quotaUsage, bool) {
+	crawled := u.getCrawled(ctx, objAPI)
+	if crawled.LastUpdate.IsZero() {
+		return quotaUsage{}, false
+	}
+	usage := crawledBucketUsage(crawled, bucket)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	current := usage
+	for _, state := range u.states() {
+		if b, ok := state.Buckets[bucket]; ok {
+			current = current.add(b.changes(crawlStart(crawled)))
+		}
+	}
+	return current, true
+}
+
+// ownerUsage returns the current usage of the objects written by a user,
+// or by the members of a group.
+func (u *realtimeUsage) ownerUsage(ctx context.Context, objAPI ObjectLayer, name string, isGroup bool) quotaUsage {
+	_, owners := u.getCrawledUsage(ctx, objAPI)
+	usage := owners.usage(name, isGroup)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	current := usage
+	for _, state := range u.states() {
+		if e, ok := state.ownerEntries(isGroup)[name]; ok {
+			current = current.add(e.changes(owners.Updated))
+		}
+	}
+	return current
+}
+
+func clampUsage(v int64) uint64 {
+	if v < 0 {
+		return 0
+	}
+	return uint64(v)
+}
+
+// dataUsageInfo returns the usage as crawled updated with the changes
+// counted by all servers since.
+func (u *realtimeUsage) dataUsageInfo(dui DataUsageInfo) DataUsageInfo {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	// Do not update the maps of the crawled usage in place.
+	bucketsUsage := make(map[string]BucketUsageInfo, len(dui.BucketsUsage))
+	for bucket, bui := range dui.BucketsUsage {
+		bucketsUsage[bucket] = bui
+	}
+	prefixesUsage := make(map[string]map[string]PrefixUsageInfo, len(dui.PrefixesUsage))
+	for bucket, prefixes := range dui.PrefixesUsage {
+		prefixesUsage[bucket] = make(map[string]PrefixUsageInfo, len(prefixes))
+		for prefix, pui := range prefixes {
+			prefixesUsage[bucket][prefix] = pui
+		}
+	}
+	bucketSizes := make(map[string]uint64, len(dui.BucketSizes))
+	for bucket, size := range dui.BucketSizes {
+		bucketSizes[bucket] = size
+	}
+	since := crawlStart(dui)
+	dui.BucketsUsage, dui.PrefixesUsage, dui.BucketSizes = bucketsUsage, prefixesUsage, bucketSizes
+
+	// Sum up the changes made since the crawl started.
+	buckets := make(map[string]quotaUsage)
+	prefixes := make(map[string]map[string]quotaUsage)
+	for _, state := range u.states() {
+		var counted bool
+		for bucket, b := range state.Buckets {
+			d := b.changes(since)
+			if d != (quotaUsage{}) {
+				buckets[bucket] = buckets[bucket].add(d)
+				counted = true
+			}
+			for prefix, p := range b.Prefixes {
+				d := p.changes(since)
+				if d == (quotaUsage{}) {
+					continue
+				}
+				if prefixes[bucket] == nil {
+					prefixes[bucket] = make(map[string]quotaUsage)
+				}
+				prefixes[bucket][prefix] = prefixes[bucket][prefix].add(d)
+				counted = true
+			}
+		}
+		if counted && state.Updated.After(dui.RealtimeUpdate) {
+			dui.RealtimeUpdate = state.Updated
+		}
+	}
+
+	var totalSize, totalObjects int64
+	for bucket, d := range buckets {
+		bui, ok := dui.BucketsUsage[bucket]
+		if !ok {
+			dui.BucketsCount++
+		}
+		size := int64(bui.Size) + d.Size
+		objects := int64(bui.ObjectsCount) + d.Objects
+		totalSize += int64(clampUsage(size)) - int64(bui.Size)
+		totalObjects += int64(clampUsage(objects)) - int64(bui.ObjectsCount)
+		bui.Size = clampUsage(size)
+		bui.ObjectsCount = clampUsage(objects)
+		dui.BucketsUsage[bucket] = bui
+		dui.BucketSizes[bucket] = bui.Size
+	}
+	dui.ObjectsTotalSize = clampUsage(int64(dui.ObjectsTotalSize) + totalSize)
+	dui.ObjectsTotalCount = clampUsage(int64(dui.ObjectsTotalCount) + totalObjects)
+
+	for bucket, changes := range prefixes {
+		if dui.PrefixesUsage[bucket] == nil {
+			dui.PrefixesUsage[bucket] = make(map[string]PrefixUsageInfo)
+		}
+		for prefix, d := range changes {
+			pui := dui.PrefixesUsage[bucket][prefix]
+			pui.Size = clampUsage(int64(pui.Size) + d.Size)
+			pui.ObjectsCount = clampUsage(int64(pui.ObjectsCount) + d.Objects)
+			dui.PrefixesUsage[bucket][prefix] = pui
+		}
+	}
+	return dui
+}
+
+// deleteBucket forgets the changes of usage of a removed bucket.
+func (u *realtimeUsage) deleteBucket(bucket string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if _, ok := u.local.Buckets[bucket]; ok {
+		delete(u.local.Buckets, bucket)
+		u.dirty = true
+	}
+	for _, state := range u.peers {
+		delete(state.Buckets, bucket)
+	}
+}
+
+func realtimeUsagePath(node string) string {
+	return pathJoin(realtimeUsagePrefix, getSHA256Hash([]byte(node))+".json")
+}
+
+// save saves the changes of usage counted by this server if any.
+func (u *realtimeUsage) save(ctx context.Context, objAPI ObjectLayer, node string) error {
+	u.mu.Lock()
+	if !u.dirty {
+		u.mu.Unlock()
+		return nil
+	}
+	data, err := json.Marshal(u.local)
+	u.dirty = false
+	u.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return saveConfig(ctx, objAPI, realtimeUsagePath(node), data)
+}
+
+// load loads the changes of usage last saved by a server.
+func loadRealtimeUsage(ctx context.Context, objAPI ObjectLayer, node string) (realtimeUsageState, error) {
+	state := newRealtimeUsageState()
+	data, err := readConfig(ctx, objAPI, realtimeUsagePath(node))
+	if err != nil {
+		return state, err
+	}
+	var v struct {
+		Version int `json:"version"`
+	}
+	if err = json.Unmarshal(data, &v); err != nil {
+		return state, err
+	}
+	if v.Version < realtimeUsageVersion2 {
+		// The changes cannot be timed, the next crawl counts them.
+		return state, nil
+	}
+	if err = json.Unmarshal(data, &state); err != nil {
+		return state, err
+	}
+	if state.Buckets == nil {
+		state.Buckets = make(map[string]*realtimeBucketUsage)
+	}
+	for _, b := range state.Buckets {
+		if b.Prefixes == nil {
+			b.Prefixes = make(map[string]*realtimeUsageEntry)
+		}
+	}
+	if state.Users == nil {
+		state.Users = make(map[string]*realtimeUsageEntry)
+	}
+	if state.Groups == nil {
+		state.Groups = make(map[string]*realtimeUsageEntry)
+	}
+	return state, nil
+}
+
+// loadPeers loads the changes of usage last saved by the other servers.
+func (u *realtimeUsage) loadPeers(ctx context.Context, objAPI ObjectLayer, peers []string) {
+	loaded := make(map[string]realtimeUsageState, len(peers))
+	for _, peer := range peers {
+		state, err := loadRealtimeUsage(ctx, objAPI, peer)
+		if err != nil {
+			if err != errConfigNotFound {
+				logger.LogIf(ctx, err)
+			}
+			continue
+		}
+		loaded[peer] = state
+	}
+
+	u.mu.Lock()
+	u.peers = loaded
+	u.mu.Unlock()
+}
+
+// initRealtimeUsage restores the changes of usage this server counted
+// before it restarted, then saves the changes periodically.
+func initRealtimeUsage(ctx context.Context, objAPI ObjectLayer) {
+	peers, local := globalEndpoints.peers()
+	if local == "" {
+		local = GetLocalPeer(globalEndpoints)
+	}
+	var remotes []string
+	for _, peer := range peers {
+		if peer != local {
+			remotes = append(remotes, peer)
+		}
+	}
+
+	go func() {
+		// Load the crawled usage before it is first needed.
+		globalRealtimeUsage.getCrawledUsage(ctx, objAPI)
+
+		if state, err := loadRealtimeUsage(ctx, objAPI, local); err == nil {
+			globalRealtimeUsage.mu.Lock()
+			globalRealtimeUsage.local = state
+			globalRealtimeUsage.mu.Unlock()
+		} else if err != errConfigNotFound {
+			logger.LogIf(ctx, err)
+		}
+
+		t := time.NewTicker(realtimeUsageSaveInterval)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				logger.LogIf(ctx, globalRealtimeUsage.save(ctx, objAPI, local))
+				globalRealtimeUsage.loadPeers(ctx, objAPI, remotes)
+			}
+		}
+	}()
+}
@@ -0,0 +1,196 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestUsagePrefixes(t *testing.T) {
+	testCases := []struct {
+		object   string
+		depth    int
+		prefixes []string
+	}{
+		{"object", 2, nil},
+		{"a/b/c/object", 0, nil},
+		{"a/b/c/object", 1, []string{"a/"}},
+		{"a/b/c/object", 2, []string{"a/", "a/b/"}},
+		{"a/b/object", 5, []string{"a/", "a/b/"}},
+	}
+	for i, testCase := range testCases {
+		if prefixes := usagePrefixes(testCase.object, testCase.depth); !reflect.DeepEqual(prefixes, testCase.prefixes) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.prefixes, prefixes)
+		}
+	}
+}
+
+func TestRealtimeUsageEntry(t *testing.T) {
+	var e realtimeUsageEntry
+	crawlStart := time.Now()
+	now := crawlStart.Add(time.Hour)
+
+	e.update(crawlStart, now, quotaUsage{Size: 10, Objects: 1})
+	e.update(crawlStart, now.Add(time.Second), quotaUsage{Size: -5, Objects: -1})
+	if len(e.Changes) != 1 {
+		t.Errorf("expected changes within a period to be summed up, got %v", e.Changes)
+	}
+	e.update(crawlStart, now.Add(time.Hour), quotaUsage{Size: 1, Objects: 1})
+	if d := e.changes(crawlStart); d != (quotaUsage{Size: 6, Objects: 1}) {
+		t.Errorf("unexpected changes %v", d)
+	}
+
+	// A newer crawl counted the changes made before it started only.
+	crawlStart = now.Add(30 * time.Minute)
+	if d := e.changes(crawlStart); d != (quotaUsage{Size: 1, Objects: 1}) {
+		t.Errorf("unexpected changes after crawl %v", d)
+	}
+	e.update(crawlStart, now.Add(2*time.Hour), quotaUsage{Size: 2})
+	if len(e.Changes) != 2 {
+		t.Errorf("expected changes counted by the crawl to be dropped, got %v", e.Changes)
+	}
+	if d := e.changes(crawlStart); d != (quotaUsage{Size: 3, Objects: 1}) {
+		t.Errorf("unexpected changes after update %v", d)
+	}
+
+	// The oldest periods are summed up.
+	for i := 0; i < 2*realtimeUsageMaxChanges; i++ {
+		e.update(crawlStart, now.Add(3*time.Hour+time.Duration(i)*realtimeUsageChangePeriod), quotaUsage{Size: 1})
+	}
+	if len(e.Changes) != realtimeUsageMaxChanges {
+		t.Errorf("expected %d periods, got %d", realtimeUsageMaxChanges, len(e.Changes))
+	}
+	if d := e.changes(crawlStart); d != (quotaUsage{Size: 3 + 2*realtimeUsageMaxChanges, Objects: 1}) {
+		t.Errorf("unexpected changes after summing up %v", d)
+	}
+}
+
+func TestRealtimeUsageDataUsageInfo(t *testing.T) {
+	crawlStart := time.Now()
+	crawled := DataUsageInfo{
+		LastUpdate:        crawlStart.Add(time.Minute),
+		CrawlStart:        crawlStart,
+		ObjectsTotalCount: 10,
+		ObjectsTotalSize:  100,
+		BucketsCount:      1,
+		BucketsUsage: map[string]BucketUsageInfo{
+			"bucket": {Size: 100, ObjectsCount: 10},
+		},
+		PrefixesUsage: map[string]map[string]PrefixUsageInfo{
+			"bucket": {"a/": {Size: 40, ObjectsCount: 4}},
+		},
+		BucketSizes: map[string]uint64{"bucket": 100},
+	}
+	after := crawlStart.Add(time.Second)
+
+	u := &realtimeUsage{
+		local: newRealtimeUsageState(),
+		peers: map[string]realtimeUsageState{
+			"peer": {
+				Updated: after,
+				Buckets: map[string]*realtimeBucketUsage{
+					"bucket": {
+						realtimeUsageEntry: realtimeUsageEntry{Changes: []realtimeUsageChange{
+							// Counted by the crawl.
+							{quotaUsage{Size: 1000, Objects: 100}, crawlStart.Add(-time.Second)},
+							{quotaUsage{Size: -50, Objects: -2}, after},
+						}},
+						Prefixes: map[string]*realtimeUsageEntry{
+							"a/": {Changes: []realtimeUsageChange{{quotaUsage{Size: -50, Objects: -2}, after}}},
+						},
+					},
+					// Counted by the crawl.
+					"stale": {
+						realtimeUsageEntry: realtimeUsageEntry{Changes: []realtimeUsageChange{
+							{quotaUsage{Size: 10, Objects: 1}, crawlStart.Add(-time.Hour)},
+						}},
+					},
+				},
+			},
+		},
+	}
+	u.local.Buckets["new"] = &realtimeBucketUsage{
+		realtimeUsageEntry: realtimeUsageEntry{Changes: []realtimeUsageChange{{quotaUsage{Size: 20, Objects: 2}, after}}},
+	}
+
+	dui := u.dataUsageInfo(crawled)
+	if dui.BucketsCount != 2 {
+		t.Errorf("expected 2 buckets, got %d", dui.BucketsCount)
+	}
+	if bui := dui.BucketsUsage["bucket"]; bui.Size != 50 || bui.ObjectsCount != 8 {
+		t.Errorf("unexpected bucket usage %v", bui)
+	}
+	if bui := dui.BucketsUsage["new"]; bui.Size != 20 || bui.ObjectsCount != 2 {
+		t.Errorf("unexpected new bucket usage %v", bui)
+	}
+	if _, ok := dui.BucketsUsage["stale"]; ok {
+		t.Error("unexpected usage of bucket with stale changes")
+	}
+	if dui.ObjectsTotalSize != 70 || dui.ObjectsTotalCount != 10 {
+		t.Errorf("unexpected totals %d %d", dui.ObjectsTotalSize, dui.ObjectsTotalCount)
+	}
+	// Usage never drops below zero.
+	if pui := dui.PrefixesUsage["bucket"]["a/"]; pui.Size != 0 || pui.ObjectsCount != 2 {
+		t.Errorf("unexpected prefix usage %v", pui)
+	}
+	if dui.RealtimeUpdate.IsZero() {
+		t.Error("expected realtime update to be set")
+	}
+	// The crawled usage is left unchanged.
+	if crawled.BucketsUsage["bucket"].Size != 100 || crawled.BucketSizes["bucket"] != 100 {
+		t.Error("crawled usage was modified")
+	}
+}
+
+func TestRealtimeUsageOwners(t *testing.T) {
+	owners := newQuotaOwnersUsage()
+	owners.Updated = time.Now().Add(-time.Minute)
+	owners.Users["alice"] = quotaUsage{Size: 100, Objects: 10}
+	owners.Groups["dev"] = quotaUsage{Size: 200, Objects: 20}
+
+	u := &realtimeUsage{
+		local: newRealtimeUsageState(),
+		peers: map[string]realtimeUsageState{
+			"peer": {
+				Users: map[string]*realtimeUsageEntry{
+					"alice": {Changes: []realtimeUsageChange{{quotaUsage{Size: 10, Objects: 1}, time.Now()}}},
+				},
+				Groups: map[string]*realtimeUsageEntry{
+					// Counted by the crawl.
+					"dev": {Changes: []realtimeUsageChange{{quotaUsage{Size: 10, Objects: 1}, owners.Updated.Add(-time.Second)}}},
+				},
+			},
+		},
+		owners: owners,
+		// Do not load the crawled usage from the backend.
+		crawledAt: time.Now().Add(time.Hour),
+	}
+
+	u.update(context.Background(), nil, "bucket", "object", "alice", []string{"dev"}, quotaUsage{Size: 5, Objects: 1})
+	if usage := u.ownerUsage(context.Background(), nil, "alice", false); usage != (quotaUsage{Size: 115, Objects: 12}) {
+		t.Errorf("unexpected user usage %v", usage)
+	}
+	if usage := u.ownerUsage(context.Background(), nil, "dev", true); usage != (quotaUsage{Size: 205, Objects: 21}) {
+		t.Errorf("unexpected group usage %v", usage)
+	}
+	if usage := u.ownerUsage(context.Background(), nil, "bob", false); usage != (quotaUsage{}) {
+		t.Errorf("unexpected usage of unknown user %v", usage)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"time"
 
 	jsoniter "github.com/json-iterator/go"
 	"github.com/minio/minio/cmd/logger"
@@ -39,8 +40,10 @@ const (
 )
 
 // storeDataUsageInBackend will store all objects sent on the gui channel until closed.
-func storeDataUsageInBackend(ctx context.Context, objAPI ObjectLayer, dui <-chan DataUsageInfo) {
+// crawlStart is the time the crawl producing them started.
+func storeDataUsageInBackend(ctx context.Context, objAPI ObjectLayer, crawlStart time.Time, dui <-chan DataUsageInfo) {
 	for dataUsageInfo := range dui {
+		dataUsageInfo.CrawlStart = crawlStart
 		dataUsageJSON, err := json.Marshal(dataUsageInfo)
 		if err != nil {
 			logger.LogIf(ctx, err)
@@ -89,19 +89,18 @@ func (er erasureObjects) removeObjectPart(bucket, object, uploadID, dataDir stri
 }
 
 // Clean-up the old multipart uploads. Should be run in a Go routine.
-func (er erasureObjects) cleanupStaleUploads(ctx context.Context, expiry time.Duration) {
+func (er erasureObjects) cleanupStaleUploads(ctx context.Context, expiry time.Duration, now time.Time) {
 	// run multiple cleanup's local to this server.
 	for _, disk := range er.getLoadBalancedLocalDisks() {
 		if disk != nil {
-			er.cleanupStaleUploadsOnDisk(ctx, disk, expiry)
+			er.cleanupStaleUploadsOnDisk(ctx, disk, expiry, now)
 			return
 		}
 	}
 }
 
 // Remove the old multipart uploads on the given disk.
-func (er erasureObjects) cleanupStaleUploadsOnDisk(ctx context.Context, disk StorageAPI, expiry time.Duration) {
-	now := time.Now()
+func (er erasureObjects) cleanupStaleUploadsOnDisk(ctx context.Context, disk StorageAPI, expiry time.Duration, now time.Time) {
 	shaDirs, err := disk.ListDir(ctx, minioMetaMultipartBucket, "", -1)
 	if err != nil {
 		return
@@ -117,7 +116,7 @@ func (er erasureObjects) cleanupStaleUploadsOnDisk(ctx context.Context, disk Sto
 			if err != nil {
 				continue
 			}
-			if now.Sub(fi.ModTime) > expiry {
+			if isMultipartUploadExpired(fi.Metadata, fi.ModTime, expiry, now) {
 				er.deleteObject(ctx, minioMetaMultipartBucket, uploadIDPath, fi.Erasure.DataBlocks+1)
 			}
 		}
@@ -272,6 +271,8 @@ func (er erasureObjects) newMultipartUpload(ctx context.Context, bucket string,
 	fi.DataDir = mustGetUUID()
 	fi.ModTime = UTCNow()
 	fi.Metadata = cloneMSS(opts.UserDefined)
+	fi.Metadata[multipartUploadObjectKey] = pathJoin(bucket, object)
+	fi.Metadata[multipartUploadInitiatedKey] = fi.ModTime.Format(time.RFC3339Nano)
 
 	uploadID := mustGetUUID()
 	uploadIDPath := er.getUploadIDDir(bucket, object, uploadID)
@@ -794,6 +795,10 @@ func (er erasureObjects) CompleteMultipartUpload(ctx context.Context, bucket str
 		fi.ModTime = UTCNow()
 	}
 
+	// Not needed anymore once the upload completed.
+	delete(fi.Metadata, multipartUploadObjectKey)
+	delete(fi.Metadata, multipartUploadInitiatedKey)
+
 	// Save successfully calculated md5sum.
 	fi.Metadata["etag"] = s3MD5
 	if opts.UserDefined["etag"] != "" { // preserve ETag if set
@@ -838,6 +843,7 @@ func (er erasureObjects) CompleteMultipartUpload(ctx context.Context, bucket str
 		return oi, err
 	}
 	defer lk.Unlock()
+	er.replacedObject(ctx, bucket, object, opts)
 
 	// Rename the multipart object to final location.
 	if onlineDisks, err = renameData(ctx, onlineDisks, minioMetaMultipartBucket, uploadIDPath,
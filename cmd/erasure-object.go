@@ -55,6 +55,7 @@ func (er erasureObjects) CopyObject(ctx context.Context, srcBucket, srcObject, d
 		return oi, err
 	}
 	defer lk.Unlock()
+	er.replacedObject(ctx, dstBucket, dstObject, dstOpts)
 
 	// Read metadata associated with the object from all disks.
 	storageDisks := er.getDisks()
@@ -560,6 +561,17 @@ func (er erasureObjects) PutObject(ctx context.Context, bucket string, object st
 	return er.putObject(ctx, bucket, object, data, opts)
 }
 
+// replacedObject - calls the ReplacedObjectFn of a write holding the
+// object lock with the latest version of the object it replaces.
+func (er erasureObjects) replacedObject(ctx context.Context, bucket, object string, opts ObjectOptions) {
+	if opts.ReplacedObjectFn == nil {
+		return
+	}
+	if oi, err := er.getObjectInfo(ctx, bucket, object, ObjectOptions{}); err == nil {
+		opts.ReplacedObjectFn(oi)
+	}
+}
+
 // putObject wrapper for erasureObjects PutObject
 func (er erasureObjects) putObject(ctx context.Context, bucket string, object string, r *PutObjReader, opts ObjectOptions) (objInfo ObjectInfo, err error) {
 	defer ObjectPathUpdated(pathJoin(bucket, object))
@@ -681,6 +693,7 @@ func (er erasureObjects) putObject(ctx context.Context, bucket string, object st
 		}
 		defer lk.Unlock()
 	}
+	er.replacedObject(ctx, bucket, object, opts)
 
 	for i, w := range writers {
 		if w == nil {
@@ -446,8 +446,9 @@ func (s *erasureSets) cleanupStaleUploads(ctx context.Context, cleanupInterval,
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
+			now := time.Now()
 			for _, set := range s.sets {
-				set.cleanupStaleUploads(ctx, expiry)
+				set.cleanupStaleUploads(ctx, expiry, now)
 			}
 		}
 	}
@@ -857,6 +858,7 @@ func (s *erasureSets) CopyObject(ctx context.Context, srcBucket, srcObject, dstB
 		Versioned:            dstOpts.Versioned,
 		VersionID:            dstOpts.VersionID,
 		MTime:                dstOpts.MTime,
+		ReplacedObjectFn:     dstOpts.ReplacedObjectFn,
 	}
 
 	return dstSet.putObject(ctx, dstBucket, dstObject, srcInfo.PutObjReader, putOpts)
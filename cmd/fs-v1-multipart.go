@@ -232,7 +232,9 @@ func (fs *FSObjects) NewMultipartUpload(ctx context.Context, bucket, object stri
 
 	// Initialize fs.json values.
 	fsMeta := newFSMetaV1()
-	fsMeta.Meta = opts.UserDefined
+	fsMeta.Meta = cloneMSS(opts.UserDefined)
+	fsMeta.Meta[multipartUploadObjectKey] = pathJoin(bucket, object)
+	fsMeta.Meta[multipartUploadInitiatedKey] = UTCNow().Format(time.RFC3339Nano)
 
 	fsMetaBytes, err := json.Marshal(fsMeta)
 	if err != nil {
@@ -710,6 +712,7 @@ func (fs *FSObjects) CompleteMultipartUpload(ctx context.Context, bucket string,
 		return oi, err
 	}
 	defer destLock.Unlock()
+	fs.replacedObject(ctx, bucket, object, opts)
 
 	bucketMetaDir := pathJoin(fs.fsPath, minioMetaBucket, bucketMetaPrefix)
 	fsMetaPath := pathJoin(bucketMetaDir, bucket, object, fs.metaJSONFile)
@@ -755,6 +758,9 @@ func (fs *FSObjects) CompleteMultipartUpload(ctx context.Context, bucket string,
 	if fsMeta.Meta == nil {
 		fsMeta.Meta = make(map[string]string)
 	}
+	// Not needed anymore once the upload completed.
+	delete(fsMeta.Meta, multipartUploadObjectKey)
+	delete(fsMeta.Meta, multipartUploadInitiatedKey)
 	fsMeta.Meta["etag"] = s3MD5
 	// Save consolidated actual size.
 	fsMeta.Meta[ReservedMetadataPrefix+"actual-size"] = strconv.FormatInt(objectActualSize, 10)
@@ -858,42 +864,68 @@ func (fs *FSObjects) cleanupStaleUploads(ctx context.Context, cleanupInterval, e
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			now := time.Now()
-			entries, err := readDir(pathJoin(fs.fsPath, minioMetaMultipartBucket))
+			fs.removeStaleUploads(ctx, expiry, time.Now())
+		}
+	}
+}
+
+// removeStaleUploads - removes the multipart uploads not updated within
+// `expiry` or aborted by a lifecycle rule of their bucket at `now`.
+func (fs *FSObjects) removeStaleUploads(ctx context.Context, expiry time.Duration, now time.Time) {
+	// Uploads are stored under the multipart directory of their bucket.
+	multipartDirs := []string{pathJoin(fs.fsPath, minioMetaMultipartBucket)}
+	buckets, err := fs.ListBuckets(ctx)
+	if err != nil {
+		logger.LogIf(ctx, err)
+	}
+	for _, bucket := range buckets {
+		multipartDirs = append(multipartDirs, pathJoin(fs.fsPath, bucket.Name, minioMetaMultipartBucket))
+	}
+	for _, multipartDir := range multipartDirs {
+		fs.removeStaleUploadsIn(ctx, multipartDir, expiry, now)
+	}
+}
+
+func (fs *FSObjects) removeStaleUploadsIn(ctx context.Context, multipartDir string, expiry time.Duration, now time.Time) {
+	entries, err := readDir(multipartDir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		uploadIDs, err := readDir(pathJoin(multipartDir, entry))
+		if err != nil {
+			continue
+		}
+
+		// Remove the trailing slash separator
+		for i := range uploadIDs {
+			uploadIDs[i] = strings.TrimSuffix(uploadIDs[i], SlashSeparator)
+		}
+
+		for _, uploadID := range uploadIDs {
+			uploadIDDir := pathJoin(multipartDir, entry, uploadID)
+			fi, err := fsStatDir(ctx, uploadIDDir)
 			if err != nil {
 				continue
 			}
-			for _, entry := range entries {
-				uploadIDs, err := readDir(pathJoin(fs.fsPath, minioMetaMultipartBucket, entry))
-				if err != nil {
-					continue
-				}
-
-				// Remove the trailing slash separator
-				for i := range uploadIDs {
-					uploadIDs[i] = strings.TrimSuffix(uploadIDs[i], SlashSeparator)
-				}
-
-				for _, uploadID := range uploadIDs {
-					fi, err := fsStatDir(ctx, pathJoin(fs.fsPath, minioMetaMultipartBucket, entry, uploadID))
-					if err != nil {
-						continue
-					}
-					if now.Sub(fi.ModTime()) > expiry {
-						fsRemoveAll(ctx, pathJoin(fs.fsPath, minioMetaMultipartBucket, entry, uploadID))
-						// It is safe to ignore any directory not empty error (in case there were multiple uploadIDs on the same object)
-						fsRemoveDir(ctx, pathJoin(fs.fsPath, minioMetaMultipartBucket, entry))
-
-						// Remove uploadID from the append file map and its corresponding temporary file
-						fs.appendFileMapMu.Lock()
-						bgAppend, ok := fs.appendFileMap[uploadID]
-						if ok {
-							_ = fsRemoveFile(ctx, bgAppend.filePath)
-							delete(fs.appendFileMap, uploadID)
-						}
-						fs.appendFileMapMu.Unlock()
-					}
+			var fsMeta fsMetaV1
+			if fsMetaBuf, err := ioutil.ReadFile(pathJoin(uploadIDDir, fs.metaJSONFile)); err == nil {
+				// Uploads with unreadable metadata are only removed once expired.
+				_ = json.Unmarshal(fsMetaBuf, &fsMeta)
+			}
+			if isMultipartUploadExpired(fsMeta.Meta, fi.ModTime(), expiry, now) {
+				fsRemoveAll(ctx, uploadIDDir)
+				// It is safe to ignore any directory not empty error (in case there were multiple uploadIDs on the same object)
+				fsRemoveDir(ctx, pathJoin(multipartDir, entry))
+
+				// Remove uploadID from the append file map and its corresponding temporary file
+				fs.appendFileMapMu.Lock()
+				bgAppend, ok := fs.appendFileMap[uploadID]
+				if ok {
+					_ = fsRemoveFile(ctx, bgAppend.filePath)
+					delete(fs.appendFileMap, uploadID)
 				}
+				fs.appendFileMapMu.Unlock()
 			}
 		}
 	}
@@ -761,7 +761,7 @@ func (fs *FSObjects) CopyObject(ctx context.Context, srcBucket, srcObject, dstBu
 		return ObjectInfo{}, err
 	}
 
-	objInfo, err := fs.putObject(ctx, dstBucket, dstObject, srcInfo.PutObjReader, ObjectOptions{ServerSideEncryption: dstOpts.ServerSideEncryption, UserDefined: srcInfo.UserDefined})
+	objInfo, err := fs.putObject(ctx, dstBucket, dstObject, srcInfo.PutObjReader, ObjectOptions{ServerSideEncryption: dstOpts.ServerSideEncryption, UserDefined: srcInfo.UserDefined, ReplacedObjectFn: dstOpts.ReplacedObjectFn})
 	if err != nil {
 		return oi, toObjectErr(err, dstBucket, dstObject)
 	}
@@ -1070,6 +1070,17 @@ func (fs *FSObjects) getObjectInfoNoFSLock(ctx context.Context, bucket, object s
 	return fsMeta.ToObjectInfo(bucket, object, fi), nil
 }
 
+// replacedObject - calls the ReplacedObjectFn of a write holding the
+// object lock with the object it replaces.
+func (fs *FSObjects) replacedObject(ctx context.Context, bucket, object string, opts ObjectOptions) {
+	if opts.ReplacedObjectFn == nil {
+		return
+	}
+	if oi, err := fs.getObjectInfo(ctx, bucket, object); err == nil {
+		opts.ReplacedObjectFn(oi)
+	}
+}
+
 // getObjectInfo - wrapper for reading object metadata and constructs ObjectInfo.
 func (fs *FSObjects) getObjectInfo(ctx context.Context, bucket, object string) (oi ObjectInfo, e error) {
 	if strings.HasSuffix(object, SlashSeparator) && !fs.isObjectDir(bucket, object) {
@@ -1243,6 +1254,7 @@ func (fs *FSObjects) putObject(ctx context.Context, bucket string, object string
 
 	fsMeta := newFSMetaV1()
 	fsMeta.Meta = meta
+	fs.replacedObject(ctx, bucket, object, opts)
 
 	// This is a special case with size as '0' and object ends
 	// with a slash separator, we treat it like a valid operation
@@ -223,6 +223,10 @@ func StartGateway(ctx *cli.Context, gw Gateway) {
 	// Set when gateway is enabled
 	globalIsGateway = true
 
+	// Bucket replication is not supported by gateways, no replication
+	// task is ever queued or journaled.
+	globalReplicationState = nil
+
 	enableConfigOps := false
 
 	// TODO: We need to move this code with globalConfigSys.Init()
@@ -245,15 +249,19 @@ func StartGateway(ctx *cli.Context, gw Gateway) {
 	// avoid URL path encoding minio/minio#8950
 	router := mux.NewRouter().SkipClean(true).UseEncodedPath()
 
-	if globalEtcdClient != nil {
-		// Enable STS router if etcd is enabled.
+	// Gateways storing objects on a local filesystem persist IAM
+	// on that filesystem when etcd is not configured.
+	fsGateway, isFSGateway := gw.(FSGateway)
+
+	enableIAMOps := globalEtcdClient != nil || isFSGateway
+
+	if enableIAMOps {
+		// Enable STS router if IAM is persisted.
 		registerSTSRouter(router)
 	}
 
-	enableIAMOps := globalEtcdClient != nil
-
-	// Enable IAM admin APIs if etcd is enabled, if not just enable basic
-	// operations such as profiling, server info etc.
+	// Enable IAM admin APIs if IAM is persisted, if not just enable
+	// basic operations such as profiling, server info etc.
 	registerAdminRouter(router, enableConfigOps, enableIAMOps)
 
 	// Add healthcheck router
@@ -326,7 +334,11 @@ func StartGateway(ctx *cli.Context, gw Gateway) {
 
 	if enableIAMOps {
 		// Initialize users credentials and policies in background.
-		globalIAMSys.InitStore(newObject)
+		if isFSGateway {
+			globalIAMSys.InitFSStore(newObject, fsGateway.FSPath())
+		} else {
+			globalIAMSys.InitStore(newObject)
+		}
 
 		go globalIAMSys.Init(GlobalContext, newObject)
 	}
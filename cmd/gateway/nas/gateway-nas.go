@@ -96,6 +96,12 @@ func (g *NAS) NewGatewayLayer(creds auth.Credentials) (minio.ObjectLayer, error)
 	return &nasObjects{newObject}, nil
 }
 
+// FSPath implements FSGateway interface, IAM configuration is persisted
+// on the NAS mount when etcd is not configured.
+func (g *NAS) FSPath() string {
+	return g.path
+}
+
 // Production - nas gateway is production ready.
 func (g *NAS) Production() bool {
 	return true
@@ -777,6 +777,10 @@ func addCustomHeaders(h http.Handler) http.Handler {
 func (s customHeaderHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Set custom headers such as x-amz-request-id for each request.
 	w.Header().Set(xhttp.AmzRequestID, mustGetRequestID(UTCNow()))
+	if globalPolicyDebug {
+		// Let the request hold its policy decision for the audit log.
+		r = r.WithContext(logger.WithPolicyDecision(r.Context()))
+	}
 	s.handler.ServeHTTP(logger.NewResponseWriter(w), r)
 }
 
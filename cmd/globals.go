@@ -94,6 +94,9 @@ const (
 	// Limit of location constraint XML for unauthenticted PUT bucket operations.
 	maxLocationConstraintSize = 3 * humanize.MiByte
 
+	// Limit of object tagging XML of PUT object tagging operations.
+	maxObjectTaggingSize = 1 * humanize.MiByte
+
 	// Maximum size of default bucket encryption configuration allowed
 	maxBucketSSEConfigSize = 1 * humanize.MiByte
 
@@ -299,6 +302,9 @@ var (
 
 	globalETCDOnly bool
 
+	// Attach the policy decision of denied requests to their audit log.
+	globalPolicyDebug bool
+
 	globalDefaultFilesystemPath = ""
 
 	globalUpgradeMode bool = false
@@ -131,6 +131,10 @@ func (t *apiConfig) getRequestsPool() (chan struct{}, time.Duration) {
 
 // maxClients throttles the S3 API calls
 func maxClients(f http.HandlerFunc) http.HandlerFunc {
+	// Requests are also throttled by the rate limits of
+	// users, groups and buckets.
+	f = rateLimitHandler(f)
+
 	return func(w http.ResponseWriter, r *http.Request) {
 		pool, deadline := globalAPIConfig.getRequestsPool()
 		if pool == nil {
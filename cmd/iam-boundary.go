@@ -0,0 +1,174 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+
+	iampolicy "github.com/minio/minio/pkg/iam/policy"
+)
+
+// SetPermissionBoundary - sets the permission boundary of a user or a
+// group, an empty policy name removes it.
+func (sys *IAMSys) SetPermissionBoundary(name string, isGroup bool, policyName string) error {
+	if !sys.Initialized() {
+		return errServerNotInitialized
+	}
+
+	if sys.usersSysType != MinIOUsersSysType {
+		return errIAMActionNotAllowed
+	}
+
+	sys.store.lock()
+	defer sys.store.unlock()
+
+	if isGroup {
+		if err := sys.reloadGroupForUpdate(context.Background(), name); err != nil {
+			return err
+		}
+	} else if err := sys.reloadUserForUpdate(context.Background(), name); err != nil {
+		return err
+	}
+
+	if policyName != "" {
+		if _, ok := sys.iamPolicyDocsMap[policyName]; !ok {
+			return errNoSuchPolicy
+		}
+	}
+
+	if isGroup {
+		gi, ok := sys.iamGroupsMap[name]
+		if !ok {
+			return errNoSuchGroup
+		}
+		gi.PermissionBoundary = policyName
+		if err := sys.store.saveGroupInfo(context.Background(), name, gi); err != nil {
+			return err
+		}
+		sys.iamGroupsMap[name] = gi
+		return nil
+	}
+
+	cred, ok := sys.iamUsersMap[name]
+	if !ok {
+		return errNoSuchUser
+	}
+
+	// Service accounts and temporary credentials are bound by
+	// the permission boundaries of their parent user.
+	if cred.IsTemp() || cred.IsServiceAccount() {
+		return errIAMActionNotAllowed
+	}
+
+	cred.PermissionBoundary = policyName
+	if err := sys.store.saveUserIdentity(context.Background(), name, regularUser, newUserIdentity(cred)); err != nil {
+		return err
+	}
+	sys.iamUsersMap[name] = cred
+	return nil
+}
+
+// permissionBoundaries - returns the names of the permission boundaries
+// of a user and of the groups it is a member of, service accounts and
+// temporary credentials have the boundaries of their parent user and of
+// the groups claimed by their token.
+// Assumes that sys.store.rlock is held.
+func (sys *IAMSys) permissionBoundaries(accessKey string) []string {
+	var boundaries []string
+	// Temporary credentials of OpenID providers are bound by the
+	// boundaries of the IAM groups claimed by their token.
+	if cred, ok := sys.iamUsersMap[accessKey]; ok && cred.IsTemp() {
+		for _, group := range cred.Groups {
+			if gi, ok := sys.iamGroupsMap[group]; ok && gi.PermissionBoundary != "" {
+				boundaries = append(boundaries, gi.PermissionBoundary)
+			}
+		}
+	}
+
+	name := accessKey
+	// Temporary credentials of a service account have the service
+	// account as parent, look up to two levels of parents.
+	for i := 0; i < 2; i++ {
+		cred, ok := sys.iamUsersMap[name]
+		if !ok || cred.ParentUser == "" {
+			break
+		}
+		name = cred.ParentUser
+	}
+
+	if cred, ok := sys.iamUsersMap[name]; ok && cred.PermissionBoundary != "" {
+		boundaries = append(boundaries, cred.PermissionBoundary)
+	}
+	for group := range sys.iamUserGroupMemberships[name] {
+		if gi, ok := sys.iamGroupsMap[group]; ok && gi.PermissionBoundary != "" {
+			boundaries = append(boundaries, gi.PermissionBoundary)
+		}
+	}
+	return boundaries
+}
+
+// deniedByBoundary - returns the permission boundary not allowing the
+// request, every boundary must allow it. A boundary whose policy was
+// deleted denies all requests.
+func (sys *IAMSys) deniedByBoundary(args iampolicy.Args) (string, bool) {
+	sys.store.rlock()
+	defer sys.store.runlock()
+
+	for _, name := range sys.permissionBoundaries(args.AccountName) {
+		p := sys.iamPolicyDocsMap[name]
+		if !p.IsAllowed(args) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// checkSessionPolicy - returns errSessionPolicyExceedsBoundary if the
+// session policy of new credentials of the user allows more than any of
+// its permission boundaries. Assumes that sys.store.rlock is held.
+func (sys *IAMSys) checkSessionPolicy(accessKey string, sessionPolicy *iampolicy.Policy) error {
+	boundaries := sys.permissionBoundaries(accessKey)
+	if len(boundaries) == 0 {
+		return nil
+	}
+
+	// Credentials without a session policy have all the permissions
+	// of the user, the boundaries are enforced on their requests.
+	if sessionPolicy == nil {
+		return nil
+	}
+
+	for _, name := range boundaries {
+		if !sessionPolicy.IsWithinBoundary(sys.iamPolicyDocsMap[name]) {
+			return errSessionPolicyExceedsBoundary
+		}
+	}
+	return nil
+}
+
+// CheckSessionPolicy - returns errSessionPolicyExceedsBoundary if the
+// session policy allows more than the permission boundaries of the user.
+func (sys *IAMSys) CheckSessionPolicy(accessKey string, sessionPolicy *iampolicy.Policy) error {
+	if !sys.Initialized() {
+		return errServerNotInitialized
+	}
+
+	sys.store.rlock()
+	defer sys.store.runlock()
+
+	return sys.checkSessionPolicy(accessKey, sessionPolicy)
+}
@@ -0,0 +1,116 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/bucket/policy"
+	"github.com/minio/minio/pkg/bucket/policy/condition"
+	iampolicy "github.com/minio/minio/pkg/iam/policy"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func TestPermissionBoundary(t *testing.T) {
+	sys := newTestIAMSys(t)
+	var err error
+
+	readOnlyBucket := iampolicy.Policy{
+		Version: iampolicy.DefaultVersion,
+		Statements: []iampolicy.Statement{
+			iampolicy.NewStatement(
+				policy.Allow,
+				iampolicy.NewActionSet(iampolicy.GetObjectAction),
+				iampolicy.NewResourceSet(iampolicy.NewResource("mybucket", "/*")),
+				condition.NewFunctions(),
+			),
+		},
+	}
+	if err = sys.SetPolicy("readwrite", iampolicy.ReadWrite); err != nil {
+		t.Fatal(err)
+	}
+	if err = sys.SetPolicy("boundary", readOnlyBucket); err != nil {
+		t.Fatal(err)
+	}
+	if err = sys.SetUser("teamadmin", madmin.UserInfo{SecretKey: "teamadmin123", PolicyName: "readwrite", Status: madmin.AccountEnabled}); err != nil {
+		t.Fatal(err)
+	}
+
+	args := func(action iampolicy.Action, bucket string) iampolicy.Args {
+		return iampolicy.Args{
+			AccountName:     "teamadmin",
+			Action:          action,
+			BucketName:      bucket,
+			ObjectName:      "object",
+			ConditionValues: map[string][]string{},
+		}
+	}
+	if !sys.IsAllowed(args(iampolicy.PutObjectAction, "mybucket")) {
+		t.Fatal("expected the user policy to allow the request without a boundary")
+	}
+
+	if err = sys.SetPermissionBoundary("teamadmin", false, "missing"); err != errNoSuchPolicy {
+		t.Fatalf("expected %v, got %v", errNoSuchPolicy, err)
+	}
+	if err = sys.AddUsersToGroup("team", []string{"teamadmin"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = sys.SetPermissionBoundary("team", true, "boundary"); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		action   iampolicy.Action
+		bucket   string
+		expected bool
+	}{
+		{iampolicy.GetObjectAction, "mybucket", true},
+		{iampolicy.PutObjectAction, "mybucket", false},
+		{iampolicy.GetObjectAction, "otherbucket", false},
+	}
+	for i, testCase := range testCases {
+		if allowed := sys.IsAllowed(args(testCase.action, testCase.bucket)); allowed != testCase.expected {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expected, allowed)
+		}
+	}
+
+	// Session policies of service accounts must be within the boundary.
+	if _, err = sys.NewServiceAccount(context.Background(), "teamadmin", &iampolicy.ReadWrite, time.Time{}); err != errSessionPolicyExceedsBoundary {
+		t.Fatalf("expected %v, got %v", errSessionPolicyExceedsBoundary, err)
+	}
+	if _, err = sys.NewServiceAccount(context.Background(), "teamadmin", &readOnlyBucket, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Deleting a boundary policy denies all requests.
+	if err = sys.DeletePolicy("boundary"); err != nil {
+		t.Fatal(err)
+	}
+	if sys.IsAllowed(args(iampolicy.GetObjectAction, "mybucket")) {
+		t.Fatal("expected a missing boundary policy to deny the request")
+	}
+
+	gd, err := sys.GetGroupDescription("team")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gd.PermissionBoundary != "boundary" {
+		t.Fatalf("expected the group boundary, got %q", gd.PermissionBoundary)
+	}
+}
@@ -0,0 +1,378 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/minio/minio-go/v7/pkg/set"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/bucket/policy"
+	"github.com/minio/minio/pkg/bucket/policy/condition"
+	iampolicy "github.com/minio/minio/pkg/iam/policy"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// sourcedPolicy - policy evaluated for a request along with its origin.
+type sourcedPolicy struct {
+	source madmin.PolicySource
+	name   string
+	group  string
+	policy iampolicy.Policy
+}
+
+func (p sourcedPolicy) String() string {
+	switch p.source {
+	case madmin.GroupPolicySource:
+		return fmt.Sprintf("policy %s of group %s", p.name, p.group)
+	case madmin.SessionPolicySource:
+		return "session policy"
+	}
+	return fmt.Sprintf("%s policy %s", p.source, p.name)
+}
+
+func toPolicyStatementMatch(statement interface{}, failed condition.Functions) madmin.PolicyStatementMatch {
+	data, err := json.Marshal(statement)
+	if err != nil {
+		logger.LogIf(GlobalContext, err)
+	}
+	m := madmin.PolicyStatementMatch{
+		Statement: data,
+		Matched:   len(failed) == 0,
+	}
+	for _, f := range failed {
+		m.FailedConditions = append(m.FailedConditions, f.String())
+	}
+	return m
+}
+
+// explain returns the statements of the policy applying to the request,
+// and whether one of them explicitly denies or allows it.
+func (p sourcedPolicy) explain(args iampolicy.Args) (e madmin.PolicyEvaluation, denies, allows bool) {
+	e = madmin.PolicyEvaluation{
+		Source: p.source,
+		Name:   p.name,
+		Group:  p.group,
+	}
+	_, matches := p.policy.Explain(args)
+	for _, match := range matches {
+		m := toPolicyStatementMatch(match.Statement, match.FailedConditions)
+		if m.Matched {
+			denies = denies || match.Statement.Effect == policy.Deny
+			allows = allows || match.Statement.Effect == policy.Allow
+		}
+		e.Statements = append(e.Statements, m)
+	}
+	return e, denies, allows
+}
+
+// policyDecision - decision of the policies applying to a request. The
+// reason of the decision and the statements applying to the request are
+// only recorded when explain is set, enforcing the policies does not pay
+// for them.
+type policyDecision struct {
+	explain bool
+	madmin.PolicySimulationResult
+}
+
+// deny - denies the request for the reason, returns false.
+func (d *policyDecision) deny(reason string, a ...interface{}) bool {
+	d.Allowed = false
+	if d.explain {
+		d.Reason = fmt.Sprintf(reason, a...)
+	}
+	return false
+}
+
+// allow - allows the request for the reason, returns true.
+func (d *policyDecision) allow(reason string, a ...interface{}) bool {
+	d.Allowed = true
+	if d.explain {
+		d.Reason = fmt.Sprintf(reason, a...)
+	}
+	return true
+}
+
+// claimPolicies - returns the policies named in the OpenID policy claim,
+// denies the request if there are none.
+func (d *policyDecision) claimPolicies(args iampolicy.Args) (set.StringSet, bool) {
+	policies, ok := args.GetPolicies(iamPolicyClaimNameOpenID())
+	if !ok {
+		// When claims are set, it should have a policy claim field.
+		return nil, d.deny("the claims have no %s claim", iamPolicyClaimNameOpenID())
+	}
+
+	// When claims are set, it should have policies as claim.
+	if policies.IsEmpty() {
+		// No policy, no access!
+		return nil, d.deny("the %s claim names no policy", iamPolicyClaimNameOpenID())
+	}
+	return policies, true
+}
+
+// evaluate - decides the request on the policies combined, the session
+// policy if any must allow the request as well.
+func (d *policyDecision) evaluate(args iampolicy.Args, policies []sourcedPolicy, session *iampolicy.Policy) bool {
+	var combined iampolicy.Policy
+	for _, p := range policies {
+		combined.Statements = append(combined.Statements, p.policy.Statements...)
+	}
+	allowed := combined.IsAllowed(args)
+	d.Allowed = allowed && (session == nil || session.IsAllowed(args))
+	if !d.explain {
+		return d.Allowed
+	}
+
+	var allowedBy, deniedBy string
+	for _, p := range policies {
+		e, denies, allows := p.explain(args)
+		d.Policies = append(d.Policies, e)
+		if denies && deniedBy == "" {
+			deniedBy = "denied by " + p.String()
+		}
+		if allows && allowedBy == "" {
+			allowedBy = "allowed by " + p.String()
+		}
+	}
+	var sessionDenies bool
+	if session != nil {
+		var e madmin.PolicyEvaluation
+		e, sessionDenies, _ = sourcedPolicy{source: madmin.SessionPolicySource, policy: *session}.explain(args)
+		d.Policies = append(d.Policies, e)
+	}
+
+	switch {
+	case deniedBy != "":
+		d.Reason = deniedBy
+	case !allowed:
+		d.Reason = "no statement allows the request"
+	case sessionDenies:
+		d.Reason = "denied by session policy"
+	case !d.Allowed:
+		d.Reason = "no statement of the session policy allows the request"
+	default:
+		d.Reason = allowedBy
+	}
+	return d.Allowed
+}
+
+// sessionPolicyFromClaims returns the session policy set in the claims,
+// nil if there is none.
+func sessionPolicyFromClaims(claims map[string]interface{}) (*iampolicy.Policy, error) {
+	spolicy, ok := claims[iampolicy.SessionPolicyName]
+	if !ok {
+		return nil, nil
+	}
+	spolicyStr, ok := spolicy.(string)
+	if !ok {
+		return nil, errInvalidArgument
+	}
+	subPolicy, err := iampolicy.ParseConfig(bytes.NewReader([]byte(spolicyStr)))
+	if err != nil {
+		// Log any error in input session policy config.
+		logger.LogIf(GlobalContext, err)
+		return nil, err
+	}
+	if subPolicy.Version == "" {
+		return nil, errInvalidArgument
+	}
+	return subPolicy, nil
+}
+
+// userPolicies returns the policies of a user and of the groups it is a
+// member of, as PolicyDBGet does. Assumes that sys.store.rlock is held.
+func (sys *IAMSys) userPolicies(name string, source madmin.PolicySource) []sourcedPolicy {
+	var policies []sourcedPolicy
+	for _, pname := range sys.iamUserPolicyMap[name].toSlice() {
+		if p, found := sys.iamPolicyDocsMap[pname]; found {
+			policies = append(policies, sourcedPolicy{source: source, name: pname, policy: p})
+		}
+	}
+	for _, group := range sys.iamUserGroupMemberships[name].ToSlice() {
+		gi, ok := sys.iamGroupsMap[group]
+		if !ok || gi.Status == statusDisabled {
+			continue
+		}
+		for _, pname := range sys.iamGroupPolicyMap[group].toSlice() {
+			if p, found := sys.iamPolicyDocsMap[pname]; found {
+				policies = append(policies, sourcedPolicy{source: madmin.GroupPolicySource, name: pname, group: group, policy: p})
+			}
+		}
+	}
+	return policies
+}
+
+// ExplainPolicy - decides the request like IsAllowed does, and returns
+// the decision along with the statements applying to the request.
+func (sys *IAMSys) ExplainPolicy(args iampolicy.Args) madmin.PolicySimulationResult {
+	d := policyDecision{explain: true}
+	sys.isAllowed(args, &d)
+	return d.PolicySimulationResult
+}
+
+// ExplainLDAPPolicy - decides the request on the policies of an LDAP
+// user and of its groups like IsAllowedLDAPSTS does.
+func (sys *IAMSys) ExplainLDAPPolicy(args iampolicy.Args, user string, groups []string) madmin.PolicySimulationResult {
+	d := policyDecision{explain: true}
+
+	sys.store.rlock()
+	defer sys.store.runlock()
+
+	sys.isAllowedLDAPPolicies(args, user, groups, &d)
+	return d.PolicySimulationResult
+}
+
+// ExplainOpenIDPolicy - decides the request on the policies named in the
+// claims like IsAllowedSTS does, without a temporary user to check the
+// claims against.
+func (sys *IAMSys) ExplainOpenIDPolicy(args iampolicy.Args) madmin.PolicySimulationResult {
+	d := policyDecision{explain: true}
+	policies, ok := d.claimPolicies(args)
+	if !ok {
+		return d.PolicySimulationResult
+	}
+
+	sys.store.rlock()
+	defer sys.store.runlock()
+
+	sys.isAllowedClaimPolicies(args, policies, &d)
+	return d.PolicySimulationResult
+}
+
+// evaluateBucketPolicy - decides the request on the bucket policy.
+func (d *policyDecision) evaluateBucketPolicy(args policy.Args, p *policy.Policy) bool {
+	d.Allowed = p.IsAllowed(args)
+	if !d.explain {
+		return d.Allowed
+	}
+
+	e := madmin.PolicyEvaluation{Source: madmin.BucketPolicySource}
+	var allowedBy, deniedBy bool
+	_, matches := p.Explain(args)
+	for _, match := range matches {
+		m := toPolicyStatementMatch(match.Statement, match.FailedConditions)
+		if m.Matched {
+			deniedBy = deniedBy || match.Statement.Effect == policy.Deny
+			allowedBy = allowedBy || match.Statement.Effect == policy.Allow
+		}
+		e.Statements = append(e.Statements, m)
+	}
+	d.Policies = []madmin.PolicyEvaluation{e}
+	switch {
+	case deniedBy:
+		d.Reason = "denied by bucket policy"
+	case d.Allowed:
+		d.Reason = "allowed by bucket policy"
+	default:
+		d.Reason = "no statement of the bucket policy allows the request"
+	}
+	return d.Allowed
+}
+
+// Explain - decides the request like IsAllowed does, and returns the
+// decision along with the statements of the bucket policy applying to
+// the request.
+func (sys *PolicySys) Explain(args policy.Args) madmin.PolicySimulationResult {
+	d := policyDecision{explain: true}
+	sys.isAllowed(args, &d)
+	return d.PolicySimulationResult
+}
+
+// simulatedConditionValues returns the condition values of a request made
+// by the identity, overridden by the given condition values.
+func simulatedConditionValues(accessKey string, claims map[string]interface{}, conditions map[string][]string) map[string][]string {
+	r, err := http.NewRequest(http.MethodGet, SlashSeparator, nil)
+	if err != nil {
+		return conditions
+	}
+	values := getConditionValues(r, "", accessKey, claims)
+	for k, v := range conditions {
+		values[k] = v
+	}
+	return values
+}
+
+// simulatePolicy evaluates the policies applying to a request made by the
+// identity of the simulation args. Requests without any identity are
+// anonymous, only the bucket policy applies to them.
+func simulatePolicy(r *http.Request, sargs madmin.PolicySimulationArgs) (madmin.PolicySimulationResult, error) {
+	args := iampolicy.Args{
+		AccountName: sargs.AccessKey,
+		Action:      iampolicy.Action(sargs.Action),
+		BucketName:  sargs.Bucket,
+		ObjectName:  sargs.Object,
+	}
+
+	switch {
+	case sargs.LDAPUser != "":
+		args.Claims = map[string]interface{}{ldapUser: sargs.LDAPUser}
+		args.ConditionValues = simulatedConditionValues(sargs.LDAPUser, args.Claims, sargs.Conditions)
+		return globalIAMSys.ExplainLDAPPolicy(args, sargs.LDAPUser, sargs.LDAPGroups), nil
+	case sargs.AccessKey == "" && len(sargs.Claims) > 0:
+		args.Claims = sargs.Claims
+		args.ConditionValues = simulatedConditionValues("", args.Claims, sargs.Conditions)
+		return globalIAMSys.ExplainOpenIDPolicy(args), nil
+	case sargs.AccessKey == "":
+		return globalPolicySys.Explain(policy.Args{
+			Action:          policy.Action(sargs.Action),
+			BucketName:      sargs.Bucket,
+			ObjectName:      sargs.Object,
+			ConditionValues: simulatedConditionValues("", nil, sargs.Conditions),
+		}), nil
+	}
+
+	if sargs.AccessKey == globalActiveCred.AccessKey {
+		args.IsOwner = true
+	} else {
+		cred, ok := globalIAMSys.GetUser(sargs.AccessKey)
+		if !ok {
+			return madmin.PolicySimulationResult{}, errNoSuchUser
+		}
+		if cred.IsServiceAccount() || cred.IsTemp() {
+			claims, err := getClaimsFromToken(r, cred.SessionToken)
+			if err != nil {
+				return madmin.PolicySimulationResult{}, err
+			}
+			args.Claims = claims
+		}
+	}
+	args.ConditionValues = simulatedConditionValues(sargs.AccessKey, args.Claims, sargs.Conditions)
+	return globalIAMSys.ExplainPolicy(args), nil
+}
+
+// traceDeniedRequest attaches the policy decision of a request denied by
+// the IAM policies to its audit log entry, in policy debug mode.
+func traceDeniedRequest(ctx context.Context, args iampolicy.Args) {
+	if !globalPolicyDebug {
+		return
+	}
+	logger.SetPolicyDecision(ctx, globalIAMSys.ExplainPolicy(args))
+}
+
+// traceDeniedAnonymousRequest attaches the policy decision of an anonymous
+// request denied by the bucket policy to its audit log entry, in policy
+// debug mode.
+func traceDeniedAnonymousRequest(ctx context.Context, args policy.Args) {
+	if !globalPolicyDebug {
+		return
+	}
+	logger.SetPolicyDecision(ctx, globalPolicySys.Explain(args))
+}
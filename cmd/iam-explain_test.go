@@ -0,0 +1,85 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/minio/minio/pkg/bucket/policy"
+	"github.com/minio/minio/pkg/bucket/policy/condition"
+	iampolicy "github.com/minio/minio/pkg/iam/policy"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func TestPolicyDecisionEvaluate(t *testing.T) {
+	denyDelete := iampolicy.Policy{
+		Version: iampolicy.DefaultVersion,
+		Statements: []iampolicy.Statement{
+			iampolicy.NewStatement(
+				policy.Deny,
+				iampolicy.NewActionSet(iampolicy.DeleteObjectAction),
+				iampolicy.NewResourceSet(iampolicy.NewResource("*", "")),
+				condition.NewFunctions(),
+			),
+		},
+	}
+	policies := []sourcedPolicy{
+		{source: madmin.UserPolicySource, name: "readwrite", policy: iampolicy.ReadWrite},
+		{source: madmin.GroupPolicySource, name: "deny-delete", group: "dev", policy: denyDelete},
+	}
+
+	testCases := []struct {
+		action  iampolicy.Action
+		session *iampolicy.Policy
+		allowed bool
+		reason  string
+	}{
+		{iampolicy.GetObjectAction, nil, true, "allowed by user policy readwrite"},
+		{iampolicy.DeleteObjectAction, nil, false, "denied by policy deny-delete of group dev"},
+		{iampolicy.PutObjectAction, &iampolicy.ReadOnly, false, "no statement of the session policy allows the request"},
+		{iampolicy.GetObjectAction, &iampolicy.ReadOnly, true, "allowed by user policy readwrite"},
+	}
+
+	for i, testCase := range testCases {
+		args := iampolicy.Args{
+			AccountName: "alice",
+			Action:      testCase.action,
+			BucketName:  "bucket",
+			ObjectName:  "object",
+		}
+		result := policyDecision{explain: true}
+		if result.evaluate(args, policies, testCase.session) != testCase.allowed {
+			t.Errorf("Test %d: expected allowed %v, got %v", i+1, testCase.allowed, result.Allowed)
+		}
+		if result.Reason != testCase.reason {
+			t.Errorf("Test %d: expected reason %q, got %q", i+1, testCase.reason, result.Reason)
+		}
+		if len(result.Policies) == 0 {
+			t.Errorf("Test %d: expected the evaluated policies", i+1)
+		}
+
+		// Enforcing the policies reaches the same decision without
+		// recording it.
+		var enforced policyDecision
+		if enforced.evaluate(args, policies, testCase.session) != testCase.allowed {
+			t.Errorf("Test %d: expected enforced allowed %v, got %v", i+1, testCase.allowed, enforced.Allowed)
+		}
+		if enforced.Reason != "" || len(enforced.Policies) != 0 {
+			t.Errorf("Test %d: expected no recorded decision, got %q", i+1, enforced.Reason)
+		}
+	}
+}
@@ -0,0 +1,583 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/auth"
+	iampolicy "github.com/minio/minio/pkg/iam/policy"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// iamArchiveVersion - version of the format of IAM archives.
+const iamArchiveVersion = 1
+
+// iamArchive - the IAM state exported by the export-iam admin API, in
+// the format of the IAM store. Temporary credentials are not part of it.
+type iamArchive struct {
+	Version         int                                 `json:"version"`
+	Policies        map[string]iampolicy.Policy         `json:"policies,omitempty"`
+	Users           map[string]auth.Credentials         `json:"users,omitempty"`
+	ServiceAccounts map[string]iamArchiveServiceAccount `json:"serviceAccounts,omitempty"`
+	Groups          map[string]GroupInfo                `json:"groups,omitempty"`
+	UserPolicies    map[string]MappedPolicy             `json:"userPolicies,omitempty"`
+	GroupPolicies   map[string]MappedPolicy             `json:"groupPolicies,omitempty"`
+}
+
+// iamArchiveServiceAccount - a service account in an IAM archive. Its
+// session token is signed with the root credentials of the server, so
+// only the claims of the token are exported and the token is signed
+// again on import.
+type iamArchiveServiceAccount struct {
+	Credentials auth.Credentials       `json:"credentials"`
+	Claims      map[string]interface{} `json:"claims"`
+}
+
+func exportServiceAccount(cred auth.Credentials) (iamArchiveServiceAccount, error) {
+	claims := sessionClaims(cred.SessionToken)
+	if claims == nil {
+		return iamArchiveServiceAccount{}, fmt.Errorf("session token of service account %s cannot be verified", cred.AccessKey)
+	}
+	cred.SessionToken = ""
+	return iamArchiveServiceAccount{Credentials: cred, Claims: claims}, nil
+}
+
+// ExportIAM - returns the users, groups, policies, policy mappings and
+// service accounts as an IAM archive.
+func (sys *IAMSys) ExportIAM() (iamArchive, error) {
+	a := iamArchive{
+		Version:         iamArchiveVersion,
+		Policies:        make(map[string]iampolicy.Policy),
+		Users:           make(map[string]auth.Credentials),
+		ServiceAccounts: make(map[string]iamArchiveServiceAccount),
+		Groups:          make(map[string]GroupInfo),
+		UserPolicies:    make(map[string]MappedPolicy),
+		GroupPolicies:   make(map[string]MappedPolicy),
+	}
+	if !sys.Initialized() {
+		return a, errServerNotInitialized
+	}
+
+	sys.store.rlock()
+	defer sys.store.runlock()
+
+	for name, p := range sys.iamPolicyDocsMap {
+		a.Policies[name] = p
+	}
+	for accessKey, cred := range sys.iamUsersMap {
+		switch {
+		case cred.IsTemp():
+			continue
+		case cred.IsServiceAccount():
+			sa, err := exportServiceAccount(cred)
+			if err != nil {
+				logger.LogIf(GlobalContext, err)
+				continue
+			}
+			a.ServiceAccounts[accessKey] = sa
+		default:
+			a.Users[accessKey] = cred
+		}
+	}
+	for group, gi := range sys.iamGroupsMap {
+		a.Groups[group] = gi
+	}
+	for name, mp := range sys.iamUserPolicyMap {
+		if cred, ok := sys.iamUsersMap[name]; ok && cred.IsTemp() {
+			continue
+		}
+		a.UserPolicies[name] = mp
+	}
+	for group, mp := range sys.iamGroupPolicyMap {
+		a.GroupPolicies[group] = mp
+	}
+	return a, nil
+}
+
+// iamImport - the changes made by the import of an IAM archive. The
+// state of the IAM sub-system once the archive is imported is computed
+// and validated before anything is written.
+type iamImport struct {
+	sys     *IAMSys
+	replace bool
+	result  madmin.IAMImportResult
+
+	// IAM state once imported.
+	policies      map[string]iampolicy.Policy
+	users         map[string]auth.Credentials
+	groups        map[string]GroupInfo
+	userPolicies  map[string]MappedPolicy
+	groupPolicies map[string]MappedPolicy
+}
+
+// equalIAMEntities - returns whether an entity of the archive equals the
+// existing one. Credentials are compared in their JSON encoding so that
+// their timestamps are equal regardless of how they were decoded.
+func equalIAMEntities(a, b interface{}) bool {
+	switch a.(type) {
+	case auth.Credentials, iamArchiveServiceAccount:
+		ja, err := json.Marshal(a)
+		if err != nil {
+			return false
+		}
+		jb, err := json.Marshal(b)
+		if err != nil {
+			return false
+		}
+		return bytes.Equal(ja, jb)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// compare - returns whether an entity of the archive must be written,
+// an existing entity which differs is updated in replace mode and is
+// reported as a conflict otherwise.
+func (imp *iamImport) compare(typ madmin.IAMEntityType, name string, existing, imported interface{}, exists bool) bool {
+	entity := madmin.IAMEntity{Type: typ, Name: name}
+	switch {
+	case !exists:
+		imp.result.Added = append(imp.result.Added, entity)
+		return true
+	case equalIAMEntities(existing, imported):
+		return false
+	case imp.replace:
+		imp.result.Updated = append(imp.result.Updated, entity)
+		return true
+	}
+	imp.conflict(typ, name, fmt.Sprintf("differs from the existing %s", typ))
+	return false
+}
+
+func (imp *iamImport) conflict(typ madmin.IAMEntityType, name, reason string) {
+	imp.result.Conflicts = append(imp.result.Conflicts, madmin.IAMImportConflict{
+		IAMEntity: madmin.IAMEntity{Type: typ, Name: name},
+		Reason:    reason,
+	})
+}
+
+func (imp *iamImport) remove(typ madmin.IAMEntityType, name string) {
+	imp.result.Removed = append(imp.result.Removed, madmin.IAMEntity{Type: typ, Name: name})
+}
+
+// plan - computes the changes made by the import of the archive and the
+// resulting IAM state. Assumes that sys.store.lock() is held by caller.
+func (imp *iamImport) plan(a iamArchive) error {
+	sys := imp.sys
+
+	for name, p := range a.Policies {
+		if err := p.Validate(); err != nil {
+			return fmt.Errorf("%w: policy %s: %v", errInvalidIAMArchive, name, err)
+		}
+		cur, ok := sys.iamPolicyDocsMap[name]
+		if imp.compare(madmin.IAMPolicyEntity, name, cur, p, ok) {
+			imp.policies[name] = p
+		}
+	}
+	if imp.replace {
+		// Default canned policies are set again when the IAM
+		// sub-system is loaded, they are never removed.
+		for name := range sys.iamPolicyDocsMap {
+			if _, ok := a.Policies[name]; !ok && !isDefaultPolicy(name) {
+				imp.remove(madmin.IAMPolicyEntity, name)
+				delete(imp.policies, name)
+			}
+		}
+	}
+
+	for accessKey, cred := range a.Users {
+		if cred.AccessKey == "" {
+			cred.AccessKey = accessKey
+		}
+		if cred.AccessKey != accessKey || !auth.IsAccessKeyValid(accessKey) || !auth.IsSecretKeyValid(cred.SecretKey) ||
+			cred.IsTemp() || cred.IsServiceAccount() {
+			return fmt.Errorf("%w: invalid credentials of user %s", errInvalidIAMArchive, accessKey)
+		}
+		if accessKey == globalActiveCred.AccessKey {
+			imp.conflict(madmin.IAMUserEntity, accessKey, "is the access key of the root user")
+			continue
+		}
+		cur, ok := sys.iamUsersMap[accessKey]
+		if ok && (cur.IsTemp() || cur.IsServiceAccount()) {
+			imp.conflict(madmin.IAMUserEntity, accessKey, "is the access key of a temporary user or service account")
+			continue
+		}
+		if imp.compare(madmin.IAMUserEntity, accessKey, cur, cred, ok) {
+			imp.users[accessKey] = cred
+		}
+	}
+
+	for accessKey, sa := range a.ServiceAccounts {
+		cred := sa.Credentials
+		if cred.AccessKey != accessKey || !auth.IsAccessKeyValid(accessKey) || !auth.IsSecretKeyValid(cred.SecretKey) ||
+			cred.ParentUser == "" || !cred.IsServiceAccount() || len(sa.Claims) == 0 {
+			return fmt.Errorf("%w: invalid credentials of service account %s", errInvalidIAMArchive, accessKey)
+		}
+		if accessKey == globalActiveCred.AccessKey {
+			imp.conflict(madmin.IAMServiceAccountEntity, accessKey, "is the access key of the root user")
+			continue
+		}
+		cur, ok := sys.iamUsersMap[accessKey]
+		var existing iamArchiveServiceAccount
+		if ok {
+			if !cur.IsServiceAccount() || cur.IsTemp() {
+				imp.conflict(madmin.IAMServiceAccountEntity, accessKey, "is the access key of a user or temporary user")
+				continue
+			}
+			// An existing service account which cannot be exported
+			// always differs from the imported one.
+			existing, _ = exportServiceAccount(cur)
+		}
+		if !imp.compare(madmin.IAMServiceAccountEntity, accessKey, existing, sa, ok) {
+			continue
+		}
+		// Sign the session token with the root credentials of this server.
+		token, err := jwtgo.NewWithClaims(jwtgo.SigningMethodHS512, jwtgo.MapClaims(sa.Claims)).SignedString([]byte(globalActiveCred.SecretKey))
+		if err != nil {
+			return err
+		}
+		cred.SessionToken = token
+		imp.users[accessKey] = cred
+	}
+
+	if imp.replace {
+		for accessKey, cred := range sys.iamUsersMap {
+			switch {
+			case cred.IsTemp():
+				// Temporary credentials are never exported.
+			case cred.IsServiceAccount():
+				if _, ok := a.ServiceAccounts[accessKey]; !ok {
+					imp.remove(madmin.IAMServiceAccountEntity, accessKey)
+					delete(imp.users, accessKey)
+				}
+			default:
+				if _, ok := a.Users[accessKey]; !ok {
+					imp.remove(madmin.IAMUserEntity, accessKey)
+					delete(imp.users, accessKey)
+				}
+			}
+		}
+	}
+
+	for group, gi := range a.Groups {
+		if group == "" {
+			return fmt.Errorf("%w: group without a name", errInvalidIAMArchive)
+		}
+		cur, ok := sys.iamGroupsMap[group]
+		if imp.compare(madmin.IAMGroupEntity, group, cur, gi, ok) {
+			imp.groups[group] = gi
+		}
+	}
+	if imp.replace {
+		for group := range sys.iamGroupsMap {
+			if _, ok := a.Groups[group]; !ok {
+				imp.remove(madmin.IAMGroupEntity, group)
+				delete(imp.groups, group)
+			}
+		}
+	}
+
+	for name, mp := range a.UserPolicies {
+		if name == "" || mp.Policies == "" {
+			return fmt.Errorf("%w: invalid policy mapping of user %s", errInvalidIAMArchive, name)
+		}
+		if cred, ok := sys.iamUsersMap[name]; ok && cred.IsTemp() {
+			imp.conflict(madmin.IAMUserPolicyEntity, name, "is the access key of a temporary user")
+			continue
+		}
+		cur, ok := sys.iamUserPolicyMap[name]
+		if imp.compare(madmin.IAMUserPolicyEntity, name, cur, mp, ok) {
+			imp.userPolicies[name] = mp
+		}
+	}
+	for group, mp := range a.GroupPolicies {
+		if group == "" || mp.Policies == "" {
+			return fmt.Errorf("%w: invalid policy mapping of group %s", errInvalidIAMArchive, group)
+		}
+		cur, ok := sys.iamGroupPolicyMap[group]
+		if imp.compare(madmin.IAMGroupPolicyEntity, group, cur, mp, ok) {
+			imp.groupPolicies[group] = mp
+		}
+	}
+	if imp.replace {
+		for name := range sys.iamUserPolicyMap {
+			if cred, ok := sys.iamUsersMap[name]; ok && cred.IsTemp() {
+				continue
+			}
+			if _, ok := a.UserPolicies[name]; !ok {
+				imp.remove(madmin.IAMUserPolicyEntity, name)
+				delete(imp.userPolicies, name)
+			}
+		}
+		for group := range sys.iamGroupPolicyMap {
+			if _, ok := a.GroupPolicies[group]; !ok {
+				imp.remove(madmin.IAMGroupPolicyEntity, group)
+				delete(imp.groupPolicies, group)
+			}
+		}
+	}
+
+	return imp.validate(a)
+}
+
+// validate - verifies that the entities of the archive only refer to
+// users, groups and policies which exist once the archive is imported.
+func (imp *iamImport) validate(a iamArchive) error {
+	minioUsers := imp.sys.usersSysType == MinIOUsersSysType
+
+	isUser := func(name string) bool {
+		cred, ok := imp.users[name]
+		return ok && !cred.IsTemp() && !cred.IsServiceAccount()
+	}
+	checkPolicies := func(typ madmin.IAMEntityType, name, policies string) error {
+		for _, policy := range newMappedPolicy(policies).toSlice() {
+			if _, ok := imp.policies[policy]; !ok {
+				return fmt.Errorf("%w: %s %s refers to policy %s which does not exist", errInvalidIAMArchive, typ, name, policy)
+			}
+		}
+		return nil
+	}
+
+	for accessKey := range a.Users {
+		if cred, ok := imp.users[accessKey]; ok && cred.PermissionBoundary != "" {
+			if err := checkPolicies(madmin.IAMUserEntity, accessKey, cred.PermissionBoundary); err != nil {
+				return err
+			}
+		}
+	}
+	for accessKey := range a.ServiceAccounts {
+		cred, ok := imp.users[accessKey]
+		if ok && minioUsers && !isUser(cred.ParentUser) {
+			return fmt.Errorf("%w: parent user %s of service account %s does not exist", errInvalidIAMArchive, cred.ParentUser, accessKey)
+		}
+	}
+	for group := range a.Groups {
+		gi, ok := imp.groups[group]
+		if !ok {
+			continue
+		}
+		for _, member := range gi.Members {
+			if minioUsers && !isUser(member) {
+				return fmt.Errorf("%w: member %s of group %s does not exist", errInvalidIAMArchive, member, group)
+			}
+		}
+		if gi.PermissionBoundary != "" {
+			if err := checkPolicies(madmin.IAMGroupEntity, group, gi.PermissionBoundary); err != nil {
+				return err
+			}
+		}
+	}
+	for name := range a.UserPolicies {
+		mp, ok := imp.userPolicies[name]
+		if !ok {
+			continue
+		}
+		if minioUsers && !isUser(name) {
+			return fmt.Errorf("%w: user %s mapped to policy %s does not exist", errInvalidIAMArchive, name, mp.Policies)
+		}
+		if err := checkPolicies(madmin.IAMUserPolicyEntity, name, mp.Policies); err != nil {
+			return err
+		}
+	}
+	for group := range a.GroupPolicies {
+		mp, ok := imp.groupPolicies[group]
+		if !ok {
+			continue
+		}
+		if _, ok = imp.groups[group]; minioUsers && !ok {
+			return fmt.Errorf("%w: group %s mapped to policy %s does not exist", errInvalidIAMArchive, group, mp.Policies)
+		}
+		if err := checkPolicies(madmin.IAMGroupPolicyEntity, group, mp.Policies); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// save - writes an added or updated entity to the IAM store. Assumes
+// that sys.store.lock() is held by caller.
+func (imp *iamImport) save(ctx context.Context, entity madmin.IAMEntity) error {
+	sys := imp.sys
+	name := entity.Name
+	switch entity.Type {
+	case madmin.IAMPolicyEntity:
+		p := imp.policies[name]
+		if err := sys.store.savePolicyDoc(ctx, name, p); err != nil {
+			return err
+		}
+		sys.iamPolicyDocsMap[name] = p
+	case madmin.IAMUserEntity, madmin.IAMServiceAccountEntity:
+		cred := imp.users[name]
+		userType := regularUser
+		if entity.Type == madmin.IAMServiceAccountEntity {
+			userType = srvAccUser
+		}
+		if err := sys.store.saveUserIdentity(ctx, name, userType, newUserIdentity(cred)); err != nil {
+			return err
+		}
+		sys.iamUsersMap[name] = cred
+	case madmin.IAMGroupEntity:
+		gi := imp.groups[name]
+		if err := sys.store.saveGroupInfo(ctx, name, gi); err != nil {
+			return err
+		}
+		sys.iamGroupsMap[name] = gi
+		sys.removeGroupFromMembershipsMap(name)
+		sys.updateGroupMembershipsMap(name, &gi)
+	case madmin.IAMUserPolicyEntity:
+		mp := imp.userPolicies[name]
+		if err := sys.store.saveMappedPolicy(ctx, name, regularUser, false, mp); err != nil {
+			return err
+		}
+		sys.iamUserPolicyMap[name] = mp
+	case madmin.IAMGroupPolicyEntity:
+		mp := imp.groupPolicies[name]
+		if err := sys.store.saveMappedPolicy(ctx, name, regularUser, true, mp); err != nil {
+			return err
+		}
+		sys.iamGroupPolicyMap[name] = mp
+	}
+	return nil
+}
+
+// delete - removes an entity missing from the archive from the IAM
+// store. Assumes that sys.store.lock() is held by caller.
+func (imp *iamImport) delete(ctx context.Context, entity madmin.IAMEntity) error {
+	sys := imp.sys
+	name := entity.Name
+	switch entity.Type {
+	case madmin.IAMPolicyEntity:
+		if err := sys.store.deletePolicyDoc(ctx, name); err != nil && err != errNoSuchPolicy {
+			return err
+		}
+		delete(sys.iamPolicyDocsMap, name)
+	case madmin.IAMUserEntity, madmin.IAMServiceAccountEntity:
+		userType := regularUser
+		if entity.Type == madmin.IAMServiceAccountEntity {
+			userType = srvAccUser
+		}
+		if err := sys.store.deleteUserIdentity(ctx, name, userType); err != nil && err != errNoSuchUser {
+			return err
+		}
+		globalAccessKeyUsage.forget(ctx, sys.store, name)
+		delete(sys.iamUsersMap, name)
+	case madmin.IAMGroupEntity:
+		if err := sys.store.deleteGroupInfo(ctx, name); err != nil && err != errNoSuchGroup {
+			return err
+		}
+		sys.removeGroupFromMembershipsMap(name)
+		delete(sys.iamGroupsMap, name)
+	case madmin.IAMUserPolicyEntity:
+		if err := sys.store.deleteMappedPolicy(ctx, name, regularUser, false); err != nil && err != errNoSuchPolicy {
+			return err
+		}
+		delete(sys.iamUserPolicyMap, name)
+	case madmin.IAMGroupPolicyEntity:
+		if err := sys.store.deleteMappedPolicy(ctx, name, regularUser, true); err != nil && err != errNoSuchPolicy {
+			return err
+		}
+		delete(sys.iamGroupPolicyMap, name)
+	}
+	return nil
+}
+
+// ImportIAM - imports an IAM archive. The archive is merged unless
+// replace is set, in which case the users, groups, policies, policy
+// mappings and service accounts missing from the archive are removed.
+// The archive is validated as a whole before any change is made and
+// nothing is changed on a dry run. On error the changes made so far are
+// returned.
+func (sys *IAMSys) ImportIAM(ctx context.Context, a iamArchive, replace, dryRun bool) (madmin.IAMImportResult, error) {
+	if !sys.Initialized() {
+		return madmin.IAMImportResult{}, errServerNotInitialized
+	}
+
+	if a.Version != iamArchiveVersion {
+		return madmin.IAMImportResult{}, fmt.Errorf("%w: unsupported version %d", errInvalidIAMArchive, a.Version)
+	}
+
+	sys.store.lock()
+	defer sys.store.unlock()
+
+	if sys.usersSysType != MinIOUsersSysType && (len(a.Users) > 0 || len(a.Groups) > 0) {
+		return madmin.IAMImportResult{}, errIAMActionNotAllowed
+	}
+
+	imp := &iamImport{
+		sys:           sys,
+		replace:       replace,
+		policies:      make(map[string]iampolicy.Policy, len(sys.iamPolicyDocsMap)),
+		users:         make(map[string]auth.Credentials, len(sys.iamUsersMap)),
+		groups:        make(map[string]GroupInfo, len(sys.iamGroupsMap)),
+		userPolicies:  make(map[string]MappedPolicy, len(sys.iamUserPolicyMap)),
+		groupPolicies: make(map[string]MappedPolicy, len(sys.iamGroupPolicyMap)),
+	}
+	for k, v := range sys.iamPolicyDocsMap {
+		imp.policies[k] = v
+	}
+	for k, v := range sys.iamUsersMap {
+		imp.users[k] = v
+	}
+	for k, v := range sys.iamGroupsMap {
+		imp.groups[k] = v
+	}
+	for k, v := range sys.iamUserPolicyMap {
+		imp.userPolicies[k] = v
+	}
+	for k, v := range sys.iamGroupPolicyMap {
+		imp.groupPolicies[k] = v
+	}
+
+	if err := imp.plan(a); err != nil {
+		return madmin.IAMImportResult{}, err
+	}
+	if dryRun {
+		return imp.result, nil
+	}
+
+	// Entities are written in the order they are planned, policies
+	// first, and removed in the reverse order, so that every entity
+	// written refers to entities which exist.
+	var done madmin.IAMImportResult
+	done.Conflicts = imp.result.Conflicts
+	for _, entity := range imp.result.Added {
+		if err := imp.save(ctx, entity); err != nil {
+			return done, err
+		}
+		done.Added = append(done.Added, entity)
+	}
+	for _, entity := range imp.result.Updated {
+		if err := imp.save(ctx, entity); err != nil {
+			return done, err
+		}
+		done.Updated = append(done.Updated, entity)
+	}
+	for i := len(imp.result.Removed) - 1; i >= 0; i-- {
+		entity := imp.result.Removed[i]
+		if err := imp.delete(ctx, entity); err != nil {
+			return done, err
+		}
+		done.Removed = append(done.Removed, entity)
+	}
+	return done, nil
+}
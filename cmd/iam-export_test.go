@@ -0,0 +1,150 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	iampolicy "github.com/minio/minio/pkg/iam/policy"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func exportTestIAM(t *testing.T, sys *IAMSys) iamArchive {
+	a, err := sys.ExportIAM()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The archive is transferred as JSON.
+	data, err := json.Marshal(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var archive iamArchive
+	if err = json.Unmarshal(data, &archive); err != nil {
+		t.Fatal(err)
+	}
+	return archive
+}
+
+func TestIAMExportImport(t *testing.T) {
+	ctx := context.Background()
+
+	src := newTestIAMSys(t)
+	if err := src.SetPolicy("custom", iampolicy.ReadOnly); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.SetUser("alice", madmin.UserInfo{SecretKey: "alice12345", Status: madmin.AccountEnabled}); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.AddUsersToGroup("eng", []string{"alice"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.PolicyDBSet("alice", "readwrite", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.PolicyDBSet("eng", "custom", true); err != nil {
+		t.Fatal(err)
+	}
+	sa, err := src.NewServiceAccount(ctx, "alice", nil, timeSentinel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive := exportTestIAM(t, src)
+
+	dst := newTestIAMSys(t)
+
+	result, err := dst.ImportIAM(ctx, archive, false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The default policies are equal, only the custom one is added.
+	if len(result.Added) != 6 || len(result.Updated) != 0 || len(result.Removed) != 0 || len(result.Conflicts) != 0 {
+		t.Fatalf("unexpected dry run result %+v", result)
+	}
+	if _, ok := dst.GetUser("alice"); ok {
+		t.Fatal("expected nothing to be imported on a dry run")
+	}
+
+	if _, err = dst.ImportIAM(ctx, archive, false, false); err != nil {
+		t.Fatal(err)
+	}
+	if cred, ok := dst.GetUser("alice"); !ok || cred.SecretKey != "alice12345" {
+		t.Fatal("expected user to be imported")
+	}
+	if policies, err := dst.PolicyDBGet("alice", false); err != nil || len(policies) != 2 {
+		t.Fatalf("expected the policies of the user and its group, got %v (%v)", policies, err)
+	}
+	if ok, parent, err := dst.IsServiceAccount(sa.AccessKey); err != nil || !ok || parent != "alice" {
+		t.Fatalf("expected service account of alice to be imported, got %v %s (%v)", ok, parent, err)
+	}
+	cred, _ := dst.GetUser(sa.AccessKey)
+	if claims := sessionClaims(cred.SessionToken); claims == nil || claims[parentClaim] != "alice" {
+		t.Fatalf("expected a valid session token, got claims %v", claims)
+	}
+
+	// Importing again changes nothing.
+	if result, err = dst.ImportIAM(ctx, archive, false, false); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Added) != 0 || len(result.Updated) != 0 || len(result.Conflicts) != 0 {
+		t.Fatalf("expected no changes, got %+v", result)
+	}
+
+	if err = dst.SetUserSecretKey("alice", "changed12345"); err != nil {
+		t.Fatal(err)
+	}
+	if err = dst.SetUser("bob", madmin.UserInfo{SecretKey: "bob1234567", Status: madmin.AccountEnabled}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Merging leaves the existing users unchanged.
+	if result, err = dst.ImportIAM(ctx, archive, false, false); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0].Type != madmin.IAMUserEntity || result.Conflicts[0].Name != "alice" {
+		t.Fatalf("expected a conflict on alice, got %+v", result)
+	}
+	if cred, _ := dst.GetUser("alice"); cred.SecretKey != "changed12345" {
+		t.Fatal("expected conflicting user not to be imported")
+	}
+
+	// Replacing updates alice and removes bob.
+	if result, err = dst.ImportIAM(ctx, archive, true, false); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Updated) != 1 || len(result.Removed) != 1 || result.Removed[0].Name != "bob" {
+		t.Fatalf("unexpected replace result %+v", result)
+	}
+	if cred, _ := dst.GetUser("alice"); cred.SecretKey != "alice12345" {
+		t.Fatal("expected user to be replaced")
+	}
+	if _, ok := dst.GetUser("bob"); ok {
+		t.Fatal("expected user missing from the archive to be removed")
+	}
+
+	// Archives referring to missing users are rejected as a whole.
+	archive.Groups["ops"] = newGroupInfo([]string{"carol"})
+	if _, err = dst.ImportIAM(ctx, archive, false, false); !errors.Is(err, errInvalidIAMArchive) {
+		t.Fatalf("expected %v, got %v", errInvalidIAMArchive, err)
+	}
+	if groups, _ := dst.ListGroups(); len(groups) != 1 {
+		t.Fatalf("expected no group to be imported, got %v", groups)
+	}
+}
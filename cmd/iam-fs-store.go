@@ -0,0 +1,190 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/lock"
+)
+
+// FSGateway is implemented by gateways storing objects on a local
+// filesystem, such as NAS. Without etcd their IAM configuration is
+// persisted on that filesystem.
+type FSGateway interface {
+	// FSPath returns the path of the filesystem objects are stored on.
+	FSPath() string
+}
+
+// newIAMFSStore - returns an IAM store persisting the IAM configuration
+// as files under `.minio.sys/config/iam` of the filesystem at fsPath.
+// Multiple gateways may share the filesystem. The write lock of the store
+// also locks a file shared by all gateways, so that a change is loaded,
+// modified and saved without a change of another gateway in between.
+// Every file is replaced atomically, changes of other gateways are
+// picked up by the periodic reload of the store and the entities are
+// reloaded before they are modified.
+func newIAMFSStore(objAPI ObjectLayer, fsPath string) *IAMObjectStore {
+	return &IAMObjectStore{objAPI: objAPI, fsPath: fsPath}
+}
+
+// lockConfigFiles - locks the IAM configuration files for writing, the
+// lock is shared with all processes using the same filesystem.
+func (iamOS *IAMObjectStore) lockConfigFiles() (*lock.LockedFile, error) {
+	lockPath := pathJoin(iamOS.fsPath, minioMetaBucket, iamConfigPrefix+".lock")
+	if err := mkdirAll(pathJoin(iamOS.fsPath, minioMetaBucket, minioConfigPrefix), 0777); err != nil {
+		return nil, osErrToFileErr(err)
+	}
+	return lock.LockedOpenFile(lockPath, os.O_RDWR|os.O_CREATE, 0666)
+}
+
+// lockFS - locks the IAM configuration files along with the write lock
+// of the store, writes are still possible if the lock fails.
+func (iamOS *IAMObjectStore) lockFS() {
+	lk, err := iamOS.lockConfigFiles()
+	if err != nil {
+		logger.LogIf(GlobalContext, err)
+		return
+	}
+	iamOS.fsLock = lk
+}
+
+// saveConfigFile - writes an IAM configuration file, the data is written
+// to a temporary file and synced before being renamed over the previous
+// file so that a crash never leaves a partially written file. Writes of
+// the IAM configuration hold the write lock of the store.
+func (iamOS *IAMObjectStore) saveConfigFile(ctx context.Context, configFile string, data []byte) error {
+	tmpPath := pathJoin(iamOS.fsPath, minioMetaTmpBucket, mustGetUUID())
+	f, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0666)
+	if err != nil {
+		return osErrToFileErr(err)
+	}
+	if _, err = f.Write(data); err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	filePath := pathJoin(iamOS.fsPath, minioMetaBucket, configFile)
+	if err = fsRenameFile(ctx, tmpPath, filePath); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	// Sync the parent directory so that the rename itself is durable,
+	// not supported on all platforms.
+	if dir, derr := os.Open(path.Dir(filePath)); derr == nil {
+		dir.Sync()
+		dir.Close()
+	}
+	return nil
+}
+
+// readConfigFile - reads an IAM configuration file, files are replaced
+// atomically so no lock is needed.
+func (iamOS *IAMObjectStore) readConfigFile(configFile string) ([]byte, error) {
+	data, err := ioutil.ReadFile(pathJoin(iamOS.fsPath, minioMetaBucket, configFile))
+	if err != nil {
+		if osIsNotExist(err) {
+			return nil, errConfigNotFound
+		}
+		return nil, osErrToFileErr(err)
+	}
+
+	// Return config not found on empty content.
+	if len(data) == 0 {
+		return nil, errConfigNotFound
+	}
+	return data, nil
+}
+
+// deleteConfigFile - removes an IAM configuration file along with its
+// parent directories once they are empty.
+func (iamOS *IAMObjectStore) deleteConfigFile(configFile string) error {
+	basePath := pathJoin(iamOS.fsPath, minioMetaBucket)
+	err := deleteFile(basePath, pathJoin(basePath, configFile), false)
+	if err == errFileNotFound {
+		return errConfigNotFound
+	}
+	return err
+}
+
+// isFSStore - returns true if the IAM configuration is persisted on the
+// filesystem of an FS gateway, which may be shared with other gateways.
+func (sys *IAMSys) isFSStore() bool {
+	iamOS, ok := sys.store.(*IAMObjectStore)
+	return ok && iamOS.fsPath != ""
+}
+
+// reloadUserForUpdate - reloads a user or service account from the
+// filesystem shared with other gateways before it is modified, so that
+// their changes since the last reload are not overwritten. The caller
+// holds the write lock of the store.
+func (sys *IAMSys) reloadUserForUpdate(ctx context.Context, accessKey string) error {
+	if !sys.isFSStore() {
+		return nil
+	}
+	userType := regularUser
+	if cred, ok := sys.iamUsersMap[accessKey]; ok {
+		if cred.IsTemp() {
+			return nil
+		}
+		if cred.IsServiceAccount() {
+			userType = srvAccUser
+		}
+	}
+	err := sys.store.loadUser(ctx, accessKey, userType, sys.iamUsersMap)
+	if err == errNoSuchUser {
+		// Removed by another gateway.
+		delete(sys.iamUsersMap, accessKey)
+		delete(sys.iamUserPolicyMap, accessKey)
+		return nil
+	}
+	return err
+}
+
+// reloadGroupForUpdate - reloads a group from the filesystem shared with
+// other gateways before it is modified, see reloadUserForUpdate.
+func (sys *IAMSys) reloadGroupForUpdate(ctx context.Context, group string) error {
+	if !sys.isFSStore() {
+		return nil
+	}
+	err := sys.store.loadGroup(ctx, group, sys.iamGroupsMap)
+	if err == errNoSuchGroup {
+		// Removed by another gateway, or not created yet.
+		sys.removeGroupFromMembershipsMap(group)
+		delete(sys.iamGroupsMap, group)
+		delete(sys.iamGroupPolicyMap, group)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	gi := sys.iamGroupsMap[group]
+	sys.removeGroupFromMembershipsMap(group)
+	sys.updateGroupMembershipsMap(group, &gi)
+	return nil
+}
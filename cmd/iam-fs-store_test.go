@@ -0,0 +1,185 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+
+	iampolicy "github.com/minio/minio/pkg/iam/policy"
+	"github.com/minio/minio/pkg/lock"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func TestIAMFSStore(t *testing.T) {
+	obj, fsDir := prepareIAMTestFS(t)
+	var err error
+
+	sys := NewIAMSys()
+	sys.InitFSStore(obj, fsDir)
+
+	if err = sys.SetPolicy("readwrite", iampolicy.ReadWrite); err != nil {
+		t.Fatal(err)
+	}
+	if err = sys.SetUser("alice", madmin.UserInfo{SecretKey: "alice12345", Status: madmin.AccountEnabled}); err != nil {
+		t.Fatal(err)
+	}
+	if err = sys.PolicyDBSet("alice", "readwrite", false); err != nil {
+		t.Fatal(err)
+	}
+
+	identityFile := pathJoin(fsDir, minioMetaBucket, getUserIdentityPath("alice", regularUser))
+	if _, err = os.Stat(identityFile); err != nil {
+		t.Fatalf("expected user identity to be written to the filesystem: %v", err)
+	}
+	entries, err := ioutil.ReadDir(pathJoin(fsDir, minioMetaTmpBucket))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			t.Fatalf("expected no temporary files to be left, found %s", entry.Name())
+		}
+	}
+
+	// Another gateway on the same filesystem loads the user.
+	other := NewIAMSys()
+	other.InitFSStore(obj, fsDir)
+	if err = other.store.loadAll(context.Background(), other); err != nil {
+		t.Fatal(err)
+	}
+	if cred, ok := other.GetUser("alice"); !ok || cred.SecretKey != "alice12345" {
+		t.Fatal("expected user to be loaded from the filesystem")
+	}
+	if policies, err := other.PolicyDBGet("alice", false); err != nil || len(policies) != 1 || policies[0] != "readwrite" {
+		t.Fatalf("expected policy readwrite to be mapped, got %v (%v)", policies, err)
+	}
+
+	// Writes hold the lock shared by all gateways on the filesystem.
+	lk, err := sys.store.(*IAMObjectStore).lockConfigFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lockPath := pathJoin(fsDir, minioMetaBucket, iamConfigPrefix+".lock")
+	if _, err = lock.TryLockedOpenFile(lockPath, os.O_RDWR, 0); err != lock.ErrAlreadyLocked {
+		t.Fatalf("expected %v, got %v", lock.ErrAlreadyLocked, err)
+	}
+	lk.Close()
+
+	if err = sys.DeleteUser("alice"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(pathJoin(identityFile, "..")); !os.IsNotExist(err) {
+		t.Fatalf("expected user directory to be removed, got %v", err)
+	}
+	var u UserIdentity
+	if err = sys.store.loadIAMConfig(context.Background(), &u, getUserIdentityPath("alice", regularUser)); err != errConfigNotFound {
+		t.Fatalf("expected %v, got %v", errConfigNotFound, err)
+	}
+	if err = sys.store.deleteIAMConfig(context.Background(), getUserIdentityPath("alice", regularUser)); err != errConfigNotFound {
+		t.Fatalf("expected %v, got %v", errConfigNotFound, err)
+	}
+}
+
+// Two gateways sharing the filesystem modify the same entities without
+// overwriting the changes of each other.
+func TestIAMFSStoreSharedUpdates(t *testing.T) {
+	obj, fsDir := prepareIAMTestFS(t)
+	var err error
+
+	newGateway := func() *IAMSys {
+		sys := NewIAMSys()
+		sys.InitFSStore(obj, fsDir)
+		if err := sys.store.loadAll(context.Background(), sys); err != nil {
+			t.Fatal(err)
+		}
+		return sys
+	}
+	gw1 := newGateway()
+	for _, user := range []string{"alice", "bob"} {
+		if err = gw1.SetUser(user, madmin.UserInfo{SecretKey: user + "12345", Status: madmin.AccountEnabled}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = gw1.AddUsersToGroup("eng", []string{"alice"}); err != nil {
+		t.Fatal(err)
+	}
+	gw2 := newGateway()
+
+	// The group and the user are reloaded before they are modified.
+	if err = gw1.AddUsersToGroup("eng", []string{"bob"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = gw2.SetGroupStatus("eng", false); err != nil {
+		t.Fatal(err)
+	}
+	if err = gw1.SetUserTags("alice", map[string]string{"team": "eng"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = gw2.SetUserStatus("alice", madmin.AccountDisabled); err != nil {
+		t.Fatal(err)
+	}
+
+	gw3 := newGateway()
+	gd, err := gw3.GetGroupDescription("eng")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gd.Members) != 2 || gd.Status != statusDisabled {
+		t.Fatalf("expected both members and the status to be kept, got %+v", gd)
+	}
+	u, err := gw3.GetUserInfo("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Status != madmin.AccountDisabled || u.Tags["team"] != "eng" {
+		t.Fatalf("expected both the tags and the status to be kept, got %+v", u)
+	}
+
+	// Quotas of all users are stored in a single file, which is loaded,
+	// modified and saved under the lock.
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		sys := gw1
+		if i%2 == 1 {
+			sys = gw2
+		}
+		user := fmt.Sprintf("user%d", i)
+		if err = sys.SetUser(user, madmin.UserInfo{SecretKey: user + "12345", Status: madmin.AccountEnabled}); err != nil {
+			t.Fatal(err)
+		}
+		wg.Add(1)
+		go func(sys *IAMSys, user string) {
+			defer wg.Done()
+			if err := sys.SetQuota(context.Background(), user, false, madmin.UserQuota{Quota: 1024, Type: madmin.HardQuota}); err != nil {
+				t.Error(err)
+			}
+		}(sys, user)
+	}
+	wg.Wait()
+	q, err := gw3.GetQuotas(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(q.Users) != 20 {
+		t.Fatalf("expected the quotas of 20 users, got %d", len(q.Users))
+	}
+}
@@ -0,0 +1,174 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/handlers"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+const (
+	// IAM access key usage directory.
+	iamConfigKeyUsagePrefix = iamConfigPrefix + "/key-usage/"
+
+	// How often the last use of access keys is persisted.
+	accessKeyUsageSaveInterval = time.Minute
+)
+
+func getAccessKeyUsagePath(accessKey string) string {
+	return pathJoin(iamConfigKeyUsagePrefix, accessKey+".json")
+}
+
+// accessKeyUsageInfo is the persisted last use of an access key.
+type accessKeyUsageInfo struct {
+	Version  int                   `json:"version"`
+	LastUsed madmin.AccessKeyUsage `json:"lastUsed"`
+}
+
+// accessKeyUsage tracks the last use of the access keys of users and
+// service accounts. The usage seen by this server is kept in memory
+// and saved periodically through the IAM store, the last use of an
+// access key is the latest of the local and the persisted usage.
+type accessKeyUsage struct {
+	mu sync.Mutex
+	// last use of access keys seen by this server.
+	used map[string]madmin.AccessKeyUsage
+	// access keys used since the last save.
+	dirty map[string]struct{}
+}
+
+var globalAccessKeyUsage = newAccessKeyUsage()
+
+func newAccessKeyUsage() *accessKeyUsage {
+	return &accessKeyUsage{
+		used:  make(map[string]madmin.AccessKeyUsage),
+		dirty: make(map[string]struct{}),
+	}
+}
+
+// record - records the use of the given credentials by an authenticated
+// request, the root credentials and temporary credentials are ignored.
+func (u *accessKeyUsage) record(ctx context.Context, r *http.Request, cred auth.Credentials) {
+	if cred.AccessKey == "" || cred.AccessKey == globalActiveCred.AccessKey || cred.IsTemp() {
+		return
+	}
+
+	usage := madmin.AccessKeyUsage{
+		Time:     UTCNow(),
+		SourceIP: handlers.GetSourceIP(r),
+		API:      logger.GetReqInfo(ctx).API,
+	}
+
+	u.mu.Lock()
+	u.used[cred.AccessKey] = usage
+	u.dirty[cred.AccessKey] = struct{}{}
+	u.mu.Unlock()
+}
+
+// recordRequest - records the use of the access key of a request whose
+// signature was verified after the access key was set in the request
+// info, as done by verifyPutObjectSignature.
+func (u *accessKeyUsage) recordRequest(ctx context.Context, r *http.Request) {
+	accessKey := logger.GetReqInfo(ctx).AccessKey
+	if accessKey == "" {
+		return
+	}
+	if cred, ok := globalIAMSys.GetUser(accessKey); ok {
+		u.record(ctx, r, cred)
+	}
+}
+
+// lastUsed - returns the last use of an access key, nil if the access
+// key was never used.
+func (u *accessKeyUsage) lastUsed(ctx context.Context, store IAMStorageAPI, accessKey string) *madmin.AccessKeyUsage {
+	u.mu.Lock()
+	usage, ok := u.used[accessKey]
+	u.mu.Unlock()
+
+	var info accessKeyUsageInfo
+	if err := store.loadIAMConfig(ctx, &info, getAccessKeyUsagePath(accessKey)); err == nil {
+		if !ok || info.LastUsed.Time.After(usage.Time) {
+			usage, ok = info.LastUsed, true
+		}
+	}
+	if !ok {
+		return nil
+	}
+	return &usage
+}
+
+// forget - removes the usage of a deleted access key.
+func (u *accessKeyUsage) forget(ctx context.Context, store IAMStorageAPI, accessKey string) {
+	u.mu.Lock()
+	delete(u.used, accessKey)
+	delete(u.dirty, accessKey)
+	u.mu.Unlock()
+
+	// It is ok to ignore errors, the usage may have never been saved.
+	store.deleteIAMConfig(ctx, getAccessKeyUsagePath(accessKey))
+}
+
+// save - persists the usage of the access keys used since the last
+// save, usage saved by other servers is only replaced by later usage.
+func (u *accessKeyUsage) save(ctx context.Context, store IAMStorageAPI) {
+	u.mu.Lock()
+	used := make(map[string]madmin.AccessKeyUsage, len(u.dirty))
+	for accessKey := range u.dirty {
+		used[accessKey] = u.used[accessKey]
+	}
+	u.dirty = make(map[string]struct{})
+	u.mu.Unlock()
+
+	for accessKey, usage := range used {
+		var info accessKeyUsageInfo
+		if err := store.loadIAMConfig(ctx, &info, getAccessKeyUsagePath(accessKey)); err == nil {
+			if !usage.Time.After(info.LastUsed.Time) {
+				continue
+			}
+		}
+		info = accessKeyUsageInfo{Version: 1, LastUsed: usage}
+		if err := store.saveIAMConfig(ctx, &info, getAccessKeyUsagePath(accessKey)); err != nil {
+			logger.LogIf(ctx, err)
+			// Retry with the next save.
+			u.mu.Lock()
+			u.dirty[accessKey] = struct{}{}
+			u.mu.Unlock()
+		}
+	}
+}
+
+// run - saves the usage of access keys periodically until ctx is done.
+func (u *accessKeyUsage) run(ctx context.Context, store IAMStorageAPI) {
+	ticker := time.NewTicker(accessKeyUsageSaveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			u.save(ctx, store)
+		}
+	}
+}
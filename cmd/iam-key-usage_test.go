@@ -0,0 +1,159 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/bucket/policy"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func TestAccessKeyUsage(t *testing.T) {
+	obj, _ := prepareIAMTestFS(t)
+
+	store := newIAMObjectStore(obj)
+	cred := auth.Credentials{AccessKey: "myuser", SecretKey: "mypassword"}
+
+	r := httptest.NewRequest("GET", "http://localhost:9000/bucket", nil)
+	r.RemoteAddr = "10.0.0.1:12345"
+	ctx := logger.SetReqInfo(context.Background(), &logger.ReqInfo{API: "ListObjectsV2"})
+
+	u := newAccessKeyUsage()
+	if usage := u.lastUsed(ctx, store, cred.AccessKey); usage != nil {
+		t.Fatalf("expected no usage, got %v", usage)
+	}
+
+	// The root and temporary credentials are not tracked.
+	u.record(ctx, r, globalActiveCred)
+	u.record(ctx, r, auth.Credentials{AccessKey: "mytempuser", SecretKey: "mypassword",
+		SessionToken: "token", Expiration: UTCNow().Add(time.Hour)})
+	u.record(ctx, r, cred)
+	if len(u.used) != 1 {
+		t.Fatalf("expected a single tracked access key, got %d", len(u.used))
+	}
+
+	usage := u.lastUsed(ctx, store, cred.AccessKey)
+	if usage == nil || usage.SourceIP != "10.0.0.1" || usage.API != "ListObjectsV2" {
+		t.Fatalf("unexpected usage %v", usage)
+	}
+
+	// Usage saved by this server is seen by other servers.
+	u.save(ctx, store)
+	if len(u.dirty) != 0 {
+		t.Fatalf("expected no pending usage, got %d", len(u.dirty))
+	}
+	if peerUsage := newAccessKeyUsage().lastUsed(ctx, store, cred.AccessKey); peerUsage == nil || !peerUsage.Time.Equal(usage.Time) {
+		t.Fatalf("expected the saved usage %v, got %v", usage, peerUsage)
+	}
+
+	// Earlier usage of another server does not replace later usage.
+	peer := newAccessKeyUsage()
+	peer.record(ctx, r, cred)
+	earlier := peer.used[cred.AccessKey]
+	earlier.Time = usage.Time.Add(-time.Minute)
+	peer.used[cred.AccessKey] = earlier
+	peer.save(ctx, store)
+	if saved := newAccessKeyUsage().lastUsed(ctx, store, cred.AccessKey); saved == nil || !saved.Time.Equal(usage.Time) {
+		t.Fatalf("expected the saved usage %v, got %v", usage, saved)
+	}
+
+	u.forget(ctx, store, cred.AccessKey)
+	if usage := u.lastUsed(ctx, store, cred.AccessKey); usage != nil {
+		t.Fatalf("expected no usage after forget, got %v", usage)
+	}
+}
+
+func TestIAMSysAccessKeyLastUsed(t *testing.T) {
+	ctx := context.Background()
+	sys := newTestIAMSys(t)
+	if err := sys.SetUser("alice", madmin.UserInfo{SecretKey: "alice12345", Status: madmin.AccountEnabled}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sys.AddUsersToGroup("dev", []string{"alice"}); err != nil {
+		t.Fatal(err)
+	}
+	if groups := sys.GetUserGroups("alice"); len(groups) != 1 || groups[0] != "dev" {
+		t.Fatalf("unexpected groups %v", groups)
+	}
+	if groups := sys.GetUserGroups("bob"); len(groups) != 0 {
+		t.Fatalf("unexpected groups of unknown user %v", groups)
+	}
+
+	r := httptest.NewRequest("GET", "http://localhost:9000/bucket", nil)
+	cred, _ := sys.GetUser("alice")
+	globalAccessKeyUsage.record(logger.SetReqInfo(ctx, &logger.ReqInfo{API: "ListObjectsV2"}), r, cred)
+	defer globalAccessKeyUsage.forget(ctx, sys.store, "alice")
+
+	// The user info is looked up on the request path, the last use is
+	// only looked up by the admin handlers.
+	info, err := sys.GetUserInfo("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.LastUsed != nil {
+		t.Fatalf("unexpected last use %v", info.LastUsed)
+	}
+	if usage := sys.AccessKeyLastUsed(ctx, "alice"); usage == nil || usage.API != "ListObjectsV2" {
+		t.Fatalf("unexpected last use %v", usage)
+	}
+}
+
+func TestCheckRequestAuthTypeAccessKeyUsage(t *testing.T) {
+	sys := newTestIAMSys(t)
+	defer func(sys *IAMSys) { globalIAMSys = sys }(globalIAMSys)
+	globalIAMSys = sys
+	if err := sys.SetUser("alice", madmin.UserInfo{SecretKey: "alice12345", Status: madmin.AccountEnabled}); err != nil {
+		t.Fatal(err)
+	}
+	defer globalAccessKeyUsage.forget(context.Background(), sys.store, "alice")
+
+	check := func() APIErrorCode {
+		r, err := newTestSignedRequestV4("GET", "http://localhost:9000/bucket/object", 0, nil, "alice", "alice12345", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx := logger.SetReqInfo(context.Background(), &logger.ReqInfo{API: "GetObject"})
+		return checkRequestAuthType(ctx, r, policy.GetObjectAction, "bucket", "object")
+	}
+
+	// Denied requests are not a use of the access key.
+	if s3Err := check(); s3Err != ErrAccessDenied {
+		t.Fatalf("expected %v, got %v", ErrAccessDenied, s3Err)
+	}
+	globalAccessKeyUsage.mu.Lock()
+	_, used := globalAccessKeyUsage.used["alice"]
+	globalAccessKeyUsage.mu.Unlock()
+	if used {
+		t.Fatal("expected denied request not to be recorded")
+	}
+
+	if err := sys.PolicyDBSet("alice", "readwrite", false); err != nil {
+		t.Fatal(err)
+	}
+	if s3Err := check(); s3Err != ErrNone {
+		t.Fatalf("expected %v, got %v", ErrNone, s3Err)
+	}
+	if usage := sys.AccessKeyLastUsed(context.Background(), "alice"); usage == nil || usage.API != "GetObject" {
+		t.Fatalf("unexpected last use %v", usage)
+	}
+}
@@ -0,0 +1,277 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/set"
+	xldap "github.com/minio/minio/cmd/config/identity/ldap"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+const (
+	// IAM LDAP group sync file, holds the synced groups of the LDAP
+	// users and the result of the last sync.
+	iamConfigLDAPGroupSyncFile = iamConfigPrefix + "/ldap-group-sync.json"
+)
+
+var ldapGroupSyncLeaderLockTimeout = newDynamicTimeout(5*time.Second, time.Second)
+
+// ldapGroupSyncInfo is the persisted result of the last LDAP group sync.
+type ldapGroupSyncInfo struct {
+	Version int                        `json:"version"`
+	Status  madmin.LDAPGroupSyncStatus `json:"status"`
+	// LDAP users and their groups.
+	Groups map[string][]string `json:"groups"`
+}
+
+// ldapGroupSync periodically syncs the LDAP groups of the users with
+// temporary credentials. A single server of the cluster looks up the
+// groups, updates or revokes the credentials of the users and saves the
+// synced groups through the IAM store, the other servers load them. The
+// synced groups are used by AssumeRoleWithLDAPIdentity instead of
+// searching the groups of the user at every login.
+type ldapGroupSync struct {
+	mu   sync.RWMutex
+	info ldapGroupSyncInfo
+}
+
+var globalLDAPGroupSync = &ldapGroupSync{}
+
+// groups - returns the synced groups of an LDAP user, false if the
+// groups of the user were not synced recently.
+func (s *ldapGroupSync) groups(username string) ([]string, bool) {
+	interval := globalLDAPConfig.GroupSyncInterval
+	if interval <= 0 {
+		return nil, false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Ignore stale groups, e.g. when the directory is unavailable.
+	if UTCNow().Sub(s.info.Status.LastSync) > 2*interval {
+		return nil, false
+	}
+	groups, ok := s.info.Groups[username]
+	return groups, ok
+}
+
+// status - returns the result of the last sync, as saved by the server
+// syncing the groups.
+func (s *ldapGroupSync) status(ctx context.Context, store IAMStorageAPI) madmin.LDAPGroupSyncStatus {
+	if !globalLDAPConfig.Enabled || globalLDAPConfig.GroupSyncInterval <= 0 {
+		return madmin.LDAPGroupSyncStatus{}
+	}
+
+	if err := s.load(ctx, store); err != nil && err != errConfigNotFound {
+		logger.LogIf(ctx, err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	status := s.info.Status
+	status.Enabled = true
+	return status
+}
+
+// load - loads the groups synced by another server.
+func (s *ldapGroupSync) load(ctx context.Context, store IAMStorageAPI) error {
+	var info ldapGroupSyncInfo
+	if err := store.loadIAMConfig(ctx, &info, iamConfigLDAPGroupSyncFile); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if info.Status.LastSync.After(s.info.Status.LastSync) {
+		s.info = info
+	}
+	return nil
+}
+
+// sync - looks up the groups of the LDAP users with temporary credentials
+// and updates or revokes their credentials.
+func (s *ldapGroupSync) sync(ctx context.Context, sys *IAMSys) {
+	start := UTCNow()
+	users := sys.ldapSessionUsers()
+
+	info := ldapGroupSyncInfo{
+		Version: 1,
+		Status: madmin.LDAPGroupSyncStatus{
+			Enabled:  true,
+			LastSync: start,
+			Users:    len(users),
+		},
+		Groups: make(map[string][]string, len(users)),
+	}
+
+	for _, user := range users {
+		groups, err := globalLDAPConfig.LookupGroups(user)
+		removed := err == xldap.ErrUserNotFound || err == xldap.ErrUserDisabled
+		if err != nil && !removed {
+			// The groups of the user are unknown, its
+			// credentials are left as they are.
+			info.Status.Failed++
+			info.Status.Error = err.Error()
+			continue
+		}
+
+		updated, revoked, err := sys.syncLDAPUser(user, groups, removed)
+		if err != nil {
+			logger.LogIf(ctx, err)
+			info.Status.Failed++
+			info.Status.Error = err.Error()
+		}
+		info.Status.Updated += len(updated)
+		info.Status.Revoked += len(revoked)
+
+		// Notify all other MinIO peers to reload temp users, revoked
+		// users are removed by the reload.
+		for _, accessKey := range append(updated, revoked...) {
+			for _, nerr := range globalNotificationSys.LoadUser(accessKey, true) {
+				if nerr.Err != nil {
+					logger.GetReqInfo(ctx).SetTags("peerAddress", nerr.Host.String())
+					logger.LogIf(ctx, nerr.Err)
+				}
+			}
+		}
+
+		if !removed {
+			info.Groups[user] = groups
+		}
+	}
+	info.Status.Duration = UTCNow().Sub(start)
+
+	s.mu.Lock()
+	s.info = info
+	s.mu.Unlock()
+
+	if err := sys.store.saveIAMConfig(ctx, &info, iamConfigLDAPGroupSyncFile); err != nil {
+		logger.LogIf(ctx, err)
+	}
+}
+
+// run - syncs the LDAP groups periodically until ctx is done, only one
+// server of the cluster syncs the groups.
+func (s *ldapGroupSync) run(ctx context.Context, objAPI ObjectLayer, sys *IAMSys) {
+	ticker := time.NewTicker(globalLDAPConfig.GroupSyncInterval)
+	defer ticker.Stop()
+
+	locker := objAPI.NewNSLock(minioMetaBucket, "ldap-group-sync.lock")
+	var leader bool
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if !leader {
+			// No unlock for "leader" lock.
+			leader = locker.GetLock(ctx, ldapGroupSyncLeaderLockTimeout) == nil
+		}
+
+		if leader {
+			s.sync(ctx, sys)
+		} else if err := s.load(ctx, sys.store); err != nil && err != errConfigNotFound {
+			logger.LogIf(ctx, err)
+		}
+	}
+}
+
+// ldapSessionUsers - returns the LDAP users with temporary credentials.
+func (sys *IAMSys) ldapSessionUsers() []string {
+	if !sys.Initialized() {
+		return nil
+	}
+
+	sys.store.rlock()
+	defer sys.store.runlock()
+
+	users := set.NewStringSet()
+	for _, cred := range sys.iamUsersMap {
+		if !cred.IsTemp() || cred.ParentUser == "" || cred.ParentUser == globalActiveCred.AccessKey {
+			continue
+		}
+		// Skip temporary credentials of service accounts.
+		if _, ok := sys.iamUsersMap[cred.ParentUser]; ok {
+			continue
+		}
+		users.Add(cred.ParentUser)
+	}
+	return users.ToSlice()
+}
+
+// syncLDAPUser - sets the groups of the temporary credentials of an LDAP
+// user, credentials are revoked when the user was removed or disabled,
+// or when neither the user nor any of its groups has a policy mapped.
+// Returns the access keys of the updated and the revoked credentials.
+func (sys *IAMSys) syncLDAPUser(user string, groups []string, removed bool) (updated, revoked []string, err error) {
+	if !sys.Initialized() {
+		return nil, nil, errServerNotInitialized
+	}
+
+	sys.store.lock()
+	defer sys.store.unlock()
+
+	// With OPA policies are not mapped to users and groups.
+	mapped := !removed && globalPolicyOPA != nil
+	if !removed && !mapped {
+		_, mapped = sys.iamUserPolicyMap[user]
+		for _, group := range groups {
+			if mapped {
+				break
+			}
+			_, mapped = sys.iamGroupPolicyMap[group]
+		}
+	}
+
+	ctx := context.Background()
+	for accessKey, cred := range sys.iamUsersMap {
+		if !cred.IsTemp() || cred.ParentUser != user {
+			continue
+		}
+
+		if !mapped {
+			if err = sys.deleteTempUser(ctx, accessKey); err != nil {
+				return updated, revoked, err
+			}
+			revoked = append(revoked, accessKey)
+			continue
+		}
+
+		if set.CreateStringSet(cred.Groups...).Equals(set.CreateStringSet(groups...)) {
+			continue
+		}
+
+		cred.Groups = groups
+		ttl := int64(cred.Expiration.Sub(UTCNow()).Seconds())
+		if err = sys.store.saveUserIdentity(ctx, accessKey, stsUser, newUserIdentity(cred), options{ttl: ttl}); err != nil {
+			return updated, revoked, err
+		}
+		sys.iamUsersMap[accessKey] = cred
+		updated = append(updated, accessKey)
+	}
+	return updated, revoked, nil
+}
@@ -0,0 +1,108 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/auth"
+	iampolicy "github.com/minio/minio/pkg/iam/policy"
+)
+
+func TestSyncLDAPUser(t *testing.T) {
+	sys := newTestIAMSys(t)
+	var err error
+
+	if err = sys.SetPolicy("readwrite", iampolicy.ReadWrite); err != nil {
+		t.Fatal(err)
+	}
+	sys.iamGroupPolicyMap["eng"] = newMappedPolicy("readwrite")
+
+	newTempUser := func(user string, groups ...string) string {
+		cred, err := auth.GetNewCredentialsWithMetadata(map[string]interface{}{
+			expClaim: UTCNow().Add(time.Hour).Unix(),
+			ldapUser: user,
+		}, globalActiveCred.SecretKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cred.ParentUser = user
+		cred.Groups = groups
+		if err = sys.SetTempUser(cred.AccessKey, cred, ""); err != nil {
+			t.Fatal(err)
+		}
+		return cred.AccessKey
+	}
+	alice := newTempUser("alice", "dev", "eng")
+	bob := newTempUser("bob", "eng")
+
+	users := sys.ldapSessionUsers()
+	sort.Strings(users)
+	if expected := []string{"alice", "bob"}; !reflect.DeepEqual(users, expected) {
+		t.Fatalf("expected LDAP users %v, got %v", expected, users)
+	}
+
+	// Unchanged groups leave the credentials as they are.
+	updated, revoked, err := sys.syncLDAPUser("alice", []string{"eng", "dev"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updated) != 0 || len(revoked) != 0 {
+		t.Fatalf("expected no change, got updated %v revoked %v", updated, revoked)
+	}
+
+	updated, _, err = sys.syncLDAPUser("alice", []string{"eng", "ops"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(updated, []string{alice}) {
+		t.Fatalf("expected %s to be updated, got %v", alice, updated)
+	}
+	if cred, _ := sys.GetUser(alice); !reflect.DeepEqual(cred.Groups, []string{"eng", "ops"}) {
+		t.Fatalf("expected groups [eng ops], got %v", cred.Groups)
+	}
+
+	// Removed from all mapped groups.
+	_, revoked, err = sys.syncLDAPUser("alice", []string{"ops"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(revoked, []string{alice}) {
+		t.Fatalf("expected %s to be revoked, got %v", alice, revoked)
+	}
+	if _, ok := sys.GetUser(alice); ok {
+		t.Fatal("expected revoked credentials to be removed")
+	}
+
+	// Removed or disabled in the directory.
+	_, revoked, err = sys.syncLDAPUser("bob", nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(revoked, []string{bob}) {
+		t.Fatalf("expected %s to be revoked, got %v", bob, revoked)
+	}
+	if err = sys.LoadUser(sys.store.(*IAMObjectStore).objAPI, bob, stsUser); err != nil {
+		t.Fatalf("expected reload of revoked credentials to succeed, got %v", err)
+	}
+	if len(sys.ldapSessionUsers()) != 0 {
+		t.Fatal("expected no LDAP users with temporary credentials")
+	}
+}
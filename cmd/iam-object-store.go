@@ -35,6 +35,7 @@ import (
 	"github.com/minio/minio/cmd/logger"
 	"github.com/minio/minio/pkg/auth"
 	iampolicy "github.com/minio/minio/pkg/iam/policy"
+	"github.com/minio/minio/pkg/lock"
 	"github.com/minio/minio/pkg/madmin"
 )
 
@@ -44,6 +45,13 @@ type IAMObjectStore struct {
 	sync.RWMutex
 
 	objAPI ObjectLayer
+
+	// Set when IAM configuration is persisted directly on the
+	// filesystem of an FS gateway, see newIAMFSStore.
+	fsPath string
+	// Lock of the configuration files on that filesystem, held
+	// along with the write lock.
+	fsLock *lock.LockedFile
 }
 
 func newIAMObjectStore(objAPI ObjectLayer) *IAMObjectStore {
@@ -52,9 +60,16 @@ func newIAMObjectStore(objAPI ObjectLayer) *IAMObjectStore {
 
 func (iamOS *IAMObjectStore) lock() {
 	iamOS.Lock()
+	if iamOS.fsPath != "" {
+		iamOS.lockFS()
+	}
 }
 
 func (iamOS *IAMObjectStore) unlock() {
+	if iamOS.fsLock != nil {
+		iamOS.fsLock.Close()
+		iamOS.fsLock = nil
+	}
 	iamOS.Unlock()
 }
 
@@ -218,11 +233,20 @@ func (iamOS *IAMObjectStore) saveIAMConfig(ctx context.Context, item interface{}
 			return err
 		}
 	}
+	if iamOS.fsPath != "" {
+		return iamOS.saveConfigFile(ctx, path, data)
+	}
 	return saveConfig(ctx, iamOS.objAPI, path, data)
 }
 
 func (iamOS *IAMObjectStore) loadIAMConfig(ctx context.Context, item interface{}, path string) error {
-	data, err := readConfig(ctx, iamOS.objAPI, path)
+	var data []byte
+	var err error
+	if iamOS.fsPath != "" {
+		data, err = iamOS.readConfigFile(path)
+	} else {
+		data, err = readConfig(ctx, iamOS.objAPI, path)
+	}
 	if err != nil {
 		return err
 	}
@@ -236,6 +260,9 @@ func (iamOS *IAMObjectStore) loadIAMConfig(ctx context.Context, item interface{}
 }
 
 func (iamOS *IAMObjectStore) deleteIAMConfig(ctx context.Context, path string) error {
+	if iamOS.fsPath != "" {
+		return iamOS.deleteConfigFile(path)
+	}
 	return deleteConfig(ctx, iamOS.objAPI, path)
 }
 
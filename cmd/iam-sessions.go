@@ -0,0 +1,153 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	xjwt "github.com/minio/minio/cmd/jwt"
+	"github.com/minio/minio/pkg/auth"
+	iampolicy "github.com/minio/minio/pkg/iam/policy"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// sessionClaims - returns the claims of the session token of temporary
+// credentials, nil if the token cannot be parsed.
+func sessionClaims(token string) map[string]interface{} {
+	claims := xjwt.NewMapClaims()
+	stsTokenCallback := func(claims *xjwt.MapClaims) ([]byte, error) {
+		return []byte(globalActiveCred.SecretKey), nil
+	}
+	if err := xjwt.ParseWithClaims(token, claims, stsTokenCallback); err != nil {
+		return nil
+	}
+	return claims.Map()
+}
+
+// sessionParent - returns the identity temporary credentials were issued
+// to, the parent user or the subject of the token of the OpenID provider.
+func sessionParent(cred auth.Credentials, claims map[string]interface{}) string {
+	if cred.ParentUser != "" {
+		return cred.ParentUser
+	}
+	sub, _ := claims[subClaim].(string)
+	return sub
+}
+
+// ListSTSSessions - lists the active sessions of temporary credentials,
+// only those of the given parent identity when it is not empty.
+func (sys *IAMSys) ListSTSSessions(parent string) ([]madmin.STSSession, error) {
+	if !sys.Initialized() {
+		return nil, errServerNotInitialized
+	}
+
+	sys.store.rlock()
+	defer sys.store.runlock()
+
+	sessions := []madmin.STSSession{}
+	for accessKey, cred := range sys.iamUsersMap {
+		if !cred.IsTemp() || cred.IsExpired() {
+			continue
+		}
+
+		claims := sessionClaims(cred.SessionToken)
+		session := madmin.STSSession{
+			AccessKey:  accessKey,
+			Parent:     sessionParent(cred, claims),
+			Source:     cred.SessionSource,
+			IssuedAt:   cred.IssuedAt,
+			Expiration: cred.Expiration,
+			Groups:     cred.Groups,
+		}
+		if parent != "" && session.Parent != parent {
+			continue
+		}
+		_, session.SessionPolicy = claims[iampolicy.SessionPolicyName]
+
+		policies := sys.iamUserPolicyMap[accessKey].toSlice()
+		if _, ok := claims[ldapUser]; ok {
+			// LDAP policies are those of the user and its groups.
+			policies = sys.iamUserPolicyMap[session.Parent].toSlice()
+			for _, group := range cred.Groups {
+				policies = append(policies, sys.iamGroupPolicyMap[group].toSlice()...)
+			}
+		}
+		session.Policy = strings.Join(policies, ",")
+
+		sessions = append(sessions, session)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		if !sessions[i].IssuedAt.Equal(sessions[j].IssuedAt) {
+			return sessions[i].IssuedAt.Before(sessions[j].IssuedAt)
+		}
+		return sessions[i].AccessKey < sessions[j].AccessKey
+	})
+	return sessions, nil
+}
+
+// RevokeSTSSessions - revokes the temporary credentials with the given
+// access key, or all those of the given parent identity. Returns the
+// access keys of the revoked credentials.
+func (sys *IAMSys) RevokeSTSSessions(accessKey, parent string) ([]string, error) {
+	if !sys.Initialized() {
+		return nil, errServerNotInitialized
+	}
+
+	sys.store.lock()
+	defer sys.store.unlock()
+
+	if accessKey != "" {
+		cred, ok := sys.iamUsersMap[accessKey]
+		if !ok || !cred.IsTemp() {
+			return nil, errNoSuchUser
+		}
+		if err := sys.deleteTempUser(context.Background(), accessKey); err != nil {
+			return nil, err
+		}
+		return []string{accessKey}, nil
+	}
+
+	revoked := []string{}
+	for accessKey, cred := range sys.iamUsersMap {
+		if !cred.IsTemp() || sessionParent(cred, sessionClaims(cred.SessionToken)) != parent {
+			continue
+		}
+		if err := sys.deleteTempUser(context.Background(), accessKey); err != nil {
+			return revoked, err
+		}
+		revoked = append(revoked, accessKey)
+	}
+	return revoked, nil
+}
+
+// deleteTempUser - removes temporary credentials from the IAM store and
+// from memory, peers remove them when reloading them. Assumes that
+// sys.store.lock is held.
+func (sys *IAMSys) deleteTempUser(ctx context.Context, accessKey string) error {
+	if err := sys.store.deleteUserIdentity(ctx, accessKey, stsUser); err != nil && err != errNoSuchUser {
+		return err
+	}
+	// It is ok to ignore deletion error on the mapped policy
+	sys.store.deleteMappedPolicy(ctx, accessKey, stsUser, false)
+
+	delete(sys.iamUsersMap, accessKey)
+	delete(sys.iamUserPolicyMap, accessKey)
+	return nil
+}
@@ -0,0 +1,124 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/auth"
+	iampolicy "github.com/minio/minio/pkg/iam/policy"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func TestSTSSessions(t *testing.T) {
+	sys := newTestIAMSys(t)
+	var err error
+
+	if err = sys.SetPolicy("readwrite", iampolicy.ReadWrite); err != nil {
+		t.Fatal(err)
+	}
+	if err = sys.SetUser("alice", madmin.UserInfo{SecretKey: "alice12345", PolicyName: "readwrite", Status: madmin.AccountEnabled}); err != nil {
+		t.Fatal(err)
+	}
+
+	issuedAt := UTCNow().Add(-time.Minute)
+	newTempUser := func(parent, sub, source string, sessionPolicy bool) string {
+		m := map[string]interface{}{
+			expClaim:                   UTCNow().Add(time.Hour).Unix(),
+			iamPolicyClaimNameOpenID(): "readwrite",
+		}
+		if sub != "" {
+			m[subClaim] = sub
+		}
+		if sessionPolicy {
+			m[iampolicy.SessionPolicyName] = base64.StdEncoding.EncodeToString([]byte(`{"Version":"2012-10-17"}`))
+		}
+		cred, err := auth.GetNewCredentialsWithMetadata(m, globalActiveCred.SecretKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cred.ParentUser = parent
+		cred.SessionSource = source
+		cred.IssuedAt = issuedAt
+		issuedAt = issuedAt.Add(time.Second)
+		if err = sys.SetTempUser(cred.AccessKey, cred, "readwrite"); err != nil {
+			t.Fatal(err)
+		}
+		return cred.AccessKey
+	}
+	first := newTempUser("alice", "", assumeRole, true)
+	second := newTempUser("alice", "", assumeRole, false)
+	web := newTempUser("", "bob@example.com", webIdentity, false)
+
+	sessions, err := sys.ListSTSSessions("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []madmin.STSSession{
+		{AccessKey: first, Parent: "alice", Source: assumeRole, Policy: "readwrite", SessionPolicy: true},
+		{AccessKey: second, Parent: "alice", Source: assumeRole, Policy: "readwrite"},
+		{AccessKey: web, Parent: "bob@example.com", Source: webIdentity, Policy: "readwrite"},
+	}
+	if len(sessions) != len(expected) {
+		t.Fatalf("expected %d sessions, got %d", len(expected), len(sessions))
+	}
+	for i := range sessions {
+		if sessions[i].IssuedAt.IsZero() || sessions[i].Expiration.IsZero() {
+			t.Fatalf("expected session %d to have issue and expiration times", i)
+		}
+		sessions[i].IssuedAt, sessions[i].Expiration = time.Time{}, time.Time{}
+		if !reflect.DeepEqual(sessions[i], expected[i]) {
+			t.Fatalf("expected session %+v, got %+v", expected[i], sessions[i])
+		}
+	}
+
+	if sessions, _ = sys.ListSTSSessions("bob@example.com"); len(sessions) != 1 || sessions[0].AccessKey != web {
+		t.Fatalf("expected only the session of bob@example.com, got %+v", sessions)
+	}
+
+	if _, err = sys.RevokeSTSSessions("alice", ""); err != errNoSuchUser {
+		t.Fatalf("expected revoking a user to fail with %v, got %v", errNoSuchUser, err)
+	}
+
+	revoked, err := sys.RevokeSTSSessions(web, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(revoked, []string{web}) {
+		t.Fatalf("expected %s to be revoked, got %v", web, revoked)
+	}
+	if _, ok := sys.GetUser(web); ok {
+		t.Fatal("expected revoked session to be removed")
+	}
+
+	revoked, err = sys.RevokeSTSSessions("", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revoked) != 2 {
+		t.Fatalf("expected 2 sessions of alice to be revoked, got %v", revoked)
+	}
+	if sessions, _ = sys.ListSTSSessions(""); len(sessions) != 0 {
+		t.Fatalf("expected no sessions, got %+v", sessions)
+	}
+	if _, ok := sys.GetUser("alice"); !ok {
+		t.Fatal("expected the parent user to remain")
+	}
+}
@@ -17,7 +17,6 @@
 package cmd
 
 import (
-	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/json"
@@ -30,9 +29,11 @@ import (
 
 	humanize "github.com/dustin/go-humanize"
 	"github.com/minio/minio-go/v7/pkg/set"
+	"github.com/minio/minio-go/v7/pkg/tags"
 	"github.com/minio/minio/cmd/config"
 	"github.com/minio/minio/cmd/logger"
 	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/bucket/policy/condition"
 	iampolicy "github.com/minio/minio/pkg/iam/policy"
 	"github.com/minio/minio/pkg/madmin"
 )
@@ -89,6 +90,12 @@ const (
 	// IAM format file
 	iamFormatFile = "format.json"
 
+	// IAM quotas file of users and groups
+	iamQuotasFile = "quotas.json"
+
+	// IAM rate limits file of users, groups and buckets
+	iamRateLimitsFile = "ratelimits.json"
+
 	iamFormatVersion1 = 1
 )
 
@@ -109,6 +116,14 @@ func getIAMFormatFilePath() string {
 	return iamConfigPrefix + SlashSeparator + iamFormatFile
 }
 
+func getIAMQuotasPath() string {
+	return iamConfigPrefix + SlashSeparator + iamQuotasFile
+}
+
+func getIAMRateLimitsPath() string {
+	return iamConfigPrefix + SlashSeparator + iamRateLimitsFile
+}
+
 func getUserIdentityPath(user string, userType IAMUserType) string {
 	var basePath string
 	switch userType {
@@ -159,6 +174,9 @@ type GroupInfo struct {
 	Version int      `json:"version"`
 	Status  string   `json:"status"`
 	Members []string `json:"members"`
+
+	// PermissionBoundary caps the permissions of the members.
+	PermissionBoundary string `json:"permissionBoundary,omitempty"`
 }
 
 func newGroupInfo(members []string) GroupInfo {
@@ -379,6 +397,12 @@ func (sys *IAMSys) LoadUser(objAPI ObjectLayer, accessKey string, userType IAMUs
 
 	if globalEtcdClient == nil {
 		err := sys.store.loadUser(context.Background(), accessKey, userType, sys.iamUsersMap)
+		if err == errNoSuchUser && userType == stsUser {
+			// Temporary credentials were revoked.
+			delete(sys.iamUsersMap, accessKey)
+			delete(sys.iamUserPolicyMap, accessKey)
+			return nil
+		}
 		if err != nil {
 			return err
 		}
@@ -432,6 +456,25 @@ func (sys *IAMSys) InitStore(objAPI ObjectLayer) {
 	}
 }
 
+// InitFSStore - initializes the IAM store of a gateway storing objects
+// on the filesystem at fsPath, without etcd the IAM configuration is
+// persisted on that filesystem.
+func (sys *IAMSys) InitFSStore(objAPI ObjectLayer, fsPath string) {
+	if globalEtcdClient != nil {
+		sys.InitStore(objAPI)
+		return
+	}
+
+	sys.Lock()
+	defer sys.Unlock()
+
+	sys.store = newIAMFSStore(objAPI, fsPath)
+
+	if globalLDAPConfig.Enabled {
+		sys.EnableLDAPSys()
+	}
+}
+
 // Initialized check if IAM is initialized
 func (sys *IAMSys) Initialized() bool {
 	if sys == nil {
@@ -536,6 +579,12 @@ func (sys *IAMSys) Init(ctx context.Context, objAPI ObjectLayer) {
 	// Invalidate the old cred always, even upon error to avoid any leakage.
 	globalOldCred = auth.Credentials{}
 	go sys.store.watch(ctx, sys)
+	go globalAccessKeyUsage.run(ctx, sys.store)
+	logger.LogIf(ctx, globalRateLimitSys.load(ctx, sys))
+	go globalRateLimitSys.run(ctx, sys)
+	if globalLDAPConfig.Enabled && globalLDAPConfig.GroupSyncInterval > 0 {
+		go globalLDAPGroupSync.run(ctx, objAPI, sys)
+	}
 }
 
 // DeletePolicy - deletes a canned policy from backend or etcd.
@@ -683,6 +732,7 @@ func (sys *IAMSys) DeleteUser(accessKey string) error {
 		if u.IsServiceAccount() {
 			if u.ParentUser == accessKey {
 				_ = sys.store.deleteUserIdentity(context.Background(), u.AccessKey, srvAccUser)
+				globalAccessKeyUsage.forget(context.Background(), sys.store, u.AccessKey)
 				delete(sys.iamUsersMap, u.AccessKey)
 			}
 		}
@@ -696,6 +746,7 @@ func (sys *IAMSys) DeleteUser(accessKey string) error {
 		err = nil
 	}
 
+	globalAccessKeyUsage.forget(context.Background(), sys.store, accessKey)
 	delete(sys.iamUsersMap, accessKey)
 	delete(sys.iamUserPolicyMap, accessKey)
 
@@ -724,6 +775,32 @@ func (sys *IAMSys) CurrentPolicies(policyName string) string {
 	return strings.Join(policies, ",")
 }
 
+// GroupsPolicies - returns the policies mapped to the groups, groups
+// which are missing or disabled are skipped. Used for the groups claimed
+// by the tokens of OpenID providers.
+func (sys *IAMSys) GroupsPolicies(groups []string) []string {
+	if !sys.Initialized() {
+		return nil
+	}
+
+	sys.store.rlock()
+	defer sys.store.runlock()
+
+	var policies []string
+	for _, group := range groups {
+		if sys.usersSysType == MinIOUsersSysType {
+			gi, ok := sys.iamGroupsMap[group]
+			if !ok || gi.Status == statusDisabled {
+				continue
+			}
+		}
+
+		mp := sys.iamGroupPolicyMap[group]
+		policies = append(policies, mp.toSlice()...)
+	}
+	return policies
+}
+
 // SetTempUser - set temporary user credentials, these credentials have an expiry.
 func (sys *IAMSys) SetTempUser(accessKey string, cred auth.Credentials, policyName string) error {
 	if !sys.Initialized() {
@@ -802,6 +879,7 @@ func (sys *IAMSys) ListUsers() (map[string]madmin.UserInfo, error) {
 
 	for k, v := range sys.iamUsersMap {
 		if !v.IsTemp() && !v.IsServiceAccount() {
+			expiration, previousSecretExpiration := keyExpirations(v)
 			users[k] = madmin.UserInfo{
 				PolicyName: sys.iamUserPolicyMap[k].Policies,
 				Status: func() madmin.AccountStatus {
@@ -810,6 +888,8 @@ func (sys *IAMSys) ListUsers() (map[string]madmin.UserInfo, error) {
 					}
 					return madmin.AccountDisabled
 				}(),
+				Expiration:               expiration,
+				PreviousSecretExpiration: previousSecretExpiration,
 			}
 		}
 	}
@@ -855,6 +935,28 @@ func (sys *IAMSys) IsServiceAccount(name string) (bool, string, error) {
 	return false, "", nil
 }
 
+// GetUserGroups - get the groups a user is a member of, unlike
+// GetUserInfo it only reads the memberships held in memory.
+func (sys *IAMSys) GetUserGroups(name string) []string {
+	if !sys.Initialized() {
+		return nil
+	}
+
+	sys.store.rlock()
+	defer sys.store.runlock()
+
+	return sys.iamUserGroupMemberships[name].ToSlice()
+}
+
+// AccessKeyLastUsed - get the last use of an access key by any server,
+// nil if it was never used.
+func (sys *IAMSys) AccessKeyLastUsed(ctx context.Context, accessKey string) *madmin.AccessKeyUsage {
+	if !sys.Initialized() {
+		return nil
+	}
+	return globalAccessKeyUsage.lastUsed(ctx, sys.store, accessKey)
+}
+
 // GetUserInfo - get info on a user.
 func (sys *IAMSys) GetUserInfo(name string) (u madmin.UserInfo, err error) {
 	if !sys.Initialized() {
@@ -887,6 +989,7 @@ func (sys *IAMSys) GetUserInfo(name string) (u madmin.UserInfo, err error) {
 		return u, errIAMActionNotAllowed
 	}
 
+	expiration, previousSecretExpiration := keyExpirations(cred)
 	u = madmin.UserInfo{
 		PolicyName: sys.iamUserPolicyMap[name].Policies,
 		Status: func() madmin.AccountStatus {
@@ -895,7 +998,11 @@ func (sys *IAMSys) GetUserInfo(name string) (u madmin.UserInfo, err error) {
 			}
 			return madmin.AccountDisabled
 		}(),
-		MemberOf: sys.iamUserGroupMemberships[name].ToSlice(),
+		MemberOf:                 sys.iamUserGroupMemberships[name].ToSlice(),
+		Expiration:               expiration,
+		PreviousSecretExpiration: previousSecretExpiration,
+		Tags:                     cred.Tags,
+		PermissionBoundary:       cred.PermissionBoundary,
 	}
 	return u, nil
 }
@@ -913,6 +1020,10 @@ func (sys *IAMSys) SetUserStatus(accessKey string, status madmin.AccountStatus)
 	sys.store.lock()
 	defer sys.store.unlock()
 
+	if err := sys.reloadUserForUpdate(context.Background(), accessKey); err != nil {
+		return err
+	}
+
 	if sys.usersSysType != MinIOUsersSysType {
 		return errIAMActionNotAllowed
 	}
@@ -935,6 +1046,11 @@ func (sys *IAMSys) SetUserStatus(accessKey string, status madmin.AccountStatus)
 			}
 			return config.EnableOff
 		}(),
+		KeyExpiration:               cred.KeyExpiration,
+		PreviousSecretKey:           cred.PreviousSecretKey,
+		PreviousSecretKeyExpiration: cred.PreviousSecretKeyExpiration,
+		Tags:                        cred.Tags,
+		PermissionBoundary:          cred.PermissionBoundary,
 	})
 
 	if err := sys.store.saveUserIdentity(context.Background(), accessKey, regularUser, uinfo); err != nil {
@@ -945,8 +1061,163 @@ func (sys *IAMSys) SetUserStatus(accessKey string, status madmin.AccountStatus)
 	return nil
 }
 
-// NewServiceAccount - create a new service account
-func (sys *IAMSys) NewServiceAccount(ctx context.Context, parentUser string, sessionPolicy *iampolicy.Policy) (auth.Credentials, error) {
+// IAMQuotas - quotas of users and groups, the objects written by the
+// members of a group count against the group quota.
+type IAMQuotas struct {
+	Version int                         `json:"version"`
+	Users   map[string]madmin.UserQuota `json:"users,omitempty"`
+	Groups  map[string]madmin.UserQuota `json:"groups,omitempty"`
+}
+
+// GetQuotas - returns the quotas of all users and groups.
+func (sys *IAMSys) GetQuotas(ctx context.Context) (IAMQuotas, error) {
+	q := IAMQuotas{Version: 1}
+	if !sys.Initialized() {
+		return q, errServerNotInitialized
+	}
+
+	if err := sys.store.loadIAMConfig(ctx, &q, getIAMQuotasPath()); err != nil && err != errConfigNotFound {
+		return q, err
+	}
+	return q, nil
+}
+
+// SetQuota - sets the quota of a user or group, the quota is removed
+// when it has no limit.
+func (sys *IAMSys) SetQuota(ctx context.Context, name string, isGroup bool, quota madmin.UserQuota) error {
+	if !sys.Initialized() {
+		return errServerNotInitialized
+	}
+
+	if !quota.IsValid() {
+		return errInvalidArgument
+	}
+
+	sys.store.lock()
+	defer sys.store.unlock()
+
+	if sys.usersSysType == MinIOUsersSysType {
+		if isGroup {
+			if _, ok := sys.iamGroupsMap[name]; !ok {
+				return errNoSuchGroup
+			}
+		} else {
+			cred, ok := sys.iamUsersMap[name]
+			if !ok {
+				return errNoSuchUser
+			}
+			// Objects written by temporary users and service
+			// accounts count against the quota of their parent.
+			if cred.IsTemp() || cred.IsServiceAccount() {
+				return errIAMActionNotAllowed
+			}
+		}
+	}
+
+	q := IAMQuotas{Version: 1}
+	if err := sys.store.loadIAMConfig(ctx, &q, getIAMQuotasPath()); err != nil && err != errConfigNotFound {
+		return err
+	}
+	quotas := q.Users
+	if isGroup {
+		quotas = q.Groups
+	}
+	if quotas == nil {
+		quotas = make(map[string]madmin.UserQuota)
+	}
+	if quota.Quota == 0 && quota.Objects == 0 {
+		delete(quotas, name)
+	} else {
+		quotas[name] = quota
+	}
+	if isGroup {
+		q.Groups = quotas
+	} else {
+		q.Users = quotas
+	}
+	return sys.store.saveIAMConfig(ctx, &q, getIAMQuotasPath())
+}
+
+// IAMRateLimits - rate limits of users, groups and buckets.
+type IAMRateLimits struct {
+	Version int `json:"version"`
+	madmin.RateLimits
+}
+
+// GetRateLimits - returns the rate limits of all users, groups and
+// buckets.
+func (sys *IAMSys) GetRateLimits(ctx context.Context) (IAMRateLimits, error) {
+	l := IAMRateLimits{Version: 1}
+	if !sys.Initialized() {
+		return l, errServerNotInitialized
+	}
+
+	if err := sys.store.loadIAMConfig(ctx, &l, getIAMRateLimitsPath()); err != nil && err != errConfigNotFound {
+		return l, err
+	}
+	return l, nil
+}
+
+// SetRateLimit - sets the rate limit of a user, group or bucket, the rate
+// limit is removed when it is empty. Buckets are validated by the caller.
+func (sys *IAMSys) SetRateLimit(ctx context.Context, limitType madmin.RateLimitType, name string, limit madmin.RateLimit) error {
+	if !sys.Initialized() {
+		return errServerNotInitialized
+	}
+
+	if name == "" || !limitType.IsValid() || !limit.IsValid() {
+		return errInvalidArgument
+	}
+
+	sys.store.lock()
+	defer sys.store.unlock()
+
+	if sys.usersSysType == MinIOUsersSysType {
+		switch limitType {
+		case madmin.GroupRateLimit:
+			if _, ok := sys.iamGroupsMap[name]; !ok {
+				return errNoSuchGroup
+			}
+		case madmin.UserRateLimit:
+			cred, ok := sys.iamUsersMap[name]
+			if !ok {
+				return errNoSuchUser
+			}
+			// Requests of temporary users and service accounts
+			// are limited by the rate limit of their parent.
+			if cred.IsTemp() || cred.IsServiceAccount() {
+				return errIAMActionNotAllowed
+			}
+		}
+	}
+
+	l := IAMRateLimits{Version: 1}
+	if err := sys.store.loadIAMConfig(ctx, &l, getIAMRateLimitsPath()); err != nil && err != errConfigNotFound {
+		return err
+	}
+	var limits *map[string]madmin.RateLimit
+	switch limitType {
+	case madmin.UserRateLimit:
+		limits = &l.Users
+	case madmin.GroupRateLimit:
+		limits = &l.Groups
+	case madmin.BucketRateLimit:
+		limits = &l.Buckets
+	}
+	if *limits == nil {
+		*limits = make(map[string]madmin.RateLimit)
+	}
+	if limit.IsEmpty() {
+		delete(*limits, name)
+	} else {
+		(*limits)[name] = limit
+	}
+	return sys.store.saveIAMConfig(ctx, &l, getIAMRateLimitsPath())
+}
+
+// NewServiceAccount - create a new service account, the access key of the
+// service account is disabled after expiration unless it is zero.
+func (sys *IAMSys) NewServiceAccount(ctx context.Context, parentUser string, sessionPolicy *iampolicy.Policy, expiration time.Time) (auth.Credentials, error) {
 	if !sys.Initialized() {
 		return auth.Credentials{}, errServerNotInitialized
 	}
@@ -983,6 +1254,10 @@ func (sys *IAMSys) NewServiceAccount(ctx context.Context, parentUser string, ses
 		return auth.Credentials{}, errIAMActionNotAllowed
 	}
 
+	if err := sys.checkSessionPolicy(parentUser, sessionPolicy); err != nil {
+		return auth.Credentials{}, err
+	}
+
 	m := make(map[string]interface{})
 	m[parentClaim] = parentUser
 
@@ -999,6 +1274,7 @@ func (sys *IAMSys) NewServiceAccount(ctx context.Context, parentUser string, ses
 		return auth.Credentials{}, err
 	}
 	cred.ParentUser = parentUser
+	cred.KeyExpiration = expiration
 
 	u := newUserIdentity(cred)
 
@@ -1012,7 +1288,7 @@ func (sys *IAMSys) NewServiceAccount(ctx context.Context, parentUser string, ses
 }
 
 // ListServiceAccounts - lists all services accounts associated to a specific user
-func (sys *IAMSys) ListServiceAccounts(ctx context.Context, accessKey string) ([]string, error) {
+func (sys *IAMSys) ListServiceAccounts(ctx context.Context, accessKey string) ([]madmin.ServiceAccountInfo, error) {
 	if !sys.Initialized() {
 		return nil, errServerNotInitialized
 	}
@@ -1030,10 +1306,15 @@ func (sys *IAMSys) ListServiceAccounts(ctx context.Context, accessKey string) ([
 	sys.store.rlock()
 	defer sys.store.runlock()
 
-	var serviceAccounts []string
+	var serviceAccounts []madmin.ServiceAccountInfo
 	for k, v := range sys.iamUsersMap {
 		if v.IsServiceAccount() && v.ParentUser == accessKey {
-			serviceAccounts = append(serviceAccounts, k)
+			expiration, previousSecretExpiration := keyExpirations(v)
+			serviceAccounts = append(serviceAccounts, madmin.ServiceAccountInfo{
+				AccessKey:                k,
+				Expiration:               expiration,
+				PreviousSecretExpiration: previousSecretExpiration,
+			})
 		}
 	}
 
@@ -1080,6 +1361,7 @@ func (sys *IAMSys) DeleteServiceAccount(ctx context.Context, accessKey string) e
 		return err
 	}
 
+	globalAccessKeyUsage.forget(ctx, sys.store, accessKey)
 	delete(sys.iamUsersMap, accessKey)
 	return nil
 }
@@ -1095,10 +1377,17 @@ func (sys *IAMSys) SetUser(accessKey string, uinfo madmin.UserInfo) error {
 		SecretKey: uinfo.SecretKey,
 		Status:    string(uinfo.Status),
 	})
+	if uinfo.Expiration != nil {
+		u.Credentials.KeyExpiration = uinfo.Expiration.UTC()
+	}
 
 	sys.store.lock()
 	defer sys.store.unlock()
 
+	if err := sys.reloadUserForUpdate(context.Background(), accessKey); err != nil {
+		return err
+	}
+
 	if sys.usersSysType != MinIOUsersSysType {
 		return errIAMActionNotAllowed
 	}
@@ -1107,6 +1396,9 @@ func (sys *IAMSys) SetUser(accessKey string, uinfo madmin.UserInfo) error {
 	if cr.IsTemp() && ok {
 		return errIAMActionNotAllowed
 	}
+	// Keep the tags and the permission boundary of an existing user.
+	u.Credentials.Tags = cr.Tags
+	u.Credentials.PermissionBoundary = cr.PermissionBoundary
 
 	if err := sys.store.saveUserIdentity(context.Background(), accessKey, regularUser, u); err != nil {
 		return err
@@ -1134,12 +1426,20 @@ func (sys *IAMSys) SetUserSecretKey(accessKey string, secretKey string) error {
 	sys.store.lock()
 	defer sys.store.unlock()
 
+	if err := sys.reloadUserForUpdate(context.Background(), accessKey); err != nil {
+		return err
+	}
+
 	cred, ok := sys.iamUsersMap[accessKey]
 	if !ok {
 		return errNoSuchUser
 	}
 
 	cred.SecretKey = secretKey
+	// The secret key is replaced instantly, forget any previous
+	// secret key of an ongoing rotation.
+	cred.PreviousSecretKey = ""
+	cred.PreviousSecretKeyExpiration = time.Time{}
 	u := newUserIdentity(cred)
 	if err := sys.store.saveUserIdentity(context.Background(), accessKey, regularUser, u); err != nil {
 		return err
@@ -1149,6 +1449,205 @@ func (sys *IAMSys) SetUserSecretKey(accessKey string, secretKey string) error {
 	return nil
 }
 
+// SetUserTags - sets the tags of a user, empty tags remove them.
+func (sys *IAMSys) SetUserTags(accessKey string, tagMap map[string]string) error {
+	if !sys.Initialized() {
+		return errServerNotInitialized
+	}
+
+	if _, err := tags.NewTags(tagMap, false); err != nil {
+		return err
+	}
+
+	sys.store.lock()
+	defer sys.store.unlock()
+
+	if err := sys.reloadUserForUpdate(context.Background(), accessKey); err != nil {
+		return err
+	}
+
+	if sys.usersSysType != MinIOUsersSysType {
+		return errIAMActionNotAllowed
+	}
+
+	cred, ok := sys.iamUsersMap[accessKey]
+	if !ok {
+		return errNoSuchUser
+	}
+
+	if cred.IsTemp() || cred.IsServiceAccount() {
+		return errIAMActionNotAllowed
+	}
+
+	cred.Tags = nil
+	if len(tagMap) > 0 {
+		cred.Tags = tagMap
+	}
+
+	if err := sys.store.saveUserIdentity(context.Background(), accessKey, regularUser, newUserIdentity(cred)); err != nil {
+		return err
+	}
+
+	sys.iamUsersMap[accessKey] = cred
+	return nil
+}
+
+// PrincipalTags - returns the tags of the principal of a request, service
+// accounts and temporary credentials have the tags of their parent user
+// overridden by the session tags in their claims.
+func (sys *IAMSys) PrincipalTags(accessKey string, claims map[string]interface{}) map[string]string {
+	tagMap := make(map[string]string)
+	if accessKey == "" || !sys.Initialized() {
+		return tagMap
+	}
+
+	sys.store.rlock()
+	cred := sys.iamUsersMap[accessKey]
+	if cred.ParentUser != "" {
+		cred = sys.iamUsersMap[cred.ParentUser]
+	}
+	for k, v := range cred.Tags {
+		tagMap[k] = v
+	}
+	sys.store.runlock()
+
+	for k, v := range sessionTagsFromClaims(claims) {
+		tagMap[k] = v
+	}
+	return tagMap
+}
+
+// HasConditionKey - returns whether a policy which may apply to the
+// request of the credentials has a condition on the key for the action,
+// all the policies and the session policy in the claims are considered.
+func (sys *IAMSys) HasConditionKey(claims map[string]interface{}, action iampolicy.Action, key condition.Key) bool {
+	if !sys.Initialized() {
+		return false
+	}
+
+	if session, err := sessionPolicyFromClaims(claims); err == nil && session != nil {
+		if session.HasConditionKey(action, key) {
+			return true
+		}
+	}
+
+	sys.store.rlock()
+	defer sys.store.runlock()
+
+	for _, p := range sys.iamPolicyDocsMap {
+		if p.HasConditionKey(action, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// maxSecretKeyRotationOverlap is the longest time the previous secret
+// key of a rotated access key remains valid.
+const maxSecretKeyRotationOverlap = 30 * 24 * time.Hour
+
+// longTermUser - returns the credentials of a user or service account
+// along with the user type locating its identity in the IAM store.
+func (sys *IAMSys) longTermUser(accessKey string) (auth.Credentials, IAMUserType, error) {
+	cred, ok := sys.iamUsersMap[accessKey]
+	if !ok {
+		return cred, regularUser, errNoSuchUser
+	}
+	if cred.IsTemp() {
+		return cred, stsUser, errIAMActionNotAllowed
+	}
+	if cred.IsServiceAccount() {
+		return cred, srvAccUser, nil
+	}
+	if sys.usersSysType != MinIOUsersSysType {
+		return cred, regularUser, errIAMActionNotAllowed
+	}
+	return cred, regularUser, nil
+}
+
+// RotateSecretKey - replaces the secret key of a user or service account,
+// the previous secret key remains valid for the overlap duration so that
+// clients can be moved to the new secret key.
+func (sys *IAMSys) RotateSecretKey(accessKey, secretKey string, overlap time.Duration) (auth.Credentials, error) {
+	if !sys.Initialized() {
+		return auth.Credentials{}, errServerNotInitialized
+	}
+
+	if !auth.IsSecretKeyValid(secretKey) || overlap < 0 || overlap > maxSecretKeyRotationOverlap {
+		return auth.Credentials{}, errInvalidArgument
+	}
+
+	sys.store.lock()
+	defer sys.store.unlock()
+
+	if err := sys.reloadUserForUpdate(context.Background(), accessKey); err != nil {
+		return auth.Credentials{}, err
+	}
+
+	cred, userType, err := sys.longTermUser(accessKey)
+	if err != nil {
+		return auth.Credentials{}, err
+	}
+
+	cred.PreviousSecretKey = ""
+	cred.PreviousSecretKeyExpiration = time.Time{}
+	if overlap > 0 {
+		cred.PreviousSecretKey = cred.SecretKey
+		cred.PreviousSecretKeyExpiration = UTCNow().Add(overlap)
+	}
+	cred.SecretKey = secretKey
+
+	if err := sys.store.saveUserIdentity(context.Background(), accessKey, userType, newUserIdentity(cred)); err != nil {
+		return auth.Credentials{}, err
+	}
+
+	sys.iamUsersMap[accessKey] = cred
+	return cred, nil
+}
+
+// SetKeyExpiration - sets the expiration of the access key of a user or
+// service account, a zero expiration removes it.
+func (sys *IAMSys) SetKeyExpiration(accessKey string, expiration time.Time) error {
+	if !sys.Initialized() {
+		return errServerNotInitialized
+	}
+
+	sys.store.lock()
+	defer sys.store.unlock()
+
+	if err := sys.reloadUserForUpdate(context.Background(), accessKey); err != nil {
+		return err
+	}
+
+	cred, userType, err := sys.longTermUser(accessKey)
+	if err != nil {
+		return err
+	}
+
+	cred.KeyExpiration = expiration.UTC()
+
+	if err := sys.store.saveUserIdentity(context.Background(), accessKey, userType, newUserIdentity(cred)); err != nil {
+		return err
+	}
+
+	sys.iamUsersMap[accessKey] = cred
+	return nil
+}
+
+// keyExpirations returns the expiration of the access key and, during
+// a rotation, of the previous secret key of long term credentials.
+func keyExpirations(cred auth.Credentials) (expiration, previousSecretExpiration *time.Time) {
+	if !cred.KeyExpiration.IsZero() {
+		t := cred.KeyExpiration
+		expiration = &t
+	}
+	if len(cred.SecretKeys()) > 1 {
+		t := cred.PreviousSecretKeyExpiration
+		previousSecretExpiration = &t
+	}
+	return expiration, previousSecretExpiration
+}
+
 // GetUser - get user credentials
 func (sys *IAMSys) GetUser(accessKey string) (cred auth.Credentials, ok bool) {
 	if !sys.Initialized() {
@@ -1232,6 +1731,18 @@ func (sys *IAMSys) AddUsersToGroup(group string, members []string) error {
 	sys.store.lock()
 	defer sys.store.unlock()
 
+	if err := sys.reloadGroupForUpdate(context.Background(), group); err != nil {
+		return err
+	}
+	for _, member := range members {
+		if _, ok := sys.iamUsersMap[member]; !ok {
+			// Possibly added by another gateway.
+			if err := sys.reloadUserForUpdate(context.Background(), member); err != nil {
+				return err
+			}
+		}
+	}
+
 	// Validate that all members exist.
 	for _, member := range members {
 		cr, ok := sys.iamUsersMap[member]
@@ -1292,6 +1803,10 @@ func (sys *IAMSys) RemoveUsersFromGroup(group string, members []string) error {
 	sys.store.lock()
 	defer sys.store.unlock()
 
+	if err := sys.reloadGroupForUpdate(context.Background(), group); err != nil {
+		return err
+	}
+
 	// Validate that all members exist.
 	for _, member := range members {
 		cr, ok := sys.iamUsersMap[member]
@@ -1368,6 +1883,10 @@ func (sys *IAMSys) SetGroupStatus(group string, enabled bool) error {
 	sys.store.lock()
 	defer sys.store.unlock()
 
+	if err := sys.reloadGroupForUpdate(context.Background(), group); err != nil {
+		return err
+	}
+
 	if group == "" {
 		return errInvalidArgument
 	}
@@ -1423,10 +1942,11 @@ func (sys *IAMSys) GetGroupDescription(group string) (gd madmin.GroupDesc, err e
 	}
 
 	return madmin.GroupDesc{
-		Name:    group,
-		Status:  gi.Status,
-		Members: gi.Members,
-		Policy:  policy,
+		Name:               group,
+		Status:             gi.Status,
+		Members:            gi.Members,
+		Policy:             policy,
+		PermissionBoundary: gi.PermissionBoundary,
 	}, nil
 }
 
@@ -1586,56 +2106,47 @@ func (sys *IAMSys) policyDBGet(name string, isGroup bool) ([]string, error) {
 // IsAllowedServiceAccount - checks if the given service account is allowed to perform
 // actions. The permission of the parent user is checked first
 func (sys *IAMSys) IsAllowedServiceAccount(args iampolicy.Args, parent string) bool {
+	return sys.isAllowedServiceAccount(args, parent, &policyDecision{})
+}
+
+func (sys *IAMSys) isAllowedServiceAccount(args iampolicy.Args, parent string, d *policyDecision) bool {
 	// Now check if we have a subject claim
 	p, ok := args.Claims[parentClaim]
 	if ok {
 		parentInClaim, ok := p.(string)
 		if !ok {
 			// Reject malformed/malicious requests.
-			return false
+			return d.deny("the parent claim of the session token is malformed")
 		}
 		// The parent claim in the session token should be equal
 		// to the parent detected in the backend
 		if parentInClaim != parent {
-			return false
+			return d.deny("the parent user in the session token is not %s", parent)
 		}
 	} else {
 		// This is needed so a malicious user cannot
 		// use a leaked session key of another user
 		// to widen its privileges.
-		return false
+		return d.deny("the session token has no parent claim")
 	}
 
 	// Check if the parent is allowed to perform this action, reject if not
-	parentUserPolicies, err := sys.PolicyDBGet(parent, false)
-	if err != nil {
-		return false
-	}
-
-	if len(parentUserPolicies) == 0 {
-		return false
-	}
-
-	var availablePolicies []iampolicy.Policy
-
-	// Policies were found, evaluate all of them.
 	sys.store.rlock()
-	for _, pname := range parentUserPolicies {
-		p, found := sys.iamPolicyDocsMap[pname]
-		if found {
-			availablePolicies = append(availablePolicies, p)
-		}
+	u, ok := sys.iamUsersMap[parent]
+	var policies []sourcedPolicy
+	if ok && u.Status != statusDisabled {
+		policies = sys.userPolicies(parent, madmin.ParentPolicySource)
 	}
 	sys.store.runlock()
 
-	if len(availablePolicies) == 0 {
-		return false
+	if !ok {
+		return d.deny("parent user %s does not exist", parent)
 	}
-
-	combinedPolicy := availablePolicies[0]
-	for i := 1; i < len(availablePolicies); i++ {
-		combinedPolicy.Statements = append(combinedPolicy.Statements,
-			availablePolicies[i].Statements...)
+	if u.Status == statusDisabled {
+		return d.deny("parent user %s is disabled", parent)
+	}
+	if len(policies) == 0 {
+		return d.deny("no policy is attached to parent user %s or to its groups", parent)
 	}
 
 	parentArgs := args
@@ -1643,82 +2154,73 @@ func (sys *IAMSys) IsAllowedServiceAccount(args iampolicy.Args, parent string) b
 
 	saPolicyClaim, ok := args.Claims[iamPolicyClaimNameSA()]
 	if !ok {
-		return false
+		return d.deny("the session token has no service account policy claim")
 	}
 
 	saPolicyClaimStr, ok := saPolicyClaim.(string)
 	if !ok {
 		// Sub policy if set, should be a string reject
 		// malformed/malicious requests.
-		return false
+		return d.deny("the service account policy claim of the session token is malformed")
 	}
 
 	if saPolicyClaimStr == "inherited-policy" {
-		return combinedPolicy.IsAllowed(parentArgs)
+		return d.evaluate(parentArgs, policies, nil)
 	}
 
 	// Now check if we have a sessionPolicy.
-	spolicy, ok := args.Claims[iampolicy.SessionPolicyName]
-	if !ok {
-		return false
-	}
-
-	spolicyStr, ok := spolicy.(string)
-	if !ok {
-		// Sub policy if set, should be a string reject
-		// malformed/malicious requests.
-		return false
-	}
-
-	// Check if policy is parseable.
-	subPolicy, err := iampolicy.ParseConfig(bytes.NewReader([]byte(spolicyStr)))
+	subPolicy, err := sessionPolicyFromClaims(args.Claims)
 	if err != nil {
-		// Log any error in input session policy config.
-		logger.LogIf(GlobalContext, err)
-		return false
+		return d.deny("the session policy is invalid: %v", err)
 	}
-
-	// Policy without Version string value reject it.
-	if subPolicy.Version == "" {
-		return false
+	if subPolicy == nil {
+		return d.deny("the session token has no session policy")
 	}
 
-	return combinedPolicy.IsAllowed(parentArgs) && subPolicy.IsAllowed(parentArgs)
+	return d.evaluate(parentArgs, policies, subPolicy)
 }
 
 // IsAllowedLDAPSTS - checks for LDAP specific claims and values
 func (sys *IAMSys) IsAllowedLDAPSTS(args iampolicy.Args) bool {
+	return sys.isAllowedLDAPSTS(args, &policyDecision{})
+}
+
+func (sys *IAMSys) isAllowedLDAPSTS(args iampolicy.Args, d *policyDecision) bool {
 	userIface, ok := args.Claims[ldapUser]
 	if !ok {
-		return false
+		return d.deny("the session token has no LDAP user claim")
 	}
 	user, ok := userIface.(string)
 	if !ok {
-		return false
+		return d.deny("the LDAP user claim of the session token is malformed")
 	}
 
 	sys.store.rlock()
 	defer sys.store.runlock()
 
-	var groups []string
 	cred, ok := sys.iamUsersMap[args.AccountName]
 	if !ok {
-		return false
+		return d.deny("access key %s does not exist", args.AccountName)
 	}
-	groups = cred.Groups
 
+	return sys.isAllowedLDAPPolicies(args, user, cred.Groups, d)
+}
+
+// isAllowedLDAPPolicies - checks that the policies of the LDAP user and
+// of its groups allow the request. Assumes that sys.store.rlock is held.
+func (sys *IAMSys) isAllowedLDAPPolicies(args iampolicy.Args, user string, groups []string, d *policyDecision) bool {
 	// We look up the policy mapping directly to bypass
 	// users exists, group exists validations that do not
 	// apply here.
-	var policies []iampolicy.Policy
+	var policies []sourcedPolicy
 	if mp, ok := sys.iamUserPolicyMap[user]; ok {
 		for _, pname := range mp.toSlice() {
 			p, found := sys.iamPolicyDocsMap[pname]
 			if !found {
 				logger.LogIf(GlobalContext, fmt.Errorf("expected policy (%s) missing for the LDAPUser %s, rejecting the request", pname, user))
-				return false
+				return d.deny("policy %s of LDAP user %s does not exist", pname, user)
 			}
-			policies = append(policies, p)
+			policies = append(policies, sourcedPolicy{source: madmin.UserPolicySource, name: pname, policy: p})
 		}
 	}
 	for _, group := range groups {
@@ -1730,42 +2232,33 @@ func (sys *IAMSys) IsAllowedLDAPSTS(args iampolicy.Args) bool {
 			p, found := sys.iamPolicyDocsMap[pname]
 			if !found {
 				logger.LogIf(GlobalContext, fmt.Errorf("expected policy (%s) missing for the LDAPGroup %s, rejecting the request", pname, group))
-				return false
+				return d.deny("policy %s of LDAP group %s does not exist", pname, group)
 			}
-			policies = append(policies, p)
+			policies = append(policies, sourcedPolicy{source: madmin.GroupPolicySource, name: pname, group: group, policy: p})
 		}
 	}
 	if len(policies) == 0 {
-		return false
-	}
-	combinedPolicy := policies[0]
-	for i := 1; i < len(policies); i++ {
-		combinedPolicy.Statements =
-			append(combinedPolicy.Statements,
-				policies[i].Statements...)
+		return d.deny("no policy is attached to LDAP user %s or to its groups", user)
 	}
-	return combinedPolicy.IsAllowed(args)
+	return d.evaluate(args, policies, nil)
 }
 
 // IsAllowedSTS is meant for STS based temporary credentials,
 // which implements claims validation and verification other than
 // applying policies.
 func (sys *IAMSys) IsAllowedSTS(args iampolicy.Args) bool {
+	return sys.isAllowedSTS(args, &policyDecision{})
+}
+
+func (sys *IAMSys) isAllowedSTS(args iampolicy.Args, d *policyDecision) bool {
 	// If it is an LDAP request, check that user and group
 	// policies allow the request.
 	if sys.usersSysType == LDAPUsersSysType {
-		return sys.IsAllowedLDAPSTS(args)
+		return sys.isAllowedLDAPSTS(args, d)
 	}
 
-	policies, ok := args.GetPolicies(iamPolicyClaimNameOpenID())
+	policies, ok := d.claimPolicies(args)
 	if !ok {
-		// When claims are set, it should have a policy claim field.
-		return false
-	}
-
-	// When claims are set, it should have policies as claim.
-	if policies.IsEmpty() {
-		// No policy, no access!
 		return false
 	}
 
@@ -1776,63 +2269,41 @@ func (sys *IAMSys) IsAllowedSTS(args iampolicy.Args) bool {
 	mp, ok := sys.iamUserPolicyMap[args.AccountName]
 	if !ok {
 		// No policy set for the user that we can find, no access!
-		return false
+		return d.deny("no policy is attached to temporary user %s", args.AccountName)
 	}
 
 	if !policies.Equals(mp.policySet()) {
 		// When claims has a policy, it should match the
 		// policy of args.AccountName which server remembers.
 		// if not reject such requests.
-		return false
+		return d.deny("the policies of the claims are not the policies of temporary user %s", args.AccountName)
 	}
 
-	var availablePolicies []iampolicy.Policy
-	for pname := range policies {
+	return sys.isAllowedClaimPolicies(args, policies, d)
+}
+
+// isAllowedClaimPolicies - checks that the policies named in the claims,
+// and the session policy if any, allow the request. Assumes that
+// sys.store.rlock is held.
+func (sys *IAMSys) isAllowedClaimPolicies(args iampolicy.Args, names set.StringSet, d *policyDecision) bool {
+	var policies []sourcedPolicy
+	for _, pname := range names.ToSlice() {
 		p, found := sys.iamPolicyDocsMap[pname]
 		if !found {
 			// all policies presented in the claim should exist
 			logger.LogIf(GlobalContext, fmt.Errorf("expected policy (%s) missing from the JWT claim %s, rejecting the request", pname, iamPolicyClaimNameOpenID()))
-			return false
+			return d.deny("policy %s of the claims does not exist", pname)
 		}
-		availablePolicies = append(availablePolicies, p)
-	}
-
-	combinedPolicy := availablePolicies[0]
-	for i := 1; i < len(availablePolicies); i++ {
-		combinedPolicy.Statements = append(combinedPolicy.Statements,
-			availablePolicies[i].Statements...)
+		policies = append(policies, sourcedPolicy{source: madmin.ClaimPolicySource, name: pname, policy: p})
 	}
 
-	// Now check if we have a sessionPolicy.
-	spolicy, ok := args.Claims[iampolicy.SessionPolicyName]
-	if ok {
-		spolicyStr, ok := spolicy.(string)
-		if !ok {
-			// Sub policy if set, should be a string reject
-			// malformed/malicious requests.
-			return false
-		}
-
-		// Check if policy is parseable.
-		subPolicy, err := iampolicy.ParseConfig(bytes.NewReader([]byte(spolicyStr)))
-		if err != nil {
-			// Log any error in input session policy config.
-			logger.LogIf(GlobalContext, err)
-			return false
-		}
-
-		// Policy without Version string value reject it.
-		if subPolicy.Version == "" {
-			return false
-		}
-
-		// Sub policy is set and valid.
-		return combinedPolicy.IsAllowed(args) && subPolicy.IsAllowed(args)
+	// Now check if we have a sessionPolicy, it is optional.
+	subPolicy, err := sessionPolicyFromClaims(args.Claims)
+	if err != nil {
+		return d.deny("the session policy is invalid: %v", err)
 	}
 
-	// Sub policy not set, this is most common since subPolicy
-	// is optional, use the inherited policies.
-	return combinedPolicy.IsAllowed(args)
+	return d.evaluate(args, policies, subPolicy)
 }
 
 // GetCombinedPolicy returns a combined policy combining all policies
@@ -1864,51 +2335,75 @@ func (sys *IAMSys) GetCombinedPolicy(policies ...string) iampolicy.Policy {
 
 // IsAllowed - checks given policy args is allowed to continue the Rest API.
 func (sys *IAMSys) IsAllowed(args iampolicy.Args) bool {
+	return sys.isAllowed(args, &policyDecision{})
+}
+
+func (sys *IAMSys) isAllowed(args iampolicy.Args, d *policyDecision) bool {
 	// If opa is configured, use OPA always.
 	if globalPolicyOPA != nil {
 		ok, err := globalPolicyOPA.IsAllowed(args)
 		if err != nil {
 			logger.LogIf(GlobalContext, err)
+			return d.deny("OPA failed: %v", err)
 		}
-		return ok
+		if !ok {
+			return d.deny("denied by OPA")
+		}
+		return d.allow("allowed by OPA")
 	}
 
 	// Policies don't apply to the owner.
 	if args.IsOwner {
-		return true
+		return d.allow("policies do not apply to the owner")
+	}
+
+	// Permission boundaries cap the permissions of users, of their
+	// service accounts and of their temporary credentials.
+	if boundary, denied := sys.deniedByBoundary(args); denied {
+		return d.deny("denied by permission boundary %s", boundary)
 	}
 
 	// If the credential is temporary, perform STS related checks.
 	ok, err := sys.IsTempUser(args.AccountName)
 	if err != nil {
-		return false
+		return d.deny("access key %s: %v", args.AccountName, err)
 	}
 	if ok {
-		return sys.IsAllowedSTS(args)
+		return sys.isAllowedSTS(args, d)
 	}
 
 	// If the credential is for a service account, perform related check
 	ok, parentUser, err := sys.IsServiceAccount(args.AccountName)
 	if err != nil {
-		return false
+		return d.deny("access key %s: %v", args.AccountName, err)
 	}
 	if ok {
-		return sys.IsAllowedServiceAccount(args, parentUser)
+		return sys.isAllowedServiceAccount(args, parentUser, d)
 	}
 
-	// Continue with the assumption of a regular user
-	policies, err := sys.PolicyDBGet(args.AccountName, false)
-	if err != nil {
-		return false
+	// Continue with the assumption of a regular user, its
+	// policies do not apply while it is disabled.
+	sys.store.rlock()
+	u, ok := sys.iamUsersMap[args.AccountName]
+	var policies []sourcedPolicy
+	if ok && u.Status != statusDisabled {
+		policies = sys.userPolicies(args.AccountName, madmin.UserPolicySource)
 	}
+	sys.store.runlock()
 
+	if !ok {
+		return d.deny("access key %s does not exist", args.AccountName)
+	}
+	if u.Status == statusDisabled {
+		return d.deny("user %s is disabled", args.AccountName)
+	}
 	if len(policies) == 0 {
 		// No policy found.
-		return false
+		return d.deny("no policy is attached to user %s or to its groups", args.AccountName)
 	}
 
 	// Policies were found, evaluate all of them.
-	return sys.GetCombinedPolicy(policies...).IsAllowed(args)
+	return d.evaluate(args, policies, nil)
 }
 
 // Set default canned policies only if not already overridden by users.
@@ -0,0 +1,50 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+// prepareIAMTestFS returns a configured FS object layer and its
+// directory, which is removed when the test finishes.
+func prepareIAMTestFS(t *testing.T) (ObjectLayer, string) {
+	obj, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(fsDir) })
+	if err = newTestConfig(globalMinioDefaultRegion, obj); err != nil {
+		t.Fatal(err)
+	}
+	return obj, fsDir
+}
+
+// newTestIAMSys returns an IAM system backed by the object store of
+// a new FS object layer.
+func newTestIAMSys(t *testing.T) *IAMSys {
+	obj, _ := prepareIAMTestFS(t)
+	sys := NewIAMSys()
+	sys.InitStore(obj)
+	// Load the default canned policies.
+	if err := sys.store.loadAll(context.Background(), sys); err != nil {
+		t.Fatal(err)
+	}
+	return sys
+}
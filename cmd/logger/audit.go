@@ -18,6 +18,7 @@ package logger
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -124,6 +125,28 @@ func (lrw *ResponseWriter) Size() int {
 	return lrw.bytesWritten
 }
 
+// Key used to hold the policy decision of a request in its context.
+const contextPolicyDecisionKey = contextKeyType("policydecision")
+
+// policyDecision - holds the policy decision of a request.
+type policyDecision struct {
+	decision interface{}
+}
+
+// WithPolicyDecision - returns a copy of ctx able to hold the policy
+// decision of the request, logged along with its audit entry.
+func WithPolicyDecision(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextPolicyDecisionKey, &policyDecision{})
+}
+
+// SetPolicyDecision - sets the policy decision of the request, it is
+// ignored unless ctx was returned by WithPolicyDecision.
+func SetPolicyDecision(ctx context.Context, decision interface{}) {
+	if d, ok := ctx.Value(contextPolicyDecisionKey).(*policyDecision); ok {
+		d.decision = decision
+	}
+}
+
 // AuditLog - logs audit logs to all audit targets.
 func AuditLog(w http.ResponseWriter, r *http.Request, api string, reqClaims map[string]interface{}, filterKeys ...string) {
 	// Fast exit if there is not audit target configured
@@ -158,6 +181,9 @@ func AuditLog(w http.ResponseWriter, r *http.Request, api string, reqClaims map[
 		delete(entry.ReqHeader, filterKey)
 		delete(entry.RespHeader, filterKey)
 	}
+	if d, ok := r.Context().Value(contextPolicyDecisionKey).(*policyDecision); ok {
+		entry.PolicyDecision = d.decision
+	}
 	entry.API.Name = api
 	entry.API.Bucket = bucket
 	entry.API.Object = object
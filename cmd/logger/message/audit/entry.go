@@ -49,6 +49,8 @@ type Entry struct {
 	ReqQuery   map[string]string      `json:"requestQuery,omitempty"`
 	ReqHeader  map[string]string      `json:"requestHeader,omitempty"`
 	RespHeader map[string]string      `json:"responseHeader,omitempty"`
+	// PolicyDecision is only set for denied requests, in policy debug mode.
+	PolicyDecision interface{} `json:"policyDecision,omitempty"`
 }
 
 // ToEntry - constructs an audit entry object.
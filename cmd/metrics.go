@@ -38,6 +38,14 @@ var (
 		},
 		[]string{"api"},
 	)
+	bucketReplicationLag = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "bucket_replication_lag_seconds",
+			Help:    "Time taken from the upload of an object until it was replicated to the target by current MinIO server instance",
+			Buckets: []float64{1, 5, 15, 60, 300, 900, 3600, 14400, 86400},
+		},
+		[]string{"bucket", "target"},
+	)
 	minioVersionInfo = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: "minio",
@@ -55,6 +63,7 @@ var (
 
 func init() {
 	prometheus.MustRegister(httpRequestsDuration)
+	prometheus.MustRegister(bucketReplicationLag)
 	prometheus.MustRegister(newMinioCollector())
 	prometheus.MustRegister(minioVersionInfo)
 }
@@ -87,8 +96,10 @@ func (c *minioCollector) Collect(ch chan<- prometheus.Metric) {
 
 	storageMetricsPrometheus(ch)
 	bucketUsageMetricsPrometheus(ch)
+	bucketReplicationMetricsPrometheus(ch)
 	networkMetricsPrometheus(ch)
 	httpMetricsPrometheus(ch)
+	rateLimitMetricsPrometheus(ch)
 	cacheMetricsPrometheus(ch)
 	gatewayMetricsPrometheus(ch)
 	healingMetricsPrometheus(ch)
@@ -345,6 +356,22 @@ func httpMetricsPrometheus(ch chan<- prometheus.Metric) {
 	}
 }
 
+// collects the requests throttled by the rate limits of users, groups and
+// buckets in Prometheus specific format and sends to given channel
+func rateLimitMetricsPrometheus(ch chan<- prometheus.Metric) {
+	for m, value := range globalRateLimitSys.throttledRequests() {
+		ch <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc(
+				prometheus.BuildFQName("s3", "requests", "throttled_total"),
+				"Total number of s3 requests rejected by the rate limits of users, groups and buckets in current MinIO server instance",
+				[]string{"type", "name", "reason"}, nil),
+			prometheus.CounterValue,
+			float64(value),
+			string(m.limitType), m.name, m.reason,
+		)
+	}
+}
+
 // collects network metrics for MinIO server in Prometheus specific format
 // and sends to given channel
 func networkMetricsPrometheus(ch chan<- prometheus.Metric) {
@@ -416,6 +443,7 @@ func bucketUsageMetricsPrometheus(ch chan<- prometheus.Metric) {
 	if dataUsageInfo.LastUpdate.IsZero() {
 		return
 	}
+	dataUsageInfo = globalRealtimeUsage.dataUsageInfo(dataUsageInfo)
 
 	for bucket, usageInfo := range dataUsageInfo.BucketsUsage {
 		// Total space used by bucket
@@ -488,6 +516,57 @@ func bucketUsageMetricsPrometheus(ch chan<- prometheus.Metric) {
 	}
 }
 
+// Populates prometheus with the replication metrics of current MinIO
+// server instance per bucket and remote target.
+func bucketReplicationMetricsPrometheus(ch chan<- prometheus.Metric) {
+	if globalIsGateway || globalReplicationState == nil {
+		return
+	}
+
+	now := UTCNow()
+	metrics := globalReplicationState.getMetrics("")
+	for bucket, bm := range metrics.Buckets {
+		for arn, tm := range bm.Targets {
+			var pendingAge time.Duration
+			if !tm.OldestPending.IsZero() {
+				pendingAge = now.Sub(tm.OldestPending)
+			}
+			var lastErrorTime float64
+			if !tm.LastErrorTime.IsZero() {
+				lastErrorTime = float64(tm.LastErrorTime.Unix())
+			}
+			for _, m := range []struct {
+				name      string
+				help      string
+				valueType prometheus.ValueType
+				value     float64
+			}{
+				{"pending_count", "Number of operations pending to be replicated to the target", prometheus.GaugeValue, float64(tm.Pending)},
+				{"pending_bytes", "Total size of the operations pending to be replicated to the target", prometheus.GaugeValue, float64(tm.PendingSize)},
+				{"oldest_pending_seconds", "Age of the oldest operation pending to be replicated to the target", prometheus.GaugeValue, pendingAge.Seconds()},
+				{"failed_count", "Number of operations which permanently failed to replicate to the target", prometheus.GaugeValue, float64(tm.Failed)},
+				{"failed_bytes", "Total size of the operations which permanently failed to replicate to the target", prometheus.GaugeValue, float64(tm.FailedSize)},
+				{"replicated_total", "Total number of operations replicated to the target", prometheus.CounterValue, float64(tm.Replicated)},
+				{"replicated_bytes_total", "Total size of the operations replicated to the target", prometheus.CounterValue, float64(tm.ReplicatedSize)},
+				{"failed_attempts_total", "Total number of failed attempts to replicate to the target", prometheus.CounterValue, float64(tm.FailedAttempts)},
+				{"last_error_timestamp_seconds", "Time of the last error returned by the target", prometheus.GaugeValue, lastErrorTime},
+				{"bandwidth_bytes_per_second", "Bandwidth used to replicate to the target", prometheus.GaugeValue, tm.CurrentBandwidth},
+				{"bandwidth_limit_bytes_per_second", "Bandwidth limit of the target across all servers, zero if not limited", prometheus.GaugeValue, float64(tm.BandwidthLimit)},
+			} {
+				ch <- prometheus.MustNewConstMetric(
+					prometheus.NewDesc(
+						prometheus.BuildFQName("bucket", "replication", "target_"+m.name),
+						m.help,
+						[]string{"bucket", "target"}, nil),
+					m.valueType,
+					m.value,
+					bucket, arn,
+				)
+			}
+		}
+	}
+}
+
 // collects storage metrics for MinIO server in Prometheus specific format
 // and sends to given channel
 func storageMetricsPrometheus(ch chan<- prometheus.Metric) {
@@ -1382,6 +1382,133 @@ func sendEvent(args eventArgs) {
 	globalNotificationSys.Send(args)
 }
 
+// GetReplicationQueueInfo - returns the replication queue state of all
+// nodes including self, merged per bucket.
+func (sys *NotificationSys) GetReplicationQueueInfo(ctx context.Context, bucket string) madmin.ReplicationQueueInfo {
+	infos := make([]madmin.ReplicationQueueInfo, len(sys.peerClients))
+	g := errgroup.WithNErrs(len(sys.peerClients))
+	for index := range sys.peerClients {
+		if sys.peerClients[index] == nil {
+			continue
+		}
+		index := index
+		g.Go(func() error {
+			var err error
+			infos[index], err = sys.peerClients[index].GetReplicationQueueInfo(ctx, bucket)
+			return err
+		}, index)
+	}
+
+	for index, err := range g.Wait() {
+		if err != nil {
+			reqInfo := (&logger.ReqInfo{}).AppendTags("peerAddress",
+				sys.peerClients[index].host.String())
+			ctx := logger.SetReqInfo(ctx, reqInfo)
+			logger.LogOnceIf(ctx, err, sys.peerClients[index].host.String())
+		}
+	}
+	infos = append(infos, globalReplicationState.getQueueInfo(bucket))
+
+	merged := madmin.ReplicationQueueInfo{
+		Buckets: make(map[string]madmin.BucketReplicationQueueInfo),
+	}
+	for _, info := range infos {
+		for b, bi := range info.Buckets {
+			m, ok := merged.Buckets[b]
+			if !ok {
+				m.FailureReasons = make(map[string]uint64)
+			}
+			m.Pending += bi.Pending
+			m.PendingSize += bi.PendingSize
+			m.Retrying += bi.Retrying
+			if !bi.OldestPending.IsZero() && (m.OldestPending.IsZero() || bi.OldestPending.Before(m.OldestPending)) {
+				m.OldestPending = bi.OldestPending
+			}
+			m.Failed += bi.Failed
+			m.FailedSize += bi.FailedSize
+			m.FailedObjects = append(m.FailedObjects, bi.FailedObjects...)
+			m.Dropped += bi.Dropped
+			for reason, n := range bi.FailureReasons {
+				m.FailureReasons[reason] += n
+			}
+			merged.Buckets[b] = m
+		}
+	}
+	return merged
+}
+
+// GetReplicationMetrics - returns the replication metrics of all nodes
+// including self, merged per bucket and target.
+func (sys *NotificationSys) GetReplicationMetrics(ctx context.Context, bucket string) madmin.ReplicationMetrics {
+	metrics := make([]madmin.ReplicationMetrics, len(sys.peerClients))
+	g := errgroup.WithNErrs(len(sys.peerClients))
+	for index := range sys.peerClients {
+		if sys.peerClients[index] == nil {
+			continue
+		}
+		index := index
+		g.Go(func() error {
+			var err error
+			metrics[index], err = sys.peerClients[index].GetReplicationMetrics(ctx, bucket)
+			return err
+		}, index)
+	}
+
+	for index, err := range g.Wait() {
+		if err != nil {
+			reqInfo := (&logger.ReqInfo{}).AppendTags("peerAddress",
+				sys.peerClients[index].host.String())
+			ctx := logger.SetReqInfo(ctx, reqInfo)
+			logger.LogOnceIf(ctx, err, sys.peerClients[index].host.String())
+		}
+	}
+	metrics = append(metrics, globalReplicationState.getMetrics(bucket))
+	return mergeReplicationMetrics(metrics...)
+}
+
+// GetLifecycleActionLog - returns the lifecycle actions applied to the
+// objects of the bucket by the crawlers of all nodes including self.
+func (sys *NotificationSys) GetLifecycleActionLog(ctx context.Context, bucket string, since time.Time) madmin.LifecycleActionLog {
+	logs := make([]madmin.LifecycleActionLog, len(sys.peerClients))
+	g := errgroup.WithNErrs(len(sys.peerClients))
+	for index := range sys.peerClients {
+		if sys.peerClients[index] == nil {
+			continue
+		}
+		index := index
+		g.Go(func() error {
+			var err error
+			logs[index], err = sys.peerClients[index].GetLifecycleActionLog(ctx, bucket, since)
+			return err
+		}, index)
+	}
+
+	for index, err := range g.Wait() {
+		if err != nil {
+			reqInfo := (&logger.ReqInfo{}).AppendTags("peerAddress",
+				sys.peerClients[index].host.String())
+			ctx := logger.SetReqInfo(ctx, reqInfo)
+			logger.LogOnceIf(ctx, err, sys.peerClients[index].host.String())
+
+			// Fall back to the actions last saved by the node.
+			objAPI := newObjectLayerFn()
+			if objAPI == nil {
+				continue
+			}
+			state, err := loadLifecycleActionLog(ctx, objAPI, sys.peerClients[index].host.String())
+			if err != nil {
+				if err != errConfigNotFound {
+					logger.LogIf(ctx, err)
+				}
+				continue
+			}
+			logs[index] = getBucketLifecycleActionLog(state.Buckets, bucket, since)
+		}
+	}
+	logs = append(logs, globalLifecycleActionLog.get(bucket, since))
+	return mergeLifecycleActionLogs(bucket, logs...)
+}
+
 // GetBandwidthReports - gets the bandwidth report from all nodes including self.
 func (sys *NotificationSys) GetBandwidthReports(ctx context.Context, buckets ...string) bandwidth.Report {
 	reports := make([]*bandwidth.Report, len(sys.peerClients))
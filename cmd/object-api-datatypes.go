@@ -107,6 +107,10 @@ type DataUsageInfo struct {
 	// This does not indicate a full scan.
 	LastUpdate time.Time `json:"lastUpdate"`
 
+	// CrawlStart is the timestamp of when the crawl of the usage started,
+	// changes made before are counted.
+	CrawlStart time.Time `json:"crawlStart,omitempty"`
+
 	// Objects total count across all buckets
 	ObjectsTotalCount uint64 `json:"objectsCount"`
 
@@ -134,10 +138,24 @@ type DataUsageInfo struct {
 	// - object size histogram per bucket
 	BucketsUsage map[string]BucketUsageInfo `json:"bucketsUsageInfo"`
 
+	// Usage of the prefixes of each bucket, down to the configured
+	// prefix depth of the crawler.
+	PrefixesUsage map[string]map[string]PrefixUsageInfo `json:"prefixesUsageInfo,omitempty"`
+
+	// RealtimeUpdate is the timestamp of the latest change of usage
+	// counted since the crawl, zero if the usage is as crawled.
+	RealtimeUpdate time.Time `json:"realtimeUpdate,omitempty"`
+
 	// Deprecated kept here for backward compatibility reasons.
 	BucketSizes map[string]uint64 `json:"bucketsSizes"`
 }
 
+// PrefixUsageInfo - usage of the objects under a prefix.
+type PrefixUsageInfo struct {
+	Size         uint64 `json:"size"`
+	ObjectsCount uint64 `json:"objectsCount"`
+}
+
 // BucketInfo - represents bucket metadata.
 type BucketInfo struct {
 	// Name of the bucket.
@@ -398,9 +398,25 @@ func (e BucketQuotaConfigNotFound) Error() string {
 type BucketQuotaExceeded GenericError
 
 func (e BucketQuotaExceeded) Error() string {
+	if e.Object != "" {
+		return "Bucket quota exceeded for bucket: " + e.Bucket + ", prefix: " + e.Object
+	}
 	return "Bucket quota exceeded for bucket: " + e.Bucket
 }
 
+// UserQuotaExceeded - quota of a user or group exceeded.
+type UserQuotaExceeded struct {
+	Name  string
+	Group bool
+}
+
+func (e UserQuotaExceeded) Error() string {
+	if e.Group {
+		return "Group quota exceeded for group: " + e.Name
+	}
+	return "User quota exceeded for user: " + e.Name
+}
+
 // BucketReplicationConfigNotFound - no bucket replication config found
 type BucketReplicationConfigNotFound GenericError
 
@@ -408,6 +424,16 @@ func (e BucketReplicationConfigNotFound) Error() string {
 	return "The replication configuration was not found: " + e.Bucket
 }
 
+// BucketInventoryConfigNotFound - no bucket inventory config found with the given id
+type BucketInventoryConfigNotFound struct {
+	Bucket string
+	ID     string
+}
+
+func (e BucketInventoryConfigNotFound) Error() string {
+	return "The inventory configuration " + e.ID + " was not found: " + e.Bucket
+}
+
 // BucketRemoteDestinationNotFound bucket does not exist.
 type BucketRemoteDestinationNotFound GenericError
 
@@ -464,6 +490,13 @@ func (e BucketRemoteArnInvalid) Error() string {
 	return "Remote ARN has invalid format: " + e.Bucket
 }
 
+// BucketRemoteLocalPathInvalid local tier path overlaps the drives of the server.
+type BucketRemoteLocalPathInvalid GenericError
+
+func (e BucketRemoteLocalPathInvalid) Error() string {
+	return "Local tier path cannot be, contain or be inside a drive of the server: " + e.Object
+}
+
 // BucketRemoteRemoveDisallowed when replication configuration exists
 type BucketRemoteRemoveDisallowed GenericError
 
@@ -52,6 +52,11 @@ type ObjectOptions struct {
 	VersionPurgeStatus            VersionPurgeStatusType // Is only set in DELETE operations for delete marker version to be permanently deleted.
 	TransitionStatus              string                 // status of the transition
 	NoLock                        bool                   // indicates to lower layers if the caller is expecting to hold locks.
+
+	// ReplacedObjectFn is only set in PUT, COPY and multipart complete
+	// operations, it is called with the latest version of the object
+	// replaced by the write once the write holds the object lock.
+	ReplacedObjectFn func(ObjectInfo)
 }
 
 // BucketOptions represents bucket options for ObjectLayer bucket operations
@@ -268,6 +268,7 @@ func setPutObjHeaders(w http.ResponseWriter, objInfo ObjectInfo, delete bool) {
 				UserTags:     objInfo.UserTags,
 				VersionID:    objInfo.VersionID,
 				ModTime:      objInfo.ModTime,
+				Size:         objInfo.Size,
 				IsLatest:     objInfo.IsLatest,
 				DeleteMarker: objInfo.DeleteMarker,
 			})
@@ -891,6 +891,47 @@ func newPeerRESTClient(peer *xnet.Host) *peerRESTClient {
 	return &peerRESTClient{host: peer, restClient: restClient}
 }
 
+// GetReplicationQueueInfo - returns the replication queue state of the peer.
+func (client *peerRESTClient) GetReplicationQueueInfo(ctx context.Context, bucket string) (info madmin.ReplicationQueueInfo, err error) {
+	values := make(url.Values)
+	values.Set(peerRESTBucket, bucket)
+	respBody, err := client.callWithContext(ctx, peerRESTMethodGetReplicationQueue, values, nil, -1)
+	if err != nil {
+		return info, err
+	}
+	defer http.DrainBody(respBody)
+	err = gob.NewDecoder(respBody).Decode(&info)
+	return info, err
+}
+
+// GetReplicationMetrics - returns the replication metrics of the peer.
+func (client *peerRESTClient) GetReplicationMetrics(ctx context.Context, bucket string) (metrics madmin.ReplicationMetrics, err error) {
+	values := make(url.Values)
+	values.Set(peerRESTBucket, bucket)
+	respBody, err := client.callWithContext(ctx, peerRESTMethodGetReplicationMetrics, values, nil, -1)
+	if err != nil {
+		return metrics, err
+	}
+	defer http.DrainBody(respBody)
+	err = gob.NewDecoder(respBody).Decode(&metrics)
+	return metrics, err
+}
+
+// GetLifecycleActionLog - returns the lifecycle actions applied to the
+// objects of the bucket by the crawler of the peer.
+func (client *peerRESTClient) GetLifecycleActionLog(ctx context.Context, bucket string, since time.Time) (log madmin.LifecycleActionLog, err error) {
+	values := make(url.Values)
+	values.Set(peerRESTBucket, bucket)
+	values.Set(peerRESTSince, since.Format(time.RFC3339))
+	respBody, err := client.callWithContext(ctx, peerRESTMethodGetLifecycleActionLog, values, nil, -1)
+	if err != nil {
+		return log, err
+	}
+	defer http.DrainBody(respBody)
+	err = gob.NewDecoder(respBody).Decode(&log)
+	return log, err
+}
+
 // MonitorBandwidth - send http trace request to peer nodes
 func (client *peerRESTClient) MonitorBandwidth(ctx context.Context, buckets []string) (*bandwidth.Report, error) {
 	values := make(url.Values)
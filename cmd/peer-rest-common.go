@@ -17,7 +17,7 @@
 package cmd
 
 const (
-	peerRESTVersion       = "v11"
+	peerRESTVersion       = "v14"
 	peerRESTVersionPrefix = SlashSeparator + peerRESTVersion
 	peerRESTPrefix        = minioReservedBucketPath + "/peer"
 	peerRESTPath          = peerRESTPrefix + peerRESTVersionPrefix
@@ -58,6 +58,9 @@ const (
 	peerRESTMethodGetBandwidth           = "/bandwidth"
 	peerRESTMethodGetMetacacheListing    = "/getmetacache"
 	peerRESTMethodUpdateMetacacheListing = "/updatemetacache"
+	peerRESTMethodGetReplicationQueue    = "/getreplicationqueue"
+	peerRESTMethodGetReplicationMetrics  = "/getreplicationmetrics"
+	peerRESTMethodGetLifecycleActionLog  = "/getlifecycleactionlog"
 )
 
 const (
@@ -73,6 +76,7 @@ const (
 	peerRESTProfiler    = "profiler"
 	peerRESTTraceAll    = "all"
 	peerRESTTraceErr    = "err"
+	peerRESTSince       = "since"
 
 	peerRESTListenBucket = "bucket"
 	peerRESTListenPrefix = "prefix"
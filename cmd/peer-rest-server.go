@@ -1025,6 +1025,55 @@ func (s *peerRESTServer) GetBandwidth(w http.ResponseWriter, r *http.Request) {
 	w.(http.Flusher).Flush()
 }
 
+// GetReplicationQueueHandler - returns the replication queue state of this node.
+func (s *peerRESTServer) GetReplicationQueueHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	ctx := newContext(r, w, "GetReplicationQueue")
+	info := globalReplicationState.getQueueInfo(r.URL.Query().Get(peerRESTBucket))
+
+	defer w.(http.Flusher).Flush()
+	logger.LogIf(ctx, gob.NewEncoder(w).Encode(info))
+}
+
+// GetReplicationMetricsHandler - returns the replication metrics of this node.
+func (s *peerRESTServer) GetReplicationMetricsHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	ctx := newContext(r, w, "GetReplicationMetrics")
+	metrics := globalReplicationState.getMetrics(r.URL.Query().Get(peerRESTBucket))
+
+	defer w.(http.Flusher).Flush()
+	logger.LogIf(ctx, gob.NewEncoder(w).Encode(metrics))
+}
+
+// GetLifecycleActionLogHandler - returns the lifecycle actions applied to
+// the objects of a bucket by the crawler of this node.
+func (s *peerRESTServer) GetLifecycleActionLogHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	since, err := time.Parse(time.RFC3339, r.URL.Query().Get(peerRESTSince))
+	if err != nil {
+		s.writeErrorResponse(w, err)
+		return
+	}
+
+	ctx := newContext(r, w, "GetLifecycleActionLog")
+	log := globalLifecycleActionLog.get(r.URL.Query().Get(peerRESTBucket), since)
+
+	defer w.(http.Flusher).Flush()
+	logger.LogIf(ctx, gob.NewEncoder(w).Encode(log))
+}
+
 // registerPeerRESTHandlers - register peer rest router.
 func registerPeerRESTHandlers(router *mux.Router) {
 	server := &peerRESTServer{}
@@ -1064,4 +1113,7 @@ func registerPeerRESTHandlers(router *mux.Router) {
 	subrouter.Methods(http.MethodPost).Path(peerRESTVersionPrefix + peerRESTMethodGetBandwidth).HandlerFunc(httpTraceHdrs(server.GetBandwidth))
 	subrouter.Methods(http.MethodPost).Path(peerRESTVersionPrefix + peerRESTMethodGetMetacacheListing).HandlerFunc(httpTraceHdrs(server.GetMetacacheListingHandler))
 	subrouter.Methods(http.MethodPost).Path(peerRESTVersionPrefix + peerRESTMethodUpdateMetacacheListing).HandlerFunc(httpTraceHdrs(server.UpdateMetacacheListingHandler))
+	subrouter.Methods(http.MethodPost).Path(peerRESTVersionPrefix + peerRESTMethodGetReplicationQueue).HandlerFunc(httpTraceHdrs(server.GetReplicationQueueHandler))
+	subrouter.Methods(http.MethodPost).Path(peerRESTVersionPrefix + peerRESTMethodGetReplicationMetrics).HandlerFunc(httpTraceHdrs(server.GetReplicationMetricsHandler))
+	subrouter.Methods(http.MethodPost).Path(peerRESTVersionPrefix + peerRESTMethodGetLifecycleActionLog).HandlerFunc(httpTraceHdrs(server.GetLifecycleActionLogHandler))
 }
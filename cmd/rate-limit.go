@@ -0,0 +1,515 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/madmin"
+	"golang.org/x/time/rate"
+)
+
+// Interval at which the rate limits are reloaded from the IAM store,
+// changes made on other servers are applied within it.
+const rateLimitRefreshInterval = 10 * time.Second
+
+// Reasons for throttling a request, reported by the metrics.
+const (
+	rateLimitReasonRate        = "rate"
+	rateLimitReasonConcurrency = "concurrency"
+)
+
+// rateLimitKey identifies the rate limit of a user, group or bucket.
+type rateLimitKey struct {
+	limitType madmin.RateLimitType
+	name      string
+}
+
+// rateLimitMetric identifies the requests throttled by a rate limit for
+// the same reason.
+type rateLimitMetric struct {
+	rateLimitKey
+	reason string
+}
+
+// rateLimiter enforces the share of a cluster wide rate limit of a user,
+// group or bucket on the local server.
+type rateLimiter struct {
+	rateLimitKey
+	limit madmin.RateLimit
+
+	requests  *rate.Limiter
+	ingress   *rate.Limiter
+	egress    *rate.Limiter
+	maxActive int64
+	active    int64 // accessed atomically
+}
+
+// newRateLimiter - returns the limiter of the share of the rate limit
+// enforced by each of the servers, the same way as the requests_max API
+// setting is shared.
+func newRateLimiter(key rateLimitKey, limit madmin.RateLimit, servers int) *rateLimiter {
+	if servers < 1 {
+		servers = 1
+	}
+	share := func(v float64) float64 {
+		return math.Max(v/float64(servers), 1)
+	}
+
+	l := &rateLimiter{rateLimitKey: key, limit: limit}
+	if limit.RequestsPerSecond > 0 {
+		burst := limit.Burst
+		if burst == 0 {
+			burst = int(math.Ceil(limit.RequestsPerSecond))
+		}
+		l.requests = rate.NewLimiter(rate.Limit(limit.RequestsPerSecond/float64(servers)), int(share(float64(burst))))
+	}
+	if limit.ConcurrentRequests > 0 {
+		l.maxActive = int64(share(float64(limit.ConcurrentRequests)))
+	}
+	if limit.IngressBytesPerSecond > 0 {
+		bps := share(float64(limit.IngressBytesPerSecond))
+		l.ingress = rate.NewLimiter(rate.Limit(bps), int(bps))
+	}
+	if limit.EgressBytesPerSecond > 0 {
+		bps := share(float64(limit.EgressBytesPerSecond))
+		l.egress = rate.NewLimiter(rate.Limit(bps), int(bps))
+	}
+	return l
+}
+
+// acquire - takes one of the concurrent request slots, false if none is
+// available.
+func (l *rateLimiter) acquire() bool {
+	if l.maxActive == 0 {
+		return true
+	}
+	if atomic.AddInt64(&l.active, 1) > l.maxActive {
+		atomic.AddInt64(&l.active, -1)
+		return false
+	}
+	return true
+}
+
+// release - gives back a slot taken by acquire.
+func (l *rateLimiter) release() {
+	if l.maxActive > 0 {
+		atomic.AddInt64(&l.active, -1)
+	}
+}
+
+// rateLimitSys - rate limits of the S3 API requests of users, groups and
+// buckets, configured through the IAM store.
+type rateLimitSys struct {
+	mu        sync.Mutex
+	limiters  map[rateLimitKey]*rateLimiter
+	hasGroups bool
+	throttled map[rateLimitMetric]uint64
+}
+
+var globalRateLimitSys = newRateLimitSys()
+
+func newRateLimitSys() *rateLimitSys {
+	return &rateLimitSys{
+		limiters:  make(map[rateLimitKey]*rateLimiter),
+		throttled: make(map[rateLimitMetric]uint64),
+	}
+}
+
+// load - reloads the rate limits from the IAM store, the current limits
+// are kept on error. Limiters of unchanged rate limits are kept so that
+// the requests being served keep counting against them.
+func (sys *rateLimitSys) load(ctx context.Context, iamSys *IAMSys) error {
+	l, err := iamSys.GetRateLimits(ctx)
+	if err != nil {
+		return err
+	}
+
+	sys.mu.Lock()
+	defer sys.mu.Unlock()
+
+	sys.set(l.RateLimits, len(globalEndpoints.Hostnames()))
+	return nil
+}
+
+// run - reloads the rate limits periodically until ctx is done, to apply
+// the changes made on other servers.
+func (sys *rateLimitSys) run(ctx context.Context, iamSys *IAMSys) {
+	ticker := time.NewTicker(rateLimitRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			logger.LogIf(ctx, sys.load(ctx, iamSys))
+		}
+	}
+}
+
+// set - replaces the rate limits, must be called with sys.mu held.
+func (sys *rateLimitSys) set(limits madmin.RateLimits, servers int) {
+	limiters := make(map[rateLimitKey]*rateLimiter)
+	for limitType, m := range map[madmin.RateLimitType]map[string]madmin.RateLimit{
+		madmin.UserRateLimit:   limits.Users,
+		madmin.GroupRateLimit:  limits.Groups,
+		madmin.BucketRateLimit: limits.Buckets,
+	} {
+		for name, limit := range m {
+			key := rateLimitKey{limitType: limitType, name: name}
+			if l, ok := sys.limiters[key]; ok && l.limit == limit {
+				limiters[key] = l
+				continue
+			}
+			limiters[key] = newRateLimiter(key, limit, servers)
+		}
+	}
+	sys.limiters = limiters
+	sys.hasGroups = len(limits.Groups) > 0
+}
+
+// enabled - returns true if any rate limit is set.
+func (sys *rateLimitSys) enabled() bool {
+	sys.mu.Lock()
+	defer sys.mu.Unlock()
+
+	return len(sys.limiters) > 0
+}
+
+// limitersOf - returns the limiters of the bucket and of the user making
+// the request along with its groups. Requests made with temporary
+// credentials and service accounts count against their parent user.
+func (sys *rateLimitSys) limitersOf(cred auth.Credentials, bucket string) []*rateLimiter {
+	sys.mu.Lock()
+	hasGroups := sys.hasGroups
+	sys.mu.Unlock()
+
+	user := cred.AccessKey
+	if cred.ParentUser != "" {
+		user = cred.ParentUser
+	}
+	var groups []string
+	if user != "" && hasGroups {
+		groups = append(groups, cred.Groups...)
+		groups = append(groups, globalIAMSys.GetUserGroups(user)...)
+	}
+
+	sys.mu.Lock()
+	defer sys.mu.Unlock()
+
+	var limiters []*rateLimiter
+	add := func(key rateLimitKey) {
+		if l, ok := sys.limiters[key]; ok {
+			for _, added := range limiters {
+				if added == l {
+					return
+				}
+			}
+			limiters = append(limiters, l)
+		}
+	}
+	if bucket != "" {
+		add(rateLimitKey{limitType: madmin.BucketRateLimit, name: bucket})
+	}
+	if user != "" {
+		add(rateLimitKey{limitType: madmin.UserRateLimit, name: user})
+	}
+	for _, group := range groups {
+		add(rateLimitKey{limitType: madmin.GroupRateLimit, name: group})
+	}
+	return limiters
+}
+
+// throttle - counts a request throttled by a limiter.
+func (sys *rateLimitSys) throttle(l *rateLimiter, reason string) {
+	sys.mu.Lock()
+	defer sys.mu.Unlock()
+
+	sys.throttled[rateLimitMetric{rateLimitKey: l.rateLimitKey, reason: reason}]++
+}
+
+// throttledRequests - returns the number of requests throttled by each
+// rate limit and reason.
+func (sys *rateLimitSys) throttledRequests() map[rateLimitMetric]uint64 {
+	sys.mu.Lock()
+	defer sys.mu.Unlock()
+
+	throttled := make(map[rateLimitMetric]uint64, len(sys.throttled))
+	for m, v := range sys.throttled {
+		throttled[m] = v
+	}
+	return throttled
+}
+
+// enforce - applies the rate limits of the bucket and of the user making
+// an authenticated request, returns ErrSlowDown when the request rate or
+// the concurrent requests exceed one of them. Rate limits already applied
+// to the request by a previous authorization check are skipped. The body
+// and the response of the request are throttled by the bandwidth limits
+// when the request went through rateLimitHandler.
+func (sys *rateLimitSys) enforce(ctx context.Context, cred auth.Credentials, bucket string) APIErrorCode {
+	if !sys.enabled() {
+		return ErrNone
+	}
+
+	req, _ := ctx.Value(rateLimitRequestKey{}).(*rateLimitRequest)
+	var limiters []*rateLimiter
+	for _, l := range sys.limitersOf(cred, bucket) {
+		if req == nil || !req.applied(l) {
+			limiters = append(limiters, l)
+		}
+	}
+	if len(limiters) == 0 {
+		return ErrNone
+	}
+
+	var reservations []*rate.Reservation
+	cancel := func() {
+		for _, r := range reservations {
+			r.Cancel()
+		}
+	}
+	for _, l := range limiters {
+		if l.requests == nil {
+			continue
+		}
+		r := l.requests.Reserve()
+		if !r.OK() || r.Delay() > 0 {
+			r.Cancel()
+			cancel()
+			sys.throttle(l, rateLimitReasonRate)
+			return ErrSlowDown
+		}
+		reservations = append(reservations, r)
+	}
+
+	// Concurrent requests are only limited when the slots can be
+	// released at the end of the request.
+	if req == nil {
+		return ErrNone
+	}
+	for i, l := range limiters {
+		if !l.acquire() {
+			for _, acquired := range limiters[:i] {
+				acquired.release()
+			}
+			cancel()
+			sys.throttle(l, rateLimitReasonConcurrency)
+			return ErrSlowDown
+		}
+	}
+	req.add(limiters)
+	return ErrNone
+}
+
+type rateLimitRequestKey struct{}
+
+// rateLimitRequest - limiters applied to a request.
+type rateLimitRequest struct {
+	mu       sync.Mutex
+	limiters []*rateLimiter
+}
+
+func (req *rateLimitRequest) applied(l *rateLimiter) bool {
+	req.mu.Lock()
+	defer req.mu.Unlock()
+
+	for _, applied := range req.limiters {
+		if applied == l {
+			return true
+		}
+	}
+	return false
+}
+
+func (req *rateLimitRequest) add(limiters []*rateLimiter) {
+	req.mu.Lock()
+	defer req.mu.Unlock()
+
+	req.limiters = append(req.limiters, limiters...)
+}
+
+// release - releases the concurrent request slots held by the request.
+func (req *rateLimitRequest) release() {
+	req.mu.Lock()
+	defer req.mu.Unlock()
+
+	for _, l := range req.limiters {
+		l.release()
+	}
+	req.limiters = nil
+}
+
+// bandwidth - returns the ingress or egress limiters of the request.
+func (req *rateLimitRequest) bandwidth(ingress bool) []*rate.Limiter {
+	req.mu.Lock()
+	defer req.mu.Unlock()
+
+	var limiters []*rate.Limiter
+	for _, l := range req.limiters {
+		bl := l.egress
+		if ingress {
+			bl = l.ingress
+		}
+		if bl != nil {
+			limiters = append(limiters, bl)
+		}
+	}
+	return limiters
+}
+
+// waitBandwidth - waits until n bytes can be transferred by all limiters.
+func waitBandwidth(ctx context.Context, limiters []*rate.Limiter, n int) error {
+	for _, l := range limiters {
+		if err := l.WaitN(ctx, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// maxBandwidthChunk - returns the largest number of bytes that can be
+// waited for at once on all limiters.
+func maxBandwidthChunk(limiters []*rate.Limiter, n int) int {
+	for _, l := range limiters {
+		if b := l.Burst(); b < n {
+			n = b
+		}
+	}
+	return n
+}
+
+// The bandwidth limits are applied to the body and to the response of the
+// S3 API requests, which carry the data read by PutObjReader and written
+// from GetObjectReader, instead of to the object readers. This throttles
+// all the handlers the same way, including S3 Select and multipart
+// uploads, and does not throttle the reads and writes of the server for
+// itself, e.g. by replication, healing and lifecycle transitions.
+
+// rateLimitReader - throttles the body of a request by the ingress
+// bandwidth limits applied to it.
+type rateLimitReader struct {
+	io.ReadCloser
+	ctx context.Context
+	req *rateLimitRequest
+}
+
+func (r *rateLimitReader) Read(p []byte) (int, error) {
+	limiters := r.req.bandwidth(true)
+	if len(limiters) == 0 {
+		return r.ReadCloser.Read(p)
+	}
+
+	p = p[:maxBandwidthChunk(limiters, len(p))]
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		if werr := waitBandwidth(r.ctx, limiters, n); werr != nil && err == nil {
+			err = werr
+		}
+	}
+	return n, err
+}
+
+// rateLimitWriter - throttles the response of a request by the egress
+// bandwidth limits applied to it.
+type rateLimitWriter struct {
+	http.ResponseWriter
+	ctx context.Context
+	req *rateLimitRequest
+}
+
+func (w *rateLimitWriter) Write(p []byte) (n int, err error) {
+	limiters := w.req.bandwidth(false)
+	if len(limiters) == 0 {
+		return w.ResponseWriter.Write(p)
+	}
+
+	for len(p) > 0 {
+		chunk := p[:maxBandwidthChunk(limiters, len(p))]
+		if err = waitBandwidth(w.ctx, limiters, len(chunk)); err != nil {
+			return n, err
+		}
+		var m int
+		m, err = w.ResponseWriter.Write(chunk)
+		n += m
+		if err != nil {
+			return n, err
+		}
+		p = p[m:]
+	}
+	return n, nil
+}
+
+// Flush - calls the underlying Flush.
+func (w *rateLimitWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack - calls the underlying Hijack, the hijacked connection is not
+// throttled.
+func (w *rateLimitWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+	return nil, nil, errors.New("response writer does not support hijacking")
+}
+
+// CloseNotify - calls the underlying CloseNotify, the returned channel
+// never receives if it is not supported.
+func (w *rateLimitWriter) CloseNotify() <-chan bool {
+	//nolint:staticcheck // http.CloseNotifier is deprecated but may be used by handlers.
+	if notifier, ok := w.ResponseWriter.(http.CloseNotifier); ok {
+		return notifier.CloseNotify()
+	}
+	return make(chan bool)
+}
+
+// rateLimitHandler - tracks the rate limits applied to a request once it
+// is authenticated, see rateLimitSys.enforce, and releases them when the
+// request is done.
+func rateLimitHandler(f http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !globalRateLimitSys.enabled() {
+			f.ServeHTTP(w, r)
+			return
+		}
+
+		req := &rateLimitRequest{}
+		defer req.release()
+
+		ctx := context.WithValue(r.Context(), rateLimitRequestKey{}, req)
+		r = r.WithContext(ctx)
+		if r.Body != nil {
+			r.Body = &rateLimitReader{ReadCloser: r.Body, ctx: ctx, req: req}
+		}
+		f.ServeHTTP(&rateLimitWriter{ResponseWriter: w, ctx: ctx, req: req}, r)
+	}
+}
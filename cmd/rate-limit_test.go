@@ -0,0 +1,177 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/madmin"
+	"golang.org/x/time/rate"
+)
+
+func TestNewRateLimiter(t *testing.T) {
+	l := newRateLimiter(rateLimitKey{madmin.UserRateLimit, "alice"}, madmin.RateLimit{
+		RequestsPerSecond:     10,
+		Burst:                 20,
+		ConcurrentRequests:    3,
+		IngressBytesPerSecond: 4 << 20,
+	}, 4)
+	if l.requests.Limit() != rate.Limit(2.5) || l.requests.Burst() != 5 {
+		t.Fatalf("expected 2.5 requests per second with a burst of 5, got %v and %d", l.requests.Limit(), l.requests.Burst())
+	}
+	// Each server allows at least one concurrent request.
+	if l.maxActive != 1 {
+		t.Fatalf("expected 1 concurrent request, got %d", l.maxActive)
+	}
+	if l.ingress.Limit() != rate.Limit(1<<20) {
+		t.Fatalf("expected 1MiB/s ingress, got %v", l.ingress.Limit())
+	}
+	if l.egress != nil {
+		t.Fatal("expected egress not to be limited")
+	}
+}
+
+func newTestRateLimitSys(limits madmin.RateLimits) *rateLimitSys {
+	sys := newRateLimitSys()
+	sys.set(limits, 1)
+	return sys
+}
+
+func TestRateLimitRequests(t *testing.T) {
+	sys := newTestRateLimitSys(madmin.RateLimits{
+		Buckets: map[string]madmin.RateLimit{"bucket": {RequestsPerSecond: 0.001, Burst: 2}},
+	})
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if s3Err := sys.enforce(ctx, auth.Credentials{}, "bucket"); s3Err != ErrNone {
+			t.Fatalf("request %d: expected no error, got %v", i+1, s3Err)
+		}
+	}
+	if s3Err := sys.enforce(ctx, auth.Credentials{}, "bucket"); s3Err != ErrSlowDown {
+		t.Fatalf("expected %v, got %v", ErrSlowDown, s3Err)
+	}
+	if s3Err := sys.enforce(ctx, auth.Credentials{}, "other-bucket"); s3Err != ErrNone {
+		t.Fatalf("expected other buckets not to be limited, got %v", s3Err)
+	}
+
+	m := rateLimitMetric{rateLimitKey{madmin.BucketRateLimit, "bucket"}, rateLimitReasonRate}
+	if throttled := sys.throttledRequests(); throttled[m] != 1 {
+		t.Fatalf("expected 1 throttled request, got %v", throttled)
+	}
+}
+
+func TestRateLimitConcurrentRequests(t *testing.T) {
+	sys := newTestRateLimitSys(madmin.RateLimits{
+		Users:  map[string]madmin.RateLimit{"alice": {ConcurrentRequests: 1}},
+		Groups: map[string]madmin.RateLimit{"eng": {ConcurrentRequests: 1}},
+	})
+
+	// Temporary credentials count against their parent user.
+	alice := auth.Credentials{AccessKey: "alice-temp", ParentUser: "alice"}
+	bob := auth.Credentials{AccessKey: "bob", Groups: []string{"eng"}}
+
+	req1 := &rateLimitRequest{}
+	ctx1 := context.WithValue(context.Background(), rateLimitRequestKey{}, req1)
+	if s3Err := sys.enforce(ctx1, alice, "bucket"); s3Err != ErrNone {
+		t.Fatalf("expected no error, got %v", s3Err)
+	}
+	// Limits already applied to the request are skipped.
+	if s3Err := sys.enforce(ctx1, alice, "other-bucket"); s3Err != ErrNone {
+		t.Fatalf("expected no error, got %v", s3Err)
+	}
+
+	req2 := &rateLimitRequest{}
+	ctx2 := context.WithValue(context.Background(), rateLimitRequestKey{}, req2)
+	if s3Err := sys.enforce(ctx2, alice, "bucket"); s3Err != ErrSlowDown {
+		t.Fatalf("expected %v, got %v", ErrSlowDown, s3Err)
+	}
+	if s3Err := sys.enforce(ctx2, bob, "bucket"); s3Err != ErrNone {
+		t.Fatalf("expected other users not to be limited, got %v", s3Err)
+	}
+
+	req1.release()
+	if s3Err := sys.enforce(ctx1, alice, "bucket"); s3Err != ErrNone {
+		t.Fatalf("expected no error once released, got %v", s3Err)
+	}
+}
+
+func TestRateLimitBandwidth(t *testing.T) {
+	sys := newTestRateLimitSys(madmin.RateLimits{
+		Buckets: map[string]madmin.RateLimit{"bucket": {EgressBytesPerSecond: 1000}},
+	})
+
+	req := &rateLimitRequest{}
+	ctx := context.WithValue(context.Background(), rateLimitRequestKey{}, req)
+	defer req.release()
+	if s3Err := sys.enforce(ctx, auth.Credentials{}, "bucket"); s3Err != ErrNone {
+		t.Fatalf("expected no error, got %v", s3Err)
+	}
+
+	rec := httptest.NewRecorder()
+	w := &rateLimitWriter{ResponseWriter: rec, ctx: ctx, req: req}
+	data := bytes.Repeat([]byte("a"), 1500)
+
+	start := time.Now()
+	n, err := w.Write(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(data) || !bytes.Equal(rec.Body.Bytes(), data) {
+		t.Fatalf("expected %d bytes to be written, got %d", len(data), n)
+	}
+	// The burst of one second is sent at once, the rest is throttled.
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Fatalf("expected the response to be throttled, took %v", elapsed)
+	}
+}
+
+func TestRateLimitLoad(t *testing.T) {
+	iamSys := newTestIAMSys(t)
+	sys := newRateLimitSys()
+	ctx := context.Background()
+
+	if err := sys.load(ctx, iamSys); err != nil {
+		t.Fatal(err)
+	}
+	if sys.enabled() {
+		t.Fatal("expected no rate limit to be set")
+	}
+
+	if err := iamSys.SetRateLimit(ctx, madmin.BucketRateLimit, "bucket", madmin.RateLimit{RequestsPerSecond: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sys.load(ctx, iamSys); err != nil {
+		t.Fatal(err)
+	}
+	if !sys.enabled() {
+		t.Fatal("expected the rate limit of the bucket to be loaded")
+	}
+
+	// The current limits are kept when they cannot be loaded.
+	if err := sys.load(ctx, NewIAMSys()); err == nil {
+		t.Fatal("expected an error from an uninitialized IAM system")
+	}
+	if !sys.enabled() {
+		t.Fatal("expected the rate limit of the bucket to be kept")
+	}
+}
@@ -470,6 +470,8 @@ func serverMain(ctx *cli.Context) {
 	}
 
 	initDataCrawler(GlobalContext, newObject)
+	initRealtimeUsage(GlobalContext, newObject)
+	initLifecycleActionLog(GlobalContext, newObject)
 
 	if err = initServer(GlobalContext, newObject); err != nil {
 		var cerr config.Err
@@ -134,6 +134,19 @@ func checkKeyValid(accessKey string) (auth.Credentials, bool, APIErrorCode) {
 	return cred, owner, ErrNone
 }
 
+// matchSecretKey - returns the credentials with the secret key which
+// signed a request, during a secret key rotation the previous secret
+// key is tried when the current secret key does not match.
+func matchSecretKey(cred auth.Credentials, match func(secretKey string) bool) (auth.Credentials, bool) {
+	for _, secretKey := range cred.SecretKeys() {
+		if match(secretKey) {
+			cred.SecretKey = secretKey
+			return cred, true
+		}
+	}
+	return cred, false
+}
+
 // sumHMAC calculate hmac between two input byte array.
 func sumHMAC(key []byte, data []byte) []byte {
 	hash := hmac.New(sha256.New, key)
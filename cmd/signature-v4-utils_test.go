@@ -19,8 +19,10 @@ package cmd
 import (
 	"net/http"
 	"testing"
+	"time"
 
 	xhttp "github.com/minio/minio/cmd/http"
+	"github.com/minio/minio/pkg/auth"
 )
 
 // TestSkipContentSha256Cksum - Test validate the logic which decides whether
@@ -272,3 +274,41 @@ func TestGetContentSha256Cksum(t *testing.T) {
 		}
 	}
 }
+
+func TestMatchSecretKey(t *testing.T) {
+	cred := auth.Credentials{
+		AccessKey:                   "myuser",
+		SecretKey:                   "mynewpassword",
+		PreviousSecretKey:           "myoldpassword",
+		PreviousSecretKeyExpiration: UTCNow().Add(time.Hour),
+	}
+
+	testCases := []struct {
+		signedWith string
+		expected   bool
+	}{
+		{"mynewpassword", true},
+		{"myoldpassword", true},
+		{"mypassword", false},
+	}
+
+	for i, testCase := range testCases {
+		matched, ok := matchSecretKey(cred, func(secretKey string) bool {
+			return secretKey == testCase.signedWith
+		})
+		if ok != testCase.expected {
+			t.Fatalf("Test %d: expected match %v, got %v", i+1, testCase.expected, ok)
+		}
+		if ok && matched.SecretKey != testCase.signedWith {
+			t.Fatalf("Test %d: expected secret key %s, got %s", i+1, testCase.signedWith, matched.SecretKey)
+		}
+	}
+
+	// The previous secret key is rejected once the overlap ended.
+	cred.PreviousSecretKeyExpiration = UTCNow().Add(-time.Hour)
+	if _, ok := matchSecretKey(cred, func(secretKey string) bool {
+		return secretKey == "myoldpassword"
+	}); ok {
+		t.Fatal("expected the previous secret key to be rejected")
+	}
+}
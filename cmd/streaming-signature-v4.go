@@ -124,14 +124,19 @@ func calculateSeedSignature(r *http.Request) (cred auth.Credentials, signature s
 	// Get string to sign from canonical request.
 	stringToSign := getStringToSign(canonicalRequest, date, signV4Values.Credential.getScope())
 
-	// Get hmac signing key.
-	signingKey := getSigningKey(cred.SecretKey, signV4Values.Credential.scope.date, region, serviceS3)
-
-	// Calculate signature.
-	newSignature := getSignature(signingKey, stringToSign)
-
-	// Verify if signature match.
-	if !compareSignatureV4(newSignature, signV4Values.Signature) {
+	// Verify if signature match, the chunk signatures are verified
+	// with the same secret key as the seed signature.
+	var newSignature string
+	cred, ok := matchSecretKey(cred, func(secretKey string) bool {
+		// Get hmac signing key.
+		signingKey := getSigningKey(secretKey, signV4Values.Credential.scope.date, region, serviceS3)
+
+		// Calculate signature.
+		newSignature = getSignature(signingKey, stringToSign)
+
+		return compareSignatureV4(newSignature, signV4Values.Signature)
+	})
+	if !ok {
 		return cred, "", "", time.Time{}, ErrSignatureDoesNotMatch
 	}
 
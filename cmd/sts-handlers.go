@@ -184,8 +184,10 @@ func (sts *stsAPIHandlers) AssumeRole(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var sessionPolicy *iampolicy.Policy
 	if len(sessionPolicyStr) > 0 {
-		sessionPolicy, err := iampolicy.ParseConfig(bytes.NewReader([]byte(sessionPolicyStr)))
+		var err error
+		sessionPolicy, err = iampolicy.ParseConfig(bytes.NewReader([]byte(sessionPolicyStr)))
 		if err != nil {
 			writeSTSErrorResponse(ctx, w, true, ErrSTSInvalidParameterValue, err)
 			return
@@ -198,6 +200,12 @@ func (sts *stsAPIHandlers) AssumeRole(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// The session policy must be within the permission boundaries of the user.
+	if err := globalIAMSys.CheckSessionPolicy(user.AccessKey, sessionPolicy); err != nil {
+		writeSTSErrorResponse(ctx, w, true, ErrSTSInvalidParameterValue, err)
+		return
+	}
+
 	var err error
 	m := make(map[string]interface{})
 	m[expClaim], err = openid.GetDefaultExpiration(r.Form.Get(stsDurationSeconds))
@@ -234,6 +242,9 @@ func (sts *stsAPIHandlers) AssumeRole(w http.ResponseWriter, r *http.Request) {
 	// in obtaining service accounts by this cred.
 	cred.ParentUser = user.AccessKey
 
+	cred.SessionSource = assumeRole
+	cred.IssuedAt = UTCNow()
+
 	// Set the newly generated credentials.
 	if err = globalIAMSys.SetTempUser(cred.AccessKey, cred, policyName); err != nil {
 		writeSTSErrorResponse(ctx, w, true, ErrSTSInternalError, err)
@@ -291,18 +302,13 @@ func (sts *stsAPIHandlers) AssumeRoleWithSSO(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	v, err := globalOpenIDValidators.Get("jwt")
-	if err != nil {
-		writeSTSErrorResponse(ctx, w, true, ErrSTSInvalidParameterValue, err)
-		return
-	}
-
 	token := r.Form.Get(stsToken)
 	if token == "" {
 		token = r.Form.Get(stsWebIdentityToken)
 	}
 
-	m, err := v.Validate(token, r.Form.Get(stsDurationSeconds))
+	// The token is validated by the OpenID provider which issued it.
+	v, m, err := globalOpenIDValidators.Validate(token, r.Form.Get(stsDurationSeconds))
 	if err != nil {
 		switch err {
 		case openid.ErrTokenExpired:
@@ -325,17 +331,29 @@ func (sts *stsAPIHandlers) AssumeRoleWithSSO(w http.ResponseWriter, r *http.Requ
 	// This is a MinIO STS API specific value, this value should
 	// be set and configured on your identity provider as part of
 	// JWT custom claims.
-	var policyName string
-	policySet, ok := iampolicy.GetPoliciesFromClaims(m, iamPolicyClaimNameOpenID())
-	if ok {
-		policyName = globalIAMSys.CurrentPolicies(strings.Join(policySet.ToSlice(), ","))
+	policyClaimName := iamPolicyClaimNameOpenID()
+	var groups []string
+	if jwt, ok := v.(*openid.JWT); ok {
+		policyClaimName = jwt.PolicyClaimName()
+		groups = jwt.Groups(m)
 	}
 
+	var policies []string
+	if policySet, ok := iampolicy.GetPoliciesFromClaims(m, policyClaimName); ok {
+		policies = policySet.ToSlice()
+	}
+	// Groups claimed by the token resolve to the policies
+	// mapped to the IAM groups of the same name.
+	policies = append(policies, globalIAMSys.GroupsPolicies(groups)...)
+	policyName := globalIAMSys.CurrentPolicies(strings.Join(policies, ","))
+
 	if policyName == "" && globalPolicyOPA == nil {
 		writeSTSErrorResponse(ctx, w, true, ErrSTSInvalidParameterValue,
-			fmt.Errorf("%s claim missing from the JWT token, credentials will not be generated", iamPolicyClaimNameOpenID()))
+			fmt.Errorf("%s claim missing from the JWT token and no policy is mapped to its groups, credentials will not be generated", policyClaimName))
 		return
 	}
+	// Temporary credentials are always verified against the
+	// policy claim of the default provider.
 	m[iamPolicyClaimNameOpenID()] = policyName
 
 	sessionPolicyStr := r.Form.Get(stsPolicy)
@@ -375,6 +393,10 @@ func (sts *stsAPIHandlers) AssumeRoleWithSSO(w http.ResponseWriter, r *http.Requ
 		subFromToken, _ = v.(string)
 	}
 
+	cred.Groups = groups
+	cred.SessionSource = action
+	cred.IssuedAt = UTCNow()
+
 	// Set the newly generated credentials.
 	if err = globalIAMSys.SetTempUser(cred.AccessKey, cred, policyName); err != nil {
 		writeSTSErrorResponse(ctx, w, true, ErrSTSInternalError, err)
@@ -490,7 +512,15 @@ func (sts *stsAPIHandlers) AssumeRoleWithLDAPIdentity(w http.ResponseWriter, r *
 		}
 	}
 
-	groups, err := globalLDAPConfig.Bind(ldapUsername, ldapPassword)
+	// Groups synced by the LDAP group sync spare searching
+	// the groups of the user.
+	var err error
+	groups, ok := globalLDAPGroupSync.groups(ldapUsername)
+	if ok {
+		err = globalLDAPConfig.Authenticate(ldapUsername, ldapPassword)
+	} else {
+		groups, err = globalLDAPConfig.Bind(ldapUsername, ldapPassword)
+	}
 	if err != nil {
 		err = fmt.Errorf("LDAP server connection failure: %w", err)
 		writeSTSErrorResponse(ctx, w, true, ErrSTSInvalidParameterValue, err)
@@ -522,6 +552,9 @@ func (sts *stsAPIHandlers) AssumeRoleWithLDAPIdentity(w http.ResponseWriter, r *
 	// of large number of groups
 	cred.Groups = groups
 
+	cred.SessionSource = ldapIdentity
+	cred.IssuedAt = UTCNow()
+
 	// Set the newly generated credentials, policyName is empty on purpose
 	// LDAP policies are applied automatically using their ldapUser, ldapGroups
 	// mapping.
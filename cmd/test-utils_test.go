@@ -196,6 +196,22 @@ func prepareFS() (ObjectLayer, string, error) {
 	return obj, fsDirs[0], nil
 }
 
+// setTestFilesystemPath - sets the directory the FS backend stores the
+// data of buckets in to a temporary directory for the duration of a test,
+// FS buckets cannot be created without it.
+func setTestFilesystemPath(t *testing.T) {
+	dir, err := ioutil.TempDir(globalTestTmpDir, "minio-fs-data-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := globalDefaultFilesystemPath
+	globalDefaultFilesystemPath = dir
+	t.Cleanup(func() {
+		globalDefaultFilesystemPath = prev
+		os.RemoveAll(dir)
+	})
+}
+
 func prepareErasureSets32(ctx context.Context) (ObjectLayer, []string, error) {
 	return prepareErasure(ctx, 32)
 }
@@ -93,6 +93,13 @@ var errNoSuchPolicy = errors.New("Specified canned policy does not exist")
 // error returned in IAM subsystem when an external users systems is configured.
 var errIAMActionNotAllowed = errors.New("Specified IAM action is not allowed with LDAP configuration")
 
+// error returned in IAM subsystem when a session policy allows more than
+// the permission boundary of the user.
+var errSessionPolicyExceedsBoundary = errors.New("Session policy is not within the permission boundary of the user")
+
+// error returned in IAM subsystem when an IAM archive cannot be imported.
+var errInvalidIAMArchive = errors.New("Invalid IAM archive")
+
 // error returned in IAM subsystem when IAM sub-system is still being initialized.
 var errIAMNotInitialized = errors.New("IAM sub-system is being initialized, please try again")
 
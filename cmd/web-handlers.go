@@ -154,6 +154,7 @@ func (web *webAPIHandlers) StorageInfo(r *http.Request, args *WebGenericArgs, re
 		return toJSONError(ctx, authErr)
 	}
 	dataUsageInfo, _ := loadDataUsageFromBackend(ctx, objectAPI)
+	dataUsageInfo = globalRealtimeUsage.dataUsageInfo(dataUsageInfo)
 	reply.Used = dataUsageInfo.ObjectsTotalSize
 	reply.UIVersion = browser.UIVersion
 	return nil
@@ -749,8 +750,12 @@ next:
 			if _, err := globalBucketMetadataSys.GetLifecycleConfig(args.BucketName); err == nil {
 				hasLifecycleConfig = true
 			}
-			if hasReplicationRules(ctx, args.BucketName, []ObjectToDelete{{ObjectName: objectName}}) || hasLifecycleConfig {
+			trackUsage := globalBucketQuotaSys.tracksUsage(args.BucketName)
+			replicateDeletes := hasReplicationRules(ctx, args.BucketName, []ObjectToDelete{{ObjectName: objectName}})
+			if replicateDeletes || hasLifecycleConfig || trackUsage {
 				goi, gerr = getObjectInfoFn(ctx, args.BucketName, objectName, opts)
+			}
+			if replicateDeletes {
 				if _, replicateDel = checkReplicateDelete(ctx, args.BucketName, ObjectToDelete{ObjectName: objectName}, goi, gerr); replicateDel {
 					opts.DeleteMarkerReplicationStatus = string(replication.Pending)
 					opts.DeleteMarker = true
@@ -758,6 +763,9 @@ next:
 			}
 
 			oi, err := deleteObject(ctx, objectAPI, web.CacheAPI(), args.BucketName, objectName, nil, r, opts)
+			if trackUsage && err == nil && gerr == nil && !opts.Versioned {
+				globalBucketQuotaSys.objectDeleted(ctx, args.BucketName, goi)
+			}
 			if replicateDel && err == nil {
 				globalReplicationState.queueReplicaDeleteTask(DeletedObjectVersionInfo{
 					DeletedObject: DeletedObject{
@@ -779,6 +787,7 @@ next:
 				deleteTransitionedObject(ctx, newObjectLayerFn(), args.BucketName, objectName, lifecycle.ObjectOpts{
 					Name:         objectName,
 					UserTags:     goi.UserTags,
+					Size:         goi.Size,
 					VersionID:    goi.VersionID,
 					DeleteMarker: goi.DeleteMarker,
 					IsLatest:     goi.IsLatest,
@@ -824,6 +833,7 @@ next:
 
 		for {
 			var objects []ObjectToDelete
+			var objInfos []ObjectInfo
 			for obj := range objInfoCh {
 				if len(objects) == maxDeleteList {
 					// Reached maximum delete requests, attempt a delete for now.
@@ -863,6 +873,7 @@ next:
 				}
 
 				objects = append(objects, objToDel)
+				objInfos = append(objInfos, obj)
 			}
 
 			// Nothing to do.
@@ -881,6 +892,9 @@ next:
 					logger.LogIf(ctx, err)
 					break next
 				}
+				if !opts.Versioned {
+					globalBucketQuotaSys.objectDeleted(ctx, args.BucketName, objInfos[i])
+				}
 			}
 			// Notify deleted event for objects.
 			for _, dobj := range deletedObjects {
@@ -1183,7 +1197,12 @@ func (web *webAPIHandlers) Upload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := enforceBucketQuota(ctx, bucket, size); err != nil {
+	if authErr == nil {
+		// The object counts against the quotas of the authenticated user.
+		logger.GetReqInfo(ctx).AccessKey = claims.AccessKey
+	}
+
+	if err := enforceBucketQuota(ctx, bucket, object, size); err != nil {
 		writeWebErrorResponse(w, err)
 		return
 	}
@@ -1281,11 +1300,14 @@ func (web *webAPIHandlers) Upload(w http.ResponseWriter, r *http.Request) {
 		opts.UserDefined[xhttp.AmzObjectLockRetainUntilDate] = retentionDate.UTC().Format(iso8601TimeFormat)
 	}
 
+	qw := globalBucketQuotaSys.beginWrite(ctx, bucket, object, opts.UserDefined, &opts)
+
 	objInfo, err := putObject(GlobalContext, bucket, object, pReader, opts)
 	if err != nil {
 		writeWebErrorResponse(w, err)
 		return
 	}
+	qw.done(ctx, objInfo)
 	if objectAPI.IsEncryptionSupported() {
 		if crypto.IsEncrypted(objInfo.UserDefined) {
 			switch {
@@ -2352,6 +2374,8 @@ func toWebAPIError(ctx context.Context, err error) APIError {
 		return getAPIError(ErrStorageFull)
 	case BucketQuotaExceeded:
 		return getAPIError(ErrAdminBucketQuotaExceeded)
+	case UserQuotaExceeded:
+		return getAPIError(ErrAdminUserQuotaExceeded)
 	case BucketNotFound:
 		return getAPIError(ErrNoSuchBucket)
 	case BucketNotEmpty:
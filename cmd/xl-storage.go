@@ -382,16 +382,20 @@ func (s *xlStorage) CrawlAndGetDataUsage(ctx context.Context, cache dataUsageCac
 		var numVersions = len(fivs.Versions)
 
 		sizeS := sizeSummary{}
+		// Versions are sorted newest first.
+		var noncurrent noncurrentVersionCounter
 		for i, version := range fivs.Versions {
 			var successorModTime time.Time
 			if i > 0 {
 				successorModTime = fivs.Versions[i-1].ModTime
 			}
+			newerNoncurrentVersions := noncurrent.next(version.Deleted)
 			oi := version.ToObjectInfo(item.bucket, item.objectPath())
 			size := item.applyActions(ctx, objAPI, actionMeta{
-				numVersions:      numVersions,
-				successorModTime: successorModTime,
-				oi:               oi,
+				numVersions:             numVersions,
+				successorModTime:        successorModTime,
+				newerNoncurrentVersions: newerNoncurrentVersions,
+				oi:                      oi,
 			})
 			if !version.Deleted {
 				// Bitrot check local data
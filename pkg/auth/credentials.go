@@ -92,6 +92,31 @@ type Credentials struct {
 	Status       string    `xml:"-" json:"status,omitempty"`
 	ParentUser   string    `xml:"-" json:"parentUser,omitempty"`
 	Groups       []string  `xml:"-" json:"groups,omitempty"`
+
+	// KeyExpiration is the optional expiry of the access key of a
+	// user or service account, unlike Expiration of temporary
+	// credentials an expired access key is disabled, not removed.
+	KeyExpiration time.Time `xml:"-" json:"keyExpiration,omitempty"`
+
+	// PreviousSecretKey is the secret key replaced by the last
+	// rotation, it remains valid until PreviousSecretKeyExpiration.
+	PreviousSecretKey           string    `xml:"-" json:"previousSecretKey,omitempty"`
+	PreviousSecretKeyExpiration time.Time `xml:"-" json:"previousSecretKeyExpiration,omitempty"`
+
+	// Tags of a user, service accounts and temporary credentials
+	// have the tags of their parent user.
+	Tags map[string]string `xml:"-" json:"tags,omitempty"`
+
+	// PermissionBoundary is the name of the policy capping the
+	// permissions of a user, of its service accounts and of its
+	// temporary credentials.
+	PermissionBoundary string `xml:"-" json:"permissionBoundary,omitempty"`
+
+	// SessionSource is the STS API which issued temporary
+	// credentials e.g. "AssumeRoleWithWebIdentity".
+	SessionSource string `xml:"-" json:"sessionSource,omitempty"`
+	// IssuedAt is the time temporary credentials were issued.
+	IssuedAt time.Time `xml:"-" json:"issuedAt,omitempty"`
 }
 
 func (cred Credentials) String() string {
@@ -119,6 +144,26 @@ func (cred Credentials) IsExpired() bool {
 	return cred.Expiration.Before(time.Now().UTC())
 }
 
+// IsKeyExpired - returns whether the access key of a user or service
+// account has expired.
+func (cred Credentials) IsKeyExpired() bool {
+	if cred.KeyExpiration.IsZero() {
+		return false
+	}
+	return cred.KeyExpiration.Before(time.Now().UTC())
+}
+
+// SecretKeys - returns the secret keys which can be used to sign
+// requests, the previous secret key of a rotated credential is
+// returned until the rotation overlap window ends.
+func (cred Credentials) SecretKeys() []string {
+	secretKeys := []string{cred.SecretKey}
+	if cred.PreviousSecretKey != "" && cred.PreviousSecretKeyExpiration.After(time.Now().UTC()) {
+		secretKeys = append(secretKeys, cred.PreviousSecretKey)
+	}
+	return secretKeys
+}
+
 // IsTemp - returns whether credential is temporary or not.
 func (cred Credentials) IsTemp() bool {
 	return cred.SessionToken != "" && !cred.Expiration.IsZero() && !cred.Expiration.Equal(timeSentinel)
@@ -135,7 +180,7 @@ func (cred Credentials) IsValid() bool {
 	if cred.Status == "off" {
 		return false
 	}
-	return IsAccessKeyValid(cred.AccessKey) && IsSecretKeyValid(cred.SecretKey) && !cred.IsExpired()
+	return IsAccessKeyValid(cred.AccessKey) && IsSecretKeyValid(cred.SecretKey) && !cred.IsExpired() && !cred.IsKeyExpired()
 }
 
 // Equal - returns whether two credentials are equal or not.
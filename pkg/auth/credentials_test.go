@@ -179,3 +179,59 @@ func TestCredentialsEqual(t *testing.T) {
 		}
 	}
 }
+
+func TestCredentialsKeyExpiration(t *testing.T) {
+	cred, err := GetNewCredentials()
+	if err != nil {
+		t.Fatalf("Failed to get a new credential")
+	}
+
+	testCases := []struct {
+		expiration  time.Time
+		expired     bool
+		serviceAcct bool
+	}{
+		{time.Time{}, false, true},
+		{time.Now().UTC().Add(time.Hour), false, true},
+		{time.Now().UTC().Add(-time.Hour), true, true},
+	}
+
+	for i, testCase := range testCases {
+		c := cred
+		c.ParentUser = "parent"
+		c.KeyExpiration = testCase.expiration
+		if c.IsKeyExpired() != testCase.expired {
+			t.Fatalf("test %v: expected expired %v, got %v", i+1, testCase.expired, c.IsKeyExpired())
+		}
+		if c.IsValid() == testCase.expired {
+			t.Fatalf("test %v: expected valid %v, got %v", i+1, !testCase.expired, c.IsValid())
+		}
+		if c.IsServiceAccount() != testCase.serviceAcct {
+			t.Fatalf("test %v: expected service account %v, got %v", i+1, testCase.serviceAcct, c.IsServiceAccount())
+		}
+		if c.IsTemp() {
+			t.Fatalf("test %v: expected long term credentials", i+1)
+		}
+	}
+}
+
+func TestCredentialsSecretKeys(t *testing.T) {
+	cred := Credentials{
+		AccessKey: "myuser",
+		SecretKey: "mypassword",
+	}
+	if keys := cred.SecretKeys(); len(keys) != 1 || keys[0] != "mypassword" {
+		t.Fatalf("expected only the current secret key, got %v", keys)
+	}
+
+	cred.PreviousSecretKey = "myoldpassword"
+	cred.PreviousSecretKeyExpiration = time.Now().UTC().Add(time.Hour)
+	if keys := cred.SecretKeys(); len(keys) != 2 || keys[1] != "myoldpassword" {
+		t.Fatalf("expected current and previous secret keys, got %v", keys)
+	}
+
+	cred.PreviousSecretKeyExpiration = time.Now().UTC().Add(-time.Hour)
+	if keys := cred.SecretKeys(); len(keys) != 1 {
+		t.Fatalf("expected the previous secret key to be expired, got %v", keys)
+	}
+}
@@ -35,6 +35,13 @@ type bucketMeasurement struct {
 	expMovingAvg         float64   // Previously calculate sliding window
 }
 
+// targetMeasurement captures the bandwidth details for one remote target of a bucket
+type targetMeasurement struct {
+	*bucketMeasurement
+	bucket           string // Bucket replicating to the target
+	clusterBandwidth int64  // Cluster wide bandwidth limit of the target
+}
+
 // newBucketMeasurement creates a new instance of the measurement with the initial start time.
 func newBucketMeasurement(initTime time.Time) *bucketMeasurement {
 	return &bucketMeasurement{
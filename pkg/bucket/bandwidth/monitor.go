@@ -65,6 +65,8 @@ type Monitor struct {
 
 	activeBuckets map[string]*bucketMeasurement // Buckets with objects in flight
 
+	activeTargets map[string]*targetMeasurement // Remote targets with objects in flight
+
 	bucketMovingAvgTicker *time.Ticker // Ticker for calculating moving averages
 
 	pubsub *pubsub.PubSub // PubSub for reporting bandwidths.
@@ -80,6 +82,7 @@ type Monitor struct {
 func NewMonitor(doneCh <-chan struct{}) *Monitor {
 	m := &Monitor{
 		activeBuckets:         make(map[string]*bucketMeasurement),
+		activeTargets:         make(map[string]*targetMeasurement),
 		bucketMovingAvgTicker: time.NewTicker(2 * time.Second),
 		pubsub:                pubsub.New(),
 		bucketThrottle:        make(map[string]*throttle),
@@ -131,6 +134,21 @@ func (m *Monitor) getReport(selectBucket SelectionFunction) *bandwidth.Report {
 	return report
 }
 
+// GetTargetReport gets the bandwidth details of the remote target, the
+// limit reported is the cluster wide limit of the target.
+func (m *Monitor) GetTargetReport(arn string) (bandwidth.Details, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	t, ok := m.activeTargets[arn]
+	if !ok {
+		return bandwidth.Details{}, false
+	}
+	return bandwidth.Details{
+		LimitInBytesPerSecond:            t.clusterBandwidth,
+		CurrentBandwidthInBytesPerSecond: t.getExpMovingAvgBytesPerSecond(),
+	}, true
+}
+
 func (m *Monitor) process(doneCh <-chan struct{}) {
 	for {
 		select {
@@ -157,6 +175,9 @@ func (m *Monitor) processAvg() {
 	for _, bucketMeasurement := range m.activeBuckets {
 		bucketMeasurement.updateExponentialMovingAverage(time.Now())
 	}
+	for _, targetMeasurement := range m.activeTargets {
+		targetMeasurement.updateExponentialMovingAverage(time.Now())
+	}
 	m.pubsub.Publish(m.getReport(SelectBuckets()))
 }
 
@@ -171,10 +192,31 @@ func (m *Monitor) track(bucket string, object string, timeNow time.Time) *bucket
 	return b
 }
 
+// trackTarget returns the measurement object for the remote target of bucket
+func (m *Monitor) trackTarget(bucket string, arn string, clusterBandwidth int64, timeNow time.Time) *targetMeasurement {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	t, ok := m.activeTargets[arn]
+	if !ok {
+		t = &targetMeasurement{
+			bucketMeasurement: newBucketMeasurement(timeNow),
+			bucket:            bucket,
+		}
+		m.activeTargets[arn] = t
+	}
+	t.clusterBandwidth = clusterBandwidth
+	return t
+}
+
 // DeleteBucket deletes monitoring the 'bucket'
 func (m *Monitor) DeleteBucket(bucket string) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 	delete(m.activeBuckets, bucket)
 	delete(m.bucketThrottle, bucket)
+	for arn, t := range m.activeTargets {
+		if t.bucket == bucket {
+			delete(m.activeTargets, arn)
+		}
+	}
 }
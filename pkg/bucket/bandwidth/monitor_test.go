@@ -156,3 +156,22 @@ func TestMonitor_GetReport(t *testing.T) {
 		})
 	}
 }
+
+func TestMonitor_GetTargetReport(t *testing.T) {
+	m := NewMonitor(make(chan struct{}))
+	start := time.Now()
+	target := m.trackTarget("bucket", "arn", 2*int64(oneMiB), start)
+	target.incrementBytes(oneMiB)
+	target.updateExponentialMovingAverage(start.Add(time.Second))
+
+	got, ok := m.GetTargetReport("arn")
+	want := bandwidth.Details{LimitInBytesPerSecond: 2 * int64(oneMiB), CurrentBandwidthInBytesPerSecond: float64(oneMiB)}
+	if !ok || !reflect.DeepEqual(got, want) {
+		t.Errorf("GetTargetReport() = %v, want %v", got, want)
+	}
+
+	m.DeleteBucket("bucket")
+	if _, ok = m.GetTargetReport("arn"); ok {
+		t.Error("GetTargetReport() expected no report after the bucket was deleted")
+	}
+}
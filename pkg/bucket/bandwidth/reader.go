@@ -27,6 +27,7 @@ import (
 type MonitoredReader struct {
 	bucket            string             // Token to track bucket
 	bucketMeasurement *bucketMeasurement // bucket measurement object
+	targetMeasurement *targetMeasurement // remote target measurement object
 	object            string             // Token to track object
 	reader            io.Reader          // Reader to wrap
 	lastStop          time.Time          // Last timestamp for a measurement
@@ -37,13 +38,15 @@ type MonitoredReader struct {
 }
 
 // NewMonitoredReader returns a io.ReadCloser that reports bandwidth details
-func NewMonitoredReader(ctx context.Context, monitor *Monitor, bucket string, object string, reader io.Reader, headerSize int, bandwidthBytesPerSecond int64, clusterBandwidth int64) *MonitoredReader {
+// of the bucket and of the remote target identified by arn
+func NewMonitoredReader(ctx context.Context, monitor *Monitor, bucket string, arn string, object string, reader io.Reader, headerSize int, bandwidthBytesPerSecond int64, clusterBandwidth int64) *MonitoredReader {
 	timeNow := time.Now()
 	b := monitor.track(bucket, object, timeNow)
 	return &MonitoredReader{
 		bucket:            bucket,
 		object:            object,
 		bucketMeasurement: b,
+		targetMeasurement: monitor.trackTarget(bucket, arn, clusterBandwidth, timeNow),
 		reader:            reader,
 		lastStop:          timeNow,
 		headerSize:        headerSize,
@@ -65,6 +68,7 @@ func (m *MonitoredReader) Read(p []byte) (n int, err error) {
 	update := uint64(n + m.headerSize)
 
 	m.bucketMeasurement.incrementBytes(update)
+	m.targetMeasurement.incrementBytes(update)
 	m.lastStop = stop
 	unused := len(p) - (n + m.headerSize)
 	m.headerSize = 0 // Set to 0 post first read
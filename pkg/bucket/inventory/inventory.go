@@ -0,0 +1,352 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inventory
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"time"
+)
+
+// Format - file format of an inventory report.
+type Format string
+
+// Supported inventory report formats.
+const (
+	CSVFormat     Format = "CSV"
+	ParquetFormat Format = "Parquet"
+	ORCFormat     Format = "ORC"
+)
+
+// Frequency - how often an inventory report is generated.
+type Frequency string
+
+// Supported inventory schedules.
+const (
+	Daily  Frequency = "Daily"
+	Weekly Frequency = "Weekly"
+)
+
+// IncludedVersions - which object versions are listed in an inventory report.
+type IncludedVersions string
+
+// Supported values of IncludedObjectVersions.
+const (
+	AllVersions     IncludedVersions = "All"
+	CurrentVersions IncludedVersions = "Current"
+)
+
+// Field - an optional field which can be included in an inventory report.
+type Field string
+
+// Supported optional fields.
+const (
+	SizeField                      Field = "Size"
+	LastModifiedDateField          Field = "LastModifiedDate"
+	StorageClassField              Field = "StorageClass"
+	ETagField                      Field = "ETag"
+	IsMultipartUploadedField       Field = "IsMultipartUploaded"
+	ReplicationStatusField         Field = "ReplicationStatus"
+	EncryptionStatusField          Field = "EncryptionStatus"
+	ObjectLockRetainUntilDateField Field = "ObjectLockRetainUntilDate"
+	ObjectLockModeField            Field = "ObjectLockMode"
+	ObjectLockLegalHoldStatusField Field = "ObjectLockLegalHoldStatus"
+)
+
+// Columns which are always present in an inventory report.
+const (
+	BucketColumn         = "Bucket"
+	KeyColumn            = "Key"
+	VersionIDColumn      = "VersionId"
+	IsLatestColumn       = "IsLatest"
+	IsDeleteMarkerColumn = "IsDeleteMarker"
+)
+
+// supportedFields lists optional fields in the order
+// in which they appear in the report.
+var supportedFields = []Field{
+	SizeField,
+	LastModifiedDateField,
+	ETagField,
+	StorageClassField,
+	IsMultipartUploadedField,
+	ReplicationStatusField,
+	EncryptionStatusField,
+	ObjectLockRetainUntilDateField,
+	ObjectLockModeField,
+	ObjectLockLegalHoldStatusField,
+}
+
+// destinationARNPrefix - prefix of a destination bucket ARN.
+const destinationARNPrefix = "arn:aws:s3:::"
+
+// Maximum 1MiB size per inventory config.
+const maxInventoryConfigSize = 1 << 20
+
+// Maximum number of inventory configurations per bucket.
+const maxInventoryConfigs = 1000
+
+// Maximum length of an inventory configuration ID.
+const maxInventoryIDLength = 64
+
+var (
+	errInventoryIDMissing           = Errorf("Inventory configuration must have an Id")
+	errInventoryIDTooLong           = Errorf("Inventory configuration Id must not exceed 64 characters")
+	errInventoryDestinationInvalid  = Errorf("Destination bucket must be specified as an ARN of the form arn:aws:s3:::bucket")
+	errInventoryFormatInvalid       = Errorf("Inventory format must be one of CSV or Parquet")
+	errInventoryFormatORC           = Errorf("ORC inventory format is not supported")
+	errInventoryEncryption          = Errorf("Encryption of inventory reports is not supported")
+	errInventoryVersionsInvalid     = Errorf("IncludedObjectVersions must be one of All or Current")
+	errInventoryFrequencyInvalid    = Errorf("Schedule frequency must be one of Daily or Weekly")
+	errInventoryTooManyConfigs      = Errorf("A bucket allows a maximum of 1000 inventory configurations")
+	errInventoryDuplicateField      = Errorf("Inventory configuration has duplicate optional fields")
+	errInventoryUnsupportedFieldFmt = "Unsupported inventory optional field %s"
+)
+
+// Filter - restricts an inventory report to objects under a prefix.
+type Filter struct {
+	Prefix string `xml:"Prefix"`
+}
+
+// Encryption - server side encryption of inventory reports.
+type Encryption struct {
+	SSES3  *struct{} `xml:"SSE-S3,omitempty"`
+	SSEKMS *struct {
+		KeyID string `xml:"KeyId"`
+	} `xml:"SSE-KMS,omitempty"`
+}
+
+// S3BucketDestination - bucket where inventory reports are written.
+type S3BucketDestination struct {
+	AccountID  string      `xml:"AccountId,omitempty"`
+	Bucket     string      `xml:"Bucket"`
+	Format     Format      `xml:"Format"`
+	Prefix     string      `xml:"Prefix,omitempty"`
+	Encryption *Encryption `xml:"Encryption,omitempty"`
+}
+
+// Destination - wraps the destination bucket of an inventory report.
+type Destination struct {
+	S3BucketDestination S3BucketDestination `xml:"S3BucketDestination"`
+}
+
+// Schedule - how often an inventory report is generated.
+type Schedule struct {
+	Frequency Frequency `xml:"Frequency"`
+}
+
+// OptionalFields - optional fields included in an inventory report.
+type OptionalFields struct {
+	Fields []Field `xml:"Field"`
+}
+
+// Config - inventory configuration specified in
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_InventoryConfiguration.html
+type Config struct {
+	XMLName                xml.Name         `xml:"InventoryConfiguration"`
+	ID                     string           `xml:"Id"`
+	IsEnabled              bool             `xml:"IsEnabled"`
+	Destination            Destination      `xml:"Destination"`
+	Filter                 *Filter          `xml:"Filter,omitempty"`
+	IncludedObjectVersions IncludedVersions `xml:"IncludedObjectVersions"`
+	OptionalFields         *OptionalFields  `xml:"OptionalFields,omitempty"`
+	Schedule               Schedule         `xml:"Schedule"`
+}
+
+// ParseConfig parses InventoryConfiguration from xml
+func ParseConfig(reader io.Reader) (*Config, error) {
+	config := Config{}
+	if err := xml.NewDecoder(io.LimitReader(reader, maxInventoryConfigSize)).Decode(&config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// Validate - validates the inventory configuration
+func (c Config) Validate() error {
+	if c.ID == "" {
+		return errInventoryIDMissing
+	}
+	if len(c.ID) > maxInventoryIDLength {
+		return errInventoryIDTooLong
+	}
+
+	dst := c.Destination.S3BucketDestination
+	if !strings.HasPrefix(dst.Bucket, destinationARNPrefix) || c.DestinationBucket() == "" {
+		return errInventoryDestinationInvalid
+	}
+	switch dst.Format {
+	case CSVFormat, ParquetFormat:
+	case ORCFormat:
+		return errInventoryFormatORC
+	default:
+		return errInventoryFormatInvalid
+	}
+	if dst.Encryption != nil {
+		return errInventoryEncryption
+	}
+
+	switch c.IncludedObjectVersions {
+	case AllVersions, CurrentVersions:
+	default:
+		return errInventoryVersionsInvalid
+	}
+
+	switch c.Schedule.Frequency {
+	case Daily, Weekly:
+	default:
+		return errInventoryFrequencyInvalid
+	}
+
+	if c.OptionalFields != nil {
+		seen := make(map[Field]struct{}, len(c.OptionalFields.Fields))
+		for _, f := range c.OptionalFields.Fields {
+			if !isSupportedField(f) {
+				return Errorf(errInventoryUnsupportedFieldFmt, f)
+			}
+			if _, ok := seen[f]; ok {
+				return errInventoryDuplicateField
+			}
+			seen[f] = struct{}{}
+		}
+	}
+	return nil
+}
+
+func isSupportedField(f Field) bool {
+	for _, sf := range supportedFields {
+		if sf == f {
+			return true
+		}
+	}
+	return false
+}
+
+// DestinationBucket returns the name of the bucket where reports are written.
+func (c Config) DestinationBucket() string {
+	return strings.TrimPrefix(c.Destination.S3BucketDestination.Bucket, destinationARNPrefix)
+}
+
+// Prefix returns the object name prefix the report is restricted to.
+func (c Config) Prefix() string {
+	if c.Filter == nil {
+		return ""
+	}
+	return c.Filter.Prefix
+}
+
+// HasField returns true if the optional field is included in the report.
+func (c Config) HasField(f Field) bool {
+	if c.OptionalFields == nil {
+		return false
+	}
+	for _, of := range c.OptionalFields.Fields {
+		if of == f {
+			return true
+		}
+	}
+	return false
+}
+
+// Fields returns the optional fields of the report in report order.
+func (c Config) Fields() []Field {
+	var fields []Field
+	for _, f := range supportedFields {
+		if c.HasField(f) {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// Columns returns the names of all columns of the report in order.
+func (c Config) Columns() []string {
+	columns := []string{BucketColumn, KeyColumn}
+	if c.IncludedObjectVersions == AllVersions {
+		columns = append(columns, VersionIDColumn, IsLatestColumn, IsDeleteMarkerColumn)
+	}
+	for _, f := range c.Fields() {
+		columns = append(columns, string(f))
+	}
+	return columns
+}
+
+// Interval returns the time between two consecutive reports.
+func (c Config) Interval() time.Duration {
+	if c.Schedule.Frequency == Weekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// Configs - all inventory configurations of a bucket,
+// as persisted in the bucket metadata.
+type Configs struct {
+	XMLName xml.Name `xml:"InventoryConfigurations"`
+	Configs []Config `xml:"InventoryConfiguration"`
+}
+
+// ParseConfigs parses all inventory configurations of a bucket from xml
+func ParseConfigs(reader io.Reader) (*Configs, error) {
+	configs := Configs{}
+	if err := xml.NewDecoder(reader).Decode(&configs); err != nil {
+		return nil, err
+	}
+	return &configs, nil
+}
+
+// Get returns the inventory configuration with the given id.
+func (c Configs) Get(id string) (Config, bool) {
+	for _, config := range c.Configs {
+		if config.ID == id {
+			return config, true
+		}
+	}
+	return Config{}, false
+}
+
+// Put returns a copy of the configurations with config added,
+// replacing any existing configuration with the same id.
+func (c Configs) Put(config Config) (*Configs, error) {
+	configs := &Configs{Configs: make([]Config, 0, len(c.Configs)+1)}
+	for _, existing := range c.Configs {
+		if existing.ID != config.ID {
+			configs.Configs = append(configs.Configs, existing)
+		}
+	}
+	if len(configs.Configs) >= maxInventoryConfigs {
+		return nil, errInventoryTooManyConfigs
+	}
+	configs.Configs = append(configs.Configs, config)
+	return configs, nil
+}
+
+// Remove returns a copy of the configurations without the
+// configuration with the given id, and whether it was found.
+func (c Configs) Remove(id string) (*Configs, bool) {
+	configs := &Configs{Configs: make([]Config, 0, len(c.Configs))}
+	var found bool
+	for _, existing := range c.Configs {
+		if existing.ID == id {
+			found = true
+			continue
+		}
+		configs.Configs = append(configs.Configs, existing)
+	}
+	return configs, found
+}
@@ -0,0 +1,169 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inventory
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseAndValidateInventoryConfig(t *testing.T) {
+	testCases := []struct {
+		inputConfig string
+		expectedErr error
+	}{
+		{ // Valid CSV inventory configuration
+			inputConfig: `<InventoryConfiguration><Id>report1</Id><IsEnabled>true</IsEnabled><Destination><S3BucketDestination><Bucket>arn:aws:s3:::destbucket</Bucket><Format>CSV</Format><Prefix>reports</Prefix></S3BucketDestination></Destination><Filter><Prefix>data/</Prefix></Filter><IncludedObjectVersions>All</IncludedObjectVersions><OptionalFields><Field>Size</Field><Field>ETag</Field></OptionalFields><Schedule><Frequency>Daily</Frequency></Schedule></InventoryConfiguration>`,
+			expectedErr: nil,
+		},
+		{ // Valid Parquet inventory configuration without optional elements
+			inputConfig: `<InventoryConfiguration><Id>report1</Id><IsEnabled>true</IsEnabled><Destination><S3BucketDestination><Bucket>arn:aws:s3:::destbucket</Bucket><Format>Parquet</Format></S3BucketDestination></Destination><IncludedObjectVersions>Current</IncludedObjectVersions><Schedule><Frequency>Weekly</Frequency></Schedule></InventoryConfiguration>`,
+			expectedErr: nil,
+		},
+		{ // Missing Id
+			inputConfig: `<InventoryConfiguration><IsEnabled>true</IsEnabled><Destination><S3BucketDestination><Bucket>arn:aws:s3:::destbucket</Bucket><Format>CSV</Format></S3BucketDestination></Destination><IncludedObjectVersions>All</IncludedObjectVersions><Schedule><Frequency>Daily</Frequency></Schedule></InventoryConfiguration>`,
+			expectedErr: errInventoryIDMissing,
+		},
+		{ // Destination is not an ARN
+			inputConfig: `<InventoryConfiguration><Id>report1</Id><IsEnabled>true</IsEnabled><Destination><S3BucketDestination><Bucket>destbucket</Bucket><Format>CSV</Format></S3BucketDestination></Destination><IncludedObjectVersions>All</IncludedObjectVersions><Schedule><Frequency>Daily</Frequency></Schedule></InventoryConfiguration>`,
+			expectedErr: errInventoryDestinationInvalid,
+		},
+		{ // ORC format
+			inputConfig: `<InventoryConfiguration><Id>report1</Id><IsEnabled>true</IsEnabled><Destination><S3BucketDestination><Bucket>arn:aws:s3:::destbucket</Bucket><Format>ORC</Format></S3BucketDestination></Destination><IncludedObjectVersions>All</IncludedObjectVersions><Schedule><Frequency>Daily</Frequency></Schedule></InventoryConfiguration>`,
+			expectedErr: errInventoryFormatORC,
+		},
+		{ // Encrypted reports
+			inputConfig: `<InventoryConfiguration><Id>report1</Id><IsEnabled>true</IsEnabled><Destination><S3BucketDestination><Bucket>arn:aws:s3:::destbucket</Bucket><Format>CSV</Format><Encryption><SSE-S3></SSE-S3></Encryption></S3BucketDestination></Destination><IncludedObjectVersions>All</IncludedObjectVersions><Schedule><Frequency>Daily</Frequency></Schedule></InventoryConfiguration>`,
+			expectedErr: errInventoryEncryption,
+		},
+		{ // Invalid IncludedObjectVersions
+			inputConfig: `<InventoryConfiguration><Id>report1</Id><IsEnabled>true</IsEnabled><Destination><S3BucketDestination><Bucket>arn:aws:s3:::destbucket</Bucket><Format>CSV</Format></S3BucketDestination></Destination><IncludedObjectVersions>Some</IncludedObjectVersions><Schedule><Frequency>Daily</Frequency></Schedule></InventoryConfiguration>`,
+			expectedErr: errInventoryVersionsInvalid,
+		},
+		{ // Invalid schedule
+			inputConfig: `<InventoryConfiguration><Id>report1</Id><IsEnabled>true</IsEnabled><Destination><S3BucketDestination><Bucket>arn:aws:s3:::destbucket</Bucket><Format>CSV</Format></S3BucketDestination></Destination><IncludedObjectVersions>All</IncludedObjectVersions><Schedule><Frequency>Hourly</Frequency></Schedule></InventoryConfiguration>`,
+			expectedErr: errInventoryFrequencyInvalid,
+		},
+		{ // Duplicate optional fields
+			inputConfig: `<InventoryConfiguration><Id>report1</Id><IsEnabled>true</IsEnabled><Destination><S3BucketDestination><Bucket>arn:aws:s3:::destbucket</Bucket><Format>CSV</Format></S3BucketDestination></Destination><IncludedObjectVersions>All</IncludedObjectVersions><OptionalFields><Field>Size</Field><Field>Size</Field></OptionalFields><Schedule><Frequency>Daily</Frequency></Schedule></InventoryConfiguration>`,
+			expectedErr: errInventoryDuplicateField,
+		},
+		{ // Unsupported optional field
+			inputConfig: `<InventoryConfiguration><Id>report1</Id><IsEnabled>true</IsEnabled><Destination><S3BucketDestination><Bucket>arn:aws:s3:::destbucket</Bucket><Format>CSV</Format></S3BucketDestination></Destination><IncludedObjectVersions>All</IncludedObjectVersions><OptionalFields><Field>IntelligentTieringAccessTier</Field></OptionalFields><Schedule><Frequency>Daily</Frequency></Schedule></InventoryConfiguration>`,
+			expectedErr: Errorf(errInventoryUnsupportedFieldFmt, "IntelligentTieringAccessTier"),
+		},
+	}
+
+	for i, tc := range testCases {
+		config, err := ParseConfig(bytes.NewReader([]byte(tc.inputConfig)))
+		if err != nil {
+			t.Fatalf("%d: Unexpected parse error %v", i+1, err)
+		}
+		err = config.Validate()
+		if err == nil && tc.expectedErr == nil {
+			continue
+		}
+		if err == nil || tc.expectedErr == nil || err.Error() != tc.expectedErr.Error() {
+			t.Errorf("%d: Expected %v got %v", i+1, tc.expectedErr, err)
+		}
+	}
+}
+
+func TestInventoryConfigColumns(t *testing.T) {
+	testCases := []struct {
+		config          Config
+		expectedColumns []string
+		expectedPrefix  string
+		expectedBucket  string
+		expectedPeriod  time.Duration
+	}{
+		{
+			config: Config{
+				Destination:            Destination{S3BucketDestination{Bucket: "arn:aws:s3:::dest"}},
+				IncludedObjectVersions: CurrentVersions,
+				Schedule:               Schedule{Frequency: Daily},
+			},
+			expectedColumns: []string{"Bucket", "Key"},
+			expectedBucket:  "dest",
+			expectedPeriod:  24 * time.Hour,
+		},
+		{
+			config: Config{
+				Destination:            Destination{S3BucketDestination{Bucket: "arn:aws:s3:::dest"}},
+				Filter:                 &Filter{Prefix: "logs/"},
+				IncludedObjectVersions: AllVersions,
+				// Fields are reported in a fixed order regardless of configuration order.
+				OptionalFields: &OptionalFields{Fields: []Field{ReplicationStatusField, SizeField}},
+				Schedule:       Schedule{Frequency: Weekly},
+			},
+			expectedColumns: []string{"Bucket", "Key", "VersionId", "IsLatest", "IsDeleteMarker", "Size", "ReplicationStatus"},
+			expectedPrefix:  "logs/",
+			expectedBucket:  "dest",
+			expectedPeriod:  7 * 24 * time.Hour,
+		},
+	}
+
+	for i, tc := range testCases {
+		if got := tc.config.Columns(); !reflect.DeepEqual(got, tc.expectedColumns) {
+			t.Errorf("%d: Expected columns %v got %v", i+1, tc.expectedColumns, got)
+		}
+		if got := tc.config.Prefix(); got != tc.expectedPrefix {
+			t.Errorf("%d: Expected prefix %q got %q", i+1, tc.expectedPrefix, got)
+		}
+		if got := tc.config.DestinationBucket(); got != tc.expectedBucket {
+			t.Errorf("%d: Expected bucket %q got %q", i+1, tc.expectedBucket, got)
+		}
+		if got := tc.config.Interval(); got != tc.expectedPeriod {
+			t.Errorf("%d: Expected interval %v got %v", i+1, tc.expectedPeriod, got)
+		}
+	}
+}
+
+func TestInventoryConfigsPutRemove(t *testing.T) {
+	configs := &Configs{}
+	var err error
+	for _, id := range []string{"a", "b", "a"} {
+		configs, err = configs.Put(Config{ID: id, IsEnabled: id == "a"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(configs.Configs) != 2 {
+		t.Fatalf("Expected 2 configurations, got %d", len(configs.Configs))
+	}
+	if c, ok := configs.Get("a"); !ok || !c.IsEnabled {
+		t.Fatalf("Expected configuration a to be replaced, got %v", c)
+	}
+
+	configs, found := configs.Remove("b")
+	if !found || len(configs.Configs) != 1 {
+		t.Fatalf("Expected configuration b to be removed, got %v", configs.Configs)
+	}
+	if _, found = configs.Remove("c"); found {
+		t.Fatal("Expected configuration c to be missing")
+	}
+
+	var manyConfigs Configs
+	for i := 0; i < maxInventoryConfigs; i++ {
+		manyConfigs.Configs = append(manyConfigs.Configs, Config{ID: string(rune('a' + i))})
+	}
+	if _, err = manyConfigs.Put(Config{ID: "one-too-many"}); err != errInventoryTooManyConfigs {
+		t.Fatalf("Expected %v, got %v", errInventoryTooManyConfigs, err)
+	}
+}
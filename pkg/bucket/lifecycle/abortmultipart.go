@@ -0,0 +1,69 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lifecycle
+
+import (
+	"encoding/xml"
+)
+
+var (
+	errAbortIncompleteMultipartUploadNoDays = Errorf("DaysAfterInitiation must be a positive integer when used with AbortIncompleteMultipartUpload")
+)
+
+// AbortIncompleteMultipartUpload - an action for lifecycle configuration rule.
+type AbortIncompleteMultipartUpload struct {
+	XMLName             xml.Name       `xml:"AbortIncompleteMultipartUpload"`
+	DaysAfterInitiation ExpirationDays `xml:"DaysAfterInitiation,omitempty"`
+
+	set bool
+}
+
+// MarshalXML is extended to leave out
+// <AbortIncompleteMultipartUpload></AbortIncompleteMultipartUpload> tags
+func (a AbortIncompleteMultipartUpload) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if !a.set {
+		return nil
+	}
+	type abortIncompleteMultipartUploadWrapper AbortIncompleteMultipartUpload
+	return e.EncodeElement(abortIncompleteMultipartUploadWrapper(a), start)
+}
+
+// UnmarshalXML decodes the AbortIncompleteMultipartUpload field from the XML form.
+func (a *AbortIncompleteMultipartUpload) UnmarshalXML(d *xml.Decoder, startElement xml.StartElement) error {
+	type abortIncompleteMultipartUploadWrapper AbortIncompleteMultipartUpload
+	var abort abortIncompleteMultipartUploadWrapper
+	err := d.DecodeElement(&abort, &startElement)
+	if err != nil {
+		return err
+	}
+	*a = AbortIncompleteMultipartUpload(abort)
+	a.set = true
+	return nil
+}
+
+// Validate - validates the "AbortIncompleteMultipartUpload" element
+func (a AbortIncompleteMultipartUpload) Validate() error {
+	if a.set && a.IsDaysNull() {
+		return errAbortIncompleteMultipartUploadNoDays
+	}
+	return nil
+}
+
+// IsDaysNull returns true if days field is null
+func (a AbortIncompleteMultipartUpload) IsDaysNull() bool {
+	return a.DaysAfterInitiation == ExpirationDays(0)
+}
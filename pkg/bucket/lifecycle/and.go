@@ -25,13 +25,17 @@ type And struct {
 	XMLName xml.Name `xml:"And"`
 	Prefix  string   `xml:"Prefix,omitempty"`
 	Tags    []Tag    `xml:"Tag,omitempty"`
+
+	ObjectSizeGreaterThan int64 `xml:"ObjectSizeGreaterThan,omitempty"`
+	ObjectSizeLessThan    int64 `xml:"ObjectSizeLessThan,omitempty"`
 }
 
 var errDuplicateTagKey = Errorf("Duplicate Tag Keys are not allowed")
 
 // isEmpty returns true if Tags field is null
 func (a And) isEmpty() bool {
-	return len(a.Tags) == 0 && a.Prefix == ""
+	return len(a.Tags) == 0 && a.Prefix == "" &&
+		a.ObjectSizeGreaterThan == 0 && a.ObjectSizeLessThan == 0
 }
 
 // Validate - validates the And field
@@ -39,6 +43,12 @@ func (a And) Validate() error {
 	if a.ContainsDuplicateTag() {
 		return errDuplicateTagKey
 	}
+	if a.ObjectSizeGreaterThan < 0 || a.ObjectSizeLessThan < 0 {
+		return errInvalidObjectSize
+	}
+	if a.ObjectSizeGreaterThan > 0 && a.ObjectSizeLessThan > 0 && a.ObjectSizeGreaterThan >= a.ObjectSizeLessThan {
+		return errInvalidObjectSize
+	}
 	for _, t := range a.Tags {
 		if err := t.Validate(); err != nil {
 			return err
@@ -21,7 +21,8 @@ import (
 )
 
 var (
-	errInvalidFilter = Errorf("Filter must have exactly one of Prefix, Tag, or And specified")
+	errInvalidFilter     = Errorf("Filter must have exactly one of Prefix, Tag, ObjectSizeGreaterThan, ObjectSizeLessThan or And specified")
+	errInvalidObjectSize = Errorf("ObjectSizeGreaterThan and ObjectSizeLessThan must be positive integers and ObjectSizeGreaterThan must be less than ObjectSizeLessThan")
 )
 
 // Filter - a filter for a lifecycle configuration Rule.
@@ -31,12 +32,16 @@ type Filter struct {
 	And     And
 	Tag     Tag
 
+	ObjectSizeGreaterThan int64 `xml:"ObjectSizeGreaterThan,omitempty"`
+	ObjectSizeLessThan    int64 `xml:"ObjectSizeLessThan,omitempty"`
+
 	// Caching tags, only once
 	cachedTags []string
 }
 
 // MarshalXML - produces the xml representation of the Filter struct
-// only one of Prefix, And and Tag should be present in the output.
+// only one of Prefix, And, Tag and the object size conditions should be
+// present in the output.
 func (f Filter) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 	if err := e.EncodeToken(start); err != nil {
 		return err
@@ -51,6 +56,14 @@ func (f Filter) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 		if err := e.EncodeElement(f.Tag, xml.StartElement{Name: xml.Name{Local: "Tag"}}); err != nil {
 			return err
 		}
+	case f.ObjectSizeGreaterThan != 0:
+		if err := e.EncodeElement(f.ObjectSizeGreaterThan, xml.StartElement{Name: xml.Name{Local: "ObjectSizeGreaterThan"}}); err != nil {
+			return err
+		}
+	case f.ObjectSizeLessThan != 0:
+		if err := e.EncodeElement(f.ObjectSizeLessThan, xml.StartElement{Name: xml.Name{Local: "ObjectSizeLessThan"}}); err != nil {
+			return err
+		}
 	default:
 		// Always print Prefix field when both And & Tag are empty
 		if err := e.EncodeElement(f.Prefix, xml.StartElement{Name: xml.Name{Local: "Prefix"}}); err != nil {
@@ -63,22 +76,32 @@ func (f Filter) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 
 // Validate - validates the filter element
 func (f Filter) Validate() error {
-	// A Filter must have exactly one of Prefix, Tag, or And specified.
+	// A Filter must have exactly one of Prefix, Tag, ObjectSizeGreaterThan,
+	// ObjectSizeLessThan or And specified.
+	n := 0
 	if !f.And.isEmpty() {
-		if f.Prefix != "" {
-			return errInvalidFilter
-		}
-		if !f.Tag.IsEmpty() {
-			return errInvalidFilter
-		}
+		n++
 		if err := f.And.Validate(); err != nil {
 			return err
 		}
 	}
 	if f.Prefix != "" {
-		if !f.Tag.IsEmpty() {
-			return errInvalidFilter
-		}
+		n++
+	}
+	if !f.Tag.IsEmpty() {
+		n++
+	}
+	if f.ObjectSizeGreaterThan != 0 {
+		n++
+	}
+	if f.ObjectSizeLessThan != 0 {
+		n++
+	}
+	if n > 1 {
+		return errInvalidFilter
+	}
+	if f.ObjectSizeGreaterThan < 0 || f.ObjectSizeLessThan < 0 {
+		return errInvalidObjectSize
 	}
 	if !f.Tag.IsEmpty() {
 		if err := f.Tag.Validate(); err != nil {
@@ -114,3 +137,19 @@ func (f Filter) TestTags(tags []string) bool {
 	}
 	return true
 }
+
+// BySize returns true if the object size satisfies the object size
+// conditions of the Filter, it returns true if there are none.
+func (f Filter) BySize(sz int64) bool {
+	greaterThan, lessThan := f.ObjectSizeGreaterThan, f.ObjectSizeLessThan
+	if !f.And.isEmpty() {
+		greaterThan, lessThan = f.And.ObjectSizeGreaterThan, f.And.ObjectSizeLessThan
+	}
+	if greaterThan > 0 && sz <= greaterThan {
+		return false
+	}
+	if lessThan > 0 && sz >= lessThan {
+		return false
+	}
+	return true
+}
@@ -107,6 +107,44 @@ func TestUnsupportedFilters(t *testing.T) {
 						</Filter>`,
 			expectedErr: errInvalidFilter,
 		},
+		{ // Filter with ObjectSizeGreaterThan
+			inputXML: ` <Filter>
+							<ObjectSizeGreaterThan>1024</ObjectSizeGreaterThan>
+						</Filter>`,
+			expectedErr: nil,
+		},
+		{ // Filter without And with Prefix and ObjectSizeLessThan
+			inputXML: ` <Filter>
+							<Prefix>key-prefix</Prefix>
+							<ObjectSizeLessThan>1024</ObjectSizeLessThan>
+						</Filter>`,
+			expectedErr: errInvalidFilter,
+		},
+		{ // Filter with And, Prefix and object size range
+			inputXML: ` <Filter>
+							<And>
+							<Prefix>key-prefix</Prefix>
+							<ObjectSizeGreaterThan>1024</ObjectSizeGreaterThan>
+							<ObjectSizeLessThan>4096</ObjectSizeLessThan>
+							</And>
+						</Filter>`,
+			expectedErr: nil,
+		},
+		{ // Filter with And and empty object size range
+			inputXML: ` <Filter>
+							<And>
+							<ObjectSizeGreaterThan>4096</ObjectSizeGreaterThan>
+							<ObjectSizeLessThan>1024</ObjectSizeLessThan>
+							</And>
+						</Filter>`,
+			expectedErr: errInvalidObjectSize,
+		},
+		{ // Filter with negative ObjectSizeLessThan
+			inputXML: ` <Filter>
+							<ObjectSizeLessThan>-1</ObjectSizeLessThan>
+						</Filter>`,
+			expectedErr: errInvalidObjectSize,
+		},
 	}
 	for i, tc := range testCases {
 		t.Run(fmt.Sprintf("Test %d", i+1), func(t *testing.T) {
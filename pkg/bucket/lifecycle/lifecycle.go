@@ -94,7 +94,7 @@ func (lc Lifecycle) HasActiveRules(prefix string, recursive bool) bool {
 			}
 		}
 
-		if rule.NoncurrentVersionExpiration.NoncurrentDays > 0 {
+		if !rule.NoncurrentVersionExpiration.IsNull() {
 			return true
 		}
 		if rule.NoncurrentVersionTransition.NoncurrentDays > 0 {
@@ -159,46 +159,59 @@ func (lc Lifecycle) Validate() error {
 // FilterActionableRules returns the rules actions that need to be executed
 // after evaluating prefix/tag filtering
 func (lc Lifecycle) FilterActionableRules(obj ObjectOpts) []Rule {
+	var rules []Rule
+	for _, idx := range lc.actionableRules(obj) {
+		rules = append(rules, lc.Rules[idx])
+	}
+	return rules
+}
+
+// actionableRules is like FilterActionableRules but returns the indexes
+// of the rules in lc.Rules.
+func (lc Lifecycle) actionableRules(obj ObjectOpts) []int {
 	if obj.Name == "" {
 		return nil
 	}
-	var rules []Rule
-	for _, rule := range lc.Rules {
+	var rules []int
+	for idx, rule := range lc.Rules {
 		if rule.Status == Disabled {
 			continue
 		}
 		if !strings.HasPrefix(obj.Name, rule.Prefix()) {
 			continue
 		}
+		if !rule.Filter.BySize(obj.Size) {
+			continue
+		}
 		// Indicates whether MinIO will remove a delete marker with no
 		// noncurrent versions. If set to true, the delete marker will
 		// be expired; if set to false the policy takes no action. This
 		// cannot be specified with Days or Date in a Lifecycle
 		// Expiration Policy.
 		if rule.Expiration.DeleteMarker.val {
-			rules = append(rules, rule)
+			rules = append(rules, idx)
 			continue
 		}
 		// The NoncurrentVersionExpiration action requests MinIO to expire
 		// noncurrent versions of objects x days after the objects become
-		// noncurrent.
-		if !rule.NoncurrentVersionExpiration.IsDaysNull() {
-			rules = append(rules, rule)
+		// noncurrent, or once more than n newer noncurrent versions exist.
+		if !rule.NoncurrentVersionExpiration.IsNull() {
+			rules = append(rules, idx)
 			continue
 		}
 		// The NoncurrentVersionTransition action requests MinIO to transition
 		// noncurrent versions of objects x days after the objects become
 		// noncurrent.
 		if !rule.NoncurrentVersionTransition.IsDaysNull() {
-			rules = append(rules, rule)
+			rules = append(rules, idx)
 			continue
 		}
 
 		if rule.Filter.TestTags(strings.Split(obj.UserTags, "&")) {
-			rules = append(rules, rule)
+			rules = append(rules, idx)
 		}
 		if !rule.Transition.IsNull() {
-			rules = append(rules, rule)
+			rules = append(rules, idx)
 		}
 	}
 	return rules
@@ -210,6 +223,7 @@ type ObjectOpts struct {
 	Name             string
 	UserTags         string
 	ModTime          time.Time
+	Size             int64
 	VersionID        string
 	IsLatest         bool
 	DeleteMarker     bool
@@ -218,31 +232,46 @@ type ObjectOpts struct {
 	TransitionStatus string
 	RestoreOngoing   bool
 	RestoreExpires   time.Time
+	// Number of noncurrent versions of the object newer than this version
+	NewerNoncurrentVersions int
 }
 
 // ComputeAction returns the action to perform by evaluating all lifecycle rules
 // against the object name and its modification time.
 func (lc Lifecycle) ComputeAction(obj ObjectOpts) Action {
+	action, _ := lc.ComputeActionRule(obj)
+	return action
+}
+
+// ComputeActionRule is like ComputeAction but also returns the index in
+// lc.Rules of the rule the action stems from, -1 if there is no action.
+// Rule IDs are optional and cannot identify the rule.
+func (lc Lifecycle) ComputeActionRule(obj ObjectOpts) (Action, int) {
 	var action = NoneAction
+	var ruleIdx = -1
 	if obj.ModTime.IsZero() {
-		return action
+		return action, ruleIdx
 	}
 
-	for _, rule := range lc.FilterActionableRules(obj) {
+	for _, idx := range lc.actionableRules(obj) {
+		rule := lc.Rules[idx]
 		if obj.DeleteMarker && obj.NumVersions == 1 && rule.Expiration.DeleteMarker.val {
 			// Indicates whether MinIO will remove a delete marker with no noncurrent versions.
 			// Only latest marker is removed. If set to true, the delete marker will be expired;
 			// if set to false the policy takes no action. This cannot be specified with Days or
 			// Date in a Lifecycle Expiration Policy.
-			return DeleteVersionAction
+			return DeleteVersionAction, idx
 		}
 
-		if !rule.NoncurrentVersionExpiration.IsDaysNull() {
+		if !rule.NoncurrentVersionExpiration.IsNull() {
 			if obj.VersionID != "" && !obj.IsLatest && !obj.SuccessorModTime.IsZero() {
 				// Non current versions should be deleted if their age exceeds non current days configuration
 				// https://docs.aws.amazon.com/AmazonS3/latest/dev/intro-lifecycle-rules.html#intro-lifecycle-rules-actions
-				if time.Now().After(ExpectedExpiryTime(obj.SuccessorModTime, int(rule.NoncurrentVersionExpiration.NoncurrentDays))) {
-					return DeleteVersionAction
+				// The newest NewerNoncurrentVersions non current versions are retained regardless of their age.
+				nve := rule.NoncurrentVersionExpiration
+				if obj.NewerNoncurrentVersions >= nve.NewerNoncurrentVersions &&
+					(nve.IsDaysNull() || time.Now().After(ExpectedExpiryTime(obj.SuccessorModTime, int(nve.NoncurrentDays)))) {
+					return DeleteVersionAction, idx
 				}
 			}
 		}
@@ -251,13 +280,14 @@ func (lc Lifecycle) ComputeAction(obj ObjectOpts) Action {
 				// Non current versions should be deleted if their age exceeds non current days configuration
 				// https://docs.aws.amazon.com/AmazonS3/latest/dev/intro-lifecycle-rules.html#intro-lifecycle-rules-actions
 				if time.Now().After(ExpectedExpiryTime(obj.SuccessorModTime, int(rule.NoncurrentVersionTransition.NoncurrentDays))) {
-					return TransitionVersionAction
+					return TransitionVersionAction, idx
 				}
 			}
 		}
 
 		// Remove the object or simply add a delete marker (once) in a versioned bucket
 		if obj.VersionID == "" || obj.IsLatest && !obj.DeleteMarker {
+			prevAction := action
 			switch {
 			case !rule.Expiration.IsDateNull():
 				if time.Now().UTC().After(rule.Expiration.Date.Time) {
@@ -289,9 +319,41 @@ func (lc Lifecycle) ComputeAction(obj ObjectOpts) Action {
 					}
 				}
 			}
+			if action != prevAction {
+				ruleIdx = idx
+			}
 		}
 	}
-	return action
+	return action, ruleIdx
+}
+
+// AbortIncompleteMultipartUploadTime returns the time after which the
+// incomplete multipart upload of the object is to be aborted according to
+// the AbortIncompleteMultipartUpload actions of the matching rules, the
+// earliest one if several rules match. obj.ModTime is the initiation time
+// of the upload and obj.UserTags the tags it was initiated with. A zero
+// time is returned if no rule matches.
+func (lc Lifecycle) AbortIncompleteMultipartUploadTime(obj ObjectOpts) time.Time {
+	var abortTime time.Time
+	if obj.Name == "" || obj.ModTime.IsZero() {
+		return abortTime
+	}
+	for _, rule := range lc.Rules {
+		if rule.Status == Disabled || rule.AbortIncompleteMultipartUpload.IsDaysNull() {
+			continue
+		}
+		if !strings.HasPrefix(obj.Name, rule.Prefix()) {
+			continue
+		}
+		if !rule.Filter.TestTags(strings.Split(obj.UserTags, "&")) {
+			continue
+		}
+		t := ExpectedExpiryTime(obj.ModTime, int(rule.AbortIncompleteMultipartUpload.DaysAfterInitiation))
+		if abortTime.IsZero() || t.Before(abortTime) {
+			abortTime = t
+		}
+	}
+	return abortTime
 }
 
 // ExpectedExpiryTime calculates the expiry, transition or restore date/time based on a object modtime.
@@ -319,6 +381,11 @@ func (lc Lifecycle) PredictExpiryTime(obj ObjectOpts) (string, time.Time) {
 	// expiration date and its associated rule ID.
 	for _, rule := range lc.FilterActionableRules(obj) {
 		if !rule.NoncurrentVersionExpiration.IsDaysNull() && !obj.IsLatest && obj.VersionID != "" {
+			// The expiry of versions retained as one of the newer noncurrent
+			// versions depends on future versions and cannot be predicted.
+			if rule.NoncurrentVersionExpiration.NewerNoncurrentVersions > 0 {
+				continue
+			}
 			return rule.ID, ExpectedExpiryTime(time.Now(), int(rule.NoncurrentVersionExpiration.NoncurrentDays))
 		}
 
@@ -261,6 +261,7 @@ func TestComputeActions(t *testing.T) {
 		objectName     string
 		objectTags     string
 		objectModTime  time.Time
+		objectSize     int64
 		expectedAction Action
 	}{
 		// Empty object name (unexpected case) should always return NoneAction
@@ -395,6 +396,38 @@ func TestComputeActions(t *testing.T) {
 			objectModTime:  time.Now().UTC().Add(-24 * time.Hour), // Created 1 day ago
 			expectedAction: DeleteAction,
 		},
+		// Should remove, object larger than ObjectSizeGreaterThan
+		{
+			inputConfig:    `<LifecycleConfiguration><Rule><Filter><ObjectSizeGreaterThan>1024</ObjectSizeGreaterThan></Filter><Status>Enabled</Status><Expiration><Days>1</Days></Expiration></Rule></LifecycleConfiguration>`,
+			objectName:     "foxdir/fooobject",
+			objectModTime:  time.Now().UTC().Add(-48 * time.Hour), // Created 2 day ago
+			objectSize:     2048,
+			expectedAction: DeleteAction,
+		},
+		// Should not remove, object not larger than ObjectSizeGreaterThan
+		{
+			inputConfig:    `<LifecycleConfiguration><Rule><Filter><ObjectSizeGreaterThan>1024</ObjectSizeGreaterThan></Filter><Status>Enabled</Status><Expiration><Days>1</Days></Expiration></Rule></LifecycleConfiguration>`,
+			objectName:     "foxdir/fooobject",
+			objectModTime:  time.Now().UTC().Add(-48 * time.Hour), // Created 2 day ago
+			objectSize:     1024,
+			expectedAction: NoneAction,
+		},
+		// Should remove, prefix matches and object size within range
+		{
+			inputConfig:    `<LifecycleConfiguration><Rule><Filter><And><Prefix>foxdir/</Prefix><ObjectSizeGreaterThan>1024</ObjectSizeGreaterThan><ObjectSizeLessThan>4096</ObjectSizeLessThan></And></Filter><Status>Enabled</Status><Expiration><Days>1</Days></Expiration></Rule></LifecycleConfiguration>`,
+			objectName:     "foxdir/fooobject",
+			objectModTime:  time.Now().UTC().Add(-48 * time.Hour), // Created 2 day ago
+			objectSize:     2048,
+			expectedAction: DeleteAction,
+		},
+		// Should not remove, object size out of range
+		{
+			inputConfig:    `<LifecycleConfiguration><Rule><Filter><And><Prefix>foxdir/</Prefix><ObjectSizeGreaterThan>1024</ObjectSizeGreaterThan><ObjectSizeLessThan>4096</ObjectSizeLessThan></And></Filter><Status>Enabled</Status><Expiration><Days>1</Days></Expiration></Rule></LifecycleConfiguration>`,
+			objectName:     "foxdir/fooobject",
+			objectModTime:  time.Now().UTC().Add(-48 * time.Hour), // Created 2 day ago
+			objectSize:     4096,
+			expectedAction: NoneAction,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -408,6 +441,7 @@ func TestComputeActions(t *testing.T) {
 				Name:     tc.objectName,
 				UserTags: tc.objectTags,
 				ModTime:  tc.objectModTime,
+				Size:     tc.objectSize,
 				IsLatest: true,
 			}); resultAction != tc.expectedAction {
 				t.Fatalf("Expected action: `%v`, got: `%v`", tc.expectedAction, resultAction)
@@ -474,3 +508,157 @@ func TestHasActiveRules(t *testing.T) {
 
 	}
 }
+
+func TestAbortIncompleteMultipartUploadTime(t *testing.T) {
+	initiated := time.Date(2020, time.March, 10, 13, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		inputConfig       string
+		objectName        string
+		objectTags        string
+		expectedAbortTime time.Time
+	}{
+		{
+			inputConfig:       `<LifecycleConfiguration><Rule><Filter><Prefix>foodir/</Prefix></Filter><Status>Enabled</Status><AbortIncompleteMultipartUpload><DaysAfterInitiation>3</DaysAfterInitiation></AbortIncompleteMultipartUpload></Rule></LifecycleConfiguration>`,
+			objectName:        "foodir/fooobject",
+			expectedAbortTime: time.Date(2020, time.March, 14, 0, 0, 0, 0, time.UTC),
+		},
+		{ // Prefix not matching
+			inputConfig: `<LifecycleConfiguration><Rule><Filter><Prefix>foodir/</Prefix></Filter><Status>Enabled</Status><AbortIncompleteMultipartUpload><DaysAfterInitiation>3</DaysAfterInitiation></AbortIncompleteMultipartUpload></Rule></LifecycleConfiguration>`,
+			objectName:  "zdir/fooobject",
+		},
+		{ // Disabled rule
+			inputConfig: `<LifecycleConfiguration><Rule><Filter><Prefix>foodir/</Prefix></Filter><Status>Disabled</Status><AbortIncompleteMultipartUpload><DaysAfterInitiation>3</DaysAfterInitiation></AbortIncompleteMultipartUpload></Rule></LifecycleConfiguration>`,
+			objectName:  "foodir/fooobject",
+		},
+		{ // Rule without AbortIncompleteMultipartUpload
+			inputConfig: `<LifecycleConfiguration><Rule><Filter><Prefix>foodir/</Prefix></Filter><Status>Enabled</Status><Expiration><Days>5</Days></Expiration></Rule></LifecycleConfiguration>`,
+			objectName:  "foodir/fooobject",
+		},
+		{ // Earliest of the matching rules
+			inputConfig:       `<LifecycleConfiguration><Rule><Filter><Prefix>foodir/</Prefix></Filter><Status>Enabled</Status><AbortIncompleteMultipartUpload><DaysAfterInitiation>7</DaysAfterInitiation></AbortIncompleteMultipartUpload></Rule><Rule><Filter><Prefix>foodir/sub/</Prefix></Filter><Status>Enabled</Status><AbortIncompleteMultipartUpload><DaysAfterInitiation>1</DaysAfterInitiation></AbortIncompleteMultipartUpload></Rule></LifecycleConfiguration>`,
+			objectName:        "foodir/sub/fooobject",
+			expectedAbortTime: time.Date(2020, time.March, 12, 0, 0, 0, 0, time.UTC),
+		},
+		{ // Tags matching
+			inputConfig:       `<LifecycleConfiguration><Rule><Filter><Tag><Key>tag1</Key><Value>value1</Value></Tag></Filter><Status>Enabled</Status><AbortIncompleteMultipartUpload><DaysAfterInitiation>3</DaysAfterInitiation></AbortIncompleteMultipartUpload></Rule></LifecycleConfiguration>`,
+			objectName:        "fooobject",
+			objectTags:        "tag1=value1",
+			expectedAbortTime: time.Date(2020, time.March, 14, 0, 0, 0, 0, time.UTC),
+		},
+		{ // Tags not matching
+			inputConfig: `<LifecycleConfiguration><Rule><Filter><Tag><Key>tag1</Key><Value>value1</Value></Tag></Filter><Status>Enabled</Status><AbortIncompleteMultipartUpload><DaysAfterInitiation>3</DaysAfterInitiation></AbortIncompleteMultipartUpload></Rule></LifecycleConfiguration>`,
+			objectName:  "fooobject",
+			objectTags:  "tag1=value2",
+		},
+	}
+
+	for i, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprintf("Test_%d", i+1), func(t *testing.T) {
+			lc, err := ParseLifecycleConfig(bytes.NewReader([]byte(tc.inputConfig)))
+			if err != nil {
+				t.Fatalf("Got unexpected error: %v", err)
+			}
+			abortTime := lc.AbortIncompleteMultipartUploadTime(ObjectOpts{
+				Name:     tc.objectName,
+				UserTags: tc.objectTags,
+				ModTime:  initiated,
+			})
+			if !abortTime.Equal(tc.expectedAbortTime) {
+				t.Fatalf("Expected abort time: `%v`, got: `%v`", tc.expectedAbortTime, abortTime)
+			}
+		})
+	}
+}
+
+func TestNewerNoncurrentVersions(t *testing.T) {
+	testCases := []struct {
+		inputConfig             string
+		successorModTime        time.Time
+		newerNoncurrentVersions int
+		expectedAction          Action
+	}{
+		// Retained as one of the newer noncurrent versions
+		{
+			inputConfig:             `<LifecycleConfiguration><Rule><Status>Enabled</Status><NoncurrentVersionExpiration><NewerNoncurrentVersions>5</NewerNoncurrentVersions></NoncurrentVersionExpiration></Rule></LifecycleConfiguration>`,
+			successorModTime:        time.Now().UTC().Add(-10 * 24 * time.Hour),
+			newerNoncurrentVersions: 4,
+			expectedAction:          NoneAction,
+		},
+		// More than 5 newer noncurrent versions
+		{
+			inputConfig:             `<LifecycleConfiguration><Rule><Status>Enabled</Status><NoncurrentVersionExpiration><NewerNoncurrentVersions>5</NewerNoncurrentVersions></NoncurrentVersionExpiration></Rule></LifecycleConfiguration>`,
+			successorModTime:        time.Now().UTC().Add(-time.Hour),
+			newerNoncurrentVersions: 5,
+			expectedAction:          DeleteVersionAction,
+		},
+		// More than 5 newer noncurrent versions, but noncurrent for less than NoncurrentDays
+		{
+			inputConfig:             `<LifecycleConfiguration><Rule><Status>Enabled</Status><NoncurrentVersionExpiration><NoncurrentDays>3</NoncurrentDays><NewerNoncurrentVersions>5</NewerNoncurrentVersions></NoncurrentVersionExpiration></Rule></LifecycleConfiguration>`,
+			successorModTime:        time.Now().UTC().Add(-time.Hour),
+			newerNoncurrentVersions: 7,
+			expectedAction:          NoneAction,
+		},
+		// More than 5 newer noncurrent versions, noncurrent for more than NoncurrentDays
+		{
+			inputConfig:             `<LifecycleConfiguration><Rule><Status>Enabled</Status><NoncurrentVersionExpiration><NoncurrentDays>3</NoncurrentDays><NewerNoncurrentVersions>5</NewerNoncurrentVersions></NoncurrentVersionExpiration></Rule></LifecycleConfiguration>`,
+			successorModTime:        time.Now().UTC().Add(-10 * 24 * time.Hour),
+			newerNoncurrentVersions: 7,
+			expectedAction:          DeleteVersionAction,
+		},
+	}
+
+	for i, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprintf("Test_%d", i+1), func(t *testing.T) {
+			lc, err := ParseLifecycleConfig(bytes.NewReader([]byte(tc.inputConfig)))
+			if err != nil {
+				t.Fatalf("Got unexpected error: %v", err)
+			}
+			if resultAction := lc.ComputeAction(ObjectOpts{
+				Name:                    "foodir/fooobject",
+				ModTime:                 tc.successorModTime.Add(-time.Hour),
+				VersionID:               "0bd4f1d5-a6b3-4f6a-9d36-1c8e2ad0b0a8",
+				SuccessorModTime:        tc.successorModTime,
+				NumVersions:             tc.newerNoncurrentVersions + 2,
+				NewerNoncurrentVersions: tc.newerNoncurrentVersions,
+			}); resultAction != tc.expectedAction {
+				t.Fatalf("Expected action: `%v`, got: `%v`", tc.expectedAction, resultAction)
+			}
+		})
+	}
+}
+
+func TestComputeActionRule(t *testing.T) {
+	lc, err := ParseLifecycleConfig(bytes.NewReader([]byte(`<LifecycleConfiguration>` +
+		`<Rule><ID>expire</ID><Filter><Prefix>foodir/</Prefix></Filter><Status>Enabled</Status><Expiration><Days>1</Days></Expiration></Rule>` +
+		`<Rule><ID>noncurrent</ID><Filter><Prefix>foodir/</Prefix></Filter><Status>Enabled</Status><NoncurrentVersionExpiration><NoncurrentDays>1</NoncurrentDays></NoncurrentVersionExpiration></Rule>` +
+		`</LifecycleConfiguration>`)))
+	if err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	action, ruleIdx := lc.ComputeActionRule(ObjectOpts{
+		Name:     "foodir/fooobject",
+		ModTime:  time.Now().UTC().Add(-48 * time.Hour),
+		IsLatest: true,
+	})
+	if action != DeleteAction || ruleIdx != 0 {
+		t.Fatalf("Expected action `%v` of rule 0, got `%v` of rule %d", DeleteAction, action, ruleIdx)
+	}
+	action, ruleIdx = lc.ComputeActionRule(ObjectOpts{
+		Name:             "foodir/fooobject",
+		ModTime:          time.Now().UTC().Add(-72 * time.Hour),
+		VersionID:        "0bd4f1d5-a6b3-4f6a-9d36-1c8e2ad0b0a8",
+		SuccessorModTime: time.Now().UTC().Add(-48 * time.Hour),
+	})
+	if action != DeleteVersionAction || ruleIdx != 1 {
+		t.Fatalf("Expected action `%v` of rule 1, got `%v` of rule %d", DeleteVersionAction, action, ruleIdx)
+	}
+	if action, ruleIdx = lc.ComputeActionRule(ObjectOpts{
+		Name:     "zdir/fooobject",
+		ModTime:  time.Now().UTC().Add(-48 * time.Hour),
+		IsLatest: true,
+	}); action != NoneAction || ruleIdx != -1 {
+		t.Fatalf("Expected no action, got `%v` of rule %d", action, ruleIdx)
+	}
+}
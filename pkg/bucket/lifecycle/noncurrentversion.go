@@ -20,10 +20,15 @@ import (
 	"encoding/xml"
 )
 
+var (
+	errInvalidNewerNoncurrentVersions = Errorf("NewerNoncurrentVersions must be a positive integer")
+)
+
 // NoncurrentVersionExpiration - an action for lifecycle configuration rule.
 type NoncurrentVersionExpiration struct {
-	XMLName        xml.Name       `xml:"NoncurrentVersionExpiration"`
-	NoncurrentDays ExpirationDays `xml:"NoncurrentDays,omitempty"`
+	XMLName                 xml.Name       `xml:"NoncurrentVersionExpiration"`
+	NoncurrentDays          ExpirationDays `xml:"NoncurrentDays,omitempty"`
+	NewerNoncurrentVersions int            `xml:"NewerNoncurrentVersions,omitempty"`
 }
 
 // NoncurrentVersionTransition - an action for lifecycle configuration rule.
@@ -34,7 +39,7 @@ type NoncurrentVersionTransition struct {
 
 // MarshalXML if non-current days not set to non zero value
 func (n NoncurrentVersionExpiration) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
-	if n.IsDaysNull() {
+	if n.IsNull() {
 		return nil
 	}
 	type noncurrentVersionExpirationWrapper NoncurrentVersionExpiration
@@ -46,6 +51,20 @@ func (n NoncurrentVersionExpiration) IsDaysNull() bool {
 	return n.NoncurrentDays == ExpirationDays(0)
 }
 
+// IsNull returns true if neither days nor the number of newer noncurrent
+// versions to retain are set
+func (n NoncurrentVersionExpiration) IsNull() bool {
+	return n.IsDaysNull() && n.NewerNoncurrentVersions == 0
+}
+
+// Validate - validates the "NoncurrentVersionExpiration" element
+func (n NoncurrentVersionExpiration) Validate() error {
+	if n.NewerNoncurrentVersions < 0 {
+		return errInvalidNewerNoncurrentVersions
+	}
+	return nil
+}
+
 // MarshalXML is extended to leave out
 // <NoncurrentVersionTransition></NoncurrentVersionTransition> tags
 func (n NoncurrentVersionTransition) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
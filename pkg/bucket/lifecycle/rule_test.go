@@ -59,6 +59,24 @@ func TestInvalidRules(t *testing.T) {
 	                    </Rule>`,
 			expectedErr: errInvalidRuleStatus,
 		},
+		{ // Rule with AbortIncompleteMultipartUpload without days
+			inputXML: ` <Rule>
+			                  <ID>rule with abort without days</ID>
+                              <Status>Enabled</Status>
+                              <AbortIncompleteMultipartUpload></AbortIncompleteMultipartUpload>
+	                    </Rule>`,
+			expectedErr: errAbortIncompleteMultipartUploadNoDays,
+		},
+		{ // Rule with AbortIncompleteMultipartUpload only
+			inputXML: ` <Rule>
+			                  <ID>rule with abort only</ID>
+                              <Status>Enabled</Status>
+                              <AbortIncompleteMultipartUpload>
+                                  <DaysAfterInitiation>7</DaysAfterInitiation>
+                              </AbortIncompleteMultipartUpload>
+	                    </Rule>`,
+			expectedErr: nil,
+		},
 	}
 
 	for i, tc := range invalidTestCases {
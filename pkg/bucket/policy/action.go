@@ -172,6 +172,14 @@ const (
 
 	// RestoreObjectAction - RestoreObject REST API action
 	RestoreObjectAction = "s3:RestoreObject"
+
+	// GetInventoryConfigurationAction - GetBucketInventoryConfiguration and
+	// ListBucketInventoryConfigurations REST API action
+	GetInventoryConfigurationAction = "s3:GetInventoryConfiguration"
+
+	// PutInventoryConfigurationAction - PutBucketInventoryConfiguration and
+	// DeleteBucketInventoryConfiguration REST API action
+	PutInventoryConfigurationAction = "s3:PutInventoryConfiguration"
 )
 
 // List of all supported object actions.
@@ -260,6 +268,8 @@ var supportedActions = map[Action]struct{}{
 	ReplicateTagsAction:                    {},
 	GetObjectVersionForReplicationAction:   {},
 	RestoreObjectAction:                    {},
+	GetInventoryConfigurationAction:        {},
+	PutInventoryConfigurationAction:        {},
 }
 
 // IsValid - checks if action is valid or not.
@@ -319,6 +329,7 @@ var actionConditionKeyMap = map[Action]condition.KeySet{
 		append([]condition.Key{
 			condition.S3XAmzServerSideEncryption,
 			condition.S3XAmzServerSideEncryptionCustomerAlgorithm,
+			condition.S3ExistingObjectTag,
 		}, condition.CommonKeys...)...),
 
 	HeadBucketAction: condition.NewKeySet(condition.CommonKeys...),
@@ -357,6 +368,8 @@ var actionConditionKeyMap = map[Action]condition.KeySet{
 			condition.S3ObjectLockRetainUntilDate,
 			condition.S3ObjectLockMode,
 			condition.S3ObjectLockLegalHold,
+			condition.S3RequestObjectTag,
+			condition.S3RequestObjectTagKeys,
 		}, condition.CommonKeys...)...),
 
 	// https://docs.aws.amazon.com/AmazonS3/latest/dev/list_amazons3.html
@@ -388,18 +401,37 @@ var actionConditionKeyMap = map[Action]condition.KeySet{
 	PutBucketObjectLockConfigurationAction: condition.NewKeySet(condition.CommonKeys...),
 	GetBucketTaggingAction:                 condition.NewKeySet(condition.CommonKeys...),
 	PutBucketTaggingAction:                 condition.NewKeySet(condition.CommonKeys...),
-	PutObjectTaggingAction:                 condition.NewKeySet(condition.CommonKeys...),
-	GetObjectTaggingAction:                 condition.NewKeySet(condition.CommonKeys...),
-	DeleteObjectTaggingAction:              condition.NewKeySet(condition.CommonKeys...),
+	PutObjectTaggingAction: condition.NewKeySet(
+		append([]condition.Key{
+			condition.S3ExistingObjectTag,
+			condition.S3RequestObjectTag,
+			condition.S3RequestObjectTagKeys,
+		}, condition.CommonKeys...)...),
+	GetObjectTaggingAction: condition.NewKeySet(
+		append([]condition.Key{
+			condition.S3ExistingObjectTag,
+		}, condition.CommonKeys...)...),
+	DeleteObjectTaggingAction: condition.NewKeySet(
+		append([]condition.Key{
+			condition.S3ExistingObjectTag,
+		}, condition.CommonKeys...)...),
 
-	PutObjectVersionTaggingAction: condition.NewKeySet(condition.CommonKeys...),
+	PutObjectVersionTaggingAction: condition.NewKeySet(
+		append([]condition.Key{
+			condition.S3VersionID,
+			condition.S3ExistingObjectTag,
+			condition.S3RequestObjectTag,
+			condition.S3RequestObjectTagKeys,
+		}, condition.CommonKeys...)...),
 	GetObjectVersionAction: condition.NewKeySet(
 		append([]condition.Key{
 			condition.S3VersionID,
+			condition.S3ExistingObjectTag,
 		}, condition.CommonKeys...)...),
 	GetObjectVersionTaggingAction: condition.NewKeySet(
 		append([]condition.Key{
 			condition.S3VersionID,
+			condition.S3ExistingObjectTag,
 		}, condition.CommonKeys...)...),
 	DeleteObjectVersionAction: condition.NewKeySet(
 		append([]condition.Key{
@@ -408,6 +440,7 @@ var actionConditionKeyMap = map[Action]condition.KeySet{
 	DeleteObjectVersionTaggingAction: condition.NewKeySet(
 		append([]condition.Key{
 			condition.S3VersionID,
+			condition.S3ExistingObjectTag,
 		}, condition.CommonKeys...)...),
 	GetReplicationConfigurationAction:    condition.NewKeySet(condition.CommonKeys...),
 	PutReplicationConfigurationAction:    condition.NewKeySet(condition.CommonKeys...),
@@ -416,4 +449,6 @@ var actionConditionKeyMap = map[Action]condition.KeySet{
 	ReplicateTagsAction:                  condition.NewKeySet(condition.CommonKeys...),
 	GetObjectVersionForReplicationAction: condition.NewKeySet(condition.CommonKeys...),
 	RestoreObjectAction:                  condition.NewKeySet(condition.CommonKeys...),
+	GetInventoryConfigurationAction:      condition.NewKeySet(condition.CommonKeys...),
+	PutInventoryConfigurationAction:      condition.NewKeySet(condition.CommonKeys...),
 }
@@ -55,6 +55,18 @@ func (functions Functions) Evaluate(values map[string][]string) bool {
 	return true
 }
 
+// Failed - returns the functions not satisfied by given values map.
+func (functions Functions) Failed(values map[string][]string) Functions {
+	var failed Functions
+	for _, f := range functions {
+		if !f.evaluate(values) {
+			failed = append(failed, f)
+		}
+	}
+
+	return failed
+}
+
 // Keys - returns list of keys used in all functions.
 func (functions Functions) Keys() KeySet {
 	keySet := NewKeySet()
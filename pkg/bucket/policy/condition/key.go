@@ -110,8 +110,31 @@ const (
 
 	// AWSUsername - user friendly name, in MinIO this value is same as your user Access Key.
 	AWSUsername Key = "aws:username"
+
+	// S3ExistingObjectTag - key representing the tags of an existing object, a
+	// tag is referenced as "s3:ExistingObjectTag/<tag-key>".
+	S3ExistingObjectTag Key = "s3:ExistingObjectTag"
+
+	// S3RequestObjectTag - key representing the object tags sent in a request,
+	// a tag is referenced as "s3:RequestObjectTag/<tag-key>".
+	S3RequestObjectTag Key = "s3:RequestObjectTag"
+
+	// S3RequestObjectTagKeys - key representing the keys of the object tags
+	// sent in a request.
+	S3RequestObjectTagKeys Key = "s3:RequestObjectTagKeys"
+
+	// AWSPrincipalTag - key representing the tags of the user making a request,
+	// a tag is referenced as "aws:PrincipalTag/<tag-key>".
+	AWSPrincipalTag Key = "aws:PrincipalTag"
 )
 
+// tagKeys - keys referencing a single tag as "<key>/<tag-key>".
+var tagKeys = []Key{
+	S3ExistingObjectTag,
+	S3RequestObjectTag,
+	AWSPrincipalTag,
+}
+
 // AllSupportedKeys - is list of all all supported keys.
 var AllSupportedKeys = append([]Key{
 	S3XAmzCopySource,
@@ -137,6 +160,10 @@ var AllSupportedKeys = append([]Key{
 	AWSPrincipalType,
 	AWSUserID,
 	AWSUsername,
+	S3ExistingObjectTag,
+	S3RequestObjectTag,
+	S3RequestObjectTagKeys,
+	AWSPrincipalTag,
 	LDAPUser,
 	// Add new supported condition keys.
 }, JWTKeys...)
@@ -152,6 +179,7 @@ var CommonKeys = append([]Key{
 	AWSPrincipalType,
 	AWSUserID,
 	AWSUsername,
+	AWSPrincipalTag,
 	S3XAmzContentSha256,
 	LDAPUser,
 }, JWTKeys...)
@@ -164,14 +192,51 @@ func substFuncFromValues(values map[string][]string) func(string) string {
 				v = strings.Replace(v, key.VarName(), rvalues[0], -1)
 			}
 		}
+		// Principal tags are supported as policy variables such as
+		// "${aws:PrincipalTag/<tag-key>}".
+		if strings.Contains(v, "${"+string(AWSPrincipalTag)+"/") {
+			for name, rvalues := range values {
+				if strings.HasPrefix(name, AWSPrincipalTag.Name()+"/") && len(rvalues) > 0 && rvalues[0] != "" {
+					v = strings.Replace(v, Key("aws:"+name).VarName(), rvalues[0], -1)
+				}
+			}
+		}
 		return v
 	}
 }
 
+// TagKey - returns the key referencing a single tag, such as
+// "s3:ExistingObjectTag/<tag-key>" for S3ExistingObjectTag.
+func (key Key) TagKey(tag string) Key {
+	return Key(string(key) + "/" + tag)
+}
+
+// Base - returns the key without its tag, such as S3ExistingObjectTag
+// for "s3:ExistingObjectTag/<tag-key>", other keys are returned as is.
+func (key Key) Base() Key {
+	if i := strings.Index(string(key), "/"); i >= 0 {
+		return key[:i]
+	}
+	return key
+}
+
 // IsValid - checks if key is valid or not.
 func (key Key) IsValid() bool {
+	base := key.Base()
+	var isTagKey bool
+	for _, tagKey := range tagKeys {
+		if tagKey == base {
+			isTagKey = true
+			break
+		}
+	}
+	// Tag keys must reference a tag, other keys must not.
+	if isTagKey != (base != key) || string(key) == string(base)+"/" {
+		return false
+	}
+
 	for _, supKey := range AllSupportedKeys {
-		if supKey == key {
+		if supKey == base {
 			return true
 		}
 	}
@@ -241,7 +306,8 @@ func (set KeySet) Add(key Key) {
 	set[key] = struct{}{}
 }
 
-// Difference - returns a key set contains difference of two keys.
+// Difference - returns a key set contains difference of two keys, a key
+// referencing a tag is in a set containing its base key.
 // Example:
 //     keySet1 := ["one", "two", "three"]
 //     keySet2 := ["two", "four", "three"]
@@ -250,7 +316,7 @@ func (set KeySet) Difference(sset KeySet) KeySet {
 	nset := make(KeySet)
 
 	for k := range set {
-		if _, ok := sset[k]; !ok {
+		if !sset.Match(k) {
 			nset.Add(k)
 		}
 	}
@@ -258,6 +324,16 @@ func (set KeySet) Difference(sset KeySet) KeySet {
 	return nset
 }
 
+// Match - returns whether the key set contains the key or, for a key
+// referencing a tag, its base key.
+func (set KeySet) Match(key Key) bool {
+	if _, ok := set[key]; ok {
+		return true
+	}
+	_, ok := set[key.Base()]
+	return ok
+}
+
 // IsEmpty - returns whether key set is empty or not.
 func (set KeySet) IsEmpty() bool {
 	return len(set) == 0
@@ -296,5 +372,6 @@ var AllSupportedAdminKeys = []Key{
 	AWSSecureTransport,
 	AWSCurrentTime,
 	AWSEpochTime,
+	AWSPrincipalTag,
 	// Add new supported condition keys.
 }
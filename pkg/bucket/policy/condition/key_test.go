@@ -38,6 +38,14 @@ func TestKeyIsValid(t *testing.T) {
 		{S3MaxKeys, true},
 		{AWSReferer, true},
 		{AWSSourceIP, true},
+		{S3ExistingObjectTag.TagKey("security"), true},
+		{S3RequestObjectTag.TagKey("project/name"), true},
+		{S3RequestObjectTagKeys, true},
+		{AWSPrincipalTag.TagKey("department"), true},
+		{S3ExistingObjectTag, false},
+		{Key("s3:ExistingObjectTag/"), false},
+		{Key("s3:RequestObjectTagKeys/security"), false},
+		{Key("s3:prefix/foo"), false},
 		{Key("foo"), false},
 	}
 
@@ -83,6 +91,8 @@ func TestKeyName(t *testing.T) {
 	}{
 		{S3XAmzCopySource, "x-amz-copy-source"},
 		{AWSReferer, "Referer"},
+		{S3ExistingObjectTag.TagKey("security"), "ExistingObjectTag/security"},
+		{AWSPrincipalTag.TagKey("department"), "PrincipalTag/department"},
 	}
 
 	for i, testCase := range testCases {
@@ -148,6 +158,7 @@ func TestKeySetDifference(t *testing.T) {
 	}{
 		{NewKeySet(), NewKeySet(S3XAmzCopySource), NewKeySet()},
 		{NewKeySet(S3Prefix, S3Delimiter, S3MaxKeys), NewKeySet(S3Delimiter, S3MaxKeys), NewKeySet(S3Prefix)},
+		{NewKeySet(S3ExistingObjectTag.TagKey("security"), S3RequestObjectTag.TagKey("security")), NewKeySet(S3ExistingObjectTag), NewKeySet(S3RequestObjectTag.TagKey("security"))},
 	}
 
 	for i, testCase := range testCases {
@@ -212,3 +223,25 @@ func TestKeySetToSlice(t *testing.T) {
 		}
 	}
 }
+
+func TestSubstFuncFromValuesPrincipalTag(t *testing.T) {
+	subst := substFuncFromValues(map[string][]string{
+		"username":                {"john"},
+		"PrincipalTag/department": {"finance"},
+	})
+
+	testCases := []struct {
+		value          string
+		expectedResult string
+	}{
+		{"${aws:username}/${aws:PrincipalTag/department}", "john/finance"},
+		{"${aws:PrincipalTag/project}", "${aws:PrincipalTag/project}"},
+		{"department", "department"},
+	}
+
+	for i, testCase := range testCases {
+		if result := subst(testCase.value); result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+		}
+	}
+}
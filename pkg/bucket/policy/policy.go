@@ -19,6 +19,8 @@ package policy
 import (
 	"encoding/json"
 	"io"
+
+	"github.com/minio/minio/pkg/bucket/policy/condition"
 )
 
 // DefaultVersion - default policy version as per AWS S3 specification.
@@ -69,6 +71,49 @@ func (policy Policy) IsAllowed(args Args) bool {
 	return false
 }
 
+// StatementMatch - statement applying to the principal, the action and
+// the resource of a request, it matches the request if none of its
+// conditions failed.
+type StatementMatch struct {
+	Statement        Statement
+	FailedConditions condition.Functions
+}
+
+// Explain - checks given policy args is allowed to continue the Rest API
+// and returns the statements applying to its principal, action and
+// resource.
+func (policy Policy) Explain(args Args) (bool, []StatementMatch) {
+	var matches []StatementMatch
+	for _, statement := range policy.Statements {
+		if statement.appliesTo(args) {
+			matches = append(matches, StatementMatch{
+				Statement:        statement,
+				FailedConditions: statement.Conditions.Failed(args.ConditionValues),
+			})
+		}
+	}
+
+	return policy.IsAllowed(args), matches
+}
+
+// HasConditionKey - returns whether a statement of the policy for the
+// action has a condition on the key, conditions on a single tag such as
+// "s3:ExistingObjectTag/<tag-key>" are conditions on their base key.
+func (policy Policy) HasConditionKey(action Action, key condition.Key) bool {
+	for _, statement := range policy.Statements {
+		if !statement.Actions.Contains(action) {
+			continue
+		}
+		for k := range statement.Conditions.Keys() {
+			if k.Base() == key {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 // IsEmpty - returns whether policy is empty or not.
 func (policy Policy) IsEmpty() bool {
 	return len(policy.Statements) == 0
@@ -33,27 +33,34 @@ type Statement struct {
 	Conditions condition.Functions `json:"Condition,omitempty"`
 }
 
-// IsAllowed - checks given policy args is allowed to continue the Rest API.
-func (statement Statement) IsAllowed(args Args) bool {
-	check := func() bool {
-		if !statement.Principal.Match(args.AccountName) {
-			return false
-		}
+// appliesTo - checks whether statement applies to the principal, the
+// action and the resource of given policy args, regardless of its
+// conditions.
+func (statement Statement) appliesTo(args Args) bool {
+	if !statement.Principal.Match(args.AccountName) {
+		return false
+	}
 
-		if !statement.Actions.Contains(args.Action) {
-			return false
+	if !statement.Actions.Contains(args.Action) {
+		return false
+	}
+
+	resource := args.BucketName
+	if args.ObjectName != "" {
+		if !strings.HasPrefix(args.ObjectName, "/") {
+			resource += "/"
 		}
 
-		resource := args.BucketName
-		if args.ObjectName != "" {
-			if !strings.HasPrefix(args.ObjectName, "/") {
-				resource += "/"
-			}
+		resource += args.ObjectName
+	}
 
-			resource += args.ObjectName
-		}
+	return statement.Resources.Match(resource, args.ConditionValues)
+}
 
-		if !statement.Resources.Match(resource, args.ConditionValues) {
+// IsAllowed - checks given policy args is allowed to continue the Rest API.
+func (statement Statement) IsAllowed(args Args) bool {
+	check := func() bool {
+		if !statement.appliesTo(args) {
 			return false
 		}
 
@@ -55,7 +55,7 @@ var (
 	errReplicationTooManyRules        = Errorf("Replication configuration allows a maximum of 1000 rules")
 	errReplicationNoRule              = Errorf("Replication configuration should have at least one rule")
 	errReplicationUniquePriority      = Errorf("Replication configuration has duplicate priority")
-	errReplicationDestinationMismatch = Errorf("The destination bucket must be same for all rules")
+	errReplicationDestinationMismatch = Errorf("The destination bucket must be same for all rules of a target")
 	errRoleArnMissing                 = Errorf("Missing required parameter `Role` in ReplicationConfiguration")
 )
 
@@ -90,19 +90,18 @@ func (c Config) Validate(bucket string, sameTarget bool) error {
 	if len(c.Rules) == 0 {
 		return errReplicationNoRule
 	}
-	if c.RoleArn == "" {
-		return errRoleArnMissing
-	}
 	// Validate all the rules in the replication config
-	targetMap := make(map[string]struct{})
+	targetMap := make(map[string]string)
 	priorityMap := make(map[string]struct{})
 	for _, r := range c.Rules {
-		if len(targetMap) == 0 {
-			targetMap[r.Destination.Bucket] = struct{}{}
+		arn := c.TargetArn(r)
+		if arn == "" {
+			return errRoleArnMissing
 		}
-		if _, ok := targetMap[r.Destination.Bucket]; !ok {
+		if dest, ok := targetMap[arn]; ok && dest != r.Destination.Bucket {
 			return errReplicationDestinationMismatch
 		}
+		targetMap[arn] = r.Destination.Bucket
 		if err := r.Validate(bucket, sameTarget); err != nil {
 			return err
 		}
@@ -123,6 +122,41 @@ type ObjectOpts struct {
 	IsLatest     bool
 	DeleteMarker bool
 	SSEC         bool
+	// TargetArn restricts the evaluation to the rules of a target.
+	TargetArn string
+}
+
+// TargetArn returns the ARN of the remote target the rule replicates to.
+func (c Config) TargetArn(r Rule) string {
+	if r.Destination.ARN != "" {
+		return r.Destination.ARN
+	}
+	return c.RoleArn
+}
+
+// TargetArns returns the ARNs of all remote targets of the configuration.
+func (c Config) TargetArns() []string {
+	var arns []string
+	seen := make(map[string]struct{})
+	for _, r := range c.Rules {
+		arn := c.TargetArn(r)
+		if _, ok := seen[arn]; ok || arn == "" {
+			continue
+		}
+		seen[arn] = struct{}{}
+		arns = append(arns, arn)
+	}
+	return arns
+}
+
+// HasTarget returns true if any rule replicates to the target.
+func (c Config) HasTarget(arn string) bool {
+	for _, r := range c.Rules {
+		if c.TargetArn(r) == arn {
+			return true
+		}
+	}
+	return false
 }
 
 // FilterActionableRules returns the rules actions that need to be executed
@@ -136,6 +170,9 @@ func (c Config) FilterActionableRules(obj ObjectOpts) []Rule {
 		if rule.Status == Disabled {
 			continue
 		}
+		if obj.TargetArn != "" && obj.TargetArn != c.TargetArn(rule) {
+			continue
+		}
 		if !strings.HasPrefix(obj.Name, rule.Prefix()) {
 			continue
 		}
@@ -157,8 +194,35 @@ func (c Config) GetDestination() Destination {
 	return Destination{}
 }
 
-// Replicate returns true if the object should be replicated.
+// GetTargetDestination returns the destination of the highest priority
+// rule of the target matching the object.
+func (c Config) GetTargetDestination(obj ObjectOpts) Destination {
+	if rules := c.FilterActionableRules(obj); len(rules) > 0 {
+		return rules[0].Destination
+	}
+	return Destination{}
+}
+
+// FilterTargetArns returns the ARNs of the remote targets the object
+// should be replicated to.
+func (c Config) FilterTargetArns(obj ObjectOpts) []string {
+	var arns []string
+	for _, arn := range c.TargetArns() {
+		opts := obj
+		opts.TargetArn = arn
+		if c.Replicate(opts) {
+			arns = append(arns, arn)
+		}
+	}
+	return arns
+}
+
+// Replicate returns true if the object should be replicated, to any
+// target unless obj.TargetArn is set.
 func (c Config) Replicate(obj ObjectOpts) bool {
+	if obj.TargetArn == "" {
+		return len(c.FilterTargetArns(obj)) > 0
+	}
 
 	for _, rule := range c.FilterActionableRules(obj) {
 		// check MinIO extension for versioned deletes
@@ -0,0 +1,125 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package replication
+
+import (
+	"bytes"
+	"encoding/xml"
+	"reflect"
+	"testing"
+)
+
+const (
+	testArn1 = "arn:minio:replication:us-east-1:c5be6b16-769d-432a-9ef1-4567081f3566:dest1"
+	testArn2 = "arn:minio:replication:us-east-1:8a8b1d3c-0f45-4c57-9b3e-8d1f1a5a2b11:dest2"
+)
+
+func TestParseConfigMultipleTargets(t *testing.T) {
+	data := `<ReplicationConfiguration>
+<Rule><ID>dr</ID><Status>Enabled</Status><Priority>2</Priority>
+<DeleteMarkerReplication><Status>Enabled</Status></DeleteMarkerReplication>
+<DeleteReplication><Status>Disabled</Status></DeleteReplication>
+<Destination><Bucket>` + testArn1 + `</Bucket></Destination><Filter><Prefix></Prefix></Filter></Rule>
+<Rule><ID>analytics</ID><Status>Enabled</Status><Priority>1</Priority>
+<DeleteMarkerReplication><Status>Disabled</Status></DeleteMarkerReplication>
+<DeleteReplication><Status>Disabled</Status></DeleteReplication>
+<Destination><Bucket>` + testArn2 + `</Bucket><StorageClass>STANDARD</StorageClass></Destination><Filter><Prefix>logs/</Prefix></Filter></Rule>
+</ReplicationConfiguration>`
+
+	cfg, err := ParseConfig(bytes.NewReader([]byte(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = cfg.Validate("source", false); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Rules[0].Destination.Bucket != "dest1" || cfg.Rules[0].Destination.ARN != testArn1 {
+		t.Fatalf("unexpected destination %#v", cfg.Rules[0].Destination)
+	}
+	if !reflect.DeepEqual(cfg.TargetArns(), []string{testArn1, testArn2}) {
+		t.Fatalf("unexpected targets %v", cfg.TargetArns())
+	}
+
+	testCases := []struct {
+		opts     ObjectOpts
+		expected []string
+	}{
+		{ObjectOpts{Name: "logs/a"}, []string{testArn1, testArn2}},
+		{ObjectOpts{Name: "data/a"}, []string{testArn1}},
+		{ObjectOpts{Name: "logs/a", DeleteMarker: true}, []string{testArn1}},
+		{ObjectOpts{Name: "logs/a", VersionID: "v1"}, nil},
+	}
+	for i, tc := range testCases {
+		if arns := cfg.FilterTargetArns(tc.opts); !reflect.DeepEqual(arns, tc.expected) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, tc.expected, arns)
+		}
+	}
+
+	opts := ObjectOpts{Name: "logs/a", TargetArn: testArn2}
+	if dest := cfg.GetTargetDestination(opts); dest.Bucket != "dest2" || dest.StorageClass != "STANDARD" {
+		t.Fatalf("unexpected destination %#v", dest)
+	}
+
+	// Destination ARNs survive a round trip.
+	out, err := xml.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg2, err := ParseConfig(bytes.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cfg2.TargetArns(), cfg.TargetArns()) {
+		t.Fatalf("unexpected targets after round trip %v", cfg2.TargetArns())
+	}
+}
+
+func TestConfigValidateTargets(t *testing.T) {
+	rule := func(dest Destination, priority int) Rule {
+		return Rule{
+			Status:                  Enabled,
+			Priority:                priority,
+			DeleteMarkerReplication: DeleteMarkerReplication{Status: Disabled},
+			DeleteReplication:       DeleteReplication{Status: Disabled},
+			Destination:             dest,
+		}
+	}
+	testCases := []struct {
+		cfg         Config
+		expectedErr error
+	}{
+		// Legacy configuration with a single Role.
+		{Config{RoleArn: testArn1, Rules: []Rule{rule(Destination{Bucket: "dest1"}, 1)}}, nil},
+		// Rules without target.
+		{Config{Rules: []Rule{rule(Destination{Bucket: "dest1"}, 1)}}, errRoleArnMissing},
+		// Rules of different targets replicating to different buckets.
+		{Config{Rules: []Rule{
+			rule(Destination{Bucket: "dest1", ARN: testArn1}, 1),
+			rule(Destination{Bucket: "dest2", ARN: testArn2}, 2),
+		}}, nil},
+		// Rules of the same target replicating to different buckets.
+		{Config{RoleArn: testArn1, Rules: []Rule{
+			rule(Destination{Bucket: "dest1"}, 1),
+			rule(Destination{Bucket: "dest2"}, 2),
+		}}, errReplicationDestinationMismatch},
+	}
+	for i, tc := range testCases {
+		if err := tc.cfg.Validate("source", false); err != tc.expectedErr {
+			t.Errorf("Test %d: expected %v, got %v", i+1, tc.expectedErr, err)
+		}
+	}
+}
@@ -24,7 +24,7 @@ import (
 // Name - event type enum.
 // Refer http://docs.aws.amazon.com/AmazonS3/latest/dev/NotificationHowTo.html#notification-how-to-event-types-and-destinations
 // for most basic values we have since extend this and its not really much applicable other than a reference point.
-// "s3:Replication:OperationCompletedReplication" is a MinIO extension.
+// "s3:Replication:OperationCompletedReplication" and "s3:Quota:ThresholdReached" are MinIO extensions.
 type Name int
 
 // Values of event Name
@@ -58,6 +58,9 @@ const (
 	ObjectTransitionAll
 	ObjectTransitionFailed
 	ObjectTransitionComplete
+	QuotaAll
+	QuotaThresholdReached
+	QuotaExceeded
 )
 
 // Expand - returns expanded values of abbreviated event type.
@@ -102,6 +105,11 @@ func (name Name) Expand() []Name {
 			ObjectTransitionFailed,
 			ObjectTransitionComplete,
 		}
+	case QuotaAll:
+		return []Name{
+			QuotaThresholdReached,
+			QuotaExceeded,
+		}
 	default:
 		return []Name{name}
 	}
@@ -166,6 +174,12 @@ func (name Name) String() string {
 		return "s3:ObjectTransition:Failed"
 	case ObjectTransitionComplete:
 		return "s3:ObjectTransition:Complete"
+	case QuotaAll:
+		return "s3:Quota:*"
+	case QuotaThresholdReached:
+		return "s3:Quota:ThresholdReached"
+	case QuotaExceeded:
+		return "s3:Quota:Exceeded"
 	}
 
 	return ""
@@ -274,6 +288,12 @@ func ParseName(s string) (Name, error) {
 		return ObjectTransitionComplete, nil
 	case "s3:ObjectTransition:*":
 		return ObjectTransitionAll, nil
+	case "s3:Quota:ThresholdReached":
+		return QuotaThresholdReached, nil
+	case "s3:Quota:Exceeded":
+		return QuotaExceeded, nil
+	case "s3:Quota:*":
+		return QuotaAll, nil
 	default:
 		return 0, &ErrInvalidEventName{s}
 	}
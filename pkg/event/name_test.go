@@ -35,6 +35,7 @@ func TestNameExpand(t *testing.T) {
 			ObjectCreatedPost, ObjectCreatedPut, ObjectCreatedPutRetention, ObjectCreatedPutLegalHold, ObjectReplicationComplete, ObjectReplicationFailed}},
 		{ObjectRemovedAll, []Name{ObjectRemovedDelete, ObjectRemovedDeleteMarkerCreated}},
 		{ObjectAccessedHead, []Name{ObjectAccessedHead}},
+		{QuotaAll, []Name{QuotaThresholdReached, QuotaExceeded}},
 	}
 
 	for i, testCase := range testCases {
@@ -69,6 +70,8 @@ func TestNameString(t *testing.T) {
 		{ObjectCreatedPutLegalHold, "s3:ObjectCreated:PutLegalHold"},
 		{ObjectAccessedGetRetention, "s3:ObjectAccessed:GetRetention"},
 		{ObjectAccessedGetLegalHold, "s3:ObjectAccessed:GetLegalHold"},
+		{QuotaThresholdReached, "s3:Quota:ThresholdReached"},
+		{QuotaExceeded, "s3:Quota:Exceeded"},
 
 		{blankName, ""},
 	}
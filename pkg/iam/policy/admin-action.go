@@ -102,6 +102,16 @@ const (
 	AttachPolicyAdminAction = "admin:AttachUserOrGroupPolicy"
 	// ListUserPoliciesAdminAction - allows listing user policies
 	ListUserPoliciesAdminAction = "admin:ListUserPolicies"
+	// SimulatePolicyAdminAction - allows simulating the policies applying to a request
+	SimulatePolicyAdminAction = "admin:SimulatePolicy"
+	// ExportIAMAdminAction - allows exporting the users, groups, policies and service accounts
+	ExportIAMAdminAction = "admin:ExportIAM"
+	// ImportIAMAdminAction - allows importing the users, groups, policies and service accounts
+	ImportIAMAdminAction = "admin:ImportIAM"
+	// ListSTSSessionsAdminAction - allows listing the temporary credentials of users
+	ListSTSSessionsAdminAction = "admin:ListSTSSessions"
+	// RevokeSTSSessionsAdminAction - allows revoking the temporary credentials of users
+	RevokeSTSSessionsAdminAction = "admin:RevokeSTSSessions"
 
 	// Bucket quota Actions
 
@@ -109,6 +119,14 @@ const (
 	SetBucketQuotaAdminAction = "admin:SetBucketQuota"
 	// GetBucketQuotaAdminAction - allow getting bucket quota
 	GetBucketQuotaAdminAction = "admin:GetBucketQuota"
+	// SetUserQuotaAdminAction - allow setting the quota of users and groups
+	SetUserQuotaAdminAction = "admin:SetUserQuota"
+	// GetUserQuotaAdminAction - allow getting the quota of users and groups
+	GetUserQuotaAdminAction = "admin:GetUserQuota"
+	// SetRateLimitAdminAction - allow setting the rate limits of users, groups and buckets
+	SetRateLimitAdminAction = "admin:SetRateLimit"
+	// GetRateLimitAdminAction - allow getting the rate limits of users, groups and buckets
+	GetRateLimitAdminAction = "admin:GetRateLimit"
 
 	// Bucket Target admin Actions
 
@@ -116,6 +134,14 @@ const (
 	SetBucketTargetAction = "admin:SetBucketTarget"
 	// GetBucketTargetAction - allow getting bucket targets
 	GetBucketTargetAction = "admin:GetBucketTarget"
+	// ReplicationInfoAdminAction - allow getting replication queue and metrics
+	ReplicationInfoAdminAction = "admin:ReplicationInfo"
+	// ReplicationResyncAdminAction - allow starting and canceling replication resyncs
+	ReplicationResyncAdminAction = "admin:ReplicationResync"
+
+	// LifecycleInfoAdminAction - allow previewing lifecycle configurations
+	// and listing the lifecycle actions applied
+	LifecycleInfoAdminAction = "admin:LifecycleInfo"
 
 	// AllAdminActions - provides all admin permissions
 	AllAdminActions = "admin:*"
@@ -155,10 +181,22 @@ var supportedAdminActions = map[AdminAction]struct{}{
 	GetPolicyAdminAction:           {},
 	AttachPolicyAdminAction:        {},
 	ListUserPoliciesAdminAction:    {},
+	SimulatePolicyAdminAction:      {},
+	ExportIAMAdminAction:           {},
+	ImportIAMAdminAction:           {},
+	ListSTSSessionsAdminAction:     {},
+	RevokeSTSSessionsAdminAction:   {},
 	SetBucketQuotaAdminAction:      {},
 	GetBucketQuotaAdminAction:      {},
+	SetUserQuotaAdminAction:        {},
+	GetUserQuotaAdminAction:        {},
+	SetRateLimitAdminAction:        {},
+	GetRateLimitAdminAction:        {},
 	SetBucketTargetAction:          {},
 	GetBucketTargetAction:          {},
+	ReplicationInfoAdminAction:     {},
+	ReplicationResyncAdminAction:   {},
+	LifecycleInfoAdminAction:       {},
 	AllAdminActions:                {},
 }
 
@@ -202,8 +240,20 @@ var adminActionConditionKeyMap = map[Action]condition.KeySet{
 	GetPolicyAdminAction:           condition.NewKeySet(condition.AllSupportedAdminKeys...),
 	AttachPolicyAdminAction:        condition.NewKeySet(condition.AllSupportedAdminKeys...),
 	ListUserPoliciesAdminAction:    condition.NewKeySet(condition.AllSupportedAdminKeys...),
+	SimulatePolicyAdminAction:      condition.NewKeySet(condition.AllSupportedAdminKeys...),
+	ExportIAMAdminAction:           condition.NewKeySet(condition.AllSupportedAdminKeys...),
+	ImportIAMAdminAction:           condition.NewKeySet(condition.AllSupportedAdminKeys...),
+	ListSTSSessionsAdminAction:     condition.NewKeySet(condition.AllSupportedAdminKeys...),
+	RevokeSTSSessionsAdminAction:   condition.NewKeySet(condition.AllSupportedAdminKeys...),
 	SetBucketQuotaAdminAction:      condition.NewKeySet(condition.AllSupportedAdminKeys...),
 	GetBucketQuotaAdminAction:      condition.NewKeySet(condition.AllSupportedAdminKeys...),
+	SetUserQuotaAdminAction:        condition.NewKeySet(condition.AllSupportedAdminKeys...),
+	GetUserQuotaAdminAction:        condition.NewKeySet(condition.AllSupportedAdminKeys...),
+	SetRateLimitAdminAction:        condition.NewKeySet(condition.AllSupportedAdminKeys...),
+	GetRateLimitAdminAction:        condition.NewKeySet(condition.AllSupportedAdminKeys...),
 	SetBucketTargetAction:          condition.NewKeySet(condition.AllSupportedAdminKeys...),
 	GetBucketTargetAction:          condition.NewKeySet(condition.AllSupportedAdminKeys...),
+	ReplicationInfoAdminAction:     condition.NewKeySet(condition.AllSupportedAdminKeys...),
+	ReplicationResyncAdminAction:   condition.NewKeySet(condition.AllSupportedAdminKeys...),
+	LifecycleInfoAdminAction:       condition.NewKeySet(condition.AllSupportedAdminKeys...),
 }
@@ -23,6 +23,7 @@ import (
 
 	"github.com/minio/minio-go/v7/pkg/set"
 	"github.com/minio/minio/pkg/bucket/policy"
+	"github.com/minio/minio/pkg/bucket/policy/condition"
 )
 
 // DefaultVersion - default policy version as per AWS S3 specification.
@@ -122,6 +123,60 @@ func (iamp Policy) IsAllowed(args Args) bool {
 	return false
 }
 
+// StatementMatch - statement applying to the action and the resource of
+// a request, it matches the request if none of its conditions failed.
+type StatementMatch struct {
+	Statement        Statement
+	FailedConditions condition.Functions
+}
+
+// Explain - checks given policy args is allowed to continue the Rest API
+// and returns the statements applying to its action and resource.
+func (iamp Policy) Explain(args Args) (bool, []StatementMatch) {
+	var matches []StatementMatch
+	for _, statement := range iamp.Statements {
+		if statement.appliesTo(args) {
+			matches = append(matches, StatementMatch{
+				Statement:        statement,
+				FailedConditions: statement.Conditions.Failed(args.ConditionValues),
+			})
+		}
+	}
+
+	return iamp.IsAllowed(args), matches
+}
+
+// HasConditionKey - returns whether a statement of the policy for the
+// action has a condition on the key, conditions on a single tag such as
+// "s3:ExistingObjectTag/<tag-key>" are conditions on their base key.
+func (iamp Policy) HasConditionKey(action Action, key condition.Key) bool {
+	for _, statement := range iamp.Statements {
+		if !statement.Actions.Match(action) {
+			continue
+		}
+		for k := range statement.Conditions.Keys() {
+			if k.Base() == key {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// IsWithinBoundary - returns whether every action and resource allowed by
+// the policy is unconditionally allowed by the boundary policy. Wildcard
+// actions and resources must be matched by the boundary as a whole.
+func (iamp Policy) IsWithinBoundary(boundary Policy) bool {
+	for _, statement := range iamp.Statements {
+		if !statement.isWithin(boundary) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // IsEmpty - returns whether policy is empty or not.
 func (iamp Policy) IsEmpty() bool {
 	return len(iamp.Statements) == 0
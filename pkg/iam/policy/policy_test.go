@@ -223,6 +223,181 @@ func TestPolicyIsAllowed(t *testing.T) {
 	}
 }
 
+func TestPolicyExplain(t *testing.T) {
+	_, IPNet, err := net.ParseCIDR("192.168.1.0/24")
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+	func1, err := condition.NewIPAddressFunc(
+		condition.AWSSourceIP,
+		IPNet,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	p := Policy{
+		Version: DefaultVersion,
+		Statements: []Statement{
+			NewStatement(
+				policy.Allow,
+				NewActionSet(GetObjectAction),
+				NewResourceSet(NewResource("mybucket", "/myobject*")),
+				condition.NewFunctions(),
+			),
+			NewStatement(
+				policy.Deny,
+				NewActionSet(GetObjectAction),
+				NewResourceSet(NewResource("mybucket", "*")),
+				condition.NewFunctions(func1),
+			),
+			NewStatement(
+				policy.Allow,
+				NewActionSet(PutObjectAction),
+				NewResourceSet(NewResource("*", "")),
+				condition.NewFunctions(),
+			),
+		},
+	}
+
+	testCases := []struct {
+		sourceIP       string
+		expectedResult bool
+		expectedFailed []int
+	}{
+		// Deny statement matches the source IP.
+		{"192.168.1.10", false, []int{0, 0}},
+		// Deny statement does not apply, its condition failed.
+		{"10.0.0.1", true, []int{0, 1}},
+	}
+
+	for i, testCase := range testCases {
+		result, matches := p.Explain(Args{
+			AccountName:     "Q3AM3UQ867SPQQA43P2F",
+			Action:          GetObjectAction,
+			BucketName:      "mybucket",
+			ObjectName:      "myobject",
+			ConditionValues: map[string][]string{"SourceIp": {testCase.sourceIP}},
+		})
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+		}
+		if len(matches) != len(testCase.expectedFailed) {
+			t.Fatalf("case %v: expected: %v matches, got: %v\n", i+1, len(testCase.expectedFailed), len(matches))
+		}
+		for j, match := range matches {
+			if len(match.FailedConditions) != testCase.expectedFailed[j] {
+				t.Fatalf("case %v: statement %v: expected: %v failed conditions, got: %v\n", i+1, j+1, testCase.expectedFailed[j], match.FailedConditions)
+			}
+		}
+	}
+}
+
+func TestPolicyHasConditionKey(t *testing.T) {
+	func1, err := condition.NewStringEqualsFunc(
+		condition.S3ExistingObjectTag.TagKey("project"),
+		"blue",
+	)
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	p := Policy{
+		Version: DefaultVersion,
+		Statements: []Statement{
+			NewStatement(
+				policy.Allow,
+				NewActionSet(GetObjectAction),
+				NewResourceSet(NewResource("mybucket", "*")),
+				condition.NewFunctions(func1),
+			),
+			NewStatement(
+				policy.Allow,
+				NewActionSet(PutObjectAction),
+				NewResourceSet(NewResource("mybucket", "*")),
+				condition.NewFunctions(),
+			),
+		},
+	}
+
+	testCases := []struct {
+		action         Action
+		key            condition.Key
+		expectedResult bool
+	}{
+		{GetObjectAction, condition.S3ExistingObjectTag, true},
+		{GetObjectAction, condition.S3RequestObjectTag, false},
+		{PutObjectAction, condition.S3ExistingObjectTag, false},
+		{DeleteObjectAction, condition.S3ExistingObjectTag, false},
+	}
+
+	for i, testCase := range testCases {
+		result := p.HasConditionKey(testCase.action, testCase.key)
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestPolicyIsWithinBoundary(t *testing.T) {
+	func1, err := condition.NewStringEqualsFunc(condition.AWSUsername, "foo")
+	if err != nil {
+		t.Fatalf("unexpected error. %v\n", err)
+	}
+
+	boundary := Policy{
+		Version: DefaultVersion,
+		Statements: []Statement{
+			NewStatement(
+				policy.Allow,
+				NewActionSet(GetObjectAction, PutObjectAction),
+				NewResourceSet(NewResource("mybucket", "/*")),
+				condition.NewFunctions(),
+			),
+			NewStatement(
+				policy.Allow,
+				NewActionSet(ListBucketAction),
+				NewResourceSet(NewResource("mybucket", "/*")),
+				condition.NewFunctions(),
+			),
+			NewStatement(
+				policy.Allow,
+				NewActionSet(DeleteObjectAction),
+				NewResourceSet(NewResource("mybucket", "/*")),
+				condition.NewFunctions(func1),
+			),
+		},
+	}
+
+	newPolicy := func(effect policy.Effect, actions ActionSet, resources ResourceSet) Policy {
+		return Policy{
+			Version:    DefaultVersion,
+			Statements: []Statement{NewStatement(effect, actions, resources, condition.NewFunctions())},
+		}
+	}
+
+	testCases := []struct {
+		p              Policy
+		expectedResult bool
+	}{
+		{newPolicy(policy.Allow, NewActionSet(GetObjectAction), NewResourceSet(NewResource("mybucket", "/prefix/*"))), true},
+		{newPolicy(policy.Allow, NewActionSet(ListBucketAction), NewResourceSet(NewResource("mybucket", ""))), true},
+		{newPolicy(policy.Allow, NewActionSet(GetObjectAction), NewResourceSet(NewResource("otherbucket", "/*"))), false},
+		{newPolicy(policy.Allow, NewActionSet(AllActions), NewResourceSet(NewResource("mybucket", "/*"))), false},
+		// Conditional boundary statements do not allow unconditionally.
+		{newPolicy(policy.Allow, NewActionSet(DeleteObjectAction), NewResourceSet(NewResource("mybucket", "/*"))), false},
+		// Denied actions only restrict the policy.
+		{newPolicy(policy.Deny, NewActionSet(AllActions), NewResourceSet(NewResource("*", ""))), true},
+	}
+
+	for i, testCase := range testCases {
+		result := testCase.p.IsWithinBoundary(boundary)
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
 func TestPolicyIsEmpty(t *testing.T) {
 	case1Policy := Policy{
 		Version: DefaultVersion,
@@ -32,26 +32,32 @@ type Statement struct {
 	Conditions condition.Functions `json:"Condition,omitempty"`
 }
 
-// IsAllowed - checks given policy args is allowed to continue the Rest API.
-func (statement Statement) IsAllowed(args Args) bool {
-	check := func() bool {
-		if !statement.Actions.Match(args.Action) {
-			return false
-		}
-
-		resource := args.BucketName
-		if args.ObjectName != "" {
-			if !strings.HasPrefix(args.ObjectName, "/") {
-				resource += "/"
-			}
+// appliesTo - checks whether statement applies to the action and the
+// resource of given policy args, regardless of its conditions.
+func (statement Statement) appliesTo(args Args) bool {
+	if !statement.Actions.Match(args.Action) {
+		return false
+	}
 
-			resource += args.ObjectName
-		} else {
+	resource := args.BucketName
+	if args.ObjectName != "" {
+		if !strings.HasPrefix(args.ObjectName, "/") {
 			resource += "/"
 		}
 
-		// For admin statements, resource match can be ignored.
-		if !statement.Resources.Match(resource, args.ConditionValues) && !statement.isAdmin() {
+		resource += args.ObjectName
+	} else {
+		resource += "/"
+	}
+
+	// For admin statements, resource match can be ignored.
+	return statement.Resources.Match(resource, args.ConditionValues) || statement.isAdmin()
+}
+
+// IsAllowed - checks given policy args is allowed to continue the Rest API.
+func (statement Statement) IsAllowed(args Args) bool {
+	check := func() bool {
+		if !statement.appliesTo(args) {
 			return false
 		}
 
@@ -60,6 +66,59 @@ func (statement Statement) IsAllowed(args Args) bool {
 
 	return statement.Effect.IsAllowed(check())
 }
+
+// allowsAll - checks whether statement unconditionally allows the action
+// on every resource matching the resource pattern.
+func (statement Statement) allowsAll(action Action, pattern string) bool {
+	if statement.Effect != policy.Allow || len(statement.Conditions) != 0 {
+		return false
+	}
+	if !statement.Actions.Match(action) {
+		return false
+	}
+	if pattern == "" {
+		// Admin actions have no resource.
+		return statement.isAdmin()
+	}
+	if statement.Resources.Match(pattern, nil) {
+		return true
+	}
+	// Bucket patterns are matched as "<bucket>/" by requests.
+	return !strings.Contains(pattern, "/") && statement.Resources.Match(pattern+"/", nil)
+}
+
+// isWithin - checks whether every action and resource allowed by the
+// statement is unconditionally allowed by a statement of the boundary.
+func (statement Statement) isWithin(boundary Policy) bool {
+	if statement.Effect != policy.Allow {
+		return true
+	}
+
+	patterns := []string{""}
+	if len(statement.Resources) > 0 {
+		patterns = patterns[:0]
+		for r := range statement.Resources {
+			patterns = append(patterns, r.Pattern)
+		}
+	}
+
+	for action := range statement.Actions {
+		for _, pattern := range patterns {
+			allowed := false
+			for _, b := range boundary.Statements {
+				if b.allowsAll(action, pattern) {
+					allowed = true
+					break
+				}
+			}
+			if !allowed {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 func (statement Statement) isAdmin() bool {
 	for action := range statement.Actions {
 		if AdminAction(action).IsValid() {
@@ -23,6 +23,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 // GroupAddRemove is type for adding/removing members to/from a group.
@@ -68,6 +69,9 @@ type GroupDesc struct {
 	Status  string   `json:"status"`
 	Members []string `json:"members"`
 	Policy  string   `json:"policy"`
+
+	// PermissionBoundary caps the permissions of the members.
+	PermissionBoundary string `json:"permissionBoundary,omitempty"`
 }
 
 // GetGroupDescription - fetches information on a group.
@@ -163,3 +167,48 @@ func (adm *AdminClient) SetGroupStatus(ctx context.Context, group string, status
 
 	return nil
 }
+
+// LDAPGroupSyncStatus - result of the last sync of the LDAP groups of the
+// users with temporary credentials.
+type LDAPGroupSyncStatus struct {
+	Enabled  bool          `json:"enabled"`
+	LastSync time.Time     `json:"lastSync,omitempty"`
+	Duration time.Duration `json:"duration,omitempty"`
+
+	// Number of LDAP users synced.
+	Users int `json:"users"`
+	// Number of temporary credentials whose groups changed.
+	Updated int `json:"updated"`
+	// Number of temporary credentials revoked because their user
+	// was removed, disabled or is no longer in any mapped group.
+	Revoked int `json:"revoked"`
+	// Number of LDAP users whose groups could not be looked up.
+	Failed int    `json:"failed"`
+	Error  string `json:"error,omitempty"`
+}
+
+// LDAPGroupSyncStatus - returns the result of the last LDAP group sync.
+func (adm *AdminClient) LDAPGroupSyncStatus(ctx context.Context) (status LDAPGroupSyncStatus, err error) {
+	reqData := requestData{
+		relPath: adminAPIPrefix + "/ldap-group-sync-status",
+	}
+
+	// Execute GET on /minio/admin/v3/ldap-group-sync-status
+	resp, err := adm.executeMethod(ctx, http.MethodGet, reqData)
+	defer closeResponse(resp)
+	if err != nil {
+		return status, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return status, httpRespToErrorResponse(resp)
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return status, err
+	}
+
+	err = json.Unmarshal(data, &status)
+	return status, err
+}
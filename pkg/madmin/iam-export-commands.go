@@ -0,0 +1,155 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package madmin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// IAMEntityType is the kind of an IAM entity in an IAM archive.
+type IAMEntityType string
+
+const (
+	// IAMPolicyEntity is a canned policy.
+	IAMPolicyEntity IAMEntityType = "policy"
+	// IAMUserEntity is a user.
+	IAMUserEntity IAMEntityType = "user"
+	// IAMServiceAccountEntity is a service account.
+	IAMServiceAccountEntity IAMEntityType = "serviceAccount"
+	// IAMGroupEntity is a group.
+	IAMGroupEntity IAMEntityType = "group"
+	// IAMUserPolicyEntity is the policy mapping of a user.
+	IAMUserPolicyEntity IAMEntityType = "userPolicy"
+	// IAMGroupPolicyEntity is the policy mapping of a group.
+	IAMGroupPolicyEntity IAMEntityType = "groupPolicy"
+)
+
+// IAMEntity identifies an IAM entity changed by an import.
+type IAMEntity struct {
+	Type IAMEntityType `json:"type"`
+	Name string        `json:"name"`
+}
+
+// IAMImportConflict is an entity of an IAM archive which was not
+// imported.
+type IAMImportConflict struct {
+	IAMEntity
+	Reason string `json:"reason"`
+}
+
+// IAMImportOptions - options of an IAM import.
+type IAMImportOptions struct {
+	// Replace the IAM state with the one of the archive, entities
+	// missing from the archive are removed. By default the archive is
+	// merged and existing entities which differ are left unchanged.
+	Replace bool
+	// DryRun only reports the changes the import would make.
+	DryRun bool
+}
+
+// IAMImportResult - changes made, or which would be made on a dry run,
+// by an IAM import.
+type IAMImportResult struct {
+	Added     []IAMEntity         `json:"added,omitempty"`
+	Updated   []IAMEntity         `json:"updated,omitempty"`
+	Removed   []IAMEntity         `json:"removed,omitempty"`
+	Conflicts []IAMImportConflict `json:"conflicts,omitempty"`
+}
+
+// ExportIAM - exports the users, groups, policies, policy mappings and
+// service accounts of the server into an archive encrypted with the
+// given password. Temporary credentials are not exported.
+func (adm *AdminClient) ExportIAM(ctx context.Context, password string) ([]byte, error) {
+	reqData := requestData{
+		relPath: adminAPIPrefix + "/export-iam",
+	}
+
+	// Execute GET on /minio/admin/v3/export-iam
+	resp, err := adm.executeMethod(ctx, http.MethodGet, reqData)
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	data, err := DecryptData(adm.getSecretKey(), resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Encrypt the archive with the password, so that it can be
+	// imported into servers with other credentials.
+	return EncryptData(password, data)
+}
+
+// ImportIAM - imports an archive created by ExportIAM with the same
+// password.
+func (adm *AdminClient) ImportIAM(ctx context.Context, password string, archive []byte, opts IAMImportOptions) (result IAMImportResult, err error) {
+	data, err := DecryptData(password, bytes.NewReader(archive))
+	if err != nil {
+		return result, err
+	}
+
+	econfigBytes, err := EncryptData(adm.getSecretKey(), data)
+	if err != nil {
+		return result, err
+	}
+
+	queryValues := url.Values{}
+	if opts.Replace {
+		queryValues.Set("mode", "replace")
+	} else {
+		queryValues.Set("mode", "merge")
+	}
+	queryValues.Set("dryRun", strconv.FormatBool(opts.DryRun))
+
+	reqData := requestData{
+		relPath:     adminAPIPrefix + "/import-iam",
+		queryValues: queryValues,
+		content:     econfigBytes,
+	}
+
+	// Execute PUT on /minio/admin/v3/import-iam
+	resp, err := adm.executeMethod(ctx, http.MethodPut, reqData)
+	defer closeResponse(resp)
+	if err != nil {
+		return result, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return result, httpRespToErrorResponse(resp)
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return result, err
+	}
+	if err = json.Unmarshal(b, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
@@ -140,6 +140,30 @@ type DataUsageInfo struct {
 
 	// BucketsSizes is "bucket name" -> size.
 	BucketsSizes map[string]uint64 `json:"bucketsSizes"`
+
+	// BucketsUsage is "bucket name" -> usage of the bucket.
+	BucketsUsage map[string]BucketDataUsageInfo `json:"bucketsUsageInfo"`
+
+	// PrefixesUsage is "bucket name" -> "prefix" -> usage of the prefix,
+	// down to the prefix depth configured for the crawler.
+	PrefixesUsage map[string]map[string]PrefixUsageInfo `json:"prefixesUsageInfo,omitempty"`
+
+	// RealtimeUpdate is the timestamp of the latest change of usage
+	// counted since LastUpdate, zero if the usage is as crawled.
+	RealtimeUpdate time.Time `json:"realtimeUpdate,omitempty"`
+}
+
+// BucketDataUsageInfo - usage of a bucket.
+type BucketDataUsageInfo struct {
+	Size                 uint64            `json:"size"`
+	ObjectsCount         uint64            `json:"objectsCount"`
+	ObjectSizesHistogram map[string]uint64 `json:"objectsSizesHistogram"`
+}
+
+// PrefixUsageInfo - usage of the objects under a prefix.
+type PrefixUsageInfo struct {
+	Size         uint64 `json:"size"`
+	ObjectsCount uint64 `json:"objectsCount"`
 }
 
 // DataUsageInfo - returns data usage of the current object API
@@ -0,0 +1,187 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/minio/minio/pkg/bucket/lifecycle"
+)
+
+// Lifecycle actions as reported by the lifecycle preview and action log.
+const (
+	// LifecycleActionExpire - removal of the object, or creation of a
+	// delete marker in a versioned bucket.
+	LifecycleActionExpire = "expire"
+	// LifecycleActionExpireDeleteMarker - removal of a delete marker
+	// without other versions.
+	LifecycleActionExpireDeleteMarker = "expire-delete-marker"
+	// LifecycleActionNoncurrentExpire - removal of a noncurrent version.
+	LifecycleActionNoncurrentExpire = "noncurrent-expire"
+	// LifecycleActionTransition - transition of the object.
+	LifecycleActionTransition = "transition"
+	// LifecycleActionNoncurrentTransition - transition of a noncurrent version.
+	LifecycleActionNoncurrentTransition = "noncurrent-transition"
+	// LifecycleActionExpireRestored - removal of the temporary copy of
+	// a restored object.
+	LifecycleActionExpireRestored = "expire-restored"
+)
+
+// LifecycleActionSummary - number and total size of the objects a
+// lifecycle action applies to.
+type LifecycleActionSummary struct {
+	Count   uint64   `json:"count"`
+	Size    int64    `json:"size"`
+	Samples []string `json:"samples,omitempty"`
+}
+
+// LifecycleRulePreview - actions a lifecycle rule would apply.
+type LifecycleRulePreview struct {
+	ID      string                            `json:"id"`
+	Actions map[string]LifecycleActionSummary `json:"actions"`
+}
+
+// LifecyclePreview - outcome of the evaluation of a lifecycle
+// configuration against the objects of a bucket.
+type LifecyclePreview struct {
+	Bucket string `json:"bucket"`
+	Prefix string `json:"prefix,omitempty"`
+	// Object versions evaluated and their total size.
+	Scanned     uint64 `json:"scanned"`
+	ScannedSize int64  `json:"scannedSize"`
+	// True if the evaluation stopped at PreviewLifecycleOptions.MaxObjects.
+	Truncated bool                   `json:"truncated"`
+	Rules     []LifecycleRulePreview `json:"rules"`
+}
+
+// PreviewLifecycleOptions - options of a lifecycle preview.
+type PreviewLifecycleOptions struct {
+	// Only evaluate the objects under the prefix.
+	Prefix string
+	// Maximum number of object names reported per rule and action.
+	MaxSamples int
+	// Maximum number of object versions to evaluate, 100000 if zero.
+	MaxObjects int
+}
+
+// PreviewLifecycle - evaluates the lifecycle configuration against the
+// objects of the bucket without applying it, and returns what each rule
+// would expire or transition at the moment.
+func (adm *AdminClient) PreviewLifecycle(ctx context.Context, bucket string, config *lifecycle.Lifecycle, opts PreviewLifecycleOptions) (preview LifecyclePreview, err error) {
+	data, err := xml.Marshal(config)
+	if err != nil {
+		return preview, err
+	}
+
+	queryValues := url.Values{}
+	queryValues.Set("bucket", bucket)
+	queryValues.Set("prefix", opts.Prefix)
+	queryValues.Set("max-samples", strconv.Itoa(opts.MaxSamples))
+	queryValues.Set("max-objects", strconv.Itoa(opts.MaxObjects))
+
+	reqData := requestData{
+		relPath:     adminAPIPrefix + "/lifecycle-preview",
+		queryValues: queryValues,
+		content:     data,
+	}
+
+	// Execute POST on /minio/admin/v3/lifecycle-preview
+	resp, err := adm.executeMethod(ctx, http.MethodPost, reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return preview, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return preview, httpRespToErrorResponse(resp)
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return preview, err
+	}
+	if err = json.Unmarshal(b, &preview); err != nil {
+		return preview, err
+	}
+	return preview, nil
+}
+
+// LifecycleActionLogEntry - a lifecycle action applied by the crawler.
+type LifecycleActionLogEntry struct {
+	Time      time.Time `json:"time"`
+	Object    string    `json:"object"`
+	VersionID string    `json:"versionId,omitempty"`
+	Size      int64     `json:"size"`
+	Rule      string    `json:"rule"`
+	Action    string    `json:"action"`
+}
+
+// LifecycleActionLogDay - lifecycle actions applied on one day (UTC).
+type LifecycleActionLogDay struct {
+	Day     time.Time                         `json:"day"`
+	Actions map[string]LifecycleActionSummary `json:"actions"`
+}
+
+// LifecycleActionLog - lifecycle actions applied by the crawler to the
+// objects of a bucket, per day and the latest ones individually.
+type LifecycleActionLog struct {
+	Bucket  string                    `json:"bucket"`
+	Days    []LifecycleActionLogDay   `json:"days"`
+	Entries []LifecycleActionLogEntry `json:"entries,omitempty"`
+}
+
+// GetLifecycleActionLog - returns the lifecycle actions applied to the
+// objects of the bucket over the last days.
+func (adm *AdminClient) GetLifecycleActionLog(ctx context.Context, bucket string, days int) (log LifecycleActionLog, err error) {
+	queryValues := url.Values{}
+	queryValues.Set("bucket", bucket)
+	queryValues.Set("days", strconv.Itoa(days))
+
+	reqData := requestData{
+		relPath:     adminAPIPrefix + "/lifecycle-log",
+		queryValues: queryValues,
+	}
+
+	// Execute GET on /minio/admin/v3/lifecycle-log
+	resp, err := adm.executeMethod(ctx, http.MethodGet, reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return log, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return log, httpRespToErrorResponse(resp)
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return log, err
+	}
+	if err = json.Unmarshal(b, &log); err != nil {
+		return log, err
+	}
+	return log, nil
+}
@@ -175,3 +175,122 @@ func (adm *AdminClient) SetPolicy(ctx context.Context, policyName, entityName st
 	}
 	return nil
 }
+
+// SetPermissionBoundary - sets the policy capping the permissions of a
+// user or group, of their service accounts and of their STS credentials.
+// An empty policy name removes the permission boundary.
+func (adm *AdminClient) SetPermissionBoundary(ctx context.Context, policyName, entityName string, isGroup bool) error {
+	queryValues := url.Values{}
+	queryValues.Set("policyName", policyName)
+	queryValues.Set("userOrGroup", entityName)
+	groupStr := "false"
+	if isGroup {
+		groupStr = "true"
+	}
+	queryValues.Set("isGroup", groupStr)
+
+	reqData := requestData{
+		relPath:     adminAPIPrefix + "/set-permission-boundary",
+		queryValues: queryValues,
+	}
+
+	// Execute PUT on /minio/admin/v3/set-permission-boundary to set the boundary.
+	resp, err := adm.executeMethod(ctx, http.MethodPut, reqData)
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+	return nil
+}
+
+// PolicySimulationArgs - request to simulate. The identity is either an
+// access key, an LDAP user with its groups or the claims of an OpenID
+// identity.
+type PolicySimulationArgs struct {
+	AccessKey  string                 `json:"accessKey,omitempty"`
+	LDAPUser   string                 `json:"ldapUser,omitempty"`
+	LDAPGroups []string               `json:"ldapGroups,omitempty"`
+	Claims     map[string]interface{} `json:"claims,omitempty"`
+	Action     string                 `json:"action"`
+	Bucket     string                 `json:"bucket,omitempty"`
+	Object     string                 `json:"object,omitempty"`
+	// Conditions override the condition values derived from the identity.
+	Conditions map[string][]string `json:"conditions,omitempty"`
+}
+
+// PolicySource - origin of a policy evaluated for a request.
+type PolicySource string
+
+// Origins of the policies evaluated for a request.
+const (
+	UserPolicySource    PolicySource = "user"
+	GroupPolicySource   PolicySource = "group"
+	ParentPolicySource  PolicySource = "parent"
+	ClaimPolicySource   PolicySource = "claim"
+	SessionPolicySource PolicySource = "session"
+	BucketPolicySource  PolicySource = "bucket"
+)
+
+// PolicyStatementMatch - statement applying to the action and resource
+// of a request, it matched the request if none of its conditions failed.
+type PolicyStatementMatch struct {
+	Statement        json.RawMessage `json:"statement"`
+	Matched          bool            `json:"matched"`
+	FailedConditions []string        `json:"failedConditions,omitempty"`
+}
+
+// PolicyEvaluation - statements of a policy applying to a request.
+type PolicyEvaluation struct {
+	Source PolicySource `json:"source"`
+	// Name of the policy, empty for session and bucket policies.
+	Name string `json:"name,omitempty"`
+	// Group the policy is attached to, for group policies.
+	Group      string                 `json:"group,omitempty"`
+	Statements []PolicyStatementMatch `json:"statements,omitempty"`
+}
+
+// PolicySimulationResult - decision of the policies applying to a
+// request.
+type PolicySimulationResult struct {
+	Allowed  bool               `json:"allowed"`
+	Reason   string             `json:"reason"`
+	Policies []PolicyEvaluation `json:"policies,omitempty"`
+}
+
+// SimulatePolicy - evaluates the policies applying to a request made by
+// an identity and returns the decision along with the statements
+// applying to it.
+func (adm *AdminClient) SimulatePolicy(ctx context.Context, args PolicySimulationArgs) (result PolicySimulationResult, err error) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return result, err
+	}
+
+	reqData := requestData{
+		relPath: adminAPIPrefix + "/simulate-policy",
+		content: data,
+	}
+
+	// Execute POST on /minio/admin/v3/simulate-policy
+	resp, err := adm.executeMethod(ctx, http.MethodPost, reqData)
+	defer closeResponse(resp)
+	if err != nil {
+		return result, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return result, httpRespToErrorResponse(resp)
+	}
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return result, err
+	}
+
+	err = json.Unmarshal(respBytes, &result)
+	return result, err
+}
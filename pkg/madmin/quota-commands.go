@@ -23,6 +23,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strconv"
 )
 
 // QuotaType represents bucket quota type
@@ -33,29 +34,93 @@ const (
 	HardQuota QuotaType = "hard"
 	// FIFOQuota specifies a quota limit beyond which older files are deleted from bucket
 	FIFOQuota QuotaType = "fifo"
+	// SoftQuota specifies a quota limit which only raises alerts when reached
+	SoftQuota QuotaType = "soft"
 )
 
-// IsValid returns true if quota type is one of FIFO or Hard
+// IsValid returns true if quota type is one of FIFO, Hard or Soft
 func (t QuotaType) IsValid() bool {
-	return t == HardQuota || t == FIFOQuota
+	return t == HardQuota || t == FIFOQuota || t == SoftQuota
+}
+
+// DefaultQuotaAlerts are the usage percentages at which alerts are
+// raised for soft quotas without alerts configured.
+var DefaultQuotaAlerts = []int{80, 90, 100}
+
+// validQuota returns false if the quota limits are invalid, FIFO quotas
+// only apply to the size of whole buckets.
+func validQuota(t QuotaType, quota, objects uint64, alerts []int, fifo bool) bool {
+	if quota > 0 || objects > 0 {
+		if !t.IsValid() || (t == FIFOQuota && (!fifo || objects > 0)) {
+			return false
+		}
+	}
+	for _, alert := range alerts {
+		if alert <= 0 || alert > 100 {
+			return false
+		}
+	}
+	return true
 }
 
 // BucketQuota holds bucket quota restrictions
 type BucketQuota struct {
 	Quota uint64    `json:"quota"`
 	Type  QuotaType `json:"quotatype,omitempty"`
+	// Maximum number of objects, the versions of an object count once.
+	Objects uint64 `json:"objects,omitempty"`
+	// Usage percentages of the quota at which alerts are raised.
+	Alerts []int `json:"alerts,omitempty"`
+	// Quotas of prefixes, enforced on top of the bucket quota.
+	Prefixes []PrefixQuota `json:"prefixes,omitempty"`
 }
 
 // IsValid returns false if quota is invalid
 // empty quota when Quota == 0 is always true.
 func (q BucketQuota) IsValid() bool {
-	if q.Quota > 0 {
-		return q.Type.IsValid()
+	if !validQuota(q.Type, q.Quota, q.Objects, q.Alerts, true) {
+		return false
+	}
+	prefixes := make(map[string]struct{}, len(q.Prefixes))
+	for _, p := range q.Prefixes {
+		if _, ok := prefixes[p.Prefix]; ok || !p.IsValid() {
+			return false
+		}
+		prefixes[p.Prefix] = struct{}{}
 	}
-	// Empty configs are valid.
 	return true
 }
 
+// PrefixQuota holds the quota restrictions of the objects under a prefix
+// of a bucket.
+type PrefixQuota struct {
+	Prefix  string    `json:"prefix"`
+	Quota   uint64    `json:"quota"`
+	Type    QuotaType `json:"quotatype,omitempty"`
+	Objects uint64    `json:"objects,omitempty"`
+	Alerts  []int     `json:"alerts,omitempty"`
+}
+
+// IsValid returns false if the prefix quota is invalid.
+func (q PrefixQuota) IsValid() bool {
+	return q.Prefix != "" && (q.Quota > 0 || q.Objects > 0) && validQuota(q.Type, q.Quota, q.Objects, q.Alerts, false)
+}
+
+// UserQuota holds the quota restrictions of the objects written by a user,
+// or by the members of a group, across all buckets.
+type UserQuota struct {
+	Quota   uint64    `json:"quota"`
+	Type    QuotaType `json:"quotatype,omitempty"`
+	Objects uint64    `json:"objects,omitempty"`
+	Alerts  []int     `json:"alerts,omitempty"`
+}
+
+// IsValid returns false if quota is invalid
+// empty quota when Quota == 0 and Objects == 0 is always true.
+func (q UserQuota) IsValid() bool {
+	return validQuota(q.Type, q.Quota, q.Objects, q.Alerts, false)
+}
+
 // GetBucketQuota - get info on a user
 func (adm *AdminClient) GetBucketQuota(ctx context.Context, bucket string) (q BucketQuota, err error) {
 	queryValues := url.Values{}
@@ -120,3 +185,70 @@ func (adm *AdminClient) SetBucketQuota(ctx context.Context, bucket string, quota
 
 	return nil
 }
+
+// GetUserQuota - gets the quota of a user or group.
+func (adm *AdminClient) GetUserQuota(ctx context.Context, name string, isGroup bool) (q UserQuota, err error) {
+	queryValues := url.Values{}
+	queryValues.Set("userOrGroup", name)
+	queryValues.Set("isGroup", strconv.FormatBool(isGroup))
+
+	reqData := requestData{
+		relPath:     adminAPIPrefix + "/get-user-quota",
+		queryValues: queryValues,
+	}
+
+	// Execute GET on /minio/admin/v3/get-user-quota
+	resp, err := adm.executeMethod(ctx, http.MethodGet, reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return q, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return q, httpRespToErrorResponse(resp)
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return q, err
+	}
+	if err = json.Unmarshal(b, &q); err != nil {
+		return q, err
+	}
+
+	return q, nil
+}
+
+// SetUserQuota - sets the quota of a user or group, if quota and objects
+// are set to '0' quota is disabled.
+func (adm *AdminClient) SetUserQuota(ctx context.Context, name string, isGroup bool, quota *UserQuota) error {
+	data, err := json.Marshal(quota)
+	if err != nil {
+		return err
+	}
+
+	queryValues := url.Values{}
+	queryValues.Set("userOrGroup", name)
+	queryValues.Set("isGroup", strconv.FormatBool(isGroup))
+
+	reqData := requestData{
+		relPath:     adminAPIPrefix + "/set-user-quota",
+		queryValues: queryValues,
+		content:     data,
+	}
+
+	// Execute PUT on /minio/admin/v3/set-user-quota to set quota for a user or group.
+	resp, err := adm.executeMethod(ctx, http.MethodPut, reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+
+	return nil
+}
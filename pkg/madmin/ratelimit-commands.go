@@ -0,0 +1,145 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package madmin
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// RateLimitType is the kind of entity a rate limit applies to.
+type RateLimitType string
+
+const (
+	// UserRateLimit limits the requests of an IAM user, including those
+	// made with its temporary credentials and service accounts.
+	UserRateLimit RateLimitType = "user"
+	// GroupRateLimit limits the requests of each member of an IAM group.
+	GroupRateLimit RateLimitType = "group"
+	// BucketRateLimit limits the requests to a bucket.
+	BucketRateLimit RateLimitType = "bucket"
+)
+
+// IsValid returns true if the rate limit type is known.
+func (t RateLimitType) IsValid() bool {
+	switch t {
+	case UserRateLimit, GroupRateLimit, BucketRateLimit:
+		return true
+	}
+	return false
+}
+
+// RateLimit holds the S3 API limits of a user, group or bucket across the
+// cluster, a zero value means no limit.
+type RateLimit struct {
+	// Requests per second, refilling a token bucket of Burst requests.
+	RequestsPerSecond float64 `json:"requestsPerSecond,omitempty"`
+	Burst             int     `json:"burst,omitempty"`
+	// Requests served at the same time.
+	ConcurrentRequests int `json:"concurrentRequests,omitempty"`
+	// Bytes per second of request and response bodies.
+	IngressBytesPerSecond int64 `json:"ingressBytesPerSecond,omitempty"`
+	EgressBytesPerSecond  int64 `json:"egressBytesPerSecond,omitempty"`
+}
+
+// IsEmpty returns true if the rate limit does not limit anything.
+func (l RateLimit) IsEmpty() bool {
+	return l == RateLimit{}
+}
+
+// IsValid returns false if the rate limit is invalid, a burst is only
+// valid along with a request rate.
+func (l RateLimit) IsValid() bool {
+	if l.RequestsPerSecond < 0 || l.Burst < 0 || l.ConcurrentRequests < 0 ||
+		l.IngressBytesPerSecond < 0 || l.EgressBytesPerSecond < 0 {
+		return false
+	}
+	return l.Burst == 0 || l.RequestsPerSecond > 0
+}
+
+// RateLimits holds the rate limits of all users, groups and buckets.
+type RateLimits struct {
+	Users   map[string]RateLimit `json:"users,omitempty"`
+	Groups  map[string]RateLimit `json:"groups,omitempty"`
+	Buckets map[string]RateLimit `json:"buckets,omitempty"`
+}
+
+// GetRateLimits - returns the rate limits of all users, groups and buckets.
+func (adm *AdminClient) GetRateLimits(ctx context.Context) (limits RateLimits, err error) {
+	reqData := requestData{
+		relPath: adminAPIPrefix + "/get-rate-limits",
+	}
+
+	// Execute GET on /minio/admin/v3/get-rate-limits
+	resp, err := adm.executeMethod(ctx, http.MethodGet, reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return limits, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return limits, httpRespToErrorResponse(resp)
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return limits, err
+	}
+	if err = json.Unmarshal(b, &limits); err != nil {
+		return limits, err
+	}
+
+	return limits, nil
+}
+
+// SetRateLimit - sets the rate limit of a user, group or bucket, an empty
+// rate limit removes it.
+func (adm *AdminClient) SetRateLimit(ctx context.Context, limitType RateLimitType, name string, limit RateLimit) error {
+	data, err := json.Marshal(limit)
+	if err != nil {
+		return err
+	}
+
+	queryValues := url.Values{}
+	queryValues.Set("type", string(limitType))
+	queryValues.Set("name", name)
+
+	reqData := requestData{
+		relPath:     adminAPIPrefix + "/set-rate-limit",
+		queryValues: queryValues,
+		content:     data,
+	}
+
+	// Execute PUT on /minio/admin/v3/set-rate-limit to set the rate limit.
+	resp, err := adm.executeMethod(ctx, http.MethodPut, reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+
+	return nil
+}
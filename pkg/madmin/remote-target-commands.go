@@ -37,11 +37,18 @@ const (
 	ReplicationService ServiceType = "replication"
 	// ILMService specifies ilm service
 	ILMService ServiceType = "ilm"
+	// ILMLocalService specifies ilm service transitioning to a local path
+	ILMLocalService ServiceType = "ilm-local"
 )
 
 // IsValid returns true if ARN type represents replication or ilm
 func (t ServiceType) IsValid() bool {
-	return t == ReplicationService || t == ILMService
+	return t == ReplicationService || t == ILMService || t == ILMLocalService
+}
+
+// IsTransition returns true if ARN type represents a lifecycle transition tier
+func (t ServiceType) IsTransition() bool {
+	return t == ILMService || t == ILMLocalService
 }
 
 // ARN is a struct to define arn.
@@ -98,15 +105,20 @@ type BucketTarget struct {
 	Region         string            `json:"omitempty"`
 	Label          string            `json:"label,omitempty"`
 	BandwidthLimit int64             `json:"bandwidthlimit,omitempty"`
+	LocalPath      string            `json:"localpath,omitempty"`
 }
 
 // Clone returns shallow clone of BucketTarget without secret key in credentials
 func (t *BucketTarget) Clone() BucketTarget {
+	var creds *auth.Credentials
+	if t.Credentials != nil {
+		creds = &auth.Credentials{AccessKey: t.Credentials.AccessKey}
+	}
 	return BucketTarget{
 		SourceBucket: t.SourceBucket,
 		Endpoint:     t.Endpoint,
 		TargetBucket: t.TargetBucket,
-		Credentials:  &auth.Credentials{AccessKey: t.Credentials.AccessKey},
+		Credentials:  creds,
 		Secure:       t.Secure,
 		Path:         t.Path,
 		API:          t.Path,
@@ -114,6 +126,7 @@ func (t *BucketTarget) Clone() BucketTarget {
 		Type:         t.Type,
 		Region:       t.Region,
 		Label:        t.Label,
+		LocalPath:    t.LocalPath,
 	}
 }
 
@@ -131,6 +144,9 @@ func (t BucketTarget) URL() *url.URL {
 
 // Empty returns true if struct is empty.
 func (t BucketTarget) Empty() bool {
+	if t.Type == ILMLocalService {
+		return t.LocalPath == ""
+	}
 	return t.String() == "" || t.Credentials == nil
 }
 
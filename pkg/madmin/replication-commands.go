@@ -0,0 +1,279 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ReplicationFailedObject - object version which permanently failed
+// to replicate and got dead-lettered.
+type ReplicationFailedObject struct {
+	Object    string    `json:"object"`
+	VersionID string    `json:"versionId,omitempty"`
+	Operation string    `json:"operation"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"lastError,omitempty"`
+	Failed    time.Time `json:"failed"`
+}
+
+// BucketReplicationQueueInfo - replication queue state of a bucket.
+type BucketReplicationQueueInfo struct {
+	// Tasks waiting to be replicated and their total size.
+	Pending     uint64 `json:"pending"`
+	PendingSize int64  `json:"pendingSize"`
+	// Pending tasks which failed at least once and are retried.
+	Retrying uint64 `json:"retrying"`
+	// Time at which the oldest pending task was queued and its age.
+	OldestPending    time.Time     `json:"oldestPending,omitempty"`
+	OldestPendingAge time.Duration `json:"oldestPendingAge,omitempty"`
+	// Dead-lettered tasks and their total size.
+	Failed        uint64                    `json:"failed"`
+	FailedSize    int64                     `json:"failedSize"`
+	FailedObjects []ReplicationFailedObject `json:"failedObjects,omitempty"`
+	// Tasks dropped because the queue was full, these are
+	// recovered by the crawler.
+	Dropped uint64 `json:"dropped"`
+	// Number of tasks per error code of their last error.
+	FailureReasons map[string]uint64 `json:"failureReasons,omitempty"`
+}
+
+// ReplicationQueueInfo - replication queue state of all buckets.
+type ReplicationQueueInfo struct {
+	Buckets map[string]BucketReplicationQueueInfo `json:"buckets"`
+}
+
+// GetReplicationQueueInfo - returns the replication queue state of the
+// bucket, or of all buckets if bucket is empty.
+func (adm *AdminClient) GetReplicationQueueInfo(ctx context.Context, bucket string) (info ReplicationQueueInfo, err error) {
+	queryValues := url.Values{}
+	queryValues.Set("bucket", bucket)
+
+	reqData := requestData{
+		relPath:     adminAPIPrefix + "/replication-queue",
+		queryValues: queryValues,
+	}
+
+	// Execute GET on /minio/admin/v3/replication-queue
+	resp, err := adm.executeMethod(ctx, http.MethodGet, reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return info, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return info, httpRespToErrorResponse(resp)
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return info, err
+	}
+	if err = json.Unmarshal(b, &info); err != nil {
+		return info, err
+	}
+	return info, nil
+}
+
+// TargetReplicationMetrics - replication metrics of a bucket for one
+// remote target.
+type TargetReplicationMetrics struct {
+	// Operations waiting to be replicated to the target and their total size.
+	Pending     uint64 `json:"pending"`
+	PendingSize int64  `json:"pendingSize"`
+	// Time at which the oldest pending operation was queued and its age.
+	OldestPending    time.Time     `json:"oldestPending,omitempty"`
+	OldestPendingAge time.Duration `json:"oldestPendingAge,omitempty"`
+	// Dead-lettered operations and their total size.
+	Failed     uint64 `json:"failed"`
+	FailedSize int64  `json:"failedSize"`
+	// Operations replicated since the servers started, their total size
+	// and when the last one completed.
+	Replicated     uint64    `json:"replicated"`
+	ReplicatedSize int64     `json:"replicatedSize"`
+	LastReplicated time.Time `json:"lastReplicated,omitempty"`
+	// Attempts which failed since the servers started.
+	FailedAttempts uint64 `json:"failedAttempts"`
+	// Time between the upload of an object and the completion of its
+	// replication, of the last replicated object and moving average.
+	LastLag time.Duration `json:"lastLag,omitempty"`
+	AvgLag  time.Duration `json:"avgLag,omitempty"`
+	// Bandwidth used in bytes per second and the configured limit,
+	// zero if not limited.
+	CurrentBandwidth float64 `json:"currentBandwidth"`
+	BandwidthLimit   int64   `json:"bandwidthLimit,omitempty"`
+	// Last error returned by the target and when it happened.
+	LastError     string    `json:"lastError,omitempty"`
+	LastErrorTime time.Time `json:"lastErrorTime,omitempty"`
+}
+
+// BucketReplicationMetrics - replication metrics of a bucket per target ARN.
+type BucketReplicationMetrics struct {
+	Targets map[string]TargetReplicationMetrics `json:"targets"`
+}
+
+// ReplicationMetrics - replication metrics of all buckets.
+type ReplicationMetrics struct {
+	Buckets map[string]BucketReplicationMetrics `json:"buckets"`
+}
+
+// GetReplicationMetrics - returns the replication metrics per target of
+// the bucket, or of all buckets if bucket is empty.
+func (adm *AdminClient) GetReplicationMetrics(ctx context.Context, bucket string) (metrics ReplicationMetrics, err error) {
+	queryValues := url.Values{}
+	queryValues.Set("bucket", bucket)
+
+	reqData := requestData{
+		relPath:     adminAPIPrefix + "/replication-metrics",
+		queryValues: queryValues,
+	}
+
+	// Execute GET on /minio/admin/v3/replication-metrics
+	resp, err := adm.executeMethod(ctx, http.MethodGet, reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return metrics, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return metrics, httpRespToErrorResponse(resp)
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return metrics, err
+	}
+	if err = json.Unmarshal(b, &metrics); err != nil {
+		return metrics, err
+	}
+	return metrics, nil
+}
+
+// ReplicationResyncStatus - state of a replication resync.
+type ReplicationResyncStatus string
+
+const (
+	// ReplicationResyncRunning - the resync is walking the bucket or
+	// waiting for the queued objects to be replicated.
+	ReplicationResyncRunning ReplicationResyncStatus = "running"
+	// ReplicationResyncCompleted - all queued objects were processed.
+	ReplicationResyncCompleted ReplicationResyncStatus = "completed"
+	// ReplicationResyncCanceled - the resync was canceled, it can be resumed.
+	ReplicationResyncCanceled ReplicationResyncStatus = "canceled"
+	// ReplicationResyncFailed - the resync failed, it can be resumed.
+	ReplicationResyncFailed ReplicationResyncStatus = "failed"
+)
+
+// ReplicationResyncOptions - options of a replication resync.
+type ReplicationResyncOptions struct {
+	// Arn restricts the resync to a single target.
+	Arn string
+	// Reset replicates objects to the target even if they were
+	// replicated before, e.g. after the destination was wiped.
+	Reset bool
+	// Resume continues the previous canceled or failed resync.
+	Resume bool
+}
+
+// ReplicationResyncInfo - progress of the replication resync of a bucket.
+type ReplicationResyncInfo struct {
+	ID         string                  `json:"id"`
+	Bucket     string                  `json:"bucket"`
+	Arn        string                  `json:"arn,omitempty"`
+	Reset      bool                    `json:"reset,omitempty"`
+	Status     ReplicationResyncStatus `json:"status"`
+	StartTime  time.Time               `json:"startTime"`
+	EndTime    time.Time               `json:"endTime,omitempty"`
+	LastUpdate time.Time               `json:"lastUpdate"`
+	// Last object walked, a resumed resync continues from here.
+	LastObject string `json:"lastObject,omitempty"`
+
+	ObjectsScanned    uint64 `json:"objectsScanned"`
+	BytesScanned      uint64 `json:"bytesScanned"`
+	ObjectsQueued     uint64 `json:"objectsQueued"`
+	BytesQueued       uint64 `json:"bytesQueued"`
+	ObjectsReplicated uint64 `json:"objectsReplicated"`
+	BytesReplicated   uint64 `json:"bytesReplicated"`
+	ObjectsFailed     uint64 `json:"objectsFailed"`
+	BytesFailed       uint64 `json:"bytesFailed"`
+	LastError         string `json:"lastError,omitempty"`
+
+	// Error which stopped the resync.
+	Error string `json:"error,omitempty"`
+}
+
+// StartReplicationResync - starts replicating the existing objects of the
+// bucket which have not been replicated to its targets yet.
+func (adm *AdminClient) StartReplicationResync(ctx context.Context, bucket string, opts ReplicationResyncOptions) (info ReplicationResyncInfo, err error) {
+	queryValues := url.Values{}
+	queryValues.Set("bucket", bucket)
+	queryValues.Set("arn", opts.Arn)
+	queryValues.Set("reset", strconv.FormatBool(opts.Reset))
+	queryValues.Set("resume", strconv.FormatBool(opts.Resume))
+	return adm.replicationResync(ctx, http.MethodPut, queryValues)
+}
+
+// GetReplicationResync - returns the progress of the last replication
+// resync of the bucket.
+func (adm *AdminClient) GetReplicationResync(ctx context.Context, bucket string) (info ReplicationResyncInfo, err error) {
+	queryValues := url.Values{}
+	queryValues.Set("bucket", bucket)
+	return adm.replicationResync(ctx, http.MethodGet, queryValues)
+}
+
+// CancelReplicationResync - cancels the running replication resync of the bucket.
+func (adm *AdminClient) CancelReplicationResync(ctx context.Context, bucket string) (info ReplicationResyncInfo, err error) {
+	queryValues := url.Values{}
+	queryValues.Set("bucket", bucket)
+	return adm.replicationResync(ctx, http.MethodDelete, queryValues)
+}
+
+func (adm *AdminClient) replicationResync(ctx context.Context, method string, queryValues url.Values) (info ReplicationResyncInfo, err error) {
+	reqData := requestData{
+		relPath:     adminAPIPrefix + "/replication-resync",
+		queryValues: queryValues,
+	}
+
+	// Execute on /minio/admin/v3/replication-resync
+	resp, err := adm.executeMethod(ctx, method, reqData)
+
+	defer closeResponse(resp)
+	if err != nil {
+		return info, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return info, httpRespToErrorResponse(resp)
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return info, err
+	}
+	if err = json.Unmarshal(b, &info); err != nil {
+		return info, err
+	}
+	return info, nil
+}
@@ -0,0 +1,125 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package madmin
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// STSSession - an active session of temporary credentials issued by
+// the STS APIs.
+type STSSession struct {
+	AccessKey string `json:"accessKey"`
+	// Identity the credentials were issued to, the parent user or
+	// the subject of the token of the identity provider.
+	Parent string `json:"parent,omitempty"`
+	// STS API which issued the credentials e.g. "AssumeRoleWithLDAPIdentity".
+	Source     string    `json:"source,omitempty"`
+	IssuedAt   time.Time `json:"issuedAt,omitempty"`
+	Expiration time.Time `json:"expiration"`
+	// Comma separated policies of the session.
+	Policy        string   `json:"policy,omitempty"`
+	SessionPolicy bool     `json:"sessionPolicy,omitempty"`
+	Groups        []string `json:"groups,omitempty"`
+}
+
+// ListSTSSessions - lists the active STS sessions, only those of the
+// given parent identity when it is not empty.
+func (adm *AdminClient) ListSTSSessions(ctx context.Context, parent string) ([]STSSession, error) {
+	queryValues := url.Values{}
+	if parent != "" {
+		queryValues.Set("parent", parent)
+	}
+
+	reqData := requestData{
+		relPath:     adminAPIPrefix + "/list-sts-sessions",
+		queryValues: queryValues,
+	}
+
+	// Execute GET on /minio/admin/v3/list-sts-sessions
+	resp, err := adm.executeMethod(ctx, http.MethodGet, reqData)
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []STSSession
+	if err = json.Unmarshal(data, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// RevokeSTSSession - revokes the STS session with the given access key.
+func (adm *AdminClient) RevokeSTSSession(ctx context.Context, accessKey string) error {
+	queryValues := url.Values{}
+	queryValues.Set("accessKey", accessKey)
+	_, err := adm.revokeSTSSessions(ctx, queryValues)
+	return err
+}
+
+// RevokeSTSSessions - revokes all the STS sessions of a parent identity
+// and returns the access keys of the revoked sessions.
+func (adm *AdminClient) RevokeSTSSessions(ctx context.Context, parent string) ([]string, error) {
+	queryValues := url.Values{}
+	queryValues.Set("parent", parent)
+	return adm.revokeSTSSessions(ctx, queryValues)
+}
+
+func (adm *AdminClient) revokeSTSSessions(ctx context.Context, queryValues url.Values) ([]string, error) {
+	reqData := requestData{
+		relPath:     adminAPIPrefix + "/revoke-sts-sessions",
+		queryValues: queryValues,
+	}
+
+	// Execute POST on /minio/admin/v3/revoke-sts-sessions
+	resp, err := adm.executeMethod(ctx, http.MethodPost, reqData)
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var revoked []string
+	if err = json.Unmarshal(data, &revoked); err != nil {
+		return nil, err
+	}
+	return revoked, nil
+}
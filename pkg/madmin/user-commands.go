@@ -90,12 +90,35 @@ const (
 	AccountDisabled AccountStatus = "disabled"
 )
 
+// AccessKeyUsage describes the last use of an access key.
+type AccessKeyUsage struct {
+	Time     time.Time `json:"time"`
+	SourceIP string    `json:"sourceIP,omitempty"`
+	API      string    `json:"api,omitempty"`
+}
+
 // UserInfo carries information about long term users.
 type UserInfo struct {
 	SecretKey  string        `json:"secretKey,omitempty"`
 	PolicyName string        `json:"policyName,omitempty"`
 	Status     AccountStatus `json:"status"`
 	MemberOf   []string      `json:"memberOf,omitempty"`
+
+	// Expiration of the access key, the user is disabled
+	// once it has expired.
+	Expiration *time.Time `json:"expiration,omitempty"`
+	// PreviousSecretExpiration is set while a rotated secret
+	// key is still accepted.
+	PreviousSecretExpiration *time.Time      `json:"previousSecretExpiration,omitempty"`
+	LastUsed                 *AccessKeyUsage `json:"lastUsed,omitempty"`
+
+	// Tags of the user, they can be referenced in policies
+	// as "aws:PrincipalTag/<tag-key>".
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// PermissionBoundary is the policy capping the permissions
+	// of the user, of its service accounts and STS credentials.
+	PermissionBoundary string `json:"permissionBoundary,omitempty"`
 }
 
 // RemoveUser - remove a user.
@@ -268,7 +291,8 @@ func (adm *AdminClient) SetUserStatus(ctx context.Context, accessKey string, sta
 
 // AddServiceAccountReq is the request body of the add service account admin call
 type AddServiceAccountReq struct {
-	Policy *iampolicy.Policy `json:"policy,omitempty"`
+	Policy     *iampolicy.Policy `json:"policy,omitempty"`
+	Expiration *time.Time        `json:"expiration,omitempty"`
 }
 
 // AddServiceAccountResp is the response body of the add service account admin call
@@ -279,15 +303,27 @@ type AddServiceAccountResp struct {
 // AddServiceAccount - creates a new service account belonging to the user sending
 // the request while restricting the service account permission by the given policy document.
 func (adm *AdminClient) AddServiceAccount(ctx context.Context, policy *iampolicy.Policy) (auth.Credentials, error) {
+	return adm.AddServiceAccountWithExpiration(ctx, policy, time.Time{})
+}
+
+// AddServiceAccountWithExpiration - creates a new service account like
+// AddServiceAccount, the access key of the service account is disabled
+// after the given expiration. A zero expiration never expires.
+func (adm *AdminClient) AddServiceAccountWithExpiration(ctx context.Context, policy *iampolicy.Policy, expiration time.Time) (auth.Credentials, error) {
 	if policy != nil {
 		if err := policy.Validate(); err != nil {
 			return auth.Credentials{}, err
 		}
 	}
 
-	data, err := json.Marshal(AddServiceAccountReq{
+	createReq := AddServiceAccountReq{
 		Policy: policy,
-	})
+	}
+	if !expiration.IsZero() {
+		createReq.Expiration = &expiration
+	}
+
+	data, err := json.Marshal(createReq)
 	if err != nil {
 		return auth.Credentials{}, err
 	}
@@ -325,9 +361,18 @@ func (adm *AdminClient) AddServiceAccount(ctx context.Context, policy *iampolicy
 	return serviceAccountResp.Credentials, nil
 }
 
+// ServiceAccountInfo carries information about a service account.
+type ServiceAccountInfo struct {
+	AccessKey                string          `json:"accessKey"`
+	Expiration               *time.Time      `json:"expiration,omitempty"`
+	PreviousSecretExpiration *time.Time      `json:"previousSecretExpiration,omitempty"`
+	LastUsed                 *AccessKeyUsage `json:"lastUsed,omitempty"`
+}
+
 // ListServiceAccountsResp is the response body of the list service accounts call
 type ListServiceAccountsResp struct {
-	Accounts []string `json:"accounts"`
+	Accounts     []string             `json:"accounts"`
+	AccountsInfo []ServiceAccountInfo `json:"accountsInfo,omitempty"`
 }
 
 // ListServiceAccounts - list service accounts belonging to the specified user
@@ -387,3 +432,130 @@ func (adm *AdminClient) DeleteServiceAccount(ctx context.Context, serviceAccount
 
 	return nil
 }
+
+// RotateSecretKeyReq is the request body of the rotate secret key admin call
+type RotateSecretKeyReq struct {
+	// SecretKey is the new secret key, the server generates
+	// one when it is empty.
+	SecretKey string `json:"secretKey,omitempty"`
+	// Overlap is how long the previous secret key remains valid.
+	Overlap time.Duration `json:"overlap"`
+}
+
+// RotateSecretKeyResp is the response body of the rotate secret key admin call
+type RotateSecretKeyResp struct {
+	SecretKey                string     `json:"secretKey"`
+	PreviousSecretExpiration *time.Time `json:"previousSecretExpiration,omitempty"`
+}
+
+// RotateSecretKey - replaces the secret key of a user or service account,
+// the previous secret key remains valid for the overlap duration so that
+// clients can be moved to the new secret key without failing requests.
+func (adm *AdminClient) RotateSecretKey(ctx context.Context, accessKey, secretKey string, overlap time.Duration) (RotateSecretKeyResp, error) {
+	if secretKey != "" && !auth.IsSecretKeyValid(secretKey) {
+		return RotateSecretKeyResp{}, auth.ErrInvalidSecretKeyLength
+	}
+
+	data, err := json.Marshal(RotateSecretKeyReq{
+		SecretKey: secretKey,
+		Overlap:   overlap,
+	})
+	if err != nil {
+		return RotateSecretKeyResp{}, err
+	}
+
+	econfigBytes, err := EncryptData(adm.getSecretKey(), data)
+	if err != nil {
+		return RotateSecretKeyResp{}, err
+	}
+
+	queryValues := url.Values{}
+	queryValues.Set("accessKey", accessKey)
+
+	reqData := requestData{
+		relPath:     adminAPIPrefix + "/rotate-secret-key",
+		queryValues: queryValues,
+		content:     econfigBytes,
+	}
+
+	// Execute PUT on /minio/admin/v3/rotate-secret-key to rotate the secret key.
+	resp, err := adm.executeMethod(ctx, http.MethodPut, reqData)
+	defer closeResponse(resp)
+	if err != nil {
+		return RotateSecretKeyResp{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return RotateSecretKeyResp{}, httpRespToErrorResponse(resp)
+	}
+
+	data, err = DecryptData(adm.getSecretKey(), resp.Body)
+	if err != nil {
+		return RotateSecretKeyResp{}, err
+	}
+
+	var rotateResp RotateSecretKeyResp
+	if err = json.Unmarshal(data, &rotateResp); err != nil {
+		return RotateSecretKeyResp{}, err
+	}
+	return rotateResp, nil
+}
+
+// SetAccessKeyExpiration - sets the expiration of the access key of a user
+// or service account, a zero expiration removes it.
+func (adm *AdminClient) SetAccessKeyExpiration(ctx context.Context, accessKey string, expiration time.Time) error {
+	queryValues := url.Values{}
+	queryValues.Set("accessKey", accessKey)
+	if !expiration.IsZero() {
+		queryValues.Set("expiration", expiration.UTC().Format(time.RFC3339))
+	}
+
+	reqData := requestData{
+		relPath:     adminAPIPrefix + "/set-key-expiration",
+		queryValues: queryValues,
+	}
+
+	// Execute PUT on /minio/admin/v3/set-key-expiration to set the expiration.
+	resp, err := adm.executeMethod(ctx, http.MethodPut, reqData)
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+
+	return nil
+}
+
+// SetUserTags - sets the tags of a user, they are available to policy
+// conditions as "aws:PrincipalTag/<tag-key>". Empty tags remove them.
+func (adm *AdminClient) SetUserTags(ctx context.Context, accessKey string, tags map[string]string) error {
+	data, err := json.Marshal(tags)
+	if err != nil {
+		return err
+	}
+
+	queryValues := url.Values{}
+	queryValues.Set("accessKey", accessKey)
+
+	reqData := requestData{
+		relPath:     adminAPIPrefix + "/set-user-tags",
+		queryValues: queryValues,
+		content:     data,
+	}
+
+	// Execute PUT on /minio/admin/v3/set-user-tags to set the tags.
+	resp, err := adm.executeMethod(ctx, http.MethodPut, reqData)
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+
+	return nil
+}
@@ -0,0 +1,338 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package avro
+
+import (
+	"encoding/json"
+	"io"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bcicen/jstream"
+	"github.com/linkedin/goavro/v2"
+	jsonfmt "github.com/minio/minio/pkg/s3select/json"
+	"github.com/minio/minio/pkg/s3select/sql"
+)
+
+// Reader - Avro object container file record reader for S3Select.
+type Reader struct {
+	args       *ReaderArgs
+	readCloser io.ReadCloser
+	ocfReader  *goavro.OCFReader
+	schema     interface{}
+	named      map[string]namedType
+}
+
+// namedType - a record, enum or fixed type, which may be referred to by
+// its name.
+type namedType struct {
+	schema    map[string]interface{}
+	namespace string
+}
+
+var primitiveTypes = map[string]bool{
+	"null":    true,
+	"boolean": true,
+	"int":     true,
+	"long":    true,
+	"float":   true,
+	"double":  true,
+	"bytes":   true,
+	"string":  true,
+}
+
+// Read - reads single record.
+func (r *Reader) Read(dst sql.Record) (sql.Record, error) {
+	if !r.ocfReader.Scan() {
+		if err := r.ocfReader.Err(); err != nil {
+			return nil, errAvroParsingError(err)
+		}
+		return nil, io.EOF
+	}
+
+	datum, err := r.ocfReader.Read()
+	if err != nil {
+		return nil, errAvroParsingError(err)
+	}
+
+	kvs, ok := r.convert(r.schema, "", datum).(jstream.KVS)
+	if !ok {
+		// Values which are not records are returned in a single column.
+		kvs = jstream.KVS{jstream.KV{Key: "_1", Value: r.convert(r.schema, "", datum)}}
+	}
+
+	// Reuse destination if we can.
+	dstRec, ok := dst.(*jsonfmt.Record)
+	if !ok {
+		dstRec = &jsonfmt.Record{}
+	}
+	dstRec.SelectFormat = sql.SelectFmtAvro
+	dstRec.KVS = kvs
+	return dstRec, nil
+}
+
+// Close - closes underlying reader.
+func (r *Reader) Close() error {
+	return r.readCloser.Close()
+}
+
+// fullName returns the full name of the named type in the namespace.
+func fullName(name, namespace string) string {
+	if strings.Contains(name, ".") || namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
+
+// typeName returns the full name of the named type and the namespace of
+// the names used in its definition.
+func typeName(s map[string]interface{}, namespace string) (string, string) {
+	name, _ := s["name"].(string)
+	if ns, ok := s["namespace"].(string); ok {
+		namespace = ns
+	}
+	name = fullName(name, namespace)
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		return name, name[:idx]
+	}
+	return name, ""
+}
+
+// register adds the named types of the schema.
+func (r *Reader) register(schema interface{}, namespace string) {
+	switch s := schema.(type) {
+	case []interface{}:
+		for _, member := range s {
+			r.register(member, namespace)
+		}
+	case map[string]interface{}:
+		switch t := s["type"].(type) {
+		case string:
+			switch t {
+			case "record", "error", "enum", "fixed":
+				var name string
+				name, namespace = typeName(s, namespace)
+				r.named[name] = namedType{s, namespace}
+				if fields, ok := s["fields"].([]interface{}); ok {
+					for _, field := range fields {
+						if f, ok := field.(map[string]interface{}); ok {
+							r.register(f["type"], namespace)
+						}
+					}
+				}
+			case "array":
+				r.register(s["items"], namespace)
+			case "map":
+				r.register(s["values"], namespace)
+			}
+		default:
+			r.register(t, namespace)
+		}
+	}
+}
+
+// unionName returns the name of the union member, which is used as key
+// of decoded union values.
+func (r *Reader) unionName(schema interface{}, namespace string) string {
+	switch s := schema.(type) {
+	case string:
+		if primitiveTypes[s] {
+			return s
+		}
+		return fullName(s, namespace)
+	case map[string]interface{}:
+		switch t := s["type"].(type) {
+		case string:
+			switch t {
+			case "record", "error", "enum", "fixed":
+				name, _ := typeName(s, namespace)
+				return name
+			case "array", "map":
+				return t
+			}
+			if logicalType, ok := s["logicalType"].(string); ok {
+				return t + "." + logicalType
+			}
+			return t
+		default:
+			return r.unionName(t, namespace)
+		}
+	}
+	return ""
+}
+
+// convert converts the decoded value of the schema to a value of JSON
+// records, records keep the order of their fields.
+func (r *Reader) convert(schema interface{}, namespace string, v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+
+	switch s := schema.(type) {
+	case string:
+		if primitiveTypes[s] {
+			return convertPrimitive(v, "")
+		}
+		named, ok := r.named[fullName(s, namespace)]
+		if !ok {
+			named, ok = r.named[s]
+		}
+		if ok {
+			return r.convert(named.schema, named.namespace, v)
+		}
+
+	case []interface{}:
+		// Union values are maps with the member name as single key.
+		m, ok := v.(map[string]interface{})
+		if !ok || len(m) != 1 {
+			break
+		}
+		for name, value := range m {
+			for _, member := range s {
+				if r.unionName(member, namespace) == name {
+					return r.convert(member, namespace, value)
+				}
+			}
+			return convertGeneric(value)
+		}
+
+	case map[string]interface{}:
+		t, ok := s["type"].(string)
+		if !ok {
+			return r.convert(s["type"], namespace, v)
+		}
+		switch t {
+		case "record", "error":
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				break
+			}
+			_, namespace = typeName(s, namespace)
+			fields, _ := s["fields"].([]interface{})
+			kvs := make(jstream.KVS, 0, len(fields))
+			for _, field := range fields {
+				f, ok := field.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				name, _ := f["name"].(string)
+				kvs = append(kvs, jstream.KV{Key: name, Value: r.convert(f["type"], namespace, m[name])})
+			}
+			return kvs
+		case "array":
+			values, ok := v.([]interface{})
+			if !ok {
+				break
+			}
+			out := make([]interface{}, len(values))
+			for i, value := range values {
+				out[i] = r.convert(s["items"], namespace, value)
+			}
+			return out
+		case "map":
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				break
+			}
+			kvs := make(jstream.KVS, 0, len(m))
+			for _, key := range sortedKeys(m) {
+				kvs = append(kvs, jstream.KV{Key: key, Value: r.convert(s["values"], namespace, m[key])})
+			}
+			return kvs
+		default:
+			logicalType, _ := s["logicalType"].(string)
+			return convertPrimitive(v, logicalType)
+		}
+	}
+	return convertGeneric(v)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// convertGeneric converts a value without using the schema.
+func convertGeneric(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		kvs := make(jstream.KVS, 0, len(value))
+		for _, key := range sortedKeys(value) {
+			kvs = append(kvs, jstream.KV{Key: key, Value: convertGeneric(value[key])})
+		}
+		return kvs
+	case []interface{}:
+		out := make([]interface{}, len(value))
+		for i := range value {
+			out[i] = convertGeneric(value[i])
+		}
+		return out
+	}
+	return convertPrimitive(v, "")
+}
+
+func convertPrimitive(v interface{}, logicalType string) interface{} {
+	switch value := v.(type) {
+	case int32:
+		return int64(value)
+	case float32:
+		return float64(value)
+	case []byte:
+		return string(value)
+	case *big.Rat:
+		f, _ := value.Float64()
+		return f
+	case time.Duration:
+		return value.String()
+	case time.Time:
+		switch logicalType {
+		case "date":
+			return value.UTC().Format("2006-01-02")
+		case "time-millis", "time-micros":
+			return value.UTC().Format("15:04:05.999999")
+		}
+		return value.UTC().Format(time.RFC3339Nano)
+	}
+	return v
+}
+
+// NewReader - creates new Avro reader using readCloser.
+func NewReader(readCloser io.ReadCloser, args *ReaderArgs) (*Reader, error) {
+	ocfReader, err := goavro.NewOCFReader(readCloser)
+	if err != nil {
+		return nil, errAvroParsingError(err)
+	}
+
+	r := &Reader{
+		args:       args,
+		readCloser: readCloser,
+		ocfReader:  ocfReader,
+		named:      make(map[string]namedType),
+	}
+
+	if err = json.Unmarshal([]byte(ocfReader.Codec().Schema()), &r.schema); err != nil {
+		return nil, errAvroParsingError(err)
+	}
+	r.register(r.schema, "")
+	return r, nil
+}
@@ -0,0 +1,135 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package avro
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/minio/minio/pkg/s3select/sql"
+)
+
+const testSchema = `{
+  "type": "record",
+  "name": "User",
+  "namespace": "com.example",
+  "fields": [
+    {"name": "id", "type": "long"},
+    {"name": "name", "type": "string"},
+    {"name": "score", "type": ["null", "double"]},
+    {"name": "tags", "type": {"type": "array", "items": "string"}},
+    {"name": "address", "type": ["null", {
+      "type": "record",
+      "name": "Address",
+      "fields": [
+        {"name": "city", "type": "string"},
+        {"name": "zip", "type": "int"}
+      ]
+    }]},
+    {"name": "previous", "type": ["null", "Address"]},
+    {"name": "props", "type": {"type": "map", "values": "long"}},
+    {"name": "color", "type": {"type": "enum", "name": "Color", "symbols": ["RED", "GREEN"]}},
+    {"name": "created", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+    {"name": "amount", "type": {"type": "bytes", "logicalType": "decimal", "precision": 10, "scale": 2}}
+  ]
+}`
+
+func TestReader(t *testing.T) {
+	for _, codec := range []string{"null", "deflate", "snappy"} {
+		var buf bytes.Buffer
+		w, err := goavro.NewOCFWriter(goavro.OCFConfig{W: &buf, Schema: testSchema, CompressionName: codec})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		created := time.Date(2020, time.March, 4, 5, 6, 7, 0, time.UTC)
+		if err = w.Append([]map[string]interface{}{
+			{
+				"id":       int64(1),
+				"name":     "alice",
+				"score":    goavro.Union("double", 1.5),
+				"tags":     []interface{}{"a", "b"},
+				"address":  goavro.Union("com.example.Address", map[string]interface{}{"city": "Paris", "zip": int32(75000)}),
+				"previous": goavro.Union("com.example.Address", map[string]interface{}{"city": "Oslo", "zip": int32(150)}),
+				"props":    map[string]interface{}{"z": int64(2), "a": int64(1)},
+				"color":    "GREEN",
+				"created":  created,
+				"amount":   big.NewRat(12345, 100),
+			},
+			{
+				"id":       int64(2),
+				"name":     "bob",
+				"score":    nil,
+				"tags":     []interface{}{},
+				"address":  nil,
+				"previous": nil,
+				"props":    map[string]interface{}{},
+				"color":    "RED",
+				"created":  created,
+				"amount":   big.NewRat(-5, 1),
+			},
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		r, err := NewReader(ioutil.NopCloser(&buf), &ReaderArgs{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var records []string
+		for {
+			rec, err := r.Read(nil)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			format, raw := rec.Raw()
+			if format != sql.SelectFmtAvro {
+				t.Fatalf("unexpected record format %v", format)
+			}
+			b, err := json.Marshal(raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			records = append(records, string(b))
+		}
+
+		expected := []string{
+			`{"id":1,"name":"alice","score":1.5,"tags":["a","b"],"address":{"city":"Paris","zip":75000},"previous":{"city":"Oslo","zip":150},"props":{"a":1,"z":2},"color":"GREEN","created":"2020-03-04T05:06:07Z","amount":123.45}`,
+			`{"id":2,"name":"bob","score":null,"tags":[],"address":null,"previous":null,"props":{},"color":"RED","created":"2020-03-04T05:06:07Z","amount":-5}`,
+		}
+		if !reflect.DeepEqual(records, expected) {
+			t.Errorf("Codec %s: expected %v, got %v", codec, expected, records)
+		}
+	}
+}
+
+func TestReaderInvalid(t *testing.T) {
+	if _, err := NewReader(ioutil.NopCloser(bytes.NewReader([]byte("id,name\n1,one\n"))), &ReaderArgs{}); err == nil {
+		t.Errorf("expected error for non Avro file")
+	}
+}
@@ -65,7 +65,7 @@ func errMalformedXML(err error) *s3Error {
 func errInvalidCompressionFormat(err error) *s3Error {
 	return &s3Error{
 		code:       "InvalidCompressionFormat",
-		message:    "The file is not in a supported compression format. Only GZIP and BZIP2 are supported.",
+		message:    "The file is not in a supported compression format. Only GZIP, BZIP2, ZSTD, LZ4 and SNAPPY are supported.",
 		statusCode: 400,
 		cause:      err,
 	}
@@ -89,10 +89,37 @@ func errInvalidGZIPCompressionFormat(err error) *s3Error {
 	}
 }
 
+func errInvalidZSTDCompressionFormat(err error) *s3Error {
+	return &s3Error{
+		code:       "InvalidCompressionFormat",
+		message:    "ZSTD is not applicable to the queried object. Please correct the request and try again.",
+		statusCode: 400,
+		cause:      err,
+	}
+}
+
+func errInvalidLZ4CompressionFormat(err error) *s3Error {
+	return &s3Error{
+		code:       "InvalidCompressionFormat",
+		message:    "LZ4 is not applicable to the queried object. Please correct the request and try again.",
+		statusCode: 400,
+		cause:      err,
+	}
+}
+
+func errInvalidSnappyCompressionFormat(err error) *s3Error {
+	return &s3Error{
+		code:       "InvalidCompressionFormat",
+		message:    "SNAPPY is not applicable to the queried object. Please correct the request and try again.",
+		statusCode: 400,
+		cause:      err,
+	}
+}
+
 func errInvalidDataSource(err error) *s3Error {
 	return &s3Error{
 		code:       "InvalidDataSource",
-		message:    "Invalid data source type. Only CSV, JSON, and Parquet are supported.",
+		message:    "Invalid data source type. Only CSV, JSON, Parquet, ORC and Avro are supported.",
 		statusCode: 400,
 		cause:      err,
 	}
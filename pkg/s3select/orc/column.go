@@ -0,0 +1,634 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package orc
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/bcicen/jstream"
+)
+
+var errInvalidColumn = errors.New("invalid column data")
+
+// ORC timestamps are stored as seconds since 2015-01-01 00:00:00.
+var timestampBase = time.Date(2015, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+type streamKey struct {
+	column uint32
+	kind   streamKind
+}
+
+// stripeData holds the decompressed streams of the columns which are
+// read from a stripe.
+type stripeData struct {
+	streams   map[streamKey][]byte
+	encodings []columnEncoding
+	location  *time.Location
+}
+
+func (s *stripeData) stream(id uint32, kind streamKind) []byte {
+	return s.streams[streamKey{id, kind}]
+}
+
+func (s *stripeData) encoding(id uint32) columnEncoding {
+	if int(id) < len(s.encodings) {
+		return s.encodings[id]
+	}
+	return columnEncoding{}
+}
+
+// isV2 returns whether the integers of the column use the run length
+// encoding version 2.
+func (s *stripeData) isV2(id uint32) bool {
+	kind := s.encoding(id).kind
+	return kind == encodingDirectV2 || kind == encodingDictionaryV2
+}
+
+// column decodes the values of a column in a stripe.
+type column interface {
+	// load decodes the n values, including nulls, of the stripe.
+	load(s *stripeData, n int) error
+
+	// next returns the next value.
+	next() interface{}
+}
+
+// presence tracks the null values of a column.
+type presence struct {
+	id      uint32
+	present []bool
+	pos     int
+}
+
+// loadPresent decodes the present stream of the n values, it returns
+// the number of non-null values.
+func (p *presence) loadPresent(s *stripeData, n int) (int, error) {
+	p.present, p.pos = nil, 0
+	data := s.stream(p.id, streamPresent)
+	if data == nil {
+		return n, nil
+	}
+
+	present, err := decodeBools(data, n)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, b := range present {
+		if b {
+			count++
+		}
+	}
+	p.present = present
+	return count, nil
+}
+
+// isNull returns whether the next value is null.
+func (p *presence) isNull() bool {
+	if p.present == nil {
+		return false
+	}
+	null := !p.present[p.pos]
+	p.pos++
+	return null
+}
+
+// newColumn returns the column reader of the column with the id and of
+// its subcolumns.
+func newColumn(types []orcType, id uint32) (column, error) {
+	t := types[id]
+	for _, sub := range t.subtypes {
+		// Subcolumns always follow their parent.
+		if sub <= id || int(sub) >= len(types) {
+			return nil, fmt.Errorf("invalid subtype %d of column %d", sub, id)
+		}
+	}
+
+	p := presence{id: id}
+	switch t.kind {
+	case kindBoolean:
+		return &boolColumn{presence: p}, nil
+	case kindByte, kindShort, kindInt, kindLong, kindDate:
+		return &intColumn{presence: p, kind: t.kind}, nil
+	case kindFloat, kindDouble:
+		return &floatColumn{presence: p, kind: t.kind}, nil
+	case kindString, kindVarchar, kindChar, kindBinary:
+		return &stringColumn{presence: p}, nil
+	case kindTimestamp, kindTimestampInstant:
+		return &timestampColumn{presence: p, kind: t.kind}, nil
+	case kindDecimal:
+		return &decimalColumn{presence: p}, nil
+	case kindStruct:
+		if len(t.fieldNames) != len(t.subtypes) {
+			return nil, fmt.Errorf("invalid struct column %d", id)
+		}
+		c := &structColumn{presence: p, names: t.fieldNames}
+		for _, sub := range t.subtypes {
+			field, err := newColumn(types, sub)
+			if err != nil {
+				return nil, err
+			}
+			c.fields = append(c.fields, field)
+		}
+		return c, nil
+	case kindList:
+		if len(t.subtypes) != 1 {
+			return nil, fmt.Errorf("invalid list column %d", id)
+		}
+		elem, err := newColumn(types, t.subtypes[0])
+		if err != nil {
+			return nil, err
+		}
+		return &listColumn{presence: p, elem: elem}, nil
+	case kindMap:
+		if len(t.subtypes) != 2 {
+			return nil, fmt.Errorf("invalid map column %d", id)
+		}
+		key, err := newColumn(types, t.subtypes[0])
+		if err != nil {
+			return nil, err
+		}
+		value, err := newColumn(types, t.subtypes[1])
+		if err != nil {
+			return nil, err
+		}
+		return &mapColumn{presence: p, key: key, value: value}, nil
+	case kindUnion:
+		c := &unionColumn{presence: p}
+		for _, sub := range t.subtypes {
+			child, err := newColumn(types, sub)
+			if err != nil {
+				return nil, err
+			}
+			c.children = append(c.children, child)
+		}
+		return c, nil
+	}
+	return nil, fmt.Errorf("unsupported type %d of column %d", t.kind, id)
+}
+
+type boolColumn struct {
+	presence
+	values []bool
+	idx    int
+}
+
+func (c *boolColumn) load(s *stripeData, n int) (err error) {
+	count, err := c.loadPresent(s, n)
+	if err != nil {
+		return err
+	}
+	c.values, err = decodeBools(s.stream(c.id, streamData), count)
+	c.idx = 0
+	return err
+}
+
+func (c *boolColumn) next() interface{} {
+	if c.isNull() {
+		return nil
+	}
+	v := c.values[c.idx]
+	c.idx++
+	return v
+}
+
+// intColumn reads integer and date columns.
+type intColumn struct {
+	presence
+	kind   typeKind
+	values []int64
+	idx    int
+}
+
+func (c *intColumn) load(s *stripeData, n int) (err error) {
+	count, err := c.loadPresent(s, n)
+	if err != nil {
+		return err
+	}
+	c.idx = 0
+
+	data := s.stream(c.id, streamData)
+	if c.kind != kindByte {
+		c.values, err = decodeInts(data, count, true, s.isV2(c.id))
+		return err
+	}
+
+	bs, err := decodeBytes(data, count)
+	if err != nil {
+		return err
+	}
+	c.values = make([]int64, count)
+	for i, b := range bs {
+		c.values[i] = int64(int8(b))
+	}
+	return nil
+}
+
+func (c *intColumn) next() interface{} {
+	if c.isNull() {
+		return nil
+	}
+	v := c.values[c.idx]
+	c.idx++
+	if c.kind == kindDate {
+		// Days since the epoch.
+		return time.Unix(v*24*60*60, 0).UTC().Format("2006-01-02")
+	}
+	return v
+}
+
+type floatColumn struct {
+	presence
+	kind   typeKind
+	values []float64
+	idx    int
+}
+
+func (c *floatColumn) load(s *stripeData, n int) error {
+	count, err := c.loadPresent(s, n)
+	if err != nil {
+		return err
+	}
+	c.idx = 0
+
+	size := 8
+	if c.kind == kindFloat {
+		size = 4
+	}
+	data := s.stream(c.id, streamData)
+	if len(data) < count*size {
+		return errTruncatedStream
+	}
+	c.values = make([]float64, count)
+	for i := range c.values {
+		if size == 4 {
+			c.values[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:])))
+		} else {
+			c.values[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[i*8:]))
+		}
+	}
+	return nil
+}
+
+func (c *floatColumn) next() interface{} {
+	if c.isNull() {
+		return nil
+	}
+	v := c.values[c.idx]
+	c.idx++
+	return v
+}
+
+// stringColumn reads string, varchar, char and binary columns.
+type stringColumn struct {
+	presence
+	values []string
+	idx    int
+}
+
+func (c *stringColumn) load(s *stripeData, n int) error {
+	count, err := c.loadPresent(s, n)
+	if err != nil {
+		return err
+	}
+	c.idx = 0
+	v2 := s.isV2(c.id)
+
+	encoding := s.encoding(c.id)
+	if encoding.kind == encodingDirect || encoding.kind == encodingDirectV2 {
+		c.values, err = splitStrings(s.stream(c.id, streamData), s.stream(c.id, streamLength), count, v2)
+		return err
+	}
+
+	// The data stream holds the indexes of the values in the
+	// dictionary.
+	dictionary, err := splitStrings(s.stream(c.id, streamDictionaryData), s.stream(c.id, streamLength), int(encoding.dictionarySize), v2)
+	if err != nil {
+		return err
+	}
+	indexes, err := decodeInts(s.stream(c.id, streamData), count, false, v2)
+	if err != nil {
+		return err
+	}
+	c.values = make([]string, count)
+	for i, idx := range indexes {
+		if idx < 0 || idx >= int64(len(dictionary)) {
+			return errInvalidColumn
+		}
+		c.values[i] = dictionary[idx]
+	}
+	return nil
+}
+
+// splitStrings splits the data into n strings of the lengths.
+func splitStrings(data, lengthData []byte, n int, v2 bool) ([]string, error) {
+	lengths, err := decodeInts(lengthData, n, false, v2)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]string, n)
+	for i, length := range lengths {
+		if length < 0 || length > int64(len(data)) {
+			return nil, errTruncatedStream
+		}
+		values[i] = string(data[:length])
+		data = data[length:]
+	}
+	return values, nil
+}
+
+func (c *stringColumn) next() interface{} {
+	if c.isNull() {
+		return nil
+	}
+	v := c.values[c.idx]
+	c.idx++
+	return v
+}
+
+type timestampColumn struct {
+	presence
+	kind     typeKind
+	seconds  []int64
+	nanos    []int64
+	location *time.Location
+	idx      int
+}
+
+func (c *timestampColumn) load(s *stripeData, n int) error {
+	count, err := c.loadPresent(s, n)
+	if err != nil {
+		return err
+	}
+	c.idx = 0
+	v2 := s.isV2(c.id)
+
+	if c.seconds, err = decodeInts(s.stream(c.id, streamData), count, true, v2); err != nil {
+		return err
+	}
+	if c.nanos, err = decodeInts(s.stream(c.id, streamSecondary), count, false, v2); err != nil {
+		return err
+	}
+
+	// The trailing decimal zeros of the nanoseconds are removed, their
+	// count minus one is stored in the lowest 3 bits.
+	for i, v := range c.nanos {
+		zeros := v & 7
+		v >>= 3
+		if zeros != 0 {
+			for j := int64(0); j <= zeros; j++ {
+				v *= 10
+			}
+		}
+		c.nanos[i] = v
+	}
+
+	c.location = time.UTC
+	if c.kind == kindTimestamp {
+		c.location = s.location
+	}
+	return nil
+}
+
+func (c *timestampColumn) next() interface{} {
+	if c.isNull() {
+		return nil
+	}
+	seconds, nanos := c.seconds[c.idx], c.nanos[c.idx]
+	c.idx++
+
+	if c.kind == kindTimestampInstant {
+		return time.Unix(timestampBase.Unix()+seconds, nanos).UTC().Format(time.RFC3339Nano)
+	}
+
+	// Timestamps without a time zone are stored relative to the time
+	// zone of the writer, the local time is returned.
+	base := time.Date(2015, time.January, 1, 0, 0, 0, 0, c.location)
+	t := time.Unix(base.Unix()+seconds, nanos).In(c.location)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC).Format(time.RFC3339Nano)
+}
+
+type decimalColumn struct {
+	presence
+	values []float64
+	idx    int
+}
+
+func (c *decimalColumn) load(s *stripeData, n int) error {
+	count, err := c.loadPresent(s, n)
+	if err != nil {
+		return err
+	}
+	c.idx = 0
+
+	unscaled, err := decodeBigInts(s.stream(c.id, streamData), count)
+	if err != nil {
+		return err
+	}
+	scales, err := decodeInts(s.stream(c.id, streamSecondary), count, true, s.isV2(c.id))
+	if err != nil {
+		return err
+	}
+
+	c.values = make([]float64, count)
+	for i, v := range unscaled {
+		scale := scales[i]
+		if scale < 0 || scale > 64 {
+			return errInvalidColumn
+		}
+		denom := new(big.Int).Exp(big.NewInt(10), big.NewInt(scale), nil)
+		c.values[i], _ = new(big.Rat).SetFrac(v, denom).Float64()
+	}
+	return nil
+}
+
+func (c *decimalColumn) next() interface{} {
+	if c.isNull() {
+		return nil
+	}
+	v := c.values[c.idx]
+	c.idx++
+	return v
+}
+
+type structColumn struct {
+	presence
+	names  []string
+	fields []column
+}
+
+func (c *structColumn) load(s *stripeData, n int) error {
+	count, err := c.loadPresent(s, n)
+	if err != nil {
+		return err
+	}
+	for _, field := range c.fields {
+		if err = field.load(s, count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *structColumn) next() interface{} {
+	if c.isNull() {
+		return nil
+	}
+	kvs := make(jstream.KVS, len(c.fields))
+	for i, field := range c.fields {
+		kvs[i] = jstream.KV{Key: c.names[i], Value: field.next()}
+	}
+	return kvs
+}
+
+// loadLengths decodes the lengths of the list or map values and returns
+// them along with the number of elements.
+func loadLengths(s *stripeData, id uint32, count int) ([]int64, int, error) {
+	lengths, err := decodeInts(s.stream(id, streamLength), count, false, s.isV2(id))
+	if err != nil {
+		return nil, 0, err
+	}
+	total := 0
+	for _, length := range lengths {
+		if length < 0 || length > math.MaxInt32 {
+			return nil, 0, errInvalidColumn
+		}
+		total += int(length)
+	}
+	return lengths, total, nil
+}
+
+type listColumn struct {
+	presence
+	elem    column
+	lengths []int64
+	idx     int
+}
+
+func (c *listColumn) load(s *stripeData, n int) error {
+	count, err := c.loadPresent(s, n)
+	if err != nil {
+		return err
+	}
+	c.idx = 0
+
+	var total int
+	if c.lengths, total, err = loadLengths(s, c.id, count); err != nil {
+		return err
+	}
+	return c.elem.load(s, total)
+}
+
+func (c *listColumn) next() interface{} {
+	if c.isNull() {
+		return nil
+	}
+	values := make([]interface{}, c.lengths[c.idx])
+	c.idx++
+	for i := range values {
+		values[i] = c.elem.next()
+	}
+	return values
+}
+
+type mapColumn struct {
+	presence
+	key, value column
+	lengths    []int64
+	idx        int
+}
+
+func (c *mapColumn) load(s *stripeData, n int) error {
+	count, err := c.loadPresent(s, n)
+	if err != nil {
+		return err
+	}
+	c.idx = 0
+
+	var total int
+	if c.lengths, total, err = loadLengths(s, c.id, count); err != nil {
+		return err
+	}
+	if err = c.key.load(s, total); err != nil {
+		return err
+	}
+	return c.value.load(s, total)
+}
+
+func (c *mapColumn) next() interface{} {
+	if c.isNull() {
+		return nil
+	}
+	kvs := make(jstream.KVS, c.lengths[c.idx])
+	c.idx++
+	for i := range kvs {
+		key := c.key.next()
+		if s, ok := key.(string); ok {
+			kvs[i].Key = s
+		} else {
+			kvs[i].Key = fmt.Sprint(key)
+		}
+		kvs[i].Value = c.value.next()
+	}
+	return kvs
+}
+
+type unionColumn struct {
+	presence
+	children []column
+	tags     []byte
+	idx      int
+}
+
+func (c *unionColumn) load(s *stripeData, n int) error {
+	count, err := c.loadPresent(s, n)
+	if err != nil {
+		return err
+	}
+	c.idx = 0
+
+	if c.tags, err = decodeBytes(s.stream(c.id, streamData), count); err != nil {
+		return err
+	}
+	counts := make([]int, len(c.children))
+	for _, tag := range c.tags {
+		if int(tag) >= len(c.children) {
+			return errInvalidColumn
+		}
+		counts[tag]++
+	}
+	for i, child := range c.children {
+		if err = child.load(s, counts[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *unionColumn) next() interface{} {
+	if c.isNull() {
+		return nil
+	}
+	tag := c.tags[c.idx]
+	c.idx++
+	return c.children[tag].next()
+}
@@ -0,0 +1,107 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package orc
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4"
+)
+
+// compressionKind - compression of the streams and of the file footer.
+type compressionKind uint64
+
+const (
+	compressionNone compressionKind = iota
+	compressionZlib
+	compressionSnappy
+	compressionLZO
+	compressionLZ4
+	compressionZstd
+)
+
+var errTruncatedChunk = errors.New("truncated compressed chunk")
+
+// zstdDecoder is only used with DecodeAll, which is safe for concurrent use.
+var zstdDecoder, _ = zstd.NewReader(nil)
+
+// decompress returns the content of a compressed stream, which is a
+// sequence of chunks each starting with a 3 byte header holding the
+// chunk length and whether the chunk is stored uncompressed.
+func decompress(kind compressionKind, blockSize uint64, data []byte) ([]byte, error) {
+	if kind == compressionNone {
+		return data, nil
+	}
+
+	var out []byte
+	for len(data) > 0 {
+		if len(data) < 3 {
+			return nil, errTruncatedChunk
+		}
+		header := int(data[0]) | int(data[1])<<8 | int(data[2])<<16
+		isOriginal := header&1 == 1
+		length := header >> 1
+		data = data[3:]
+		if len(data) < length {
+			return nil, errTruncatedChunk
+		}
+		chunk := data[:length]
+		data = data[length:]
+
+		if isOriginal {
+			out = append(out, chunk...)
+			continue
+		}
+
+		switch kind {
+		case compressionZlib:
+			b, err := ioutil.ReadAll(flate.NewReader(bytes.NewReader(chunk)))
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, b...)
+		case compressionSnappy:
+			b, err := snappy.Decode(nil, chunk)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, b...)
+		case compressionLZ4:
+			b := make([]byte, blockSize)
+			n, err := lz4.UncompressBlock(chunk, b)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, b[:n]...)
+		case compressionZstd:
+			b, err := zstdDecoder.DecodeAll(chunk, nil)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, b...)
+		default:
+			return nil, fmt.Errorf("unsupported compression kind %d", kind)
+		}
+	}
+	return out, nil
+}
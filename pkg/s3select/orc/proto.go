@@ -0,0 +1,327 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package orc
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// The file tail and the stripe footers of ORC files are protobuf
+// messages. Only the fields needed to read the data are decoded.
+
+var errInvalidProtobuf = errors.New("invalid protobuf message")
+
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+type protoField struct {
+	num      int
+	wireType int
+	value    uint64 // varint and fixed size fields
+	data     []byte // length delimited fields
+}
+
+// decodeProto calls fn on each field of the protobuf message.
+func decodeProto(buf []byte, fn func(f protoField) error) error {
+	for len(buf) > 0 {
+		key, n := binary.Uvarint(buf)
+		if n <= 0 {
+			return errInvalidProtobuf
+		}
+		buf = buf[n:]
+
+		f := protoField{num: int(key >> 3), wireType: int(key & 7)}
+		switch f.wireType {
+		case wireVarint:
+			if f.value, n = binary.Uvarint(buf); n <= 0 {
+				return errInvalidProtobuf
+			}
+			buf = buf[n:]
+		case wireFixed64:
+			if len(buf) < 8 {
+				return errInvalidProtobuf
+			}
+			f.value = binary.LittleEndian.Uint64(buf)
+			buf = buf[8:]
+		case wireBytes:
+			length, n := binary.Uvarint(buf)
+			if n <= 0 || length > uint64(len(buf)-n) {
+				return errInvalidProtobuf
+			}
+			f.data = buf[n : n+int(length)]
+			buf = buf[n+int(length):]
+		case wireFixed32:
+			if len(buf) < 4 {
+				return errInvalidProtobuf
+			}
+			f.value = uint64(binary.LittleEndian.Uint32(buf))
+			buf = buf[4:]
+		default:
+			return errInvalidProtobuf
+		}
+
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendUint32s appends the values of a repeated integer field, which
+// are either packed into one field or stored as one field per value.
+func (f protoField) appendUint32s(dst []uint32) ([]uint32, error) {
+	if f.wireType != wireBytes {
+		return append(dst, uint32(f.value)), nil
+	}
+	buf := f.data
+	for len(buf) > 0 {
+		v, n := binary.Uvarint(buf)
+		if n <= 0 {
+			return nil, errInvalidProtobuf
+		}
+		dst = append(dst, uint32(v))
+		buf = buf[n:]
+	}
+	return dst, nil
+}
+
+type postScript struct {
+	footerLength         uint64
+	compression          compressionKind
+	compressionBlockSize uint64
+	metadataLength       uint64
+	magic                string
+}
+
+func parsePostScript(buf []byte) (ps postScript, err error) {
+	err = decodeProto(buf, func(f protoField) error {
+		switch f.num {
+		case 1:
+			ps.footerLength = f.value
+		case 2:
+			ps.compression = compressionKind(f.value)
+		case 3:
+			ps.compressionBlockSize = f.value
+		case 5:
+			ps.metadataLength = f.value
+		case 8000:
+			ps.magic = string(f.data)
+		}
+		return nil
+	})
+	return ps, err
+}
+
+type stripeInformation struct {
+	offset       uint64
+	indexLength  uint64
+	dataLength   uint64
+	footerLength uint64
+	numberOfRows uint64
+}
+
+func parseStripeInformation(buf []byte) (si stripeInformation, err error) {
+	err = decodeProto(buf, func(f protoField) error {
+		switch f.num {
+		case 1:
+			si.offset = f.value
+		case 2:
+			si.indexLength = f.value
+		case 3:
+			si.dataLength = f.value
+		case 4:
+			si.footerLength = f.value
+		case 5:
+			si.numberOfRows = f.value
+		}
+		return nil
+	})
+	return si, err
+}
+
+// typeKind - kind of a column type.
+type typeKind uint64
+
+const (
+	kindBoolean typeKind = iota
+	kindByte
+	kindShort
+	kindInt
+	kindLong
+	kindFloat
+	kindDouble
+	kindString
+	kindBinary
+	kindTimestamp
+	kindList
+	kindMap
+	kindStruct
+	kindUnion
+	kindDecimal
+	kindDate
+	kindVarchar
+	kindChar
+	kindTimestampInstant
+)
+
+type orcType struct {
+	kind       typeKind
+	subtypes   []uint32
+	fieldNames []string
+}
+
+func parseType(buf []byte) (t orcType, err error) {
+	err = decodeProto(buf, func(f protoField) (err error) {
+		switch f.num {
+		case 1:
+			t.kind = typeKind(f.value)
+		case 2:
+			t.subtypes, err = f.appendUint32s(t.subtypes)
+		case 3:
+			t.fieldNames = append(t.fieldNames, string(f.data))
+		}
+		return err
+	})
+	return t, err
+}
+
+type footer struct {
+	stripes      []stripeInformation
+	types        []orcType
+	numberOfRows uint64
+}
+
+func parseFooter(buf []byte) (ft footer, err error) {
+	err = decodeProto(buf, func(f protoField) error {
+		switch f.num {
+		case 3:
+			si, err := parseStripeInformation(f.data)
+			if err != nil {
+				return err
+			}
+			ft.stripes = append(ft.stripes, si)
+		case 4:
+			t, err := parseType(f.data)
+			if err != nil {
+				return err
+			}
+			ft.types = append(ft.types, t)
+		case 6:
+			ft.numberOfRows = f.value
+		}
+		return nil
+	})
+	return ft, err
+}
+
+// streamKind - kind of a stream in a stripe.
+type streamKind uint64
+
+const (
+	streamPresent streamKind = iota
+	streamData
+	streamLength
+	streamDictionaryData
+	streamDictionaryCount
+	streamSecondary
+	streamRowIndex
+	streamBloomFilter
+	streamBloomFilterUTF8
+)
+
+type stream struct {
+	kind   streamKind
+	column uint32
+	length uint64
+}
+
+func parseStream(buf []byte) (s stream, err error) {
+	err = decodeProto(buf, func(f protoField) error {
+		switch f.num {
+		case 1:
+			s.kind = streamKind(f.value)
+		case 2:
+			s.column = uint32(f.value)
+		case 3:
+			s.length = f.value
+		}
+		return nil
+	})
+	return s, err
+}
+
+// encodingKind - encoding of a column in a stripe.
+type encodingKind uint64
+
+const (
+	encodingDirect encodingKind = iota
+	encodingDictionary
+	encodingDirectV2
+	encodingDictionaryV2
+)
+
+type columnEncoding struct {
+	kind           encodingKind
+	dictionarySize uint64
+}
+
+func parseColumnEncoding(buf []byte) (ce columnEncoding, err error) {
+	err = decodeProto(buf, func(f protoField) error {
+		switch f.num {
+		case 1:
+			ce.kind = encodingKind(f.value)
+		case 2:
+			ce.dictionarySize = f.value
+		}
+		return nil
+	})
+	return ce, err
+}
+
+type stripeFooter struct {
+	streams        []stream
+	columns        []columnEncoding
+	writerTimezone string
+}
+
+func parseStripeFooter(buf []byte) (sf stripeFooter, err error) {
+	err = decodeProto(buf, func(f protoField) error {
+		switch f.num {
+		case 1:
+			s, err := parseStream(f.data)
+			if err != nil {
+				return err
+			}
+			sf.streams = append(sf.streams, s)
+		case 2:
+			ce, err := parseColumnEncoding(f.data)
+			if err != nil {
+				return err
+			}
+			sf.columns = append(sf.columns, ce)
+		case 3:
+			sf.writerTimezone = string(f.data)
+		}
+		return nil
+	})
+	return sf, err
+}
@@ -0,0 +1,287 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package orc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/bcicen/jstream"
+	jsonfmt "github.com/minio/minio/pkg/s3select/json"
+	"github.com/minio/minio/pkg/s3select/sql"
+)
+
+const (
+	orcMagic = "ORC"
+
+	// Size of the first read of the file tail, which usually holds
+	// the postscript and the footer.
+	tailReadSize = 16 << 10
+)
+
+// Reader - ORC record reader for S3Select.
+type Reader struct {
+	args          *ReaderArgs
+	getReaderFunc func(offset, length int64) (io.ReadCloser, error)
+	postScript    postScript
+	footer        footer
+
+	// The top level columns which are read and the ids of all their
+	// columns, only the streams of these columns are read.
+	root    *structColumn
+	columns map[uint32]bool
+
+	stripeIndex int
+	rows        int
+}
+
+// Read - reads single record.
+func (r *Reader) Read(dst sql.Record) (rec sql.Record, rerr error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			rerr = errORCParsingError(fmt.Errorf("panic reading orc record: %v", rec))
+		}
+	}()
+
+	for r.rows == 0 {
+		if r.stripeIndex >= len(r.footer.stripes) {
+			return nil, io.EOF
+		}
+		stripe := r.footer.stripes[r.stripeIndex]
+		r.stripeIndex++
+		if err := r.loadStripe(stripe); err != nil {
+			return nil, errORCParsingError(err)
+		}
+	}
+	r.rows--
+
+	kvs, _ := r.root.next().(jstream.KVS)
+
+	// Reuse destination if we can.
+	dstRec, ok := dst.(*jsonfmt.Record)
+	if !ok {
+		dstRec = &jsonfmt.Record{}
+	}
+	dstRec.SelectFormat = sql.SelectFmtORC
+	dstRec.KVS = kvs
+	return dstRec, nil
+}
+
+// Close - closes underlying readers.
+func (r *Reader) Close() error {
+	return nil
+}
+
+// readRange reads length bytes at offset of the file, a negative offset
+// reads the last bytes of the file up to length.
+func (r *Reader) readRange(offset, length int64) ([]byte, error) {
+	rc, err := r.getReaderFunc(offset, length)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	if offset < 0 {
+		return ioutil.ReadAll(io.LimitReader(rc, length))
+	}
+	buf := make([]byte, length)
+	if _, err = io.ReadFull(rc, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// readTail reads the postscript and the footer at the end of the file.
+func (r *Reader) readTail() error {
+	buf, err := r.readRange(-tailReadSize, tailReadSize)
+	if err != nil {
+		return err
+	}
+	if len(buf) == 0 {
+		return errors.New("empty file")
+	}
+
+	// The last byte is the length of the postscript.
+	psLen := int(buf[len(buf)-1])
+	if len(buf) < psLen+1 {
+		return errors.New("truncated postscript")
+	}
+	footerEnd := len(buf) - 1 - psLen
+	if r.postScript, err = parsePostScript(buf[footerEnd : len(buf)-1]); err != nil {
+		return err
+	}
+	if r.postScript.magic != orcMagic {
+		return errors.New("not an ORC file")
+	}
+
+	footerLength := r.postScript.footerLength
+	if uint64(footerEnd) < footerLength {
+		// The footer is larger than the first read.
+		size := int64(footerLength) + int64(psLen) + 1
+		if buf, err = r.readRange(-size, size); err != nil {
+			return err
+		}
+		footerEnd = len(buf) - 1 - psLen
+		if footerEnd < 0 || uint64(footerEnd) < footerLength {
+			return errors.New("truncated footer")
+		}
+	}
+
+	data, err := decompress(r.postScript.compression, r.postScript.compressionBlockSize, buf[footerEnd-int(footerLength):footerEnd])
+	if err != nil {
+		return err
+	}
+	r.footer, err = parseFooter(data)
+	return err
+}
+
+// addColumns adds the ids of the column and its subcolumns to the read
+// columns.
+func (r *Reader) addColumns(id uint32) {
+	r.columns[id] = true
+	for _, sub := range r.footer.types[id].subtypes {
+		r.addColumns(sub)
+	}
+}
+
+// loadStripe reads the streams of the read columns in the stripe.
+func (r *Reader) loadStripe(si stripeInformation) error {
+	footerOffset := si.offset + si.indexLength + si.dataLength
+	buf, err := r.readRange(int64(footerOffset), int64(si.footerLength))
+	if err != nil {
+		return err
+	}
+	if buf, err = decompress(r.postScript.compression, r.postScript.compressionBlockSize, buf); err != nil {
+		return err
+	}
+	sf, err := parseStripeFooter(buf)
+	if err != nil {
+		return err
+	}
+
+	type streamRange struct {
+		key            streamKey
+		offset, length uint64
+	}
+
+	// The streams are stored one after another, starting with the
+	// index streams.
+	var ranges []streamRange
+	offset := si.offset
+	for _, st := range sf.streams {
+		if offset+st.length > footerOffset {
+			return errors.New("stream exceeds stripe")
+		}
+		switch st.kind {
+		case streamPresent, streamData, streamLength, streamDictionaryData, streamSecondary:
+			if r.columns[st.column] {
+				ranges = append(ranges, streamRange{streamKey{st.column, st.kind}, offset, st.length})
+			}
+		}
+		offset += st.length
+	}
+
+	s := &stripeData{
+		streams:   make(map[streamKey][]byte, len(ranges)),
+		encodings: sf.columns,
+		location:  time.UTC,
+	}
+	if sf.writerTimezone != "" {
+		if loc, err := time.LoadLocation(sf.writerTimezone); err == nil {
+			s.location = loc
+		}
+	}
+
+	// Adjacent streams are read at once.
+	for i := 0; i < len(ranges); {
+		start, end := ranges[i].offset, ranges[i].offset+ranges[i].length
+		j := i + 1
+		for j < len(ranges) && ranges[j].offset == end {
+			end += ranges[j].length
+			j++
+		}
+		buf, err := r.readRange(int64(start), int64(end-start))
+		if err != nil {
+			return err
+		}
+		for ; i < j; i++ {
+			data := buf[ranges[i].offset-start : ranges[i].offset-start+ranges[i].length]
+			if data, err = decompress(r.postScript.compression, r.postScript.compressionBlockSize, data); err != nil {
+				return err
+			}
+			s.streams[ranges[i].key] = data
+		}
+	}
+
+	r.rows = int(si.numberOfRows)
+	return r.root.load(s, r.rows)
+}
+
+// NewReader - creates new ORC reader using readerFunc callback. Only the
+// given top level columns are read, all columns if columns is nil.
+func NewReader(getReaderFunc func(offset, length int64) (io.ReadCloser, error), args *ReaderArgs, columns []string) (r *Reader, err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = errORCParsingError(fmt.Errorf("panic reading orc footer: %v", rec))
+		}
+	}()
+
+	r = &Reader{
+		args:          args,
+		getReaderFunc: getReaderFunc,
+		columns:       make(map[uint32]bool),
+	}
+	if err = r.readTail(); err != nil {
+		return nil, errORCParsingError(err)
+	}
+
+	types := r.footer.types
+	if len(types) == 0 || types[0].kind != kindStruct || len(types[0].fieldNames) != len(types[0].subtypes) {
+		return nil, errORCParsingError(errors.New("root column is not a struct"))
+	}
+
+	var wanted map[string]bool
+	if columns != nil {
+		wanted = make(map[string]bool, len(columns))
+		for _, name := range columns {
+			wanted[name] = true
+		}
+	}
+
+	r.root = &structColumn{}
+	r.columns[0] = true
+	for i, id := range types[0].subtypes {
+		name := types[0].fieldNames[i]
+		if wanted != nil && !wanted[name] {
+			continue
+		}
+		if id == 0 || int(id) >= len(types) {
+			return nil, errORCParsingError(fmt.Errorf("invalid subtype %d", id))
+		}
+		field, err := newColumn(types, id)
+		if err != nil {
+			return nil, errORCParsingError(err)
+		}
+		r.root.names = append(r.root.names, name)
+		r.root.fields = append(r.root.fields, field)
+		r.addColumns(id)
+	}
+	return r, nil
+}
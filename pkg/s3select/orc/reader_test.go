@@ -0,0 +1,400 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package orc
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/minio/minio/pkg/s3select/sql"
+)
+
+type testStream struct {
+	column uint32
+	kind   streamKind
+	data   []byte
+}
+
+type testColumn struct {
+	kind     typeKind
+	subtypes []uint32
+	names    []string
+	encoding columnEncoding
+}
+
+// testFile writes ORC files with a single stripe.
+type testFile struct {
+	compression compressionKind
+	columns     []testColumn
+	streams     []testStream
+	rows        uint64
+}
+
+func appendVarintField(b []byte, num int, v uint64) []byte {
+	b = appendUvarint(b, uint64(num)<<3|wireVarint)
+	return appendUvarint(b, v)
+}
+
+func appendBytesField(b []byte, num int, data []byte) []byte {
+	b = appendUvarint(b, uint64(num)<<3|wireBytes)
+	b = appendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], v)]...)
+}
+
+func (f *testFile) compress(t *testing.T, data []byte) []byte {
+	var chunk []byte
+	switch f.compression {
+	case compressionNone:
+		return data
+	case compressionZlib:
+		var buf bytes.Buffer
+		w, _ := flate.NewWriter(&buf, flate.BestCompression)
+		w.Write(data)
+		w.Close()
+		chunk = buf.Bytes()
+	case compressionSnappy:
+		chunk = snappy.Encode(nil, data)
+	case compressionZstd:
+		enc, err := zstd.NewWriter(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		chunk = enc.EncodeAll(data, nil)
+	}
+
+	// Store the chunk uncompressed if it does not get smaller.
+	header := len(chunk) << 1
+	if len(chunk) >= len(data) {
+		chunk = data
+		header = len(data)<<1 | 1
+	}
+	return append([]byte{byte(header), byte(header >> 8), byte(header >> 16)}, chunk...)
+}
+
+func (f *testFile) bytes(t *testing.T) []byte {
+	file := []byte(orcMagic)
+
+	var indexLength, dataLength uint64
+	var stripeFooter []byte
+	for _, s := range f.streams {
+		data := f.compress(t, s.data)
+		file = append(file, data...)
+		if s.kind == streamRowIndex {
+			indexLength += uint64(len(data))
+		} else {
+			dataLength += uint64(len(data))
+		}
+
+		var st []byte
+		st = appendVarintField(st, 1, uint64(s.kind))
+		st = appendVarintField(st, 2, uint64(s.column))
+		st = appendVarintField(st, 3, uint64(len(data)))
+		stripeFooter = appendBytesField(stripeFooter, 1, st)
+	}
+	for _, c := range f.columns {
+		var ce []byte
+		ce = appendVarintField(ce, 1, uint64(c.encoding.kind))
+		ce = appendVarintField(ce, 2, c.encoding.dictionarySize)
+		stripeFooter = appendBytesField(stripeFooter, 2, ce)
+	}
+	stripeFooter = f.compress(t, stripeFooter)
+	file = append(file, stripeFooter...)
+
+	var si []byte
+	si = appendVarintField(si, 1, uint64(len(orcMagic)))
+	si = appendVarintField(si, 2, indexLength)
+	si = appendVarintField(si, 3, dataLength)
+	si = appendVarintField(si, 4, uint64(len(stripeFooter)))
+	si = appendVarintField(si, 5, f.rows)
+
+	var ft []byte
+	ft = appendVarintField(ft, 1, uint64(len(orcMagic)))
+	ft = appendVarintField(ft, 2, uint64(len(file)))
+	ft = appendBytesField(ft, 3, si)
+	for _, c := range f.columns {
+		var typ []byte
+		typ = appendVarintField(typ, 1, uint64(c.kind))
+		var subtypes []byte
+		for _, sub := range c.subtypes {
+			subtypes = appendUvarint(subtypes, uint64(sub))
+		}
+		if len(subtypes) > 0 {
+			typ = appendBytesField(typ, 2, subtypes)
+		}
+		for _, name := range c.names {
+			typ = appendBytesField(typ, 3, []byte(name))
+		}
+		ft = appendBytesField(ft, 4, typ)
+	}
+	ft = appendVarintField(ft, 6, f.rows)
+	ft = f.compress(t, ft)
+	file = append(file, ft...)
+
+	var ps []byte
+	ps = appendVarintField(ps, 1, uint64(len(ft)))
+	ps = appendVarintField(ps, 2, uint64(f.compression))
+	ps = appendVarintField(ps, 3, 256<<10)
+	ps = appendBytesField(ps, 8000, []byte(orcMagic))
+	file = append(file, ps...)
+	return append(file, byte(len(ps)))
+}
+
+// encodeInts encodes the values with literal runs of the integer run
+// length encoding version 1.
+func encodeInts(values []int64, signed bool) []byte {
+	var b []byte
+	for len(values) > 0 {
+		n := len(values)
+		if n > 128 {
+			n = 128
+		}
+		b = append(b, byte(-n))
+		for _, v := range values[:n] {
+			if signed {
+				b = appendUvarint(b, uint64(v<<1^v>>63))
+			} else {
+				b = appendUvarint(b, uint64(v))
+			}
+		}
+		values = values[n:]
+	}
+	return b
+}
+
+// encodeBools encodes the values with literal runs of the byte run
+// length encoding.
+func encodeBools(values []bool) []byte {
+	bs := make([]byte, (len(values)+7)/8)
+	for i, v := range values {
+		if v {
+			bs[i/8] |= 0x80 >> uint(i%8)
+		}
+	}
+	return append([]byte{byte(-len(bs))}, bs...)
+}
+
+func encodeDoubles(values []float64) []byte {
+	b := make([]byte, 8*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint64(b[i*8:], math.Float64bits(v))
+	}
+	return b
+}
+
+// newTestFile returns a file of 3 rows with a long, a string, a double,
+// a list, a struct, a boolean and a date column.
+func newTestFile(compression compressionKind) *testFile {
+	return &testFile{
+		compression: compression,
+		rows:        3,
+		columns: []testColumn{
+			{kind: kindStruct, subtypes: []uint32{1, 2, 3, 4, 6, 9, 10}, names: []string{"id", "name", "score", "tags", "info", "flag", "day"}},
+			{kind: kindLong},
+			{kind: kindString, encoding: columnEncoding{kind: encodingDictionary, dictionarySize: 2}},
+			{kind: kindDouble},
+			{kind: kindList, subtypes: []uint32{5}},
+			{kind: kindString},
+			{kind: kindStruct, subtypes: []uint32{7, 8}, names: []string{"city", "zip"}},
+			{kind: kindString},
+			{kind: kindInt},
+			{kind: kindBoolean},
+			{kind: kindDate},
+		},
+		streams: []testStream{
+			{0, streamRowIndex, []byte{1, 2, 3, 4, 5}},
+			{1, streamData, encodeInts([]int64{1, 2, 3}, true)},
+			{2, streamPresent, encodeBools([]bool{true, false, true})},
+			{2, streamData, encodeInts([]int64{0, 1}, false)},
+			{2, streamLength, encodeInts([]int64{5, 5}, false)},
+			{2, streamDictionaryData, []byte("alicecarol")},
+			{3, streamData, encodeDoubles([]float64{1.5, 2.5, -1})},
+			{4, streamLength, encodeInts([]int64{2, 0, 1}, false)},
+			{5, streamData, []byte("abc")},
+			{5, streamLength, encodeInts([]int64{1, 1, 1}, false)},
+			{6, streamPresent, encodeBools([]bool{true, false, true})},
+			{7, streamData, []byte("ParisOslo")},
+			{7, streamLength, encodeInts([]int64{5, 4}, false)},
+			{8, streamData, encodeInts([]int64{75000, 150}, true)},
+			{9, streamData, encodeBools([]bool{true, false, true})},
+			{10, streamData, encodeInts([]int64{18262, 0, 18263}, true)},
+		},
+	}
+}
+
+type readRange struct {
+	offset, length int64
+}
+
+func newTestReader(t *testing.T, data []byte, columns []string, ranges *[]readRange) *Reader {
+	getReader := func(offset, length int64) (io.ReadCloser, error) {
+		*ranges = append(*ranges, readRange{offset, length})
+		if offset < 0 {
+			offset += int64(len(data))
+			if offset < 0 {
+				offset = 0
+			}
+		}
+		return ioutil.NopCloser(bytes.NewReader(data[offset:])), nil
+	}
+	r, err := NewReader(getReader, &ReaderArgs{}, columns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func readAll(t *testing.T, r *Reader) []string {
+	var records []string
+	for {
+		rec, err := r.Read(nil)
+		if err == io.EOF {
+			return records
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		format, raw := rec.Raw()
+		if format != sql.SelectFmtORC {
+			t.Fatalf("unexpected record format %v", format)
+		}
+		b, err := json.Marshal(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, string(b))
+	}
+}
+
+func TestReader(t *testing.T) {
+	expected := []string{
+		`{"id":1,"name":"alice","score":1.5,"tags":["a","b"],"info":{"city":"Paris","zip":75000},"flag":true,"day":"2020-01-01"}`,
+		`{"id":2,"name":null,"score":2.5,"tags":[],"info":null,"flag":false,"day":"1970-01-01"}`,
+		`{"id":3,"name":"carol","score":-1,"tags":["c"],"info":{"city":"Oslo","zip":150},"flag":true,"day":"2020-01-02"}`,
+	}
+
+	for _, compression := range []compressionKind{compressionNone, compressionZlib, compressionSnappy, compressionZstd} {
+		var ranges []readRange
+		r := newTestReader(t, newTestFile(compression).bytes(t), nil, &ranges)
+		if records := readAll(t, r); !reflect.DeepEqual(records, expected) {
+			t.Errorf("Compression %d: expected %v, got %v", compression, expected, records)
+		}
+	}
+}
+
+func TestReaderProjection(t *testing.T) {
+	f := newTestFile(compressionNone)
+	data := f.bytes(t)
+
+	var ranges []readRange
+	r := newTestReader(t, data, []string{"info", "missing"}, &ranges)
+	expected := []string{
+		`{"info":{"city":"Paris","zip":75000}}`,
+		`{"info":null}`,
+		`{"info":{"city":"Oslo","zip":150}}`,
+	}
+	if records := readAll(t, r); !reflect.DeepEqual(records, expected) {
+		t.Errorf("expected %v, got %v", expected, records)
+	}
+
+	// Only the streams of the info column are read, which are stored
+	// after each other.
+	var streamRanges []readRange
+	offset := int64(len(orcMagic))
+	for _, s := range f.streams {
+		if s.column >= 6 && s.column <= 8 {
+			streamRanges = append(streamRanges, readRange{offset, int64(len(s.data))})
+		}
+		offset += int64(len(s.data))
+	}
+	start := streamRanges[0].offset
+	last := streamRanges[len(streamRanges)-1]
+	want := readRange{start, last.offset + last.length - start}
+
+	var dataReads []readRange
+	for _, rr := range ranges {
+		// Skip the file tail and the stripe footer.
+		if rr.offset >= 0 && rr.offset < offset {
+			dataReads = append(dataReads, rr)
+		}
+	}
+	if !reflect.DeepEqual(dataReads, []readRange{want}) {
+		t.Errorf("expected reads %v, got %v", []readRange{want}, dataReads)
+	}
+
+	// No columns are read for COUNT(*)
+	ranges = nil
+	r = newTestReader(t, data, []string{}, &ranges)
+	if records := readAll(t, r); !reflect.DeepEqual(records, []string{"{}", "{}", "{}"}) {
+		t.Errorf("unexpected records %v", records)
+	}
+}
+
+func TestReaderInvalid(t *testing.T) {
+	getReader := func(offset, length int64) (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader([]byte("id,name\n1,one\n"))), nil
+	}
+	if _, err := NewReader(getReader, &ReaderArgs{}, nil); err == nil {
+		t.Errorf("expected error for non ORC file")
+	}
+}
+
+func TestTimestampDecimalColumns(t *testing.T) {
+	s := &stripeData{
+		streams: map[streamKey][]byte{
+			{1, streamData}:      encodeInts([]int64{0, 157766400, -1}, true),
+			{1, streamSecondary}: encodeInts([]int64{0, 5<<3 | 7, 0}, false),
+			{2, streamData}:      appendUvarint(appendUvarint(nil, 24690), 9),
+			{2, streamSecondary}: encodeInts([]int64{2, 0}, true),
+		},
+		location: time.UTC,
+	}
+
+	ts := &timestampColumn{presence: presence{id: 1}, kind: kindTimestampInstant}
+	if err := ts.load(s, 3); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"2015-01-01T00:00:00Z", "2020-01-01T00:00:00.5Z", "2014-12-31T23:59:59Z"} {
+		if v := ts.next(); v != expected {
+			t.Errorf("expected %v, got %v", expected, v)
+		}
+	}
+
+	dec := &decimalColumn{presence: presence{id: 2}}
+	if err := dec.load(s, 2); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []float64{123.45, -5} {
+		if v := dec.next(); v != expected {
+			t.Errorf("expected %v, got %v", expected, v)
+		}
+	}
+}
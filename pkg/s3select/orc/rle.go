@@ -0,0 +1,435 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package orc
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+)
+
+var errTruncatedStream = errors.New("truncated stream")
+
+// decodeBytes decodes n values of the byte run length encoding.
+func decodeBytes(data []byte, n int) ([]byte, error) {
+	out := make([]byte, 0, n)
+	for len(out) < n {
+		if len(data) < 2 {
+			return nil, errTruncatedStream
+		}
+		control := int8(data[0])
+		data = data[1:]
+		if control >= 0 {
+			// Run of the same value.
+			for i := 0; i < int(control)+3; i++ {
+				out = append(out, data[0])
+			}
+			data = data[1:]
+			continue
+		}
+
+		// Literal values.
+		count := -int(control)
+		if len(data) < count {
+			return nil, errTruncatedStream
+		}
+		out = append(out, data[:count]...)
+		data = data[count:]
+	}
+	return out[:n], nil
+}
+
+// decodeBools decodes n values of the boolean run length encoding,
+// which is the byte run length encoding of bits.
+func decodeBools(data []byte, n int) ([]bool, error) {
+	bs, err := decodeBytes(data, (n+7)/8)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]bool, n)
+	for i := range out {
+		out[i] = bs[i/8]&(0x80>>uint(i%8)) != 0
+	}
+	return out, nil
+}
+
+// decodeInts decodes n values of the integer run length encoding,
+// version 1 or 2.
+func decodeInts(data []byte, n int, signed, v2 bool) ([]int64, error) {
+	d := intDecoder{data: data, signed: signed}
+	out := make([]int64, 0, n)
+	var err error
+	for len(out) < n && err == nil {
+		if v2 {
+			out, err = d.nextRunV2(out)
+		} else {
+			out, err = d.nextRunV1(out)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return out[:n], nil
+}
+
+type intDecoder struct {
+	data   []byte
+	signed bool
+}
+
+func zigzag(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (d *intDecoder) readByte() (byte, error) {
+	if len(d.data) == 0 {
+		return 0, errTruncatedStream
+	}
+	b := d.data[0]
+	d.data = d.data[1:]
+	return b, nil
+}
+
+func (d *intDecoder) readUvarint() (uint64, error) {
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		return 0, errTruncatedStream
+	}
+	d.data = d.data[n:]
+	return v, nil
+}
+
+func (d *intDecoder) readVarint() (int64, error) {
+	v, err := d.readUvarint()
+	return zigzag(v), err
+}
+
+// readBase reads a varint of the signedness of the stream.
+func (d *intDecoder) readBase() (int64, error) {
+	if d.signed {
+		return d.readVarint()
+	}
+	v, err := d.readUvarint()
+	return int64(v), err
+}
+
+func (d *intDecoder) toSigned(v uint64) int64 {
+	if d.signed {
+		return zigzag(v)
+	}
+	return int64(v)
+}
+
+// readBigEndian reads an unsigned integer of width bytes.
+func (d *intDecoder) readBigEndian(width int) (uint64, error) {
+	if len(d.data) < width {
+		return 0, errTruncatedStream
+	}
+	var v uint64
+	for _, b := range d.data[:width] {
+		v = v<<8 | uint64(b)
+	}
+	d.data = d.data[width:]
+	return v, nil
+}
+
+// readBits reads n bit packed values of width bits, the values are
+// padded to a whole byte.
+func (d *intDecoder) readBits(n, width int) ([]uint64, error) {
+	size := (n*width + 7) / 8
+	if len(d.data) < size {
+		return nil, errTruncatedStream
+	}
+	buf := d.data[:size]
+	d.data = d.data[size:]
+
+	out := make([]uint64, n)
+	var current uint64
+	bitsLeft := 0
+	for i := range out {
+		var v uint64
+		for need := width; need > 0; {
+			if bitsLeft == 0 {
+				current = uint64(buf[0])
+				buf = buf[1:]
+				bitsLeft = 8
+			}
+			take := need
+			if take > bitsLeft {
+				take = bitsLeft
+			}
+			v = v<<uint(take) | current>>uint(bitsLeft-take)&(1<<uint(take)-1)
+			bitsLeft -= take
+			need -= take
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+func (d *intDecoder) nextRunV1(out []int64) ([]int64, error) {
+	b, err := d.readByte()
+	if err != nil {
+		return nil, err
+	}
+
+	control := int8(b)
+	if control >= 0 {
+		// Run with a fixed delta.
+		b, err = d.readByte()
+		if err != nil {
+			return nil, err
+		}
+		delta := int64(int8(b))
+		base, err := d.readBase()
+		if err != nil {
+			return nil, err
+		}
+		for i := int64(0); i < int64(control)+3; i++ {
+			out = append(out, base+i*delta)
+		}
+		return out, nil
+	}
+
+	// Literal values.
+	for i := 0; i < -int(control); i++ {
+		v, err := d.readBase()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+// decodeBitWidth returns the bit width of the 5 bit encoded width.
+func decodeBitWidth(code int) int {
+	switch {
+	case code < 24:
+		return code + 1
+	case code == 24:
+		return 26
+	case code == 25:
+		return 28
+	case code == 26:
+		return 30
+	case code == 27:
+		return 32
+	case code == 28:
+		return 40
+	case code == 29:
+		return 48
+	case code == 30:
+		return 56
+	}
+	return 64
+}
+
+// closestFixedBits returns the smallest encodable bit width >= n.
+func closestFixedBits(n int) int {
+	switch {
+	case n == 0:
+		return 1
+	case n <= 24:
+		return n
+	case n <= 26:
+		return 26
+	case n <= 28:
+		return 28
+	case n <= 30:
+		return 30
+	case n <= 32:
+		return 32
+	case n <= 40:
+		return 40
+	case n <= 48:
+		return 48
+	case n <= 56:
+		return 56
+	}
+	return 64
+}
+
+func (d *intDecoder) nextRunV2(out []int64) ([]int64, error) {
+	header, err := d.readByte()
+	if err != nil {
+		return nil, err
+	}
+
+	if header>>6 == 0 {
+		// Short repeat
+		width := int(header>>3&7) + 1
+		count := int(header&7) + 3
+		v, err := d.readBigEndian(width)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < count; i++ {
+			out = append(out, d.toSigned(v))
+		}
+		return out, nil
+	}
+
+	b, err := d.readByte()
+	if err != nil {
+		return nil, err
+	}
+	length := int(header&1)<<8 | int(b) + 1
+	code := int(header >> 1 & 0x1f)
+
+	switch header >> 6 {
+	case 1:
+		// Direct
+		values, err := d.readBits(length, decodeBitWidth(code))
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range values {
+			out = append(out, d.toSigned(v))
+		}
+
+	case 2:
+		// Patched base
+		width := decodeBitWidth(code)
+		b2, err := d.readByte()
+		if err != nil {
+			return nil, err
+		}
+		b3, err := d.readByte()
+		if err != nil {
+			return nil, err
+		}
+		baseWidth := int(b2>>5) + 1
+		patchWidth := decodeBitWidth(int(b2 & 0x1f))
+		gapWidth := int(b3>>5) + 1
+		patchCount := int(b3 & 0x1f)
+
+		// The base value is stored in sign and magnitude form.
+		u, err := d.readBigEndian(baseWidth)
+		if err != nil {
+			return nil, err
+		}
+		signBit := uint64(1) << uint(baseWidth*8-1)
+		base := int64(u &^ signBit)
+		if u&signBit != 0 {
+			base = -base
+		}
+
+		values, err := d.readBits(length, width)
+		if err != nil {
+			return nil, err
+		}
+		patches, err := d.readBits(patchCount, closestFixedBits(gapWidth+patchWidth))
+		if err != nil {
+			return nil, err
+		}
+
+		// Gaps larger than 255 are stored as additional entries
+		// with a gap of 255 and no patch.
+		idx := 0
+		for _, p := range patches {
+			idx += int(p >> uint(patchWidth))
+			patch := p & (1<<uint(patchWidth) - 1)
+			if patch == 0 {
+				continue
+			}
+			if idx >= length {
+				return nil, errTruncatedStream
+			}
+			values[idx] |= patch << uint(width)
+		}
+		for _, v := range values {
+			out = append(out, base+int64(v))
+		}
+
+	case 3:
+		// Delta
+		width := 0
+		if code != 0 {
+			width = decodeBitWidth(code)
+		}
+		base, err := d.readBase()
+		if err != nil {
+			return nil, err
+		}
+		deltaBase, err := d.readVarint()
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, base)
+		if length == 1 {
+			break
+		}
+		value := base + deltaBase
+		out = append(out, value)
+
+		if width == 0 {
+			// Fixed delta
+			for i := 2; i < length; i++ {
+				value += deltaBase
+				out = append(out, value)
+			}
+			break
+		}
+
+		// The deltas have the sign of the delta base.
+		deltas, err := d.readBits(length-2, width)
+		if err != nil {
+			return nil, err
+		}
+		for _, delta := range deltas {
+			if deltaBase < 0 {
+				value -= int64(delta)
+			} else {
+				value += int64(delta)
+			}
+			out = append(out, value)
+		}
+	}
+	return out, nil
+}
+
+// decodeBigInts decodes n signed varints of unbounded size.
+func decodeBigInts(data []byte, n int) ([]*big.Int, error) {
+	out := make([]*big.Int, n)
+	for i := range out {
+		v := new(big.Int)
+		var shift uint
+		for {
+			if len(data) == 0 {
+				return nil, errTruncatedStream
+			}
+			b := data[0]
+			data = data[1:]
+			v.Or(v, new(big.Int).Lsh(big.NewInt(int64(b&0x7f)), shift))
+			shift += 7
+			if b&0x80 == 0 {
+				break
+			}
+		}
+
+		// Zigzag decoding
+		negative := v.Bit(0) == 1
+		v.Rsh(v, 1)
+		if negative {
+			v.Neg(v).Sub(v, big.NewInt(1))
+		}
+		out[i] = v
+	}
+	return out, nil
+}
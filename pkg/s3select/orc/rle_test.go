@@ -0,0 +1,107 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package orc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeInts(t *testing.T) {
+	testCases := []struct {
+		data   []byte
+		signed bool
+		v2     bool
+		values []int64
+	}{
+		// Examples of the ORC specification.
+		{[]byte{0x61, 0x00, 0x07}, false, false, repeat(7, 100)},
+		{[]byte{0x61, 0xff, 0x64}, false, false, sequence(100, -1, 100)},
+		{[]byte{0xfb, 0x02, 0x03, 0x06, 0x07, 0x0b}, false, false, []int64{2, 3, 6, 7, 11}},
+		{[]byte{0x0a, 0x27, 0x10}, false, true, repeat(10000, 5)},
+		{[]byte{0x5e, 0x03, 0x5c, 0xa1, 0xab, 0x1e, 0xde, 0xad, 0xbe, 0xef}, false, true, []int64{23713, 43806, 57005, 48879}},
+		{
+			[]byte{0x8e, 0x13, 0x2b, 0x21, 0x07, 0xd0, 0x1e, 0x00, 0x14, 0x70, 0x28, 0x32, 0x3c, 0x46, 0x50, 0x5a, 0x64, 0x6e, 0x78, 0x82, 0x8c, 0x96, 0xa0, 0xaa, 0xb4, 0xbe, 0xfc, 0xe8},
+			false, true,
+			[]int64{2030, 2000, 2020, 1000000, 2040, 2050, 2060, 2070, 2080, 2090, 2100, 2110, 2120, 2130, 2140, 2150, 2160, 2170, 2180, 2190},
+		},
+		{[]byte{0xc6, 0x09, 0x02, 0x02, 0x22, 0x42, 0x42, 0x46}, false, true, []int64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29}},
+		// Signed values
+		{[]byte{0xfd, 0x01, 0x02, 0x03}, true, false, []int64{-1, 1, -2}},
+		{[]byte{0x02, 0x01}, true, true, repeat(-1, 5)},
+		// Fixed delta
+		{[]byte{0xc0, 0x04, 0x02, 0x03}, false, true, []int64{2, 0, -2, -4, -6}},
+	}
+
+	for i, testCase := range testCases {
+		values, err := decodeInts(testCase.data, len(testCase.values), testCase.signed, testCase.v2)
+		if err != nil {
+			t.Errorf("Test %d: unexpected error %v", i+1, err)
+			continue
+		}
+		if !reflect.DeepEqual(values, testCase.values) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.values, values)
+		}
+	}
+
+	if _, err := decodeInts([]byte{0x61, 0x00}, 100, false, false); err == nil {
+		t.Errorf("expected error for truncated stream")
+	}
+}
+
+func TestDecodeBytes(t *testing.T) {
+	values, err := decodeBytes([]byte{0x61, 0x00, 0xfe, 0x44, 0x45}, 102)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := append(make([]byte, 100), 0x44, 0x45)
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected %v, got %v", expected, values)
+	}
+
+	bools, err := decodeBools([]byte{0xff, 0x80}, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(bools, []bool{true, false, false, false, false, false, false, false}) {
+		t.Errorf("unexpected booleans %v", bools)
+	}
+}
+
+func TestDecodeBigInts(t *testing.T) {
+	values, err := decodeBigInts([]byte{0x00, 0x01, 0x02, 0xff, 0x01, 0x80, 0x02}, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range []int64{0, -1, 1, -128, 128} {
+		if values[i].Int64() != expected {
+			t.Errorf("Test %d: expected %d, got %v", i+1, expected, values[i])
+		}
+	}
+}
+
+func repeat(v int64, n int) []int64 {
+	return sequence(v, 0, n)
+}
+
+func sequence(start, delta int64, n int) []int64 {
+	values := make([]int64, n)
+	for i := range values {
+		values[i] = start + int64(i)*delta
+	}
+	return values
+}
@@ -0,0 +1,136 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package parquet
+
+import (
+	"fmt"
+	"io"
+
+	parquetgo "github.com/minio/minio/pkg/s3select/internal/parquet-go"
+	"github.com/minio/minio/pkg/s3select/internal/parquet-go/data"
+	parquetgen "github.com/minio/minio/pkg/s3select/internal/parquet-go/gen-go/parquet"
+	"github.com/minio/minio/pkg/s3select/internal/parquet-go/schema"
+)
+
+// ColumnType - type of a column written by Writer.
+type ColumnType int
+
+// Supported column types.
+const (
+	StringColumn ColumnType = iota
+	Int64Column
+	BoolColumn
+)
+
+// Column - name and type of a column written by Writer.
+type Column struct {
+	Name string
+	Type ColumnType
+}
+
+// Writer - writes flat records of string, int64 and bool
+// values into a parquet file.
+type Writer struct {
+	columns []Column
+	writer  *parquetgo.Writer
+}
+
+// Write - writes a single record, values must be in column order
+// and of the matching column type.
+func (w *Writer) Write(values ...interface{}) error {
+	if len(values) != len(w.columns) {
+		return fmt.Errorf("parquet: expected %d values, got %d", len(w.columns), len(values))
+	}
+
+	record := make(map[string]*data.Column, len(w.columns))
+	for i, c := range w.columns {
+		var column *data.Column
+		switch v := values[i].(type) {
+		case string:
+			if c.Type != StringColumn {
+				return fmt.Errorf("parquet: unexpected string value for column %s", c.Name)
+			}
+			column = data.NewColumn(parquetgen.Type_BYTE_ARRAY)
+			column.AddByteArray([]byte(v), 0, 0)
+		case int64:
+			if c.Type != Int64Column {
+				return fmt.Errorf("parquet: unexpected int64 value for column %s", c.Name)
+			}
+			column = data.NewColumn(parquetgen.Type_INT64)
+			column.AddInt64(v, 0, 0)
+		case bool:
+			if c.Type != BoolColumn {
+				return fmt.Errorf("parquet: unexpected bool value for column %s", c.Name)
+			}
+			column = data.NewColumn(parquetgen.Type_BOOLEAN)
+			column.AddBoolean(v, 0, 0)
+		default:
+			return fmt.Errorf("parquet: unsupported value type %T for column %s", v, c.Name)
+		}
+		record[c.Name] = column
+	}
+	return w.writer.Write(record)
+}
+
+// Close - writes pending records and the file footer
+// and closes the underlying writer.
+func (w *Writer) Close() error {
+	return w.writer.Close()
+}
+
+// NewWriter - creates a new parquet writer for the given columns,
+// writing a row group for every rowGroupCount records.
+func NewWriter(writeCloser io.WriteCloser, columns []Column, rowGroupCount int) (*Writer, error) {
+	// The default delta and dictionary encodings of the underlying
+	// writer fail on some inputs, e.g. values of identical length.
+	plainEncoding := parquetgen.Encoding_PLAIN
+
+	schemaTree := schema.NewTree()
+	for _, c := range columns {
+		var element *schema.Element
+		var err error
+		switch c.Type {
+		case StringColumn:
+			element, err = schema.NewElement(c.Name, parquetgen.FieldRepetitionType_REQUIRED,
+				parquetgen.TypePtr(parquetgen.Type_BYTE_ARRAY), parquetgen.ConvertedTypePtr(parquetgen.ConvertedType_UTF8),
+				&plainEncoding, nil, nil)
+		case Int64Column:
+			element, err = schema.NewElement(c.Name, parquetgen.FieldRepetitionType_REQUIRED,
+				parquetgen.TypePtr(parquetgen.Type_INT64), nil, &plainEncoding, nil, nil)
+		case BoolColumn:
+			element, err = schema.NewElement(c.Name, parquetgen.FieldRepetitionType_REQUIRED,
+				parquetgen.TypePtr(parquetgen.Type_BOOLEAN), nil, &plainEncoding, nil, nil)
+		default:
+			err = fmt.Errorf("parquet: unsupported type for column %s", c.Name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err = schemaTree.Set(c.Name, element); err != nil {
+			return nil, err
+		}
+	}
+
+	writer, err := parquetgo.NewWriter(writeCloser, schemaTree, rowGroupCount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Writer{
+		columns: columns,
+		writer:  writer,
+	}, nil
+}
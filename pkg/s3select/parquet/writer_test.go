@@ -0,0 +1,92 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package parquet
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/minio/minio/pkg/s3select/sql"
+)
+
+type bufferCloser struct {
+	bytes.Buffer
+}
+
+func (b *bufferCloser) Close() error { return nil }
+
+func TestWriterRoundTrip(t *testing.T) {
+	var buf bufferCloser
+	columns := []Column{{"Key", StringColumn}, {"Size", Int64Column}, {"IsLatest", BoolColumn}}
+	w, err := NewWriter(&buf, columns, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if err = w.Write("object", int64(i), i%2 == 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Write("object", "1", true); err == nil {
+		t.Fatal("Expected error on mismatched column type")
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data := buf.Bytes()
+	r, err := NewReader(func(offset, length int64) (io.ReadCloser, error) {
+		if offset < 0 {
+			offset = int64(len(data)) + offset
+		}
+		end := int64(len(data))
+		if length > 0 {
+			end = offset + length
+		}
+		return ioutil.NopCloser(bytes.NewReader(data[offset:end])), nil
+	}, &ReaderArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var rec sql.Record
+	for i := 0; ; i++ {
+		rec, err = r.Read(rec)
+		if err == io.EOF {
+			if i != 5 {
+				t.Fatalf("Expected 5 records, got %d", i)
+			}
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out bytes.Buffer
+		if err = rec.WriteJSON(&out); err != nil {
+			t.Fatal(err)
+		}
+		isLatest := "false"
+		if i%2 == 0 {
+			isLatest = "true"
+		}
+		expected := `{"Key":"object","Size":` + string(rune('0'+i)) + `,"IsLatest":` + isLatest + "}"
+		if got := string(bytes.TrimSpace(out.Bytes())); got != expected {
+			t.Fatalf("Expected %s, got %s", expected, got)
+		}
+	}
+}
@@ -17,6 +17,8 @@
 package s3select
 
 import (
+	"bufio"
+	"bytes"
 	"compress/bzip2"
 	"errors"
 	"fmt"
@@ -24,7 +26,10 @@ import (
 	"sync"
 	"sync/atomic"
 
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
 	gzip "github.com/klauspost/pgzip"
+	"github.com/pierrec/lz4"
 )
 
 type countUpReader struct {
@@ -53,6 +58,7 @@ func newCountUpReader(reader io.Reader) *countUpReader {
 
 type progressReader struct {
 	rc              io.ReadCloser
+	decompressor    io.Closer
 	scannedReader   *countUpReader
 	processedReader *countUpReader
 
@@ -81,6 +87,9 @@ func (pr *progressReader) Close() error {
 		return nil
 	}
 	pr.closed = true
+	if pr.decompressor != nil {
+		pr.decompressor.Close()
+	}
 	return pr.rc.Close()
 }
 
@@ -91,31 +100,119 @@ func (pr *progressReader) Stats() (bytesScanned, bytesProcessed int64) {
 	return pr.scannedReader.BytesRead(), pr.processedReader.BytesRead()
 }
 
+// Magic bytes at the start of a compressed stream. The snappy and S2
+// framing formats start with a stream identifier chunk.
+var (
+	gzipMagic   = []byte{0x1f, 0x8b}
+	bzip2Magic  = []byte("BZh")
+	zstdMagic   = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	lz4Magic    = []byte{0x04, 0x22, 0x4d, 0x18}
+	snappyMagic = []byte("\xff\x06\x00\x00sNaPpY")
+	s2Magic     = []byte("\xff\x06\x00\x00S2sTwO")
+
+	// A bzip2 stream continues with the block size digit and the magic
+	// of its first block, or of the end of stream if it is empty.
+	bzip2BlockMagic = []byte{0x31, 0x41, 0x59, 0x26, 0x53, 0x59}
+	bzip2EOSMagic   = []byte{0x17, 0x72, 0x45, 0x38, 0x50, 0x90}
+)
+
+// isBzip2Header - returns whether b starts with a bzip2 stream header.
+func isBzip2Header(b []byte) bool {
+	n := len(bzip2Magic)
+	if len(b) < n+1+len(bzip2BlockMagic) || !bytes.HasPrefix(b, bzip2Magic) {
+		return false
+	}
+	if b[n] < '1' || b[n] > '9' {
+		return false
+	}
+	b = b[n+1:]
+	return bytes.HasPrefix(b, bzip2BlockMagic) || bytes.HasPrefix(b, bzip2EOSMagic)
+}
+
+// detectCompressionType - returns the compression type of the stream
+// by looking at its first bytes, noneType if none is recognized.
+func detectCompressionType(br *bufio.Reader) CompressionType {
+	// Peek returns as much as is available for short streams.
+	magic, _ := br.Peek(len(snappyMagic))
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		return gzipType
+	case isBzip2Header(magic):
+		return bzip2Type
+	case bytes.HasPrefix(magic, zstdMagic):
+		return zstdType
+	case bytes.HasPrefix(magic, lz4Magic):
+		return lz4Type
+	case bytes.HasPrefix(magic, snappyMagic):
+		return snappyType
+	case bytes.HasPrefix(magic, s2Magic):
+		return s2Type
+	}
+	return noneType
+}
+
 func newProgressReader(rc io.ReadCloser, compType CompressionType) (*progressReader, error) {
 	scannedReader := newCountUpReader(rc)
+	var src io.Reader = scannedReader
 	var r io.Reader
-	var err error
+	var decompressor io.Closer
+
+	if compType == autoType {
+		br := bufio.NewReader(scannedReader)
+		compType = detectCompressionType(br)
+		src = br
+	}
 
 	switch compType {
 	case noneType:
-		r = scannedReader
+		r = src
 	case gzipType:
-		r, err = gzip.NewReader(scannedReader)
+		gr, err := gzip.NewReader(src)
 		if err != nil {
 			if errors.Is(err, gzip.ErrHeader) || errors.Is(err, gzip.ErrChecksum) {
 				return nil, errInvalidGZIPCompressionFormat(err)
 			}
 			return nil, errTruncatedInput(err)
 		}
+		r, decompressor = gr, gr
 	case bzip2Type:
-		r = bzip2.NewReader(scannedReader)
+		r = bzip2.NewReader(src)
+	case zstdType:
+		zr, err := zstd.NewReader(src, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, errInvalidZSTDCompressionFormat(err)
+		}
+		r, decompressor = zr, zr.IOReadCloser()
+	case lz4Type:
+		r = lz4.NewReader(src)
+	case snappyType, s2Type:
+		// The S2 reader also decodes snappy framed streams.
+		r = s2.NewReader(src)
 	default:
 		return nil, errInvalidCompressionFormat(fmt.Errorf("unknown compression type '%v'", compType))
 	}
 
 	return &progressReader{
 		rc:              rc,
+		decompressor:    decompressor,
 		scannedReader:   scannedReader,
 		processedReader: newCountUpReader(r),
 	}, nil
 }
+
+// decompressionError - maps errors returned by the decompressors while
+// reading the input to the error for the compression format.
+func decompressionError(err error) error {
+	var stErr bzip2.StructuralError
+	switch {
+	case errors.As(err, &stErr):
+		return errInvalidBZIP2CompressionFormat(err)
+	case errors.Is(err, zstd.ErrMagicMismatch), errors.Is(err, zstd.ErrCRCMismatch):
+		return errInvalidZSTDCompressionFormat(err)
+	case errors.Is(err, lz4.ErrInvalid):
+		return errInvalidLZ4CompressionFormat(err)
+	case errors.Is(err, s2.ErrCorrupt), errors.Is(err, s2.ErrCRC), errors.Is(err, s2.ErrUnsupported):
+		return errInvalidSnappyCompressionFormat(err)
+	}
+	return err
+}
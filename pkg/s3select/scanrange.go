@@ -0,0 +1,184 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package s3select
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// ScanRange - represents elements inside <ScanRange/> in request XML.
+type ScanRange struct {
+	Start *uint64 `xml:"Start"`
+	End   *uint64 `xml:"End"`
+}
+
+// IsEmpty - returns whether scan range is empty or not.
+func (s ScanRange) IsEmpty() bool {
+	return s.Start == nil && s.End == nil
+}
+
+// Validate - validates the scan range.
+func (s ScanRange) Validate() error {
+	if s.Start != nil && s.End != nil && *s.Start > *s.End {
+		return errInvalidRequestParameter(fmt.Errorf("ScanRange Start %d must not be greater than End %d", *s.Start, *s.End))
+	}
+	return nil
+}
+
+// offsets returns the absolute offset of the first byte and of the last
+// byte (-1 for the end of the object) a record must start at to be
+// processed. Only specifying End processes the last End bytes, which
+// needs the object size.
+func (s ScanRange) offsets(objectSize int64) (start, end int64, err error) {
+	switch {
+	case s.Start != nil && s.End != nil:
+		return int64(*s.Start), int64(*s.End), nil
+	case s.Start != nil:
+		return int64(*s.Start), -1, nil
+	}
+
+	if objectSize < 0 {
+		return 0, 0, errInvalidRequestParameter(fmt.Errorf("ScanRange with only End requires the object size"))
+	}
+	start = objectSize - int64(*s.End)
+	if start < 0 {
+		start = 0
+	}
+	return start, -1, nil
+}
+
+// scanRangeReader - returns the records which start within a scan range.
+// A record partially included at the beginning of the range is skipped,
+// and a record starting within the range is returned completely even if
+// it extends beyond its end.
+type scanRangeReader struct {
+	rc     io.ReadCloser
+	reader *bufio.Reader
+	delim  []byte
+
+	// Absolute offset of the next byte read from reader.
+	offset     int64
+	start, end int64
+
+	record  []byte
+	pending []byte
+	err     error
+}
+
+// readRecord reads a record including its delimiter.
+func (r *scanRangeReader) readRecord() ([]byte, error) {
+	r.record = r.record[:0]
+	last := r.delim[len(r.delim)-1]
+	for {
+		b, err := r.reader.ReadSlice(last)
+		r.record = append(r.record, b...)
+		r.offset += int64(len(b))
+		switch err {
+		case nil:
+			if bytes.HasSuffix(r.record, r.delim) {
+				return r.record, nil
+			}
+		case bufio.ErrBufferFull:
+		default:
+			return r.record, err
+		}
+	}
+}
+
+func (r *scanRangeReader) Read(p []byte) (n int, err error) {
+	for len(r.pending) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if r.end >= 0 && r.offset > r.end {
+			r.err = io.EOF
+			return 0, r.err
+		}
+
+		recordStart := r.offset
+		r.pending, r.err = r.readRecord()
+		if recordStart < r.start {
+			// Remaining part of a record before the range.
+			r.pending = nil
+		}
+	}
+
+	n = copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+func (r *scanRangeReader) Close() error {
+	return r.rc.Close()
+}
+
+// newScanRangeReader opens the object at the scan range using getReader.
+// Reading starts before the range by the length of the record delimiter,
+// to find out whether the range starts at the beginning of a record.
+// If header is true, the first record of the object is returned before
+// the records of the range.
+func newScanRangeReader(getReader func(offset, length int64) (io.ReadCloser, error), scanRange ScanRange, objectSize int64, recordDelimiter string, header bool) (io.ReadCloser, error) {
+	start, end, err := scanRange.offsets(objectSize)
+	if err != nil {
+		return nil, err
+	}
+	delim := []byte(recordDelimiter)
+	var headerRecord []byte
+	if header && start > 0 {
+		rc, err := getReader(0, -1)
+		if err != nil {
+			return nil, err
+		}
+		hr := &scanRangeReader{rc: rc, reader: bufio.NewReader(rc), delim: delim}
+		headerRecord, err = hr.readRecord()
+		hr.Close()
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		headerRecord = append([]byte(nil), headerRecord...)
+		if !bytes.HasSuffix(headerRecord, delim) {
+			headerRecord = append(headerRecord, delim...)
+		}
+	}
+
+	if objectSize >= 0 && start >= objectSize {
+		// No record starts within the range.
+		return ioutil.NopCloser(bytes.NewReader(headerRecord)), nil
+	}
+
+	offset := start - int64(len(delim))
+	if offset < 0 {
+		offset = 0
+	}
+	rc, err := getReader(offset, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	return &scanRangeReader{
+		rc:      rc,
+		reader:  bufio.NewReaderSize(rc, maxRecordSize),
+		delim:   delim,
+		offset:  offset,
+		start:   start,
+		end:     end,
+		pending: headerRecord,
+	}, nil
+}
@@ -19,7 +19,6 @@ package s3select
 import (
 	"bufio"
 	"bytes"
-	"compress/bzip2"
 	"encoding/xml"
 	"errors"
 	"fmt"
@@ -30,8 +29,10 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/minio/minio/pkg/s3select/avro"
 	"github.com/minio/minio/pkg/s3select/csv"
 	"github.com/minio/minio/pkg/s3select/json"
+	"github.com/minio/minio/pkg/s3select/orc"
 	"github.com/minio/minio/pkg/s3select/parquet"
 	"github.com/minio/minio/pkg/s3select/simdj"
 	"github.com/minio/minio/pkg/s3select/sql"
@@ -49,15 +50,25 @@ const (
 	csvFormat     = "csv"
 	jsonFormat    = "json"
 	parquetFormat = "parquet"
+	orcFormat     = "orc"
+	avroFormat    = "avro"
 )
 
 // CompressionType - represents value inside <CompressionType/> in request XML.
 type CompressionType string
 
 const (
-	noneType  CompressionType = "none"
-	gzipType  CompressionType = "gzip"
-	bzip2Type CompressionType = "bzip2"
+	noneType   CompressionType = "none"
+	gzipType   CompressionType = "gzip"
+	bzip2Type  CompressionType = "bzip2"
+	zstdType   CompressionType = "zstd"
+	lz4Type    CompressionType = "lz4"
+	snappyType CompressionType = "snappy"
+	s2Type     CompressionType = "s2"
+
+	// autoType is used when <CompressionType/> is omitted, the
+	// compression format is then detected from the object data.
+	autoType CompressionType = ""
 )
 
 const (
@@ -92,7 +103,7 @@ func (c *CompressionType) UnmarshalXML(d *xml.Decoder, start xml.StartElement) e
 	}
 
 	switch parsedType {
-	case noneType, gzipType, bzip2Type:
+	case noneType, gzipType, bzip2Type, zstdType, lz4Type, snappyType, s2Type:
 	default:
 		return errInvalidCompressionFormat(fmt.Errorf("invalid compression format '%v'", s))
 	}
@@ -107,6 +118,8 @@ type InputSerialization struct {
 	CSVArgs         csv.ReaderArgs     `xml:"CSV"`
 	JSONArgs        json.ReaderArgs    `xml:"JSON"`
 	ParquetArgs     parquet.ReaderArgs `xml:"Parquet"`
+	ORCArgs         orc.ReaderArgs     `xml:"ORC"`
+	AvroArgs        avro.ReaderArgs    `xml:"Avro"`
 	unmarshaled     bool
 	format          string
 }
@@ -125,11 +138,6 @@ func (input *InputSerialization) UnmarshalXML(d *xml.Decoder, start xml.StartEle
 		return errMalformedXML(err)
 	}
 
-	// If no compression is specified, set to noneType
-	if parsedInput.CompressionType == CompressionType("") {
-		parsedInput.CompressionType = noneType
-	}
-
 	found := 0
 	if !parsedInput.CSVArgs.IsEmpty() {
 		parsedInput.format = csvFormat
@@ -140,13 +148,29 @@ func (input *InputSerialization) UnmarshalXML(d *xml.Decoder, start xml.StartEle
 		found++
 	}
 	if !parsedInput.ParquetArgs.IsEmpty() {
-		if parsedInput.CompressionType != "" && parsedInput.CompressionType != noneType {
+		if parsedInput.CompressionType != autoType && parsedInput.CompressionType != noneType {
 			return errInvalidRequestParameter(fmt.Errorf("CompressionType must be NONE for Parquet format"))
 		}
 
 		parsedInput.format = parquetFormat
 		found++
 	}
+	if !parsedInput.ORCArgs.IsEmpty() {
+		if parsedInput.CompressionType != autoType && parsedInput.CompressionType != noneType {
+			return errInvalidRequestParameter(fmt.Errorf("CompressionType must be NONE for ORC format"))
+		}
+
+		parsedInput.format = orcFormat
+		found++
+	}
+	if !parsedInput.AvroArgs.IsEmpty() {
+		if parsedInput.CompressionType != autoType && parsedInput.CompressionType != noneType {
+			return errInvalidRequestParameter(fmt.Errorf("CompressionType must be NONE for Avro format"))
+		}
+
+		parsedInput.format = avroFormat
+		found++
+	}
 
 	if found != 1 {
 		return errInvalidDataSource(nil)
@@ -157,6 +181,33 @@ func (input *InputSerialization) UnmarshalXML(d *xml.Decoder, start xml.StartEle
 	return nil
 }
 
+// validateScanRange - validates whether a scan range can be applied to the input.
+// Records must be separated by a record delimiter which is not quoted.
+func (input *InputSerialization) validateScanRange() error {
+	// Ranges are applied to the stored bytes, so the object cannot
+	// be compressed and there is nothing to detect.
+	if input.CompressionType == autoType {
+		input.CompressionType = noneType
+	}
+	if input.CompressionType != noneType {
+		return errInvalidRequestParameter(fmt.Errorf("ScanRange is not supported for compressed input"))
+	}
+
+	switch input.format {
+	case csvFormat:
+		if input.CSVArgs.AllowQuotedRecordDelimiter {
+			return errInvalidRequestParameter(fmt.Errorf("ScanRange is not supported with AllowQuotedRecordDelimiter"))
+		}
+	case jsonFormat:
+		if !strings.EqualFold(input.JSONArgs.ContentType, "lines") {
+			return errInvalidRequestParameter(fmt.Errorf("ScanRange is only supported for JSON LINES"))
+		}
+	default:
+		return errInvalidRequestParameter(fmt.Errorf("ScanRange is only supported for CSV and JSON"))
+	}
+	return nil
+}
+
 // OutputSerialization - represents elements inside <OutputSerialization/> in request XML.
 type OutputSerialization struct {
 	CSVArgs     csv.WriterArgs  `xml:"CSV"`
@@ -212,7 +263,9 @@ type S3Select struct {
 	Input          InputSerialization  `xml:"InputSerialization"`
 	Output         OutputSerialization `xml:"OutputSerialization"`
 	Progress       RequestProgress     `xml:"RequestProgress"`
+	ScanRange      ScanRange           `xml:"ScanRange"`
 
+	objectSize     int64
 	statement      *sql.SelectStatement
 	progressReader *progressReader
 	recordReader   recordReader
@@ -256,12 +309,22 @@ func (s3Select *S3Select) UnmarshalXML(d *xml.Decoder, start xml.StartElement) e
 		return errMissingRequiredParameter(fmt.Errorf("OutputSerialization must be provided"))
 	}
 
+	if !parsedS3Select.ScanRange.IsEmpty() {
+		if err := parsedS3Select.ScanRange.Validate(); err != nil {
+			return err
+		}
+		if err := parsedS3Select.Input.validateScanRange(); err != nil {
+			return err
+		}
+	}
+
 	statement, err := sql.ParseSelectStatement(parsedS3Select.Expression)
 	if err != nil {
 		return err
 	}
 
 	parsedS3Select.statement = &statement
+	parsedS3Select.objectSize = -1
 
 	*s3Select = S3Select(parsedS3Select)
 	return nil
@@ -286,12 +349,37 @@ func (s3Select *S3Select) getProgress() (bytesScanned, bytesProcessed int64) {
 	return -1, -1
 }
 
+// SetObjectSize - sets the size of the queried object. A ScanRange which
+// only specifies End requires it.
+func (s3Select *S3Select) SetObjectSize(size int64) {
+	s3Select.objectSize = size
+}
+
+// openInput opens the object, at the scan range if specified.
+func (s3Select *S3Select) openInput(getReader func(offset, length int64) (io.ReadCloser, error)) (io.ReadCloser, error) {
+	if s3Select.ScanRange.IsEmpty() {
+		return getReader(0, -1)
+	}
+
+	var recordDelimiter string
+	var header bool
+	switch s3Select.Input.format {
+	case csvFormat:
+		recordDelimiter = s3Select.Input.CSVArgs.RecordDelimiter
+		header = s3Select.Input.CSVArgs.FileHeaderInfo != "none"
+	default:
+		// JSON LINES are separated by newlines.
+		recordDelimiter = "\n"
+	}
+	return newScanRangeReader(getReader, s3Select.ScanRange, s3Select.objectSize, recordDelimiter, header)
+}
+
 // Open - opens S3 object by using callback for SQL selection query.
-// Currently CSV, JSON and Apache Parquet formats are supported.
+// Currently CSV, JSON, Apache Parquet, Apache ORC and Apache Avro formats are supported.
 func (s3Select *S3Select) Open(getReader func(offset, length int64) (io.ReadCloser, error)) error {
 	switch s3Select.Input.format {
 	case csvFormat:
-		rc, err := getReader(0, -1)
+		rc, err := s3Select.openInput(getReader)
 		if err != nil {
 			return err
 		}
@@ -305,15 +393,11 @@ func (s3Select *S3Select) Open(getReader func(offset, length int64) (io.ReadClos
 		s3Select.recordReader, err = csv.NewReader(s3Select.progressReader, &s3Select.Input.CSVArgs)
 		if err != nil {
 			rc.Close()
-			var stErr bzip2.StructuralError
-			if errors.As(err, &stErr) {
-				return errInvalidBZIP2CompressionFormat(err)
-			}
-			return err
+			return decompressionError(err)
 		}
 		return nil
 	case jsonFormat:
-		rc, err := getReader(0, -1)
+		rc, err := s3Select.openInput(getReader)
 		if err != nil {
 			return err
 		}
@@ -341,6 +425,30 @@ func (s3Select *S3Select) Open(getReader func(offset, length int64) (io.ReadClos
 		var err error
 		s3Select.recordReader, err = parquet.NewReader(getReader, &s3Select.Input.ParquetArgs)
 		return err
+	case orcFormat:
+		// Only the streams of the columns used by the query are read.
+		var err error
+		s3Select.recordReader, err = orc.NewReader(getReader, &s3Select.Input.ORCArgs, s3Select.statement.Columns())
+		return err
+	case avroFormat:
+		rc, err := getReader(0, -1)
+		if err != nil {
+			return err
+		}
+
+		// Avro object container files are compressed per block.
+		s3Select.progressReader, err = newProgressReader(rc, noneType)
+		if err != nil {
+			rc.Close()
+			return err
+		}
+
+		s3Select.recordReader, err = avro.NewReader(s3Select.progressReader, &s3Select.Input.AvroArgs)
+		if err != nil {
+			rc.Close()
+			return err
+		}
+		return nil
 	}
 
 	panic(fmt.Errorf("unknown input format '%v'", s3Select.Input.format))
@@ -405,7 +513,7 @@ func (s3Select *S3Select) Evaluate(w http.ResponseWriter) {
 	var outputQueue []sql.Record
 
 	// Create queue based on the type.
-	if s3Select.statement.IsAggregated() {
+	if s3Select.statement.IsAggregated() && !s3Select.statement.IsBuffered() {
 		outputQueue = make([]sql.Record, 0, 1)
 	} else {
 		outputQueue = make([]sql.Record, 0, 100)
@@ -441,6 +549,31 @@ func (s3Select *S3Select) Evaluate(w http.ResponseWriter) {
 		return true
 	}
 
+	// Sends all results of a GROUP BY or ORDER BY query, except the
+	// ones remaining in the queue.
+	sendResults := func() bool {
+		var results *sql.Results
+		if results, err = s3Select.statement.Results(); err != nil {
+			return false
+		}
+		defer results.Close()
+
+		for {
+			var outputRecord sql.Record
+			if outputRecord, err = results.Next(s3Select.outputRecord()); err != nil {
+				if err == io.EOF {
+					err = nil
+					return true
+				}
+				return false
+			}
+			outputQueue = append(outputQueue, outputRecord)
+			if len(outputQueue) == cap(outputQueue) && !sendRecord() {
+				return false
+			}
+		}
+	}
+
 	var rec sql.Record
 OuterLoop:
 	for {
@@ -460,7 +593,11 @@ OuterLoop:
 				break
 			}
 
-			if s3Select.statement.IsAggregated() {
+			if s3Select.statement.IsBuffered() {
+				if !sendResults() {
+					break
+				}
+			} else if s3Select.statement.IsAggregated() {
 				outputRecord := s3Select.outputRecord()
 				if err = s3Select.statement.AggregateResult(outputRecord); err != nil {
 					break
@@ -485,7 +622,11 @@ OuterLoop:
 		}
 
 		for _, inputRecord := range inputRecords {
-			if s3Select.statement.IsAggregated() {
+			if s3Select.statement.IsBuffered() {
+				if err = s3Select.statement.BufferRow(*inputRecord); err != nil {
+					break OuterLoop
+				}
+			} else if s3Select.statement.IsAggregated() {
 				if err = s3Select.statement.AggregateRow(*inputRecord); err != nil {
 					break OuterLoop
 				}
@@ -528,12 +669,24 @@ OuterLoop:
 	}
 
 	if err != nil {
+		// Remove the temporary files of the failed query right away.
+		s3Select.statement.Close()
+
+		err = decompressionError(err)
+		if serr, ok := err.(SelectError); ok {
+			_ = writer.FinishWithError(serr.ErrorCode(), serr.ErrorMessage())
+			return
+		}
 		_ = writer.FinishWithError("InternalError", err.Error())
 	}
 }
 
-// Close - closes opened S3 object.
+// Close - closes opened S3 object and removes the temporary files of the
+// query.
 func (s3Select *S3Select) Close() error {
+	if s3Select.statement != nil {
+		s3Select.statement.Close()
+	}
 	return s3Select.recordReader.Close()
 }
 
@@ -17,6 +17,7 @@
 package s3select
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/xml"
 	"fmt"
@@ -29,9 +30,15 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
 	"github.com/klauspost/cpuid"
+	gzip "github.com/klauspost/pgzip"
+	"github.com/linkedin/goavro/v2"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/simdjson-go"
+	"github.com/pierrec/lz4"
 )
 
 type testResponseWriter struct {
@@ -1059,3 +1066,509 @@ func TestParquetInput(t *testing.T) {
 		})
 	}
 }
+
+func TestScanRange(t *testing.T) {
+	csvData := "id,name\n1,one\n2,two\n3,three\n4,four\n"
+	jsonData := `{"id":1}` + "\n" + `{"id":2}` + "\n" + `{"id":3}` + "\n"
+
+	csvRequest := `<?xml version="1.0" encoding="UTF-8"?>
+<SelectObjectContentRequest>
+    <Expression>SELECT id FROM S3Object</Expression>
+    <ExpressionType>SQL</ExpressionType>
+    <InputSerialization>
+        <CompressionType>NONE</CompressionType>
+        <CSV>
+            <FileHeaderInfo>USE</FileHeaderInfo>
+        </CSV>
+    </InputSerialization>
+    <OutputSerialization>
+        <CSV>
+        </CSV>
+    </OutputSerialization>
+    <ScanRange>%s</ScanRange>
+</SelectObjectContentRequest>`
+
+	jsonRequest := `<?xml version="1.0" encoding="UTF-8"?>
+<SelectObjectContentRequest>
+    <Expression>SELECT s.id FROM S3Object s</Expression>
+    <ExpressionType>SQL</ExpressionType>
+    <InputSerialization>
+        <CompressionType>NONE</CompressionType>
+        <JSON>
+            <Type>LINES</Type>
+        </JSON>
+    </InputSerialization>
+    <OutputSerialization>
+        <CSV>
+        </CSV>
+    </OutputSerialization>
+    <ScanRange>%s</ScanRange>
+</SelectObjectContentRequest>`
+
+	testCases := []struct {
+		name       string
+		request    string
+		input      string
+		scanRange  string
+		wantResult string
+	}{
+		{"csv-all", csvRequest, csvData, "<Start>0</Start>", "1\n2\n3\n4"},
+		// Starts in the middle of the header, record 1 starts at offset 8.
+		{"csv-start-in-header", csvRequest, csvData, "<Start>3</Start><End>8</End>", "1"},
+		// Starts exactly at record 2 and ends within record 3.
+		{"csv-record-boundary", csvRequest, csvData, "<Start>14</Start><End>22</End>", "2\n3"},
+		// Starts within record 2.
+		{"csv-skip-partial", csvRequest, csvData, "<Start>15</Start>", "3\n4"},
+		// The last 7 bytes start at record 4.
+		{"csv-suffix", csvRequest, csvData, "<End>7</End>", "4"},
+		// The last 6 bytes start within record 4.
+		{"csv-suffix-partial", csvRequest, csvData, "<End>6</End>", ""},
+		{"csv-beyond-end", csvRequest, csvData, "<Start>100</Start>", ""},
+		{"json-start", jsonRequest, jsonData, "<Start>1</Start>", "2\n3"},
+		{"json-range", jsonRequest, jsonData, "<Start>0</Start><End>9</End>", "1\n2"},
+		{"json-suffix", jsonRequest, jsonData, "<End>9</End>", "3"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testReq := fmt.Sprintf(testCase.request, testCase.scanRange)
+			s3Select, err := NewS3Select(strings.NewReader(testReq))
+			if err != nil {
+				t.Fatal(err)
+			}
+			s3Select.SetObjectSize(int64(len(testCase.input)))
+
+			if err = s3Select.Open(func(offset, length int64) (io.ReadCloser, error) {
+				if offset < 0 {
+					offset += int64(len(testCase.input))
+				}
+				return ioutil.NopCloser(strings.NewReader(testCase.input[offset:])), nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			w := &testResponseWriter{}
+			s3Select.Evaluate(w)
+			s3Select.Close()
+			resp := http.Response{
+				StatusCode:    http.StatusOK,
+				Body:          ioutil.NopCloser(bytes.NewReader(w.response)),
+				ContentLength: int64(len(w.response)),
+			}
+			res, err := minio.NewSelectResults(&resp, "testbucket")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := ioutil.ReadAll(res)
+			if err != nil {
+				t.Fatal(err)
+			}
+			gotS := strings.TrimSpace(string(got))
+			if gotS != testCase.wantResult {
+				t.Errorf("got: %q\nwant: %q", gotS, testCase.wantResult)
+			}
+		})
+	}
+}
+
+func TestScanRangeValidation(t *testing.T) {
+	request := `<?xml version="1.0" encoding="UTF-8"?>
+<SelectObjectContentRequest>
+    <Expression>SELECT * FROM S3Object</Expression>
+    <ExpressionType>SQL</ExpressionType>
+    <InputSerialization>
+        <CompressionType>%s</CompressionType>
+        <CSV>
+        </CSV>
+    </InputSerialization>
+    <OutputSerialization>
+        <CSV>
+        </CSV>
+    </OutputSerialization>
+    <ScanRange>%s</ScanRange>
+</SelectObjectContentRequest>`
+
+	testCases := []struct {
+		compression string
+		scanRange   string
+		expectErr   bool
+	}{
+		{"NONE", "<Start>1</Start><End>10</End>", false},
+		{"NONE", "<Start>10</Start><End>1</End>", true},
+		{"GZIP", "<Start>1</Start>", true},
+	}
+
+	for i, testCase := range testCases {
+		_, err := NewS3Select(strings.NewReader(fmt.Sprintf(request, testCase.compression, testCase.scanRange)))
+		if (err != nil) != testCase.expectErr {
+			t.Errorf("Test %d: expected error %v, got %v", i+1, testCase.expectErr, err)
+		}
+	}
+}
+
+func TestGroupByOrderBy(t *testing.T) {
+	input := `city,name,amount
+Paris,alice,10
+London,bob,5
+Paris,carol,7
+Berlin,dave,3
+London,erin,20
+Paris,frank,1
+`
+	testCases := []struct {
+		query      string
+		wantResult string
+	}{
+		{
+			query:      "SELECT city, COUNT(*) AS n, SUM(amount) AS total FROM S3Object GROUP BY city ORDER BY total DESC",
+			wantResult: "London,2,25\nParis,3,18\nBerlin,1,3",
+		},
+		{
+			query:      "SELECT s.city, MAX(s.amount) FROM S3Object s GROUP BY s.city ORDER BY s.city",
+			wantResult: "Berlin,3\nLondon,20\nParis,10",
+		},
+		{
+			query:      "SELECT city, COUNT(*) FROM S3Object WHERE CAST(amount AS INT) > 2 GROUP BY city ORDER BY COUNT(*) DESC, city LIMIT 2",
+			wantResult: "London,2\nParis,2",
+		},
+		{
+			query:      "SELECT name, amount FROM S3Object ORDER BY amount DESC LIMIT 3",
+			wantResult: "erin,20\nalice,10\ncarol,7",
+		},
+		{
+			query:      "SELECT name FROM S3Object WHERE city = 'Paris' ORDER BY name DESC",
+			wantResult: "frank\ncarol\nalice",
+		},
+		{
+			query:      "SELECT city FROM S3Object GROUP BY city ORDER BY city DESC",
+			wantResult: "Paris\nLondon\nBerlin",
+		},
+		{
+			query:      "SELECT city, AVG(amount) FROM S3Object GROUP BY city, name ORDER BY AVG(amount) LIMIT 1",
+			wantResult: "Paris,1",
+		},
+		{
+			query:      "SELECT COUNT(*) FROM S3Object ORDER BY city",
+			wantResult: "6",
+		},
+	}
+
+	defRequest := `<?xml version="1.0" encoding="UTF-8"?>
+<SelectObjectContentRequest>
+    <Expression>%s</Expression>
+    <ExpressionType>SQL</ExpressionType>
+    <InputSerialization>
+        <CompressionType>NONE</CompressionType>
+        <CSV>
+            <FileHeaderInfo>USE</FileHeaderInfo>
+        </CSV>
+    </InputSerialization>
+    <OutputSerialization>
+        <CSV>
+        </CSV>
+    </OutputSerialization>
+    <RequestProgress>
+        <Enabled>FALSE</Enabled>
+    </RequestProgress>
+</SelectObjectContentRequest>`
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			var escaped bytes.Buffer
+			xml.EscapeText(&escaped, []byte(testCase.query))
+			s3Select, err := NewS3Select(strings.NewReader(fmt.Sprintf(defRequest, escaped.String())))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err = s3Select.Open(func(offset, length int64) (io.ReadCloser, error) {
+				return ioutil.NopCloser(strings.NewReader(input)), nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			w := &testResponseWriter{}
+			s3Select.Evaluate(w)
+			s3Select.Close()
+			resp := http.Response{
+				StatusCode:    http.StatusOK,
+				Body:          ioutil.NopCloser(bytes.NewReader(w.response)),
+				ContentLength: int64(len(w.response)),
+			}
+			res, err := minio.NewSelectResults(&resp, "testbucket")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := ioutil.ReadAll(res)
+			if err != nil {
+				t.Fatal(err)
+			}
+			gotS := strings.TrimSpace(string(got))
+			if gotS != testCase.wantResult {
+				t.Errorf("Query: %s\ngot: %q\nwant: %q", testCase.query, gotS, testCase.wantResult)
+			}
+		})
+	}
+}
+
+func TestCompressionFormats(t *testing.T) {
+	input := "name,amount\nalice,10\nbob,5\ncarol,7\n"
+
+	compress := func(compType CompressionType) []byte {
+		var buf bytes.Buffer
+		var w io.WriteCloser
+		switch compType {
+		case gzipType:
+			w = gzip.NewWriter(&buf)
+		case zstdType:
+			w, _ = zstd.NewWriter(&buf)
+		case lz4Type:
+			w = lz4.NewWriter(&buf)
+		case snappyType:
+			w = snappy.NewBufferedWriter(&buf)
+		case s2Type:
+			w = s2.NewWriter(&buf)
+		default:
+			return []byte(input)
+		}
+		if _, err := io.WriteString(w, input); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+
+	request := `<?xml version="1.0" encoding="UTF-8"?>
+<SelectObjectContentRequest>
+    <Expression>SELECT name FROM S3Object WHERE CAST(amount AS INT) > 6</Expression>
+    <ExpressionType>SQL</ExpressionType>
+    <InputSerialization>
+        %s
+        <CSV>
+            <FileHeaderInfo>USE</FileHeaderInfo>
+        </CSV>
+    </InputSerialization>
+    <OutputSerialization>
+        <CSV>
+        </CSV>
+    </OutputSerialization>
+</SelectObjectContentRequest>`
+
+	testCases := []struct {
+		compType    CompressionType
+		requestType string
+		wantErr     bool
+	}{
+		{noneType, "<CompressionType>NONE</CompressionType>", false},
+		{gzipType, "<CompressionType>GZIP</CompressionType>", false},
+		{zstdType, "<CompressionType>ZSTD</CompressionType>", false},
+		{lz4Type, "<CompressionType>LZ4</CompressionType>", false},
+		{snappyType, "<CompressionType>SNAPPY</CompressionType>", false},
+		{s2Type, "<CompressionType>S2</CompressionType>", false},
+		// The S2 reader accepts snappy framed streams.
+		{snappyType, "<CompressionType>S2</CompressionType>", false},
+		// Detect the compression format.
+		{noneType, "", false},
+		{gzipType, "", false},
+		{zstdType, "", false},
+		{lz4Type, "", false},
+		{snappyType, "", false},
+		{s2Type, "", false},
+		// Compression format does not match.
+		{noneType, "<CompressionType>ZSTD</CompressionType>", true},
+		{noneType, "<CompressionType>LZ4</CompressionType>", true},
+		{noneType, "<CompressionType>SNAPPY</CompressionType>", true},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			s3Select, err := NewS3Select(strings.NewReader(fmt.Sprintf(request, testCase.requestType)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data := compress(testCase.compType)
+			err = s3Select.Open(func(offset, length int64) (io.ReadCloser, error) {
+				return ioutil.NopCloser(bytes.NewReader(data)), nil
+			})
+			if err != nil {
+				if !testCase.wantErr {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			w := &testResponseWriter{}
+			s3Select.Evaluate(w)
+			s3Select.Close()
+			resp := http.Response{
+				StatusCode:    http.StatusOK,
+				Body:          ioutil.NopCloser(bytes.NewReader(w.response)),
+				ContentLength: int64(len(w.response)),
+			}
+			res, err := minio.NewSelectResults(&resp, "testbucket")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := ioutil.ReadAll(res)
+			if testCase.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gotS := strings.TrimSpace(string(got)); gotS != "alice\ncarol" {
+				t.Errorf("got: %q\nwant: %q", gotS, "alice\ncarol")
+			}
+		})
+	}
+}
+
+func TestDetectCompressionType(t *testing.T) {
+	testCases := []struct {
+		data     string
+		compType CompressionType
+	}{
+		{"BZh91AY&SY\x00\x00", bzip2Type},
+		// Empty bzip2 stream.
+		{"BZh9\x17\x72\x45\x38\x50\x90\x00\x00\x00\x00", bzip2Type},
+		// Text starting with the bzip2 stream magic.
+		{"BZh,name,amount\n", noneType},
+		{"BZh01AY&SY\x00\x00", noneType},
+		{"BZh91AY&SX\x00\x00", noneType},
+		{"BZh9", noneType},
+		{"\x1f\x8b\x08", gzipType},
+		{"name,amount\n", noneType},
+		{"", noneType},
+	}
+
+	for i, testCase := range testCases {
+		br := bufio.NewReader(strings.NewReader(testCase.data))
+		if compType := detectCompressionType(br); compType != testCase.compType {
+			t.Errorf("case %d: got %q, want %q", i, compType, testCase.compType)
+		}
+	}
+}
+
+func TestORCAvroInput(t *testing.T) {
+	orcData, err := ioutil.ReadFile("testdata.orc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var avroData bytes.Buffer
+	w, err := goavro.NewOCFWriter(goavro.OCFConfig{
+		W:               &avroData,
+		CompressionName: "deflate",
+		Schema: `{"type": "record", "name": "user", "fields": [
+  {"name": "id", "type": "long"},
+  {"name": "name", "type": ["null", "string"]},
+  {"name": "tags", "type": {"type": "array", "items": "string"}},
+  {"name": "info", "type": ["null", {"type": "record", "name": "info", "fields": [
+    {"name": "city", "type": "string"}, {"name": "zip", "type": "int"}]}]}
+]}`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Append([]map[string]interface{}{
+		{"id": int64(1), "name": goavro.Union("string", "alice"), "tags": []interface{}{"a", "b"}, "info": goavro.Union("info", map[string]interface{}{"city": "Paris", "zip": int32(75000)})},
+		{"id": int64(2), "name": nil, "tags": []interface{}{}, "info": nil},
+		{"id": int64(3), "name": goavro.Union("string", "carol"), "tags": []interface{}{"c"}, "info": goavro.Union("info", map[string]interface{}{"city": "Oslo", "zip": int32(150)})},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		query      string
+		wantResult string
+	}{
+		{
+			query:      "SELECT id, name FROM S3Object",
+			wantResult: "1,alice\n2,\n3,carol",
+		},
+		{
+			query:      "SELECT s.info.city, s.info.zip FROM S3Object s WHERE s.id IN (1, 3)",
+			wantResult: "Paris,75000\nOslo,150",
+		},
+		{
+			query:      "SELECT s.id, s.tags[0] FROM S3Object s WHERE s.id > 1",
+			wantResult: "2,\n3,c",
+		},
+		{
+			query:      "SELECT COUNT(*), MAX(id) FROM S3Object",
+			wantResult: "3,3",
+		},
+	}
+
+	request := `<?xml version="1.0" encoding="UTF-8"?>
+<SelectObjectContentRequest>
+    <Expression>%s</Expression>
+    <ExpressionType>SQL</ExpressionType>
+    <InputSerialization>
+        <CompressionType>NONE</CompressionType>
+        <%s>
+        </%s>
+    </InputSerialization>
+    <OutputSerialization>
+        <CSV>
+        </CSV>
+    </OutputSerialization>
+</SelectObjectContentRequest>`
+
+	for _, format := range []string{"ORC", "Avro"} {
+		data := orcData
+		if format == "Avro" {
+			data = avroData.Bytes()
+		}
+
+		for i, testCase := range testCases {
+			t.Run(fmt.Sprint(format, i), func(t *testing.T) {
+				var escaped bytes.Buffer
+				xml.EscapeText(&escaped, []byte(testCase.query))
+				s3Select, err := NewS3Select(strings.NewReader(fmt.Sprintf(request, escaped.String(), format, format)))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if err = s3Select.Open(func(offset, length int64) (io.ReadCloser, error) {
+					if offset < 0 {
+						offset += int64(len(data))
+						if offset < 0 {
+							offset = 0
+						}
+					}
+					return ioutil.NopCloser(bytes.NewReader(data[offset:])), nil
+				}); err != nil {
+					t.Fatal(err)
+				}
+
+				w := &testResponseWriter{}
+				s3Select.Evaluate(w)
+				s3Select.Close()
+				resp := http.Response{
+					StatusCode:    http.StatusOK,
+					Body:          ioutil.NopCloser(bytes.NewReader(w.response)),
+					ContentLength: int64(len(w.response)),
+				}
+				res, err := minio.NewSelectResults(&resp, "testbucket")
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, err := ioutil.ReadAll(res)
+				if err != nil {
+					t.Fatal(err)
+				}
+				gotS := strings.TrimSpace(string(got))
+				if gotS != testCase.wantResult {
+					t.Errorf("Query: %s\ngot: %q\nwant: %q", testCase.query, gotS, testCase.wantResult)
+				}
+			})
+		}
+	}
+}
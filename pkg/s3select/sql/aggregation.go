@@ -328,3 +328,71 @@ func (e *FuncExpr) getAggregate() (*Value, error) {
 
 	return nil, errInvalidAggregation
 }
+
+// mergeAggVal merges the partial aggregation src into dst, both of
+// which aggregated different rows of a group.
+func mergeAggVal(fn FuncName, dst, src *aggVal) error {
+	dst.runningCount += src.runningCount
+	if !src.seen {
+		return nil
+	}
+	isFirst := !dst.seen
+	dst.seen = true
+
+	switch fn {
+	case aggFnAvg, aggFnSum:
+		return dst.runningSum.arithOp(opPlus, src.runningSum)
+	case aggFnMin:
+		return dst.runningMin.minmax(src.runningMin, false, isFirst)
+	case aggFnMax:
+		return dst.runningMax.minmax(src.runningMax, true, isFirst)
+	}
+	return nil
+}
+
+func (vw *valueWriter) writeAggVal(fn FuncName, agg *aggVal) error {
+	var seen uint64
+	if agg.seen {
+		seen = 1
+	}
+	if err := vw.writeUvarint(seen); err != nil {
+		return err
+	}
+	if err := vw.writeUvarint(uint64(agg.runningCount)); err != nil {
+		return err
+	}
+
+	switch fn {
+	case aggFnAvg, aggFnSum:
+		return vw.writeValue(agg.runningSum)
+	case aggFnMin:
+		return vw.writeValue(agg.runningMin)
+	case aggFnMax:
+		return vw.writeValue(agg.runningMax)
+	}
+	return nil
+}
+
+func (run *spillRun) readAggVal(fn FuncName) (*aggVal, error) {
+	agg := newAggVal(fn)
+	seen, err := run.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	agg.seen = seen == 1
+	count, err := run.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	agg.runningCount = int64(count)
+
+	switch fn {
+	case aggFnAvg, aggFnSum:
+		agg.runningSum, err = run.readValue()
+	case aggFnMin:
+		agg.runningMin, err = run.readValue()
+	case aggFnMax:
+		agg.runningMax, err = run.readValue()
+	}
+	return agg, err
+}
@@ -107,3 +107,12 @@ func errDataSource(err error) *s3Error {
 		cause:      err,
 	}
 }
+
+func errOverMaxQueryStorage(err error) *s3Error {
+	return &s3Error{
+		code:       "OverMaxQueryStorage",
+		message:    fmt.Sprintf("The GROUP BY or ORDER BY clause requires more temporary storage than allowed: %v", err),
+		statusCode: 400,
+		cause:      err,
+	}
+}
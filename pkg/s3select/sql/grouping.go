@@ -0,0 +1,635 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package sql
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+)
+
+var (
+	errGroupBySelectAll  = errors.New("GROUP BY and ORDER BY require a list of select expressions")
+	errGroupByNotGrouped = func(i int) error {
+		return fmt.Errorf("select expression %d must be an aggregation or only depend on GROUP BY expressions", i+1)
+	}
+	errGroupByAggregation = errors.New("GROUP BY clause cannot have an aggregation")
+	errOrderByAggregation = errors.New("ORDER BY clause can only have an aggregation with GROUP BY")
+)
+
+// resultTerm - a select or ORDER BY expression of a GROUP BY or
+// ORDER BY query.
+type resultTerm struct {
+	expr *Expression
+
+	// Whether the expression is an aggregation.
+	isAggregation bool
+
+	// Index of the value evaluated on the first row of a group, for
+	// non-aggregation expressions of GROUP BY queries.
+	row int
+
+	// Index of the result column an ORDER BY expression refers to,
+	// otherwise -1.
+	column int
+}
+
+// walkAST calls fn on all pointers of the AST node, depth first.
+func walkAST(node reflect.Value, fn func(interface{})) {
+	switch node.Kind() {
+	case reflect.Ptr:
+		if node.IsNil() {
+			return
+		}
+		fn(node.Interface())
+		walkAST(node.Elem(), fn)
+	case reflect.Struct:
+		for i := 0; i < node.NumField(); i++ {
+			// Skip evaluation state.
+			if node.Type().Field(i).PkgPath != "" {
+				continue
+			}
+			walkAST(node.Field(i), fn)
+		}
+	case reflect.Slice:
+		for i := 0; i < node.Len(); i++ {
+			walkAST(node.Index(i), fn)
+		}
+	}
+}
+
+// getKeypaths returns all path expressions of the expression.
+func getKeypaths(e *Expression) []string {
+	var paths []string
+	walkAST(reflect.ValueOf(e), func(node interface{}) {
+		if jpath, ok := node.(*JSONPath); ok {
+			paths = append(paths, jpath.String())
+		}
+	})
+	return paths
+}
+
+// getAggregations returns all aggregation function calls of the expression.
+func getAggregations(e *Expression) []*FuncExpr {
+	var funcs []*FuncExpr
+	walkAST(reflect.ValueOf(e), func(node interface{}) {
+		if fn, ok := node.(*FuncExpr); ok {
+			switch fn.getFunctionName() {
+			case aggFnAvg, aggFnCount, aggFnMax, aggFnMin, aggFnSum:
+				funcs = append(funcs, fn)
+			}
+		}
+	})
+	return funcs
+}
+
+// isGrouped checks whether a non-aggregation expression only depends on
+// the GROUP BY expressions, i.e. it is one of them or only refers to
+// path expressions which are grouped by.
+func isGrouped(e *Expression, groupBy []*Expression) bool {
+	grouped := make(map[string]bool)
+	for _, g := range groupBy {
+		if reflect.DeepEqual(e, g) {
+			return true
+		}
+		if jpath, ok := getKeypath(g); ok {
+			grouped[jpath.String()] = true
+		}
+	}
+
+	for _, path := range getKeypaths(e) {
+		if !grouped[path] {
+			return false
+		}
+	}
+	return true
+}
+
+// resultColumns returns the names of the result columns.
+func (e *SelectStatement) resultColumns() []string {
+	columns := make([]string, len(e.selectAST.Expression.Expressions))
+	for i, expr := range e.selectAST.Expression.Expressions {
+		if expr.As != "" {
+			columns[i] = expr.As
+		} else if comp, ok := getLastKeypathComponent(expr.Expression); ok && !e.selectTerms[i].isAggregation {
+			columns[i] = comp
+		} else {
+			columns[i] = fmt.Sprintf("_%d", i+1)
+		}
+	}
+	return columns
+}
+
+// orderByColumn returns the index of the result column the ORDER BY
+// expression refers to by name, otherwise -1.
+func (e *SelectStatement) orderByColumn(ob *OrderByExpression) int {
+	jpath, ok := getKeypath(ob.Expression)
+	if !ok || len(jpath.PathExpr) > 0 {
+		return -1
+	}
+	for i, column := range e.columns {
+		if column == jpath.BaseKey.String() {
+			return i
+		}
+	}
+	return -1
+}
+
+// analyzeResultTerms analyzes the select and ORDER BY expressions of
+// GROUP BY and ORDER BY queries.
+func (e *SelectStatement) analyzeResultTerms() error {
+	s := e.selectAST
+	if s.Expression.All {
+		return errGroupBySelectAll
+	}
+
+	// Make path expressions comparable, see isGrouped.
+	for _, g := range s.GroupBy {
+		getKeypaths(g)
+	}
+
+	grouped := len(s.GroupBy) > 0
+	analyze := func(expr *Expression) (resultTerm, error) {
+		term := resultTerm{expr: expr, row: -1, column: -1}
+		qp := expr.analyze(s)
+		if qp.err != nil {
+			return term, qp.err
+		}
+		term.isAggregation = qp.isAggregation
+		if grouped && !term.isAggregation {
+			term.row = len(e.groupRowExprs)
+			e.groupRowExprs = append(e.groupRowExprs, expr)
+		}
+		if term.isAggregation {
+			e.aggregations = append(e.aggregations, expr)
+			e.aggFuncs = append(e.aggFuncs, getAggregations(expr)...)
+		}
+		return term, nil
+	}
+
+	for i, expr := range s.Expression.Expressions {
+		term, err := analyze(expr.Expression)
+		if err != nil {
+			return err
+		}
+		if grouped && !term.isAggregation && !isGrouped(expr.Expression, s.GroupBy) {
+			return errGroupByNotGrouped(i)
+		}
+		e.selectTerms = append(e.selectTerms, term)
+	}
+	e.columns = e.resultColumns()
+
+	for _, ob := range s.OrderBy {
+		if column := e.orderByColumn(ob); column >= 0 {
+			e.orderByTerms = append(e.orderByTerms, resultTerm{expr: ob.Expression, row: -1, column: column})
+			continue
+		}
+		term, err := analyze(ob.Expression)
+		if err != nil {
+			return err
+		}
+		if term.isAggregation && !grouped {
+			return errOrderByAggregation
+		}
+		if grouped && !term.isAggregation && !isGrouped(ob.Expression, s.GroupBy) {
+			return errors.New("ORDER BY expression must be an aggregation or only depend on GROUP BY expressions")
+		}
+		e.orderByTerms = append(e.orderByTerms, term)
+	}
+
+	for _, g := range s.GroupBy {
+		qp := g.analyze(s)
+		if qp.err != nil {
+			return qp.err
+		}
+		if qp.isAggregation {
+			return errGroupByAggregation
+		}
+	}
+	return nil
+}
+
+// IsBuffered returns whether the results are only available once all
+// input rows are processed, which is the case for GROUP BY queries and
+// ORDER BY queries without aggregation.
+func (e *SelectStatement) IsBuffered() bool {
+	return len(e.selectAST.GroupBy) > 0 ||
+		(len(e.selectAST.OrderBy) > 0 && !e.selectQProp.isAggregation)
+}
+
+// BufferRow - groups or buffers the input record for sorting. Applies
+// only to buffered queries.
+func (e *SelectStatement) BufferRow(input Record) error {
+	ok, err := e.isPassingWhereClause(input)
+	if err != nil || !ok {
+		return err
+	}
+
+	if len(e.selectAST.GroupBy) > 0 {
+		return e.groupRow(input)
+	}
+
+	values := make([]*Value, len(e.selectTerms))
+	for i, term := range e.selectTerms {
+		if values[i], err = term.expr.evalNode(input); err != nil {
+			return err
+		}
+	}
+	keys, err := e.orderByKeys(values, func(term resultTerm) (*Value, error) {
+		return term.expr.evalNode(input)
+	})
+	if err != nil {
+		return err
+	}
+
+	if e.sorter == nil {
+		e.sorter = newSorter(e.selectAST.OrderBy, e.limitValue, &e.spillBudget)
+	}
+	return e.sorter.add(keys, values)
+}
+
+func (e *SelectStatement) orderByKeys(values []*Value, eval func(term resultTerm) (*Value, error)) ([]*Value, error) {
+	keys := make([]*Value, len(e.orderByTerms))
+	for i, term := range e.orderByTerms {
+		if term.column >= 0 {
+			keys[i] = values[term.column]
+			continue
+		}
+		var err error
+		if keys[i], err = eval(term); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
+// groupRow aggregates the input record into its group.
+func (e *SelectStatement) groupRow(input Record) error {
+	var key bytes.Buffer
+	vw := valueWriter{w: &key}
+	for _, g := range e.selectAST.GroupBy {
+		v, err := g.evalNode(input)
+		if err != nil {
+			return err
+		}
+		if err = vw.writeValue(v); err != nil {
+			return err
+		}
+	}
+
+	if e.groups == nil {
+		funcs := make([]FuncName, len(e.aggFuncs))
+		for i, fn := range e.aggFuncs {
+			funcs[i] = fn.getFunctionName()
+		}
+		e.groups = newGroupTable(funcs, &e.spillBudget)
+	}
+
+	group, err := e.groups.get(key.String(), func() ([]*Value, error) {
+		row := make([]*Value, len(e.groupRowExprs))
+		for i, expr := range e.groupRowExprs {
+			v, err := expr.evalNode(input)
+			if err != nil {
+				return nil, err
+			}
+			row[i] = copyValue(v)
+		}
+		return row, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	e.setAggregates(group.aggs)
+	for _, expr := range e.aggregations {
+		if err = expr.aggregateRow(input); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setAggregates sets the aggregation state of a group.
+func (e *SelectStatement) setAggregates(aggs []*aggVal) {
+	for i, fn := range e.aggFuncs {
+		fn.aggregate = aggs[i]
+	}
+}
+
+// groupResult returns the result row of a group along with its sort keys.
+func (e *SelectStatement) groupResult(group *groupState) (values, keys []*Value, err error) {
+	e.setAggregates(group.aggs)
+	eval := func(term resultTerm) (*Value, error) {
+		if term.isAggregation {
+			return term.expr.evalNode(nil)
+		}
+		return group.row[term.row], nil
+	}
+
+	values = make([]*Value, len(e.selectTerms))
+	for i, term := range e.selectTerms {
+		if values[i], err = eval(term); err != nil {
+			return nil, nil, err
+		}
+	}
+	keys, err = e.orderByKeys(values, eval)
+	return values, keys, err
+}
+
+// Results - the result rows of a buffered query.
+type Results struct {
+	columns []string
+	limit   int64
+	count   int64
+	next    func() ([]*Value, error)
+	close   func()
+}
+
+// Next - sets the next result row into output, returns io.EOF after
+// the last row.
+func (r *Results) Next(output Record) (Record, error) {
+	if r.limit >= 0 && r.count >= r.limit {
+		return nil, io.EOF
+	}
+	values, err := r.next()
+	if err != nil {
+		return nil, err
+	}
+	for i, column := range r.columns {
+		if output, err = output.Set(column, values[i]); err != nil {
+			return nil, err
+		}
+	}
+	r.count++
+	return output, nil
+}
+
+// Close - removes temporary files of the results.
+func (r *Results) Close() {
+	r.close()
+}
+
+// Close - removes the temporary files of a buffered query, including
+// those of a query which failed before its results were returned.
+func (e *SelectStatement) Close() {
+	if e.groups != nil {
+		e.groups.close()
+	}
+	if e.sorter != nil {
+		e.sorter.close()
+	}
+	e.spillBudget.close()
+}
+
+// Results - returns the results of a buffered query after all input
+// records have been processed.
+func (e *SelectStatement) Results() (*Results, error) {
+	results := &Results{
+		columns: e.columns,
+		limit:   e.limitValue,
+		close:   e.Close,
+	}
+
+	var next func() ([]*Value, error)
+	var err error
+	switch {
+	case len(e.selectAST.GroupBy) > 0 && e.groups != nil:
+		var nextGroup func() (*groupState, error)
+		if nextGroup, err = e.groups.iterator(); err != nil {
+			break
+		}
+		next = func() ([]*Value, error) {
+			group, err := nextGroup()
+			if err != nil {
+				return nil, err
+			}
+			values, _, err := e.groupResult(group)
+			return values, err
+		}
+		if len(e.orderByTerms) == 0 {
+			break
+		}
+
+		e.sorter = newSorter(e.selectAST.OrderBy, e.limitValue, &e.spillBudget)
+		for {
+			var group *groupState
+			if group, err = nextGroup(); err != nil {
+				break
+			}
+			var values, keys []*Value
+			if values, keys, err = e.groupResult(group); err != nil {
+				break
+			}
+			if err = e.sorter.add(keys, values); err != nil {
+				break
+			}
+		}
+		if err != io.EOF {
+			break
+		}
+		next, err = e.sorter.iterator()
+	case e.sorter != nil:
+		next, err = e.sorter.iterator()
+	default:
+		// No input rows.
+		next = func() ([]*Value, error) {
+			return nil, io.EOF
+		}
+	}
+	if err != nil {
+		results.Close()
+		return nil, err
+	}
+
+	results.next = next
+	return results, nil
+}
+
+// groupState - the state of a group of a GROUP BY query.
+type groupState struct {
+	key string
+
+	// Values of the non-aggregation expressions of the first row.
+	row []*Value
+
+	// State of each aggregation function.
+	aggs []*aggVal
+}
+
+// groupTable - the groups of a GROUP BY query, spilling sorted runs
+// into temporary files beyond maxInMemoryRows groups.
+type groupTable struct {
+	funcs  []FuncName
+	groups map[string]*groupState
+	runs   []*spillRun
+	budget *spillBudget
+}
+
+func newGroupTable(funcs []FuncName, budget *spillBudget) *groupTable {
+	return &groupTable{
+		funcs:  funcs,
+		groups: make(map[string]*groupState),
+		budget: budget,
+	}
+}
+
+// get returns the group of the key, creating it with the row values
+// returned by newRow if needed.
+func (t *groupTable) get(key string, newRow func() ([]*Value, error)) (*groupState, error) {
+	if group, ok := t.groups[key]; ok {
+		return group, nil
+	}
+
+	if len(t.groups) >= maxInMemoryRows {
+		if err := t.spill(); err != nil {
+			return nil, err
+		}
+	}
+
+	row, err := newRow()
+	if err != nil {
+		return nil, err
+	}
+	group := &groupState{key: key, row: row, aggs: make([]*aggVal, len(t.funcs))}
+	for i, fn := range t.funcs {
+		group.aggs[i] = newAggVal(fn)
+	}
+	t.groups[key] = group
+	return group, nil
+}
+
+func (t *groupTable) sortedGroups() []*groupState {
+	groups := make([]*groupState, 0, len(t.groups))
+	for _, group := range t.groups {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].key < groups[j].key
+	})
+	return groups
+}
+
+// spill writes the groups in memory as a run sorted by key.
+func (t *groupTable) spill() error {
+	run, err := newSpillRun(t.budget)
+	if err != nil {
+		return err
+	}
+	t.runs = append(t.runs, run)
+
+	for _, group := range t.sortedGroups() {
+		if err = run.writeBytes([]byte(group.key)); err != nil {
+			return err
+		}
+		if err = run.writeValues(group.row); err != nil {
+			return err
+		}
+		for i, agg := range group.aggs {
+			if err = run.writeAggVal(t.funcs[i], agg); err != nil {
+				return err
+			}
+		}
+	}
+	t.groups = make(map[string]*groupState)
+	return nil
+}
+
+func (t *groupTable) readGroup(run *spillRun) (interface{}, error) {
+	key, err := run.readBytes()
+	if err != nil {
+		return nil, err
+	}
+	group := &groupState{key: string(key), aggs: make([]*aggVal, len(t.funcs))}
+	if group.row, err = run.readValues(); err != nil {
+		return nil, err
+	}
+	for i, fn := range t.funcs {
+		if group.aggs[i], err = run.readAggVal(fn); err != nil {
+			return nil, err
+		}
+	}
+	return group, nil
+}
+
+// iterator returns a function returning all groups, followed by
+// io.EOF. Partial groups of the spilled runs are merged.
+func (t *groupTable) iterator() (func() (*groupState, error), error) {
+	if len(t.runs) == 0 {
+		groups := t.sortedGroups()
+		return func() (*groupState, error) {
+			if len(groups) == 0 {
+				return nil, io.EOF
+			}
+			group := groups[0]
+			groups = groups[1:]
+			return group, nil
+		}, nil
+	}
+
+	if len(t.groups) > 0 {
+		if err := t.spill(); err != nil {
+			return nil, err
+		}
+	}
+	m, err := newRunMerger(t.runs, t.readGroup, func(a, b interface{}) bool {
+		return a.(*groupState).key < b.(*groupState).key
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var pending *groupState
+	return func() (*groupState, error) {
+		if pending == nil {
+			item, err := m.next()
+			if err != nil {
+				return nil, err
+			}
+			pending = item.(*groupState)
+		}
+		group := pending
+		pending = nil
+		for {
+			item, err := m.next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			next := item.(*groupState)
+			if next.key != group.key {
+				pending = next
+				break
+			}
+			for i, fn := range t.funcs {
+				if err = mergeAggVal(fn, group.aggs[i], next.aggs[i]); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return group, nil
+	}, nil
+}
+
+func (t *groupTable) close() {
+	closeSpillRuns(t.runs)
+	t.runs = nil
+	t.groups = nil
+}
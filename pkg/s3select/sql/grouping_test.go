@@ -0,0 +1,255 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package sql
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"sync/atomic"
+	"testing"
+)
+
+func TestParseGroupByOrderBy(t *testing.T) {
+	testCases := []struct {
+		query     string
+		expectErr bool
+	}{
+		{"SELECT s.a, COUNT(*) FROM S3Object s GROUP BY s.a", false},
+		{"SELECT s.a, s.b, SUM(s.c) FROM S3Object s GROUP BY s.a, s.b ORDER BY SUM(s.c) DESC", false},
+		{"SELECT UPPER(s.a) AS u, COUNT(*) AS n FROM S3Object s GROUP BY s.a ORDER BY n, u ASC", false},
+		{"SELECT s.a FROM S3Object s ORDER BY s.b DESC LIMIT 10", false},
+		{"SELECT COUNT(*) FROM S3Object s ORDER BY s.b", false},
+		{"SELECT * FROM S3Object s GROUP BY s.a", true},
+		{"SELECT * FROM S3Object s ORDER BY s.a", true},
+		{"SELECT s.b, COUNT(*) FROM S3Object s GROUP BY s.a", true},
+		{"SELECT s.a FROM S3Object s GROUP BY COUNT(*)", true},
+		{"SELECT s.a FROM S3Object s ORDER BY COUNT(*)", true},
+		{"SELECT s.a, COUNT(*) FROM S3Object s GROUP BY s.a ORDER BY s.b", true},
+	}
+
+	for i, testCase := range testCases {
+		_, err := ParseSelectStatement(testCase.query)
+		if (err != nil) != testCase.expectErr {
+			t.Errorf("Test %d: %s: expected error %v, got %v", i+1, testCase.query, testCase.expectErr, err)
+		}
+	}
+}
+
+func TestSorterSpill(t *testing.T) {
+	defer func(n int) { maxInMemoryRows = n }(maxInMemoryRows)
+	maxInMemoryRows = 3
+
+	var budget spillBudget
+	s := newSorter([]*OrderByExpression{{Direction: "DESC"}}, -1, &budget)
+	defer s.close()
+	for _, v := range []int64{5, 1, 9, 3, 7, 2, 8} {
+		if err := s.add([]*Value{FromInt(v)}, []*Value{FromString("v"), FromInt(v)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if budget.runs != 2 {
+		t.Fatalf("expected 2 spilled runs, got %d", budget.runs)
+	}
+
+	next, err := s.iterator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []int64
+	for {
+		values, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		i, _ := values[1].ToInt()
+		got = append(got, i)
+	}
+	want := []int64{9, 8, 7, 5, 3, 2, 1}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestGroupTableSpill(t *testing.T) {
+	defer func(n int) { maxInMemoryRows = n }(maxInMemoryRows)
+	maxInMemoryRows = 2
+
+	var budget spillBudget
+	funcs := []FuncName{aggFnCount, aggFnSum, aggFnMax}
+	groups := newGroupTable(funcs, &budget)
+	defer groups.close()
+
+	for _, row := range []struct {
+		key string
+		val int64
+	}{{"a", 1}, {"b", 2}, {"c", 3}, {"a", 4}, {"b", 5}, {"a", 6}} {
+		group, err := groups.get(row.key, func() ([]*Value, error) {
+			return []*Value{FromString(row.key)}, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		group.aggs[0].runningCount++
+		for i, fn := range funcs[1:] {
+			agg := group.aggs[i+1]
+			first := !agg.seen
+			agg.seen = true
+			switch fn {
+			case aggFnSum:
+				agg.runningSum.arithOp(opPlus, FromFloat(float64(row.val)))
+			case aggFnMax:
+				agg.runningMax.minmax(FromInt(row.val), true, first)
+			}
+		}
+	}
+	if budget.runs == 0 {
+		t.Fatal("expected spilled runs")
+	}
+
+	next, err := groups.iterator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][3]int64{"a": {3, 11, 6}, "b": {2, 7, 5}, "c": {1, 3, 3}}
+	n := 0
+	for {
+		group, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		key, _ := group.row[0].ToString()
+		sum, _ := group.aggs[1].runningSum.ToFloat()
+		max, _ := group.aggs[2].runningMax.ToInt()
+		got := [3]int64{group.aggs[0].runningCount, int64(sum), max}
+		if got != want[key] {
+			t.Errorf("group %s: expected %v, got %v", key, want[key], got)
+		}
+		n++
+	}
+	if n != len(want) {
+		t.Fatalf("expected %d groups, got %d", len(want), n)
+	}
+}
+
+func TestSpillLimit(t *testing.T) {
+	defer func(n int, size int64) { maxInMemoryRows, maxSpillSize = n, size }(maxInMemoryRows, maxSpillSize)
+	maxInMemoryRows, maxSpillSize = 1, 16
+
+	var budget spillBudget
+	defer budget.close()
+	s := newSorter([]*OrderByExpression{{}}, -1, &budget)
+	defer s.close()
+	var err error
+	for i := 0; i < 10 && err == nil; i++ {
+		err = s.add([]*Value{FromInt(int64(i))}, []*Value{FromString("some long value")})
+	}
+	if serr, ok := err.(*s3Error); !ok || serr.ErrorCode() != "OverMaxQueryStorage" {
+		t.Fatalf("expected OverMaxQueryStorage error, got %v", err)
+	}
+}
+
+func TestSpillFilesRemoved(t *testing.T) {
+	defer func(n int, dir string) { maxInMemoryRows, spillDir = n, dir }(maxInMemoryRows, spillDir)
+	dir, err := ioutil.TempDir("", "s3select-spill-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	maxInMemoryRows, spillDir = 1, dir
+
+	// A query failing before its results are returned.
+	var stmt SelectStatement
+	defer stmt.Close()
+	stmt.sorter = newSorter([]*OrderByExpression{{}}, -1, &stmt.spillBudget)
+	for i := 0; i < 3; i++ {
+		if err = stmt.sorter.add([]*Value{FromInt(int64(i))}, []*Value{FromString("v")}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if files, _ := ioutil.ReadDir(dir); len(files) != stmt.spillBudget.runs {
+		t.Fatalf("expected %d temporary files, got %d", stmt.spillBudget.runs, len(files))
+	}
+	stmt.Close()
+	if files, _ := ioutil.ReadDir(dir); len(files) != 0 {
+		t.Fatalf("expected temporary files to be removed, got %d", len(files))
+	}
+	if size := atomic.LoadInt64(&totalSpillSize); size != 0 {
+		t.Fatalf("expected no temporary storage to be used, got %d", size)
+	}
+	// Closing again is a no-op.
+	stmt.Close()
+}
+
+func TestSpillTotalLimit(t *testing.T) {
+	defer func(n int, size int64) { maxInMemoryRows, maxTotalSpillSize = n, size }(maxInMemoryRows, maxTotalSpillSize)
+	maxInMemoryRows, maxTotalSpillSize = 1, 64
+
+	// The temporary storage used by a query counts against the limit of
+	// the other queries.
+	var budget spillBudget
+	s := newSorter([]*OrderByExpression{{}}, -1, &budget)
+	var err error
+	for i := 0; i < 10 && err == nil; i++ {
+		err = s.add([]*Value{FromInt(int64(i))}, []*Value{FromString("some long value")})
+	}
+	if serr, ok := err.(*s3Error); !ok || serr.ErrorCode() != "OverMaxQueryStorage" {
+		t.Fatalf("expected OverMaxQueryStorage error, got %v", err)
+	}
+
+	var other spillBudget
+	run, err := newSpillRun(&other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.close()
+	if _, err = run.Write(make([]byte, 64)); err == nil {
+		t.Fatal("expected the total limit to be reached")
+	}
+
+	budget.close()
+	if _, err = run.Write(make([]byte, 64)); err != nil {
+		t.Fatalf("expected the storage of the closed query to be released, got %v", err)
+	}
+}
+
+func TestSpillLimitFromEnv(t *testing.T) {
+	testCases := []struct {
+		value string
+		limit int64
+	}{
+		{"", 100},
+		{"1GiB", 1 << 30},
+		{"500MB", 500 * 1000 * 1000},
+		{"invalid", 100},
+	}
+	for i, testCase := range testCases {
+		if limit := spillLimit(testCase.value, 100); limit != testCase.limit {
+			t.Errorf("Test %d: expected %d, got %d", i+1, testCase.limit, limit)
+		}
+	}
+}
@@ -85,10 +85,24 @@ func (qi *QuotedIdentifier) Capture(values []string) error {
 
 // Select is the top level AST node type
 type Select struct {
-	Expression *SelectExpression `parser:"\"SELECT\" @@"`
-	From       *TableExpression  `parser:"\"FROM\" @@"`
-	Where      *Expression       `parser:"( \"WHERE\" @@ )?"`
-	Limit      *LitValue         `parser:"( \"LIMIT\" @@ )?"`
+	Expression *SelectExpression    `parser:"\"SELECT\" @@"`
+	From       *TableExpression     `parser:"\"FROM\" @@"`
+	Where      *Expression          `parser:"( \"WHERE\" @@ )?"`
+	GroupBy    []*Expression        `parser:"( \"GROUP\" \"BY\" @@ ( \",\" @@ )* )?"`
+	OrderBy    []*OrderByExpression `parser:"( \"ORDER\" \"BY\" @@ ( \",\" @@ )* )?"`
+	Limit      *LitValue            `parser:"( \"LIMIT\" @@ )?"`
+}
+
+// OrderByExpression represents an expression of the ORDER BY clause
+// along with its sort direction
+type OrderByExpression struct {
+	Expression *Expression `parser:"@@"`
+	Direction  string      `parser:"@( \"ASC\" | \"DESC\" )?"`
+}
+
+// IsDescending returns whether the expression is sorted in descending order.
+func (e *OrderByExpression) IsDescending() bool {
+	return strings.EqualFold(e.Direction, "DESC")
 }
 
 // SelectExpression represents the items requested in the select
@@ -348,7 +362,7 @@ type Identifier struct {
 var (
 	sqlLexer = lexer.Must(lexer.Regexp(`(\s+)` +
 		`|(?P<Timeword>(?i)\b(?:YEAR|MONTH|DAY|HOUR|MINUTE|SECOND|TIMEZONE_HOUR|TIMEZONE_MINUTE)\b)` +
-		`|(?P<Keyword>(?i)\b(?:SELECT|FROM|TOP|DISTINCT|ALL|WHERE|GROUP|BY|HAVING|UNION|MINUS|EXCEPT|INTERSECT|ORDER|LIMIT|OFFSET|TRUE|FALSE|NULL|IS|NOT|ANY|SOME|BETWEEN|AND|OR|LIKE|ESCAPE|AS|IN|BOOL|INT|INTEGER|STRING|FLOAT|DECIMAL|NUMERIC|TIMESTAMP|AVG|COUNT|MAX|MIN|SUM|COALESCE|NULLIF|CAST|DATE_ADD|DATE_DIFF|EXTRACT|TO_STRING|TO_TIMESTAMP|UTCNOW|CHAR_LENGTH|CHARACTER_LENGTH|LOWER|SUBSTRING|TRIM|UPPER|LEADING|TRAILING|BOTH|FOR)\b)` +
+		`|(?P<Keyword>(?i)\b(?:SELECT|FROM|TOP|DISTINCT|ALL|WHERE|GROUP|BY|HAVING|UNION|MINUS|EXCEPT|INTERSECT|ORDER|ASC|DESC|LIMIT|OFFSET|TRUE|FALSE|NULL|IS|NOT|ANY|SOME|BETWEEN|AND|OR|LIKE|ESCAPE|AS|IN|BOOL|INT|INTEGER|STRING|FLOAT|DECIMAL|NUMERIC|TIMESTAMP|AVG|COUNT|MAX|MIN|SUM|COALESCE|NULLIF|CAST|DATE_ADD|DATE_DIFF|EXTRACT|TO_STRING|TO_TIMESTAMP|UTCNOW|CHAR_LENGTH|CHARACTER_LENGTH|LOWER|SUBSTRING|TRIM|UPPER|LEADING|TRAILING|BOTH|FOR)\b)` +
 		`|(?P<Ident>[a-zA-Z_][a-zA-Z0-9_]*)` +
 		`|(?P<QuotIdent>"([^"]*("")?)*")` +
 		`|(?P<Number>\d*\.?\d+([eE][-+]?\d+)?)` +
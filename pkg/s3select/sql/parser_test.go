@@ -18,6 +18,7 @@ package sql
 
 import (
 	"bytes"
+	"reflect"
 	"testing"
 
 	"github.com/alecthomas/participle"
@@ -383,3 +384,28 @@ func TestSqlLexerArithOps(t *testing.T) {
 	// 	fmt.Printf("%d: %#v\n", i, t)
 	// }
 }
+
+func TestStatementColumns(t *testing.T) {
+	testCases := []struct {
+		query   string
+		columns []string
+	}{
+		{"SELECT * FROM S3Object", nil},
+		{"SELECT s.* FROM S3Object s", nil},
+		{"SELECT a, b FROM S3Object WHERE c > 1", []string{"a", "b", "c"}},
+		{"SELECT s.a.x, s.\"b\"[1] FROM S3Object s WHERE s.a.y = 1", []string{"a", "b"}},
+		{"SELECT COUNT(*) FROM S3Object", []string{}},
+		{"SELECT a, SUM(b) FROM S3Object GROUP BY a ORDER BY MAX(c)", []string{"a", "b", "c"}},
+		{"SELECT s[0] FROM S3Object s", nil},
+	}
+
+	for i, testCase := range testCases {
+		stmt, err := ParseSelectStatement(testCase.query)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if columns := stmt.Columns(); !reflect.DeepEqual(columns, testCase.columns) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.columns, columns)
+		}
+	}
+}
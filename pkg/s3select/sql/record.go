@@ -37,6 +37,10 @@ const (
 	SelectFmtSIMDJSON
 	// SelectFmtParquet - Parquet format
 	SelectFmtParquet
+	// SelectFmtORC - ORC format
+	SelectFmtORC
+	// SelectFmtAvro - Avro format
+	SelectFmtAvro
 )
 
 // WriteCSVOpts - encapsulates options for Select CSV output
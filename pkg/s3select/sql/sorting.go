@@ -0,0 +1,281 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package sql
+
+import (
+	"io"
+	"sort"
+	"strings"
+)
+
+// sortRank orders values of different types: NULL, BOOL, numbers,
+// TIMESTAMP, STRING, untyped bytes and ARRAY.
+func sortRank(v *Value) int {
+	switch v.value.(type) {
+	case nil:
+		return 0
+	case bool:
+		return 1
+	case int64, float64:
+		return 2
+	default:
+		if _, ok := v.ToTimestamp(); ok {
+			return 3
+		}
+		if _, ok := v.ToString(); ok {
+			return 4
+		}
+		if _, ok := v.ToBytes(); ok {
+			return 5
+		}
+	}
+	return 6
+}
+
+// compareValues returns -1, 0 or 1 if a sorts before, equal to or
+// after b. Values of different types are ordered by sortRank.
+func compareValues(a, b *Value) int {
+	ra, rb := sortRank(a), sortRank(b)
+	switch {
+	case ra < rb:
+		return -1
+	case ra > rb:
+		return 1
+	}
+
+	switch ra {
+	case 1:
+		x, _ := a.ToBool()
+		y, _ := b.ToBool()
+		switch {
+		case x == y:
+			return 0
+		case !x:
+			return -1
+		}
+		return 1
+	case 2:
+		if x, ok := a.ToInt(); ok {
+			if y, ok := b.ToInt(); ok {
+				switch {
+				case x < y:
+					return -1
+				case x > y:
+					return 1
+				}
+				return 0
+			}
+		}
+		x, _ := a.ToFloat()
+		y, _ := b.ToFloat()
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	case 3:
+		x, _ := a.ToTimestamp()
+		y, _ := b.ToTimestamp()
+		switch {
+		case x.Before(y):
+			return -1
+		case x.After(y):
+			return 1
+		}
+		return 0
+	case 4:
+		x, _ := a.ToString()
+		y, _ := b.ToString()
+		return strings.Compare(x, y)
+	case 5:
+		x, _ := a.ToBytes()
+		y, _ := b.ToBytes()
+		return strings.Compare(string(x), string(y))
+	case 6:
+		x, _ := a.ToArray()
+		y, _ := b.ToArray()
+		for i := 0; i < len(x) && i < len(y); i++ {
+			if c := compareValues(&x[i], &y[i]); c != 0 {
+				return c
+			}
+		}
+		switch {
+		case len(x) < len(y):
+			return -1
+		case len(x) > len(y):
+			return 1
+		}
+	}
+	return 0
+}
+
+// sortKeyValue returns a copy of the value for sorting, the type of
+// untyped values is inferred so that e.g. CSV numbers sort numerically.
+func sortKeyValue(v *Value) *Value {
+	v = copyValue(v)
+	if _, ok := v.ToBytes(); ok {
+		if err := v.InferBytesType(); err != nil {
+			return v
+		}
+	}
+	return v
+}
+
+// sortRow - a row of the result along with its sort keys.
+type sortRow struct {
+	keys   []*Value
+	values []*Value
+}
+
+// sorter - sorts the result rows of an ORDER BY query, spilling
+// sorted runs into temporary files beyond maxInMemoryRows rows.
+type sorter struct {
+	desc   []bool
+	limit  int64
+	rows   []sortRow
+	runs   []*spillRun
+	budget *spillBudget
+}
+
+func newSorter(orderBy []*OrderByExpression, limit int64, budget *spillBudget) *sorter {
+	desc := make([]bool, len(orderBy))
+	for i, ob := range orderBy {
+		desc[i] = ob.IsDescending()
+	}
+	return &sorter{desc: desc, limit: limit, budget: budget}
+}
+
+func (s *sorter) less(a, b sortRow) bool {
+	for i, desc := range s.desc {
+		c := compareValues(a.keys[i], b.keys[i])
+		if desc {
+			c = -c
+		}
+		if c != 0 {
+			return c < 0
+		}
+	}
+	return false
+}
+
+func (s *sorter) sortRows() {
+	sort.SliceStable(s.rows, func(i, j int) bool {
+		return s.less(s.rows[i], s.rows[j])
+	})
+}
+
+// add adds a row of the result.
+func (s *sorter) add(keys, values []*Value) error {
+	row := sortRow{keys: make([]*Value, len(keys)), values: make([]*Value, len(values))}
+	for i, k := range keys {
+		row.keys[i] = sortKeyValue(k)
+	}
+	for i, v := range values {
+		row.values[i] = copyValue(v)
+	}
+	s.rows = append(s.rows, row)
+
+	// With a LIMIT only the first rows need to be kept.
+	if s.limit >= 0 && s.limit < int64(maxInMemoryRows)/2 {
+		if int64(len(s.rows)) >= 2*s.limit+1 {
+			s.sortRows()
+			s.rows = s.rows[:s.limit]
+		}
+		return nil
+	}
+
+	if len(s.rows) >= maxInMemoryRows {
+		return s.spill()
+	}
+	return nil
+}
+
+// spill writes the rows in memory as a sorted run.
+func (s *sorter) spill() error {
+	run, err := newSpillRun(s.budget)
+	if err != nil {
+		return err
+	}
+	s.runs = append(s.runs, run)
+
+	s.sortRows()
+	for _, row := range s.rows {
+		if err = run.writeValues(row.keys); err != nil {
+			return err
+		}
+		if err = run.writeValues(row.values); err != nil {
+			return err
+		}
+	}
+	s.rows = s.rows[:0]
+	return nil
+}
+
+func readSortRow(run *spillRun) (interface{}, error) {
+	keys, err := run.readValues()
+	if err != nil {
+		return nil, err
+	}
+	values, err := run.readValues()
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return sortRow{keys: keys, values: values}, err
+}
+
+// iterator returns a function returning the values of the sorted rows,
+// followed by io.EOF.
+func (s *sorter) iterator() (func() ([]*Value, error), error) {
+	if len(s.runs) == 0 {
+		s.sortRows()
+		rows := s.rows
+		return func() ([]*Value, error) {
+			if len(rows) == 0 {
+				return nil, io.EOF
+			}
+			row := rows[0]
+			rows = rows[1:]
+			return row.values, nil
+		}, nil
+	}
+
+	if len(s.rows) > 0 {
+		if err := s.spill(); err != nil {
+			return nil, err
+		}
+	}
+	m, err := newRunMerger(s.runs, readSortRow, func(a, b interface{}) bool {
+		return s.less(a.(sortRow), b.(sortRow))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return func() ([]*Value, error) {
+		item, err := m.next()
+		if err != nil {
+			return nil, err
+		}
+		return item.(sortRow).values, nil
+	}, nil
+}
+
+func (s *sorter) close() {
+	closeSpillRuns(s.runs)
+	s.runs = nil
+	s.rows = nil
+}
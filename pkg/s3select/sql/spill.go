@@ -0,0 +1,439 @@
+/*
+ * MinIO Cloud Storage, (C) 2020 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package sql
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"sync/atomic"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// Environment variables setting the directory of the temporary files of
+// the queries, the system temporary directory by default, and the limit
+// of their total size.
+const (
+	envSpillDir   = "MINIO_API_SELECT_SPILL_DIR"
+	envSpillLimit = "MINIO_API_SELECT_SPILL_LIMIT"
+)
+
+// GROUP BY and ORDER BY queries keep a bounded number of groups or
+// rows in memory. Beyond that, they are spilled as sorted runs into
+// temporary files, which are merged once all input rows are processed.
+var (
+	// Number of groups or rows kept in memory.
+	maxInMemoryRows = 100000
+
+	// Maximum total size of the temporary files of a query.
+	maxSpillSize int64 = 10 << 30
+
+	// Maximum number of runs spilled by a query, all of them are
+	// open at the same time while merging.
+	maxSpillRuns = 512
+
+	// Directory of the temporary files.
+	spillDir = os.Getenv(envSpillDir)
+
+	// Maximum total size of the temporary files of all queries.
+	maxTotalSpillSize = spillLimit(os.Getenv(envSpillLimit), 4*maxSpillSize)
+
+	// Total size of the temporary files of all queries.
+	totalSpillSize int64
+)
+
+// spillLimit parses the limit of the total size of the temporary files.
+func spillLimit(v string, defaultLimit int64) int64 {
+	if v == "" {
+		return defaultLimit
+	}
+	limit, err := humanize.ParseBytes(v)
+	if err != nil || limit > math.MaxInt64 {
+		return defaultLimit
+	}
+	return int64(limit)
+}
+
+// Value types of the spill encoding.
+const (
+	spillNull byte = iota
+	spillBool
+	spillInt
+	spillFloat
+	spillString
+	spillBytes
+	spillTimestamp
+	spillArray
+)
+
+// spillBudget - accounts the temporary storage used by a query, and
+// keeps its temporary files until they are removed by close.
+type spillBudget struct {
+	size  int64
+	runs  int
+	files []*spillRun
+}
+
+// close removes all temporary files of the query, whether the query
+// completed or failed.
+func (budget *spillBudget) close() {
+	closeSpillRuns(budget.files)
+	budget.files = nil
+}
+
+// spillRun - a temporary file holding a sorted run of groups or rows.
+type spillRun struct {
+	file   *os.File
+	bw     *bufio.Writer
+	r      *bufio.Reader
+	budget *spillBudget
+	size   int64
+	closed bool
+	valueWriter
+}
+
+func newSpillRun(budget *spillBudget) (*spillRun, error) {
+	if budget.runs >= maxSpillRuns {
+		return nil, errOverMaxQueryStorage(fmt.Errorf("more than %d temporary runs required", maxSpillRuns))
+	}
+	file, err := ioutil.TempFile(spillDir, "s3select-spill-")
+	if err != nil {
+		return nil, err
+	}
+	budget.runs++
+	run := &spillRun{file: file, bw: bufio.NewWriter(file), budget: budget}
+	run.valueWriter.w = run
+	budget.files = append(budget.files, run)
+	return run, nil
+}
+
+func (run *spillRun) Write(p []byte) (int, error) {
+	n := int64(len(p))
+	run.budget.size += n
+	if run.budget.size > maxSpillSize {
+		return 0, errOverMaxQueryStorage(fmt.Errorf("more than %d bytes of temporary storage required", maxSpillSize))
+	}
+	if atomic.AddInt64(&totalSpillSize, n) > maxTotalSpillSize {
+		atomic.AddInt64(&totalSpillSize, -n)
+		return 0, errOverMaxQueryStorage(fmt.Errorf("more than %d bytes of temporary storage used by all queries", maxTotalSpillSize))
+	}
+	run.size += n
+	return run.bw.Write(p)
+}
+
+// rewind flushes the written run and prepares it for reading.
+func (run *spillRun) rewind() error {
+	if err := run.bw.Flush(); err != nil {
+		return err
+	}
+	if _, err := run.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	run.r = bufio.NewReader(run.file)
+	return nil
+}
+
+// Close removes the temporary file, it may be called more than once.
+func (run *spillRun) Close() error {
+	if run.closed {
+		return nil
+	}
+	run.closed = true
+	atomic.AddInt64(&totalSpillSize, -run.size)
+	err := run.file.Close()
+	os.Remove(run.file.Name())
+	return err
+}
+
+// valueWriter - encodes values, to spill them or to build group keys.
+type valueWriter struct {
+	w   io.Writer
+	buf [binary.MaxVarintLen64]byte
+}
+
+func (vw *valueWriter) writeUvarint(x uint64) error {
+	n := binary.PutUvarint(vw.buf[:], x)
+	_, err := vw.w.Write(vw.buf[:n])
+	return err
+}
+
+func (vw *valueWriter) writeBytes(b []byte) error {
+	if err := vw.writeUvarint(uint64(len(b))); err != nil {
+		return err
+	}
+	_, err := vw.w.Write(b)
+	return err
+}
+
+func (vw *valueWriter) writeValue(v *Value) error {
+	switch x := v.value.(type) {
+	case nil:
+		_, err := vw.w.Write([]byte{spillNull})
+		return err
+	case bool:
+		b := []byte{spillBool, 0}
+		if x {
+			b[1] = 1
+		}
+		_, err := vw.w.Write(b)
+		return err
+	case int64:
+		if _, err := vw.w.Write([]byte{spillInt}); err != nil {
+			return err
+		}
+		n := binary.PutVarint(vw.buf[:], x)
+		_, err := vw.w.Write(vw.buf[:n])
+		return err
+	case float64:
+		if _, err := vw.w.Write([]byte{spillFloat}); err != nil {
+			return err
+		}
+		return vw.writeUvarint(math.Float64bits(x))
+	case string:
+		if _, err := vw.w.Write([]byte{spillString}); err != nil {
+			return err
+		}
+		return vw.writeBytes([]byte(x))
+	case []byte:
+		if _, err := vw.w.Write([]byte{spillBytes}); err != nil {
+			return err
+		}
+		return vw.writeBytes(x)
+	case time.Time:
+		b, err := x.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		if _, err = vw.w.Write([]byte{spillTimestamp}); err != nil {
+			return err
+		}
+		return vw.writeBytes(b)
+	case []Value:
+		if _, err := vw.w.Write([]byte{spillArray}); err != nil {
+			return err
+		}
+		if err := vw.writeUvarint(uint64(len(x))); err != nil {
+			return err
+		}
+		for i := range x {
+			if err := vw.writeValue(&x[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("cannot spill value of type %T", v.value)
+}
+
+func (vw *valueWriter) writeValues(values []*Value) error {
+	if err := vw.writeUvarint(uint64(len(values))); err != nil {
+		return err
+	}
+	for _, v := range values {
+		if err := vw.writeValue(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (run *spillRun) readUvarint() (uint64, error) {
+	return binary.ReadUvarint(run.r)
+}
+
+func (run *spillRun) readBytes() ([]byte, error) {
+	n, err := run.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, n)
+	_, err = io.ReadFull(run.r, b)
+	return b, err
+}
+
+func (run *spillRun) readValue() (*Value, error) {
+	t, err := run.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t {
+	case spillNull:
+		return FromNull(), nil
+	case spillBool:
+		b, err := run.r.ReadByte()
+		return FromBool(b == 1), err
+	case spillInt:
+		x, err := binary.ReadVarint(run.r)
+		return FromInt(x), err
+	case spillFloat:
+		x, err := run.readUvarint()
+		return FromFloat(math.Float64frombits(x)), err
+	case spillString:
+		b, err := run.readBytes()
+		return FromString(string(b)), err
+	case spillBytes:
+		b, err := run.readBytes()
+		return FromBytes(b), err
+	case spillTimestamp:
+		b, err := run.readBytes()
+		if err != nil {
+			return nil, err
+		}
+		var t time.Time
+		err = t.UnmarshalBinary(b)
+		return FromTimestamp(t), err
+	case spillArray:
+		n, err := run.readUvarint()
+		if err != nil {
+			return nil, err
+		}
+		a := make([]Value, n)
+		for i := range a {
+			v, err := run.readValue()
+			if err != nil {
+				return nil, err
+			}
+			a[i] = *v
+		}
+		return FromArray(a), nil
+	}
+	return nil, errors.New("invalid spilled value")
+}
+
+func (run *spillRun) readValues() ([]*Value, error) {
+	n, err := run.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]*Value, n)
+	for i := range values {
+		if values[i], err = run.readValue(); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// copyValue returns a copy of the value which does not share memory
+// with the record it was read from, as records are reused.
+func copyValue(v *Value) *Value {
+	switch x := v.value.(type) {
+	case []byte:
+		return FromBytes(append([]byte(nil), x...))
+	case []Value:
+		a := make([]Value, len(x))
+		for i := range x {
+			a[i] = *copyValue(&x[i])
+		}
+		return FromArray(a)
+	}
+	return &Value{value: v.value}
+}
+
+// runMerger - merges sorted runs, returning their items in order.
+type runMerger struct {
+	runs  []*spillRun
+	heads []interface{}
+	order []int
+	read  func(run *spillRun) (interface{}, error)
+	less  func(a, b interface{}) bool
+}
+
+func (m *runMerger) Len() int { return len(m.order) }
+
+func (m *runMerger) Less(i, j int) bool {
+	a, b := m.heads[m.order[i]], m.heads[m.order[j]]
+	if m.less(a, b) {
+		return true
+	}
+	if m.less(b, a) {
+		return false
+	}
+	// Keep items of earlier runs first.
+	return m.order[i] < m.order[j]
+}
+
+func (m *runMerger) Swap(i, j int) { m.order[i], m.order[j] = m.order[j], m.order[i] }
+
+func (m *runMerger) Push(x interface{}) { m.order = append(m.order, x.(int)) }
+
+func (m *runMerger) Pop() interface{} {
+	n := len(m.order)
+	x := m.order[n-1]
+	m.order = m.order[:n-1]
+	return x
+}
+
+func newRunMerger(runs []*spillRun, read func(run *spillRun) (interface{}, error), less func(a, b interface{}) bool) (*runMerger, error) {
+	m := &runMerger{
+		runs:  runs,
+		heads: make([]interface{}, len(runs)),
+		read:  read,
+		less:  less,
+	}
+	for i, run := range runs {
+		if err := run.rewind(); err != nil {
+			return nil, err
+		}
+		item, err := read(run)
+		if err == io.EOF {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		m.heads[i] = item
+		m.order = append(m.order, i)
+	}
+	heap.Init(m)
+	return m, nil
+}
+
+// next returns the smallest item of all runs, or io.EOF.
+func (m *runMerger) next() (interface{}, error) {
+	if len(m.order) == 0 {
+		return nil, io.EOF
+	}
+	i := m.order[0]
+	item := m.heads[i]
+	next, err := m.read(m.runs[i])
+	switch err {
+	case nil:
+		m.heads[i] = next
+		heap.Fix(m, 0)
+	case io.EOF:
+		m.heads[i] = nil
+		heap.Pop(m)
+	default:
+		return nil, err
+	}
+	return item, nil
+}
+
+func closeSpillRuns(runs []*spillRun) {
+	for _, run := range runs {
+		run.Close()
+	}
+}
@@ -19,6 +19,7 @@ package sql
 import (
 	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/bcicen/jstream"
@@ -46,6 +47,23 @@ type SelectStatement struct {
 
 	// Count of rows that have been output.
 	outputCount int64
+
+	// Analysis result of GROUP BY and ORDER BY queries: the select
+	// and ORDER BY expressions, the names of the result columns, the
+	// non-aggregation expressions evaluated on the first row of a
+	// group and the aggregation expressions along with their
+	// aggregation functions, which have a state per group.
+	selectTerms   []resultTerm
+	orderByTerms  []resultTerm
+	columns       []string
+	groupRowExprs []*Expression
+	aggregations  []*Expression
+	aggFuncs      []*FuncExpr
+
+	// Buffered groups or rows of GROUP BY and ORDER BY queries.
+	groups      *groupTable
+	sorter      *sorter
+	spillBudget spillBudget
 }
 
 // ParseSelectStatement - parses a select query from the given string
@@ -101,11 +119,27 @@ func ParseSelectStatement(s string) (stmt SelectStatement, err error) {
 		return
 	}
 
+	// GROUP BY queries analyze each select expression by itself
+	if len(selectAST.GroupBy) > 0 {
+		if err = stmt.analyzeResultTerms(); err != nil {
+			err = errQueryAnalysisFailure(err)
+		}
+		return
+	}
+
 	// Analyze main select expression
 	stmt.selectQProp = selectAST.Expression.analyze(&selectAST)
 	err = stmt.selectQProp.err
 	if err != nil {
 		err = errQueryAnalysisFailure(err)
+		return
+	}
+
+	// Sorting a single aggregated row is a no-op
+	if len(selectAST.OrderBy) > 0 && !stmt.selectQProp.isAggregation {
+		if err = stmt.analyzeResultTerms(); err != nil {
+			err = errQueryAnalysisFailure(err)
+		}
 	}
 	return
 }
@@ -222,6 +256,59 @@ func (e *SelectStatement) IsAggregated() bool {
 	return e.selectQProp.isAggregation
 }
 
+// Columns - returns the names of the top level input columns referenced
+// by the statement, which allows readers of columnar formats to skip the
+// other columns. A nil result means that all columns are needed.
+func (e *SelectStatement) Columns() []string {
+	if e.selectAST.Expression.All {
+		return nil
+	}
+
+	var nodes []interface{}
+	for _, expr := range e.selectAST.Expression.Expressions {
+		nodes = append(nodes, expr)
+	}
+	if e.selectAST.Where != nil {
+		nodes = append(nodes, e.selectAST.Where)
+	}
+	for _, expr := range e.selectAST.GroupBy {
+		nodes = append(nodes, expr)
+	}
+	for _, expr := range e.selectAST.OrderBy {
+		nodes = append(nodes, expr)
+	}
+
+	columns := []string{}
+	found := make(map[string]bool)
+	all := false
+	for _, node := range nodes {
+		walkAST(reflect.ValueOf(node), func(node interface{}) {
+			jpath, ok := node.(*JSONPath)
+			if !ok {
+				return
+			}
+			// A path without elements is a column name, otherwise
+			// the base key is the table name.
+			name := jpath.BaseKey.String()
+			if len(jpath.PathExpr) > 0 {
+				if jpath.PathExpr[0].Key == nil {
+					all = true
+					return
+				}
+				name = jpath.PathExpr[0].Key.keyString()
+			}
+			if !found[name] {
+				found[name] = true
+				columns = append(columns, name)
+			}
+		})
+	}
+	if all {
+		return nil
+	}
+	return columns
+}
+
 // AggregateResult - returns the aggregated result after all input
 // records have been processed. Applies only to aggregation queries.
 func (e *SelectStatement) AggregateResult(output Record) error {
@@ -76,23 +76,12 @@ func (o *ObjectKey) keyString() string {
 // expression, and if so extracts the last dot separated component of
 // the path. Otherwise it returns false.
 func getLastKeypathComponent(e *Expression) (string, bool) {
-	if len(e.And) > 1 ||
-		len(e.And[0].Condition) > 1 ||
-		e.And[0].Condition[0].Not != nil ||
-		e.And[0].Condition[0].Operand.ConditionRHS != nil {
-		return "", false
-	}
-
-	operand := e.And[0].Condition[0].Operand.Operand
-	if operand.Right != nil ||
-		operand.Left.Right != nil ||
-		operand.Left.Left.Negated != nil ||
-		operand.Left.Left.Primary.JPathExpr == nil {
+	jpath, ok := getKeypath(e)
+	if !ok {
 		return "", false
 	}
 
 	// Check if path expression ends in a key
-	jpath := operand.Left.Left.Primary.JPathExpr
 	n := len(jpath.PathExpr)
 	if n > 0 && jpath.PathExpr[n-1].Key == nil {
 		return "", false
@@ -105,6 +94,26 @@ func getLastKeypathComponent(e *Expression) (string, bool) {
 	return ps, true
 }
 
+// getKeypath checks if the given expression is a path expression,
+// and if so returns it.
+func getKeypath(e *Expression) (*JSONPath, bool) {
+	if len(e.And) > 1 ||
+		len(e.And[0].Condition) > 1 ||
+		e.And[0].Condition[0].Not != nil ||
+		e.And[0].Condition[0].Operand.ConditionRHS != nil {
+		return nil, false
+	}
+
+	operand := e.And[0].Condition[0].Operand.Operand
+	if operand.Right != nil ||
+		operand.Left.Right != nil ||
+		operand.Left.Left.Negated != nil ||
+		operand.Left.Left.Primary.JPathExpr == nil {
+		return nil, false
+	}
+	return operand.Left.Left.Primary.JPathExpr, true
+}
+
 // HasKeypath returns if the from clause has a key path -
 // e.g. S3object[*].id
 func (from *TableExpression) HasKeypath() bool {